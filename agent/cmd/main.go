@@ -142,6 +142,8 @@ func parseFlags() *configs.AgentConfig {
 		"The goroutine number to propagate the bundles on managed cluster.")
 	pflag.IntVar(&agentConfig.TransportConfig.FailureThreshold, "transport-failure-threshold", 10,
 		"Restart the pod if the transport error count exceeds the transport-failure-threshold within 5 minutes.")
+	pflag.StringVar(&agentConfig.TransportConfig.TransportType, "transport-type", string(transport.Kafka),
+		"The transport type, 'kafka' or 'nats'.")
 	pflag.BoolVar(&agentConfig.SpecEnforceHohRbac, "enforce-hoh-rbac", false,
 		"enable hoh RBAC or not, default false")
 	pflag.IntVar(&agentConfig.StatusDeltaCountSwitchFactor,
@@ -100,7 +100,7 @@ func (c *initController) addStackRoxCentrals() (result ctrl.Result, err error) {
 	// manager so that it will be started automatically.
 	syncer, err := security.NewStackRoxSyncer().
 		SetLogger(logger.ZapLogger("stackrox-syncer")).
-		SetTopic(c.agentConfig.TransportConfig.KafkaCredential.StatusTopic).
+		SetTopic(c.agentConfig.TransportConfig.GetClusterTopic().StatusTopic).
 		SetProducer(c.transportClient.GetProducer()).
 		SetKubernetesClient(c.mgr.GetClient()).
 		SetPollInterval(c.agentConfig.StackroxPollInterval).
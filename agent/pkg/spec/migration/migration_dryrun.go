@@ -0,0 +1,126 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package migration
+
+import (
+	"context"
+	"fmt"
+
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	operatorv1 "open-cluster-management.io/api/operator/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/migration"
+)
+
+// previewInitializing reports the rbac that the initializing would create or update for the registration of the
+// migrating clusters, nothing is changed in the target hub
+func (s *MigrationTargetSyncer) previewInitializing(ctx context.Context, msaName, msaNamespace string) error {
+	report := &migrationv1alpha1.DryRunReport{}
+
+	clusterManager := &operatorv1.ClusterManager{}
+	err := s.client.Get(ctx, types.NamespacedName{Name: ClusterManagerName}, clusterManager)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	autoApproveUser := fmt.Sprintf("system:serviceaccount:%s:%s", msaNamespace, msaName)
+	if apierrors.IsNotFound(err) {
+		report.Conflicts = append(report.Conflicts,
+			fmt.Sprintf("ClusterManager %s is not found in the target hub", ClusterManagerName))
+	} else if !isAutoApproveEnabled(clusterManager, autoApproveUser) {
+		report.RBAC = append(report.RBAC, fmt.Sprintf("update ClusterManager/%s: auto approve the user %s",
+			ClusterManagerName, autoApproveUser))
+	}
+
+	desiredObjects := []struct {
+		kind string
+		obj  client.Object
+	}{
+		{"ClusterRole", subjectAccessReviewClusterRole(msaName)},
+		{"ClusterRoleBinding", subjectAccessReviewClusterRoleBinding(msaName, msaNamespace)},
+		{"ClusterRoleBinding", agentRegistrationClusterRoleBinding(msaName, msaNamespace)},
+	}
+	for _, desired := range desiredObjects {
+		existing := desired.obj.DeepCopyObject().(client.Object)
+		err := s.client.Get(ctx, client.ObjectKeyFromObject(desired.obj), existing)
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		if apierrors.IsNotFound(err) {
+			report.RBAC = append(report.RBAC, fmt.Sprintf("create %s/%s", desired.kind, desired.obj.GetName()))
+		} else if !apiequality.Semantic.DeepDerivative(desired.obj, existing) {
+			report.RBAC = append(report.RBAC, fmt.Sprintf("update %s/%s", desired.kind, desired.obj.GetName()))
+		}
+	}
+
+	log.Infof("previewed initializing: rbac=%v, conflicts=%v", report.RBAC, report.Conflicts)
+	s.dryRunReport = report
+	return nil
+}
+
+// isAutoApproveEnabled returns true if the auto approval is enabled for the user in the cluster manager
+func isAutoApproveEnabled(clusterManager *operatorv1.ClusterManager, user string) bool {
+	registrationConfiguration := clusterManager.Spec.RegistrationConfiguration
+	if registrationConfiguration == nil {
+		return false
+	}
+	featureEnabled := false
+	for _, featureGate := range registrationConfiguration.FeatureGates {
+		if featureGate.Feature == "ManagedClusterAutoApproval" && featureGate.Mode == operatorv1.FeatureGateModeTypeEnable {
+			featureEnabled = true
+			break
+		}
+	}
+	if !featureEnabled {
+		return false
+	}
+	for _, autoApproveUser := range registrationConfiguration.AutoApproveUsers {
+		if autoApproveUser == user {
+			return true
+		}
+	}
+	return false
+}
+
+// previewMigrationResources records the resources that would be applied into the target hub, and reports the
+// conflicts if the resources already exist or the kinds aren't installed in the target hub
+func (s *MigrationTargetSyncer) previewMigrationResources(ctx context.Context,
+	migrationResources *migration.MigrationResourceBundle,
+) error {
+	if s.dryRunReport == nil {
+		s.dryRunReport = &migrationv1alpha1.DryRunReport{}
+	}
+
+	for _, clusterResource := range migrationResources.MigrationClusterResources {
+		clusterResources := migrationv1alpha1.DryRunClusterResources{ClusterName: clusterResource.ClusterName}
+		for _, resource := range clusterResource.ResourceList {
+			resourceKey := fmt.Sprintf("%s/%s/%s", resource.GetKind(), resource.GetNamespace(), resource.GetName())
+			clusterResources.Resources = append(clusterResources.Resources, resourceKey)
+
+			existing := &unstructured.Unstructured{}
+			existing.SetGroupVersionKind(resource.GroupVersionKind())
+			err := s.client.Get(ctx, client.ObjectKeyFromObject(&resource), existing)
+			switch {
+			case err == nil:
+				s.dryRunReport.Conflicts = append(s.dryRunReport.Conflicts,
+					fmt.Sprintf("%s already exists in the target hub", resourceKey))
+			case meta.IsNoMatchError(err):
+				s.dryRunReport.Conflicts = append(s.dryRunReport.Conflicts,
+					fmt.Sprintf("%s: the kind %s isn't installed in the target hub", resourceKey,
+						resource.GroupVersionKind()))
+			case !apierrors.IsNotFound(err):
+				return fmt.Errorf("failed to get the resource %s: %w", resourceKey, err)
+			}
+		}
+		s.dryRunReport.Resources = append(s.dryRunReport.Resources, clusterResources)
+		log.Debugf("deploying: previewed %d resources for cluster %s", len(clusterResources.Resources),
+			clusterResource.ClusterName)
+	}
+	return nil
+}
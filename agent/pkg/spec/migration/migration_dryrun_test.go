@@ -0,0 +1,157 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package migration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	addonv1 "github.com/stolostron/klusterlet-addon-controller/pkg/apis/agent/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	operatorv1 "open-cluster-management.io/api/operator/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/migration"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/controller"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+func newDryRunTargetSyncer(t *testing.T, objects ...client.Object) (*MigrationTargetSyncer, *ProducerMock) {
+	t.Helper()
+	fakeClient := fake.NewClientBuilder().WithScheme(configs.GetRuntimeScheme()).WithObjects(objects...).Build()
+	configs.SetAgentConfig(&configs.AgentConfig{LeafHubName: "hub2"})
+
+	producer := &ProducerMock{}
+	transportClient := &controller.TransportClient{}
+	transportClient.SetProducer(producer)
+	syncer := NewMigrationTargetSyncer(fakeClient, transportClient, &configs.AgentConfig{
+		TransportConfig: &transport.TransportInternalConfig{
+			KafkaCredential: &transport.KafkaConfig{StatusTopic: "status"},
+		},
+		LeafHubName: "hub2",
+	})
+	return syncer, producer
+}
+
+func reportedStatus(t *testing.T, producer *ProducerMock) *migration.MigrationStatusBundle {
+	t.Helper()
+	require.NotNil(t, producer.sentEvent)
+	status := &migration.MigrationStatusBundle{}
+	require.NoError(t, producer.sentEvent.DataAs(status))
+	return status
+}
+
+func TestDryRunValidating(t *testing.T) {
+	existingCluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}
+	syncer, producer := newDryRunTargetSyncer(t, existingCluster)
+
+	evt := utils.ToCloudEvent(constants.MigrationTargetMsgKey, constants.CloudEventGlobalHubClusterName, "hub2",
+		migration.MigrationTargetBundle{
+			MigrationId:     "dry-run",
+			Stage:           migrationv1alpha1.PhaseValidating,
+			ManagedClusters: []string{"cluster1", "cluster2"},
+			DryRun:          true,
+		})
+	evt.SetTime(time.Now())
+	require.NoError(t, syncer.Sync(context.Background(), &evt))
+
+	status := reportedStatus(t, producer)
+	assert.Empty(t, status.ErrMessage)
+	assert.Equal(t, []string{"managed cluster cluster1 already exists in target hub"}, status.DryRunReport.Conflicts)
+}
+
+func TestDryRunInitializing(t *testing.T) {
+	clusterManager := &operatorv1.ClusterManager{ObjectMeta: metav1.ObjectMeta{Name: ClusterManagerName}}
+	// the clusterrole is created by the previous migration with the same name
+	existingRole := subjectAccessReviewClusterRole("test")
+	syncer, producer := newDryRunTargetSyncer(t, clusterManager, existingRole)
+
+	evt := utils.ToCloudEvent(constants.MigrationTargetMsgKey, constants.CloudEventGlobalHubClusterName, "hub2",
+		migration.MigrationTargetBundle{
+			MigrationId:                           "dry-run",
+			Stage:                                 migrationv1alpha1.PhaseInitializing,
+			ManagedServiceAccountName:             "test",
+			ManagedServiceAccountInstallNamespace: "open-cluster-management-agent-addon",
+			DryRun:                                true,
+		})
+	evt.SetTime(time.Now())
+	require.NoError(t, syncer.Sync(context.Background(), &evt))
+
+	status := reportedStatus(t, producer)
+	assert.Empty(t, status.ErrMessage)
+	assert.Equal(t, []string{
+		"update ClusterManager/cluster-manager: auto approve the user " +
+			"system:serviceaccount:open-cluster-management-agent-addon:test",
+		"create ClusterRoleBinding/global-hub-migration-test-sar",
+		"create ClusterRoleBinding/global-hub-migration-test-registration",
+	}, status.DryRunReport.RBAC)
+
+	// nothing is changed in the target hub
+	require.NoError(t, syncer.client.Get(context.Background(), client.ObjectKeyFromObject(clusterManager),
+		clusterManager))
+	assert.Nil(t, clusterManager.Spec.RegistrationConfiguration)
+	err := syncer.client.Get(context.Background(),
+		client.ObjectKey{Name: GetAgentRegistrationClusterRoleBindingName("test")}, &rbacv1.ClusterRoleBinding{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
+
+func TestDryRunDeploying(t *testing.T) {
+	existingCluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}
+	syncer, producer := newDryRunTargetSyncer(t, existingCluster)
+	syncer.processingMigrationId = "dry-run"
+
+	mcObj := &unstructured.Unstructured{}
+	mcObj.SetAPIVersion("cluster.open-cluster-management.io/v1")
+	mcObj.SetKind("ManagedCluster")
+	mcObj.SetName("cluster1")
+	addonObj := &unstructured.Unstructured{}
+	addonObj.SetAPIVersion("agent.open-cluster-management.io/v1")
+	addonObj.SetKind("KlusterletAddonConfig")
+	addonObj.SetName("cluster1")
+	addonObj.SetNamespace("cluster1")
+
+	evt := utils.ToCloudEvent(constants.MigrationTargetMsgKey, "hub1", "hub2", migration.MigrationResourceBundle{
+		MigrationId: "dry-run",
+		MigrationClusterResources: []migration.MigrationClusterResource{
+			{ClusterName: "cluster1", ResourceList: []unstructured.Unstructured{*mcObj, *addonObj}},
+		},
+		DryRun: true,
+	})
+	evt.SetExtension(migration.ExtTotalClusters, 1)
+	evt.SetTime(time.Now())
+	require.NoError(t, syncer.Sync(context.Background(), &evt))
+
+	status := reportedStatus(t, producer)
+	assert.Equal(t, migrationv1alpha1.PhaseDeploying, status.Stage)
+	assert.Empty(t, status.ErrMessage)
+	assert.Equal(t, &migrationv1alpha1.DryRunReport{
+		Resources: []migrationv1alpha1.DryRunClusterResources{
+			{
+				ClusterName: "cluster1",
+				Resources:   []string{"ManagedCluster//cluster1", "KlusterletAddonConfig/cluster1/cluster1"},
+			},
+		},
+		Conflicts: []string{"ManagedCluster//cluster1 already exists in the target hub"},
+	}, status.DryRunReport)
+
+	// nothing is applied into the target hub
+	err := syncer.client.Get(context.Background(), client.ObjectKey{Name: "cluster1"}, &corev1.Namespace{})
+	assert.True(t, apierrors.IsNotFound(err))
+	err = syncer.client.Get(context.Background(), client.ObjectKeyFromObject(addonObj),
+		&addonv1.KlusterletAddonConfig{})
+	assert.True(t, apierrors.IsNotFound(err))
+}
@@ -216,6 +216,8 @@ func (s *MigrationSourceSyncer) deploying(ctx context.Context, source *migration
 	totalClusters := len(source.ManagedClusters)
 
 	migrationBundle := migration.NewMigrationResourceBundle(source.MigrationId)
+	// the target hub only previews the resources in the dry run
+	migrationBundle.DryRun = source.DryRun
 
 	// collect clusters and klusterletAddonConfig for migration
 	for _, managedCluster := range source.ManagedClusters {
@@ -301,7 +303,7 @@ func (s *MigrationSourceSyncer) sendMigrationBundle(
 	e.SetExtension(migration.ExtTotalClusters, totalClusters)
 
 	if err := s.transportClient.GetProducer().SendEvent(
-		cecontext.WithTopic(ctx, s.transportConfig.KafkaCredential.SpecTopic), e); err != nil {
+		cecontext.WithTopic(ctx, s.transportConfig.GetClusterTopic().SpecTopic), e); err != nil {
 		return fmt.Errorf(errFailedToSendEvent, constants.MigrationTargetMsgKey, fromHub, toHub, err)
 	}
 
@@ -768,7 +770,7 @@ func (s *MigrationSourceSyncer) reportStatus(ctx context.Context, spec *migratio
 		allManagedClusterList = spec.ManagedClusters
 	}
 	reportErr := ReportMigrationStatus(
-		cecontext.WithTopic(ctx, s.transportConfig.KafkaCredential.StatusTopic),
+		cecontext.WithTopic(ctx, s.transportConfig.GetClusterTopic().StatusTopic),
 		s.transportClient,
 		&migration.MigrationStatusBundle{
 			MigrationId:     spec.MigrationId,
@@ -827,7 +829,7 @@ func ResyncMigrationEvent(ctx context.Context, transportClient transport.Transpo
 	transportConfig *transport.TransportInternalConfig,
 ) error {
 	return ReportMigrationStatus(
-		cecontext.WithTopic(ctx, transportConfig.KafkaCredential.StatusTopic),
+		cecontext.WithTopic(ctx, transportConfig.GetClusterTopic().StatusTopic),
 		transportClient,
 		&migration.MigrationStatusBundle{
 			Resync: true,
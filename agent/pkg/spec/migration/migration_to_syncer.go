@@ -87,6 +87,8 @@ type MigrationTargetSyncer struct {
 	// Batch tracking for deploying stage
 	deployingTotalClusters     int             // Total clusters expected in deploying stage
 	deployingProcessedClusters map[string]bool // Track which clusters have been processed
+	// dryRunReport is the preview of the current stage, it's reported with the status of the dry run migration
+	dryRunReport *migrationv1alpha1.DryRunReport
 }
 
 func NewMigrationTargetSyncer(client client.Client,
@@ -147,7 +149,9 @@ func (s *MigrationTargetSyncer) Sync(ctx context.Context, evt *cloudevents.Event
 		}
 
 		if reportStatus {
-			err = ReportMigrationStatus(cecontext.WithTopic(ctx, s.transportConfig.KafkaCredential.StatusTopic),
+			migrationStatus.DryRunReport = s.dryRunReport
+			s.dryRunReport = nil
+			err = ReportMigrationStatus(cecontext.WithTopic(ctx, s.transportConfig.GetClusterTopic().StatusTopic),
 				s.transportClient, migrationStatus, s.bundleVersion)
 			if err != nil {
 				log.Errorf("failed to report migration status: %v", err)
@@ -304,6 +308,20 @@ func (s *MigrationTargetSyncer) validating(
 ) error {
 	// Validate the clusters
 	if len(source.ManagedClusters) > 0 {
+		if source.DryRun {
+			// report the invalid clusters as the conflicts, rather than failing the dry run
+			s.dryRunReport = &migrationv1alpha1.DryRunReport{}
+			conflicts := map[string]string{}
+			_ = s.validateManagedClusters(ctx, source.ManagedClusters, conflicts)
+			for _, clusterName := range source.ManagedClusters {
+				if conflict, ok := conflicts[clusterName]; ok {
+					s.dryRunReport.Conflicts = append(s.dryRunReport.Conflicts, conflict)
+				}
+			}
+			log.Infof("validated %d clusters for dry run migration %s, conflicts: %d", len(source.ManagedClusters),
+				source.MigrationId, len(s.dryRunReport.Conflicts))
+			return nil
+		}
 		if err := s.validateManagedClusters(ctx, source.ManagedClusters, clusterErrors); err != nil {
 			return err
 		}
@@ -383,6 +401,9 @@ func (s *MigrationTargetSyncer) initializing(ctx context.Context,
 ) error {
 	msaName := event.ManagedServiceAccountName
 	msaNamespace := event.ManagedServiceAccountInstallNamespace
+	if event.DryRun {
+		return s.previewInitializing(ctx, msaName, msaNamespace)
+	}
 	if err := s.ensureClusterManagerAutoApproval(ctx, msaName, msaNamespace); err != nil {
 		return err
 	}
@@ -442,12 +463,17 @@ func (s *MigrationTargetSyncer) deploying(ctx context.Context, evt *cloudevents.
 		}
 		s.deployingTotalClusters = totalClusters
 		s.deployingProcessedClusters = make(map[string]bool)
+		s.dryRunReport = nil
 		log.Infof("deploying: initialized batch tracking for migration %s, expecting %d total clusters",
 			resourceEvent.MigrationId, totalClusters)
 	}
 
 	// Process the resources in this batch
-	if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+	if resourceEvent.DryRun {
+		if err := s.previewMigrationResources(ctx, resourceEvent); err != nil {
+			return err
+		}
+	} else if err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
 		return s.syncMigrationResources(ctx, resourceEvent)
 	}); err != nil {
 		return err
@@ -710,18 +736,7 @@ func (s *MigrationTargetSyncer) ensureClusterManagerAutoApproval(ctx context.Con
 func (s *MigrationTargetSyncer) ensureSubjectAccessReviewRole(ctx context.Context, msaName string) error {
 	// create or update clusterrole for the migration service account
 	subjectAccessReviewClusterRoleName := GetSubjectAccessReviewClusterRoleName(msaName)
-	subjectAccessReview := &rbacv1.ClusterRole{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: subjectAccessReviewClusterRoleName,
-		},
-		Rules: []rbacv1.PolicyRule{
-			{
-				APIGroups: []string{"authorization.k8s.io"},
-				Resources: []string{"subjectaccessreviews"},
-				Verbs:     []string{"create"},
-			},
-		},
-	}
+	subjectAccessReview := subjectAccessReviewClusterRole(msaName)
 
 	foundMigrationClusterRole := &rbacv1.ClusterRole{}
 	if err := s.client.Get(ctx,
@@ -761,25 +776,8 @@ func (s *MigrationTargetSyncer) ensureSubjectAccessReviewRole(ctx context.Contex
 func (s *MigrationTargetSyncer) ensureRegistrationClusterRoleBinding(ctx context.Context,
 	msaName, msaNamespace string,
 ) error {
-	registrationClusterRoleName := "open-cluster-management:managedcluster:bootstrap:agent-registration"
 	registrationClusterRoleBindingName := GetAgentRegistrationClusterRoleBindingName(msaName)
-	registrationClusterRoleBinding := &rbacv1.ClusterRoleBinding{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: registrationClusterRoleBindingName,
-		},
-		Subjects: []rbacv1.Subject{
-			{
-				Kind:      "ServiceAccount",
-				Name:      msaName,
-				Namespace: msaNamespace,
-			},
-		},
-		RoleRef: rbacv1.RoleRef{
-			Kind:     "ClusterRole",
-			Name:     registrationClusterRoleName,
-			APIGroup: "rbac.authorization.k8s.io",
-		},
-	}
+	registrationClusterRoleBinding := agentRegistrationClusterRoleBinding(msaName, msaNamespace)
 
 	foundRegistrationClusterRoleBinding := &rbacv1.ClusterRoleBinding{}
 	if err := s.client.Get(ctx,
@@ -821,25 +819,7 @@ func (s *MigrationTargetSyncer) ensureRegistrationClusterRoleBinding(ctx context
 func (s *MigrationTargetSyncer) ensureSubjectAccessReviewRoleBinding(ctx context.Context,
 	msaName, msaNamespace string,
 ) error {
-	accessReviewClusterRoleName := GetSubjectAccessReviewClusterRoleName(msaName)
-	accessReviewClusterRoleBindingName := GetSubjectAccessReviewClusterRoleBindingName(msaName)
-	accessReviewClusterRoleBinding := &rbacv1.ClusterRoleBinding{
-		ObjectMeta: metav1.ObjectMeta{
-			Name: accessReviewClusterRoleBindingName,
-		},
-		Subjects: []rbacv1.Subject{
-			{
-				Kind:      "ServiceAccount",
-				Name:      msaName,
-				Namespace: msaNamespace,
-			},
-		},
-		RoleRef: rbacv1.RoleRef{
-			Kind:     "ClusterRole",
-			Name:     accessReviewClusterRoleName,
-			APIGroup: "rbac.authorization.k8s.io",
-		},
-	}
+	accessReviewClusterRoleBinding := subjectAccessReviewClusterRoleBinding(msaName, msaNamespace)
 
 	foundAccessReviewClusterRoleBinding := &rbacv1.ClusterRoleBinding{}
 	if err := s.client.Get(ctx, client.ObjectKeyFromObject(accessReviewClusterRoleBinding),
@@ -881,6 +861,53 @@ func (s *MigrationTargetSyncer) ensureSubjectAccessReviewRoleBinding(ctx context
 	return nil
 }
 
+// subjectAccessReviewClusterRole allows the migration service account to create the subjectaccessreviews
+func subjectAccessReviewClusterRole(msaName string) *rbacv1.ClusterRole {
+	return &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: GetSubjectAccessReviewClusterRoleName(msaName),
+		},
+		Rules: []rbacv1.PolicyRule{
+			{
+				APIGroups: []string{"authorization.k8s.io"},
+				Resources: []string{"subjectaccessreviews"},
+				Verbs:     []string{"create"},
+			},
+		},
+	}
+}
+
+func subjectAccessReviewClusterRoleBinding(msaName, msaNamespace string) *rbacv1.ClusterRoleBinding {
+	return migrationClusterRoleBinding(GetSubjectAccessReviewClusterRoleBindingName(msaName),
+		GetSubjectAccessReviewClusterRoleName(msaName), msaName, msaNamespace)
+}
+
+// agentRegistrationClusterRoleBinding binds the migration service account with the agent registration clusterrole
+func agentRegistrationClusterRoleBinding(msaName, msaNamespace string) *rbacv1.ClusterRoleBinding {
+	return migrationClusterRoleBinding(GetAgentRegistrationClusterRoleBindingName(msaName),
+		"open-cluster-management:managedcluster:bootstrap:agent-registration", msaName, msaNamespace)
+}
+
+func migrationClusterRoleBinding(name, clusterRoleName, msaName, msaNamespace string) *rbacv1.ClusterRoleBinding {
+	return &rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      "ServiceAccount",
+				Name:      msaName,
+				Namespace: msaNamespace,
+			},
+		},
+		RoleRef: rbacv1.RoleRef{
+			Kind:     "ClusterRole",
+			Name:     clusterRoleName,
+			APIGroup: "rbac.authorization.k8s.io",
+		},
+	}
+}
+
 func GetSubjectAccessReviewClusterRoleName(managedServiceAccountName string) string {
 	return fmt.Sprintf("global-hub-migration-%s-sar", managedServiceAccountName)
 }
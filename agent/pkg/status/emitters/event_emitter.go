@@ -13,8 +13,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
 	genericbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -255,6 +257,9 @@ func (e *EventEmitter) sendEventBundle() error {
 		log.Errorw("failed to set event data for bundle", "error", err)
 		return fmt.Errorf("failed to set event data for bundle: %w", err)
 	}
+	if err := compressor.CompressEvent(&evt, configmap.GetCompressionType()); err != nil {
+		return fmt.Errorf("failed to compress event bundle: %w", err)
+	}
 
 	ctx := e.createContext()
 	if err := e.producer.SendEvent(ctx, evt); err != nil {
@@ -280,6 +285,9 @@ func (e *EventEmitter) sendEventsIndividually() error {
 			log.Errorw("failed to set event data for individual event", "error", err)
 			return fmt.Errorf("failed to set event data for individual event: %w", err)
 		}
+		if err := compressor.CompressEvent(&evt, configmap.GetCompressionType()); err != nil {
+			return fmt.Errorf("failed to compress individual event: %w", err)
+		}
 
 		if err := e.producer.SendEvent(ctx, evt); err != nil {
 			log.Errorw("failed to send individual event", "error", err, "sent", sentCount, "total", len(e.events))
@@ -13,8 +13,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
 	genericbundle "github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -323,6 +325,9 @@ func (e *ObjectEmitter) sendBundle() error {
 	if err != nil {
 		return fmt.Errorf("failed to load bundle into cloudevent: %v", err)
 	}
+	if err = compressor.CompressEvent(&evt, configmap.GetCompressionType()); err != nil {
+		return fmt.Errorf("failed to compress bundle: %v", err)
+	}
 
 	log.Debugf("sending cloudevents: %s", evt)
 
@@ -6,7 +6,9 @@ import (
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
 	specsyncers "github.com/stolostron/multicluster-global-hub/agent/pkg/spec/syncers"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/interfaces"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 )
 
@@ -73,7 +75,10 @@ func (g *genericEmitter) ToCloudEvent(payload interface{}) (*cloudevents.Event,
 	if g.dependencyVersion != nil {
 		e.SetExtension(eventversion.ExtDependencyVersion, g.dependencyVersion.String())
 	}
-	err := e.SetData(cloudevents.ApplicationJSON, payload)
+	if err := e.SetData(cloudevents.ApplicationJSON, payload); err != nil {
+		return &e, err
+	}
+	err := compressor.CompressEvent(&e, configmap.GetCompressionType())
 	return &e, err
 }
 
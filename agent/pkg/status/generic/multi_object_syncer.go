@@ -13,6 +13,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
@@ -65,7 +66,7 @@ func LaunchMultiObjectSyncer(name string, mgr ctrl.Manager, objectControllers []
 
 	// start all the controllers to update the payload
 	for _, eventController := range objectControllers {
-		err := AddObjectController(mgr, eventController, emitter, syncer.lock)
+		err := AddObjectController(mgr, name, eventController, emitter, syncer.lock)
 		if err != nil {
 			return err
 		}
@@ -137,10 +138,16 @@ type objectController struct {
 	lock *sync.Mutex
 }
 
-func AddObjectController(mgr ctrl.Manager, objectCtrl ControllerHandler, emitter interfaces.Emitter,
+// AddObjectController adds the controller of the object into the manager, it's named by the syncer and the object kind,
+// e.g. status_hub_cluster_info_route, so the same kind can be watched by multiple syncers.
+func AddObjectController(mgr ctrl.Manager, syncerName string, objectCtrl ControllerHandler, emitter interfaces.Emitter,
 	lock *sync.Mutex,
 ) error {
 	object := objectCtrl.Instance()
+	gvk, err := apiutil.GVKForObject(object, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
 	controller := &objectController{
 		log:              logger.ZapLogger(fmt.Sprintf("status.%s", object.GetObjectKind())),
 		client:           mgr.GetClient(),
@@ -150,7 +157,8 @@ func AddObjectController(mgr ctrl.Manager, objectCtrl ControllerHandler, emitter
 		lock:             lock,
 	}
 
-	controllerBuilder := ctrl.NewControllerManagedBy(mgr).For(object)
+	controllerBuilder := ctrl.NewControllerManagedBy(mgr).For(object).
+		Named(strings.ReplaceAll(fmt.Sprintf("%s_%s", syncerName, strings.ToLower(gvk.Kind)), ".", "_"))
 	if objectCtrl.Predicate() != nil {
 		controllerBuilder = controllerBuilder.WithEventFilter(objectCtrl.Predicate())
 	}
@@ -110,7 +110,7 @@ func addKafkaSyncer(ctx context.Context, mgr ctrl.Manager, producer transport.Pr
 
 	// lunch a time filter, it must be called after filter.RegisterTimeFilter(key)
 	if err := filter.LaunchTimeFilter(ctx, runtimeClient, agentConfig.PodNamespace,
-		agentConfig.TransportConfig.KafkaCredential.StatusTopic); err != nil {
+		agentConfig.TransportConfig.GetClusterTopic().StatusTopic); err != nil {
 		return fmt.Errorf("failed to launch time filter: %w", err)
 	}
 	return nil
@@ -13,6 +13,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
@@ -80,6 +81,27 @@ func (c *hubOfHubsConfigController) Reconcile(ctx context.Context, request ctrl.
 	c.setAgentConfig(agentConfigMap, AgentAggregationKey)
 	c.setAgentConfig(agentConfigMap, EnableLocalPolicyKey)
 
+	// the bundles aren't compressed if the compression type is removed from the configmap
+	compressionType := compressor.CompressionType(agentConfigMap.Data[CompressionTypeKey])
+	if compressionType == "" {
+		compressionType = compressor.NoOp
+	}
+	if err := SetCompressionType(compressionType); err != nil {
+		c.log.Errorf("failed to set the compression type: %v", err)
+	}
+
+	// the previous event rules are kept if the new rules are invalid
+	if data, found := agentConfigMap.Data[EventRulesKey]; found {
+		if rules, err := ParseEventRules(data); err != nil {
+			c.log.Errorf("failed to parse the event rules: %v", err)
+		} else {
+			c.log.Infof("setting %d event rules", len(rules))
+			SetEventRules(rules)
+		}
+	} else {
+		SetEventRules(nil)
+	}
+
 	logLevel := agentConfigMap.Data[string(AgentLogLevelKey)]
 	if logLevel != "" {
 		logger.SetLogLevel(logger.LogLevel(logLevel))
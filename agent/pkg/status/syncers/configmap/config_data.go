@@ -6,6 +6,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 )
 
@@ -33,6 +34,7 @@ var (
 	agentConfigs = map[string]AgentConfigValue{
 		AgentAggregationKey:  AggregationFull,
 		EnableLocalPolicyKey: EnableLocalPolicyTrue,
+		CompressionTypeKey:   AgentConfigValue(compressor.NoOp),
 	}
 
 	// Mutex to protect concurrent access to the intervals maps
@@ -43,6 +45,9 @@ const (
 	AgentAggregationKey  = "aggregationLevel"
 	EnableLocalPolicyKey = "enableLocalPolicies"
 	AgentLogLevelKey     = "logLevel"
+	// CompressionTypeKey specifies the compressor of the status bundles, e.g. "zstd", "snappy", "lz4" or "gzip",
+	// the manager decompresses the bundle based on the type stamped in the event, so it can be changed on the fly
+	CompressionTypeKey = "compressionType"
 )
 
 type AgentConfigValue string
@@ -98,6 +103,23 @@ func GetEnableLocalPolicy() AgentConfigValue {
 	return agentConfigs[EnableLocalPolicyKey]
 }
 
+func GetCompressionType() compressor.CompressionType {
+	intervalsMutex.RLock()
+	defer intervalsMutex.RUnlock()
+	return compressor.CompressionType(agentConfigs[CompressionTypeKey])
+}
+
+// SetCompressionType sets the compressor of the status bundles, the unsupported type is ignored.
+func SetCompressionType(compressionType compressor.CompressionType) error {
+	if _, err := compressor.NewCompressor(compressionType); err != nil {
+		return fmt.Errorf("invalid compression type %s: %w", compressionType, err)
+	}
+	intervalsMutex.Lock()
+	defer intervalsMutex.Unlock()
+	agentConfigs[CompressionTypeKey] = AgentConfigValue(compressionType)
+	return nil
+}
+
 func GetResyncInterval(eventType enum.EventType) time.Duration {
 	intervalsMutex.RLock()
 	defer intervalsMutex.RUnlock()
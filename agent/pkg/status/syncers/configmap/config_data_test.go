@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 )
 
@@ -54,3 +55,17 @@ func TestAgentConfigs(t *testing.T) {
 	assert.Equal(AggregationFull, GetAggregationLevel())
 	assert.Equal(EnableLocalPolicyTrue, GetEnableLocalPolicy())
 }
+
+func TestSetCompressionType(t *testing.T) {
+	assert := assert.New(t)
+	assert.Equal(compressor.NoOp, GetCompressionType())
+
+	assert.NoError(SetCompressionType(compressor.Zstd))
+	assert.Equal(compressor.Zstd, GetCompressionType())
+
+	// the unsupported type is ignored
+	assert.Error(SetCompressionType("brotli"))
+	assert.Equal(compressor.Zstd, GetCompressionType())
+
+	assert.NoError(SetCompressionType(compressor.NoOp))
+}
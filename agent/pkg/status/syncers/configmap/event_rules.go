@@ -0,0 +1,255 @@
+package configmap
+
+import (
+	"fmt"
+	"regexp"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/yaml"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// EventRulesKey is the key of the filter and redaction rules of the kube events in the agent configmap, which are
+// rendered by the operator from the agent-event-rules annotation of the global hub, e.g.
+//
+//	eventRules: |
+//	  - name: drop-probe-noise
+//	    reasons: ["ProbeSucceeded"]
+//	    action: drop
+//	  - name: mask-token
+//	    message: 'token=\S+'
+//	    action: mask
+//	    maskPattern: 'token=\S+'
+const EventRulesKey = "eventRules"
+
+// MaskedValue replaces the masked part of the event fields
+const MaskedValue = "******"
+
+type EventRuleAction string
+
+const (
+	// EventRuleDrop drops the matched events
+	EventRuleDrop EventRuleAction = "drop"
+	// EventRuleSample keeps one of every sampleRate matched events
+	EventRuleSample EventRuleAction = "sample"
+	// EventRuleMask masks the maskFields of the matched events
+	EventRuleMask EventRuleAction = "mask"
+)
+
+// the fields of the kube event can be masked
+const (
+	MaskFieldMessage     = "message"
+	MaskFieldAnnotations = "annotations"
+)
+
+// EventRule matches the kube events by all the given conditions, the empty condition matches everything
+type EventRule struct {
+	Name string `json:"name"`
+	// EventTypes are the event types of the emitters, e.g. "event.managedcluster", all if it's empty
+	EventTypes []string `json:"eventTypes,omitempty"`
+	// Reasons are the reasons of the event
+	Reasons []string `json:"reasons,omitempty"`
+	// Types are the types of the event, e.g. "Normal" or "Warning"
+	Types []string `json:"types,omitempty"`
+	// InvolvedObject matches the involved object of the event, the name is a regex
+	InvolvedObject *InvolvedObjectMatcher `json:"involvedObject,omitempty"`
+	// Message is the regex of the event message
+	Message string `json:"message,omitempty"`
+
+	Action EventRuleAction `json:"action"`
+	// SampleRate is the N of keeping one of every N matched events for the sample action
+	SampleRate int `json:"sampleRate,omitempty"`
+	// MaskFields are the fields to mask for the mask action, "message" and "annotations", default is "message"
+	MaskFields []string `json:"maskFields,omitempty"`
+	// MaskPattern is the regex of the part to mask in the fields, the whole field is masked if it's empty
+	MaskPattern string `json:"maskPattern,omitempty"`
+
+	messageRe     *regexp.Regexp
+	objectNameRe  *regexp.Regexp
+	maskPatternRe *regexp.Regexp
+	matched       uint64
+}
+
+type InvolvedObjectMatcher struct {
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+var (
+	eventRules      []*EventRule
+	eventRulesMutex sync.Mutex
+
+	filteredEventsCounter = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "multicluster_global_hub_agent_filtered_events_total",
+			Help: "The number of the kube events dropped, sampled out or masked by the event rules.",
+		},
+		[]string{"type", "rule", "action"},
+	)
+	registerEventRulesMetricsOnce sync.Once
+)
+
+// RegisterEventRulesMetrics registers the metrics of the event rules to the controller runtime metrics registry.
+func RegisterEventRulesMetrics() {
+	registerEventRulesMetricsOnce.Do(func() {
+		metrics.Registry.MustRegister(filteredEventsCounter)
+	})
+}
+
+// ParseEventRules parses the rules from the configmap value, and compiles the regexes of them.
+func ParseEventRules(data string) ([]*EventRule, error) {
+	rules := []*EventRule{}
+	if err := yaml.Unmarshal([]byte(data), &rules); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the event rules: %w", err)
+	}
+	for i, rule := range rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule-%d", i)
+		}
+		var err error
+		if rule.Message != "" {
+			if rule.messageRe, err = regexp.Compile(rule.Message); err != nil {
+				return nil, fmt.Errorf("invalid message regex of the event rule %s: %w", rule.Name, err)
+			}
+		}
+		if rule.InvolvedObject != nil && rule.InvolvedObject.Name != "" {
+			if rule.objectNameRe, err = regexp.Compile(rule.InvolvedObject.Name); err != nil {
+				return nil, fmt.Errorf("invalid involved object name regex of the event rule %s: %w", rule.Name, err)
+			}
+		}
+		switch rule.Action {
+		case EventRuleDrop:
+		case EventRuleSample:
+			if rule.SampleRate < 1 {
+				return nil, fmt.Errorf("the sample rate of the event rule %s must be at least 1", rule.Name)
+			}
+		case EventRuleMask:
+			if len(rule.MaskFields) == 0 {
+				rule.MaskFields = []string{MaskFieldMessage}
+			}
+			for _, field := range rule.MaskFields {
+				if field != MaskFieldMessage && field != MaskFieldAnnotations {
+					return nil, fmt.Errorf("the mask field %s of the event rule %s isn't supported", field, rule.Name)
+				}
+			}
+			if rule.MaskPattern != "" {
+				if rule.maskPatternRe, err = regexp.Compile(rule.MaskPattern); err != nil {
+					return nil, fmt.Errorf("invalid mask pattern of the event rule %s: %w", rule.Name, err)
+				}
+			}
+		default:
+			return nil, fmt.Errorf("the action %q of the event rule %s isn't supported", rule.Action, rule.Name)
+		}
+	}
+	return rules, nil
+}
+
+// SetEventRules replaces the event rules, the rules are cleared if it's empty.
+func SetEventRules(rules []*EventRule) {
+	eventRulesMutex.Lock()
+	defer eventRulesMutex.Unlock()
+	eventRules = rules
+}
+
+// ApplyEventRules applies the first matched rule to the kube event of the event type. It returns nil if the event is
+// dropped or sampled out, otherwise the event to be transformed, which is a masked copy if it's masked.
+func ApplyEventRules(eventType enum.EventType, evt *corev1.Event) *corev1.Event {
+	eventRulesMutex.Lock()
+	defer eventRulesMutex.Unlock()
+
+	shortType := enum.ShortenEventType(string(eventType))
+	for _, rule := range eventRules {
+		if !rule.matches(shortType, evt) {
+			continue
+		}
+		switch rule.Action {
+		case EventRuleDrop:
+			filteredEventsCounter.WithLabelValues(shortType, rule.Name, string(rule.Action)).Inc()
+			return nil
+		case EventRuleSample:
+			rule.matched++
+			if (rule.matched-1)%uint64(rule.SampleRate) != 0 {
+				filteredEventsCounter.WithLabelValues(shortType, rule.Name, string(rule.Action)).Inc()
+				return nil
+			}
+			return evt
+		case EventRuleMask:
+			filteredEventsCounter.WithLabelValues(shortType, rule.Name, string(rule.Action)).Inc()
+			return rule.mask(evt)
+		}
+	}
+	return evt
+}
+
+// EventRulesTransform wraps the transform of the event emitter, so the event rules are applied before the event is
+// transformed into the bundle.
+func EventRulesTransform(eventType enum.EventType, transform func(client.Client, client.Object) interface{},
+) func(client.Client, client.Object) interface{} {
+	return func(c client.Client, obj client.Object) interface{} {
+		evt, ok := obj.(*corev1.Event)
+		if !ok {
+			return transform(c, obj)
+		}
+		if evt = ApplyEventRules(eventType, evt); evt == nil {
+			return nil
+		}
+		return transform(c, evt)
+	}
+}
+
+func (r *EventRule) matches(shortType string, evt *corev1.Event) bool {
+	if len(r.EventTypes) > 0 && !utils.ContainsString(r.EventTypes, shortType) {
+		return false
+	}
+	if len(r.Reasons) > 0 && !utils.ContainsString(r.Reasons, evt.Reason) {
+		return false
+	}
+	if len(r.Types) > 0 && !utils.ContainsString(r.Types, evt.Type) {
+		return false
+	}
+	if r.InvolvedObject != nil {
+		if r.InvolvedObject.Kind != "" && r.InvolvedObject.Kind != evt.InvolvedObject.Kind {
+			return false
+		}
+		if r.InvolvedObject.Namespace != "" && r.InvolvedObject.Namespace != evt.InvolvedObject.Namespace {
+			return false
+		}
+		if r.objectNameRe != nil && !r.objectNameRe.MatchString(evt.InvolvedObject.Name) {
+			return false
+		}
+	}
+	if r.messageRe != nil && !r.messageRe.MatchString(evt.Message) {
+		return false
+	}
+	return true
+}
+
+// mask returns the copy of the event with the mask fields masked, the event from the cache mustn't be modified.
+func (r *EventRule) mask(evt *corev1.Event) *corev1.Event {
+	masked := evt.DeepCopy()
+	for _, field := range r.MaskFields {
+		switch field {
+		case MaskFieldMessage:
+			masked.Message = r.maskValue(masked.Message)
+		case MaskFieldAnnotations:
+			for key, value := range masked.Annotations {
+				masked.Annotations[key] = r.maskValue(value)
+			}
+		}
+	}
+	return masked
+}
+
+func (r *EventRule) maskValue(value string) string {
+	if r.maskPatternRe == nil {
+		return MaskedValue
+	}
+	return r.maskPatternRe.ReplaceAllString(value, MaskedValue)
+}
@@ -0,0 +1,110 @@
+package configmap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestParseEventRules(t *testing.T) {
+	rules, err := ParseEventRules(`
+- reasons: ["ProbeSucceeded"]
+  action: drop
+- name: mask-token
+  message: 'token=\S+'
+  action: mask
+`)
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	assert.Equal(t, "rule-0", rules[0].Name)
+	assert.Equal(t, []string{MaskFieldMessage}, rules[1].MaskFields)
+
+	cases := map[string]string{
+		"unknown action":     "- action: keep",
+		"invalid sample":     "- action: sample",
+		"invalid mask field": "- action: mask\n  maskFields: [\"reason\"]",
+		"invalid message":    "- action: drop\n  message: '['",
+		"invalid object":     "- action: drop\n  involvedObject:\n    name: '('",
+		"invalid pattern":    "- action: mask\n  maskPattern: '['",
+		"invalid yaml":       "action: drop",
+	}
+	for name, data := range cases {
+		_, err := ParseEventRules(data)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestApplyEventRules(t *testing.T) {
+	rules, err := ParseEventRules(`
+- name: drop-policy-probe
+  eventTypes: ["event.localrootpolicy"]
+  reasons: ["ProbeSucceeded"]
+  action: drop
+- name: sample-warnings
+  types: ["Warning"]
+  involvedObject:
+    kind: ManagedCluster
+    name: '^cluster-'
+  action: sample
+  sampleRate: 3
+- name: mask-token
+  message: 'token='
+  action: mask
+  maskFields: ["message", "annotations"]
+  maskPattern: 'token=\S+'
+`)
+	require.NoError(t, err)
+	SetEventRules(rules)
+	defer SetEventRules(nil)
+
+	newEvent := func(reason, eventType, name, message string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "event",
+				Annotations: map[string]string{"source": "token=abc"},
+			},
+			Reason:         reason,
+			Type:           eventType,
+			Message:        message,
+			InvolvedObject: corev1.ObjectReference{Kind: "ManagedCluster", Name: name},
+		}
+	}
+
+	// drop the events of the event type only
+	probe := newEvent("ProbeSucceeded", "Normal", "cluster-1", "probe succeeded")
+	assert.Nil(t, ApplyEventRules(enum.LocalRootPolicyEventType, probe))
+	assert.Equal(t, probe, ApplyEventRules(enum.ManagedClusterEventType, probe))
+
+	// keep one of every 3 matched events
+	kept := 0
+	for i := 0; i < 6; i++ {
+		if ApplyEventRules(enum.ManagedClusterEventType, newEvent("Failed", "Warning", "cluster-1", "failed")) != nil {
+			kept++
+		}
+	}
+	assert.Equal(t, 2, kept)
+	other := newEvent("Failed", "Warning", "local-cluster", "failed")
+	assert.Equal(t, other, ApplyEventRules(enum.ManagedClusterEventType, other))
+
+	// mask the copy of the event
+	secret := newEvent("Imported", "Normal", "cluster-1", "imported with token=abc by admin")
+	masked := ApplyEventRules(enum.ManagedClusterEventType, secret)
+	require.NotNil(t, masked)
+	assert.Equal(t, "imported with ****** by admin", masked.Message)
+	assert.Equal(t, "******", masked.Annotations["source"])
+	assert.Equal(t, "imported with token=abc by admin", secret.Message)
+
+	// the transform receives the masked event
+	message := func(_ client.Client, obj client.Object) interface{} {
+		return obj.(*corev1.Event).Message
+	}
+	assert.Nil(t, EventRulesTransform(enum.LocalRootPolicyEventType, message)(nil, probe))
+	assert.Equal(t, "imported with ****** by admin",
+		EventRulesTransform(enum.ManagedClusterEventType, message)(nil, secret))
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/emitters"
 	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/generic"
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/status/syncers/configmap"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -28,13 +29,14 @@ func AddEventSyncer(ctx context.Context, mgr ctrl.Manager,
 	}
 
 	runtimeClient = mgr.GetClient()
+	configmap.RegisterEventRulesMetrics()
 
 	managedClusterEventEmitter := emitters.NewEventEmitter(
 		enum.ManagedClusterEventType,
 		producer,
 		runtimeClient,
 		managedClusterEventPredicate,
-		managedClusterEventTransform,
+		configmap.EventRulesTransform(enum.ManagedClusterEventType, managedClusterEventTransform),
 		emitters.WithPostSend(managedClusterPostSend),
 	)
 
@@ -43,7 +45,7 @@ func AddEventSyncer(ctx context.Context, mgr ctrl.Manager,
 		producer,
 		runtimeClient,
 		localRootPolicyEventPredicate,
-		localRootPolicyEventTransform,
+		configmap.EventRulesTransform(enum.LocalRootPolicyEventType, localRootPolicyEventTransform),
 		emitters.WithPostSend(localRootPolicyPostSend),
 	)
 
@@ -52,7 +54,7 @@ func AddEventSyncer(ctx context.Context, mgr ctrl.Manager,
 		producer,
 		runtimeClient,
 		clusterGroupUpgradeEventPredicate,
-		clusterGroupUpgradeEventTransform,
+		configmap.EventRulesTransform(enum.ClusterGroupUpgradesEventType, clusterGroupUpgradeEventTransform),
 		emitters.WithPostSend(clusterGroupUpgradePostSend),
 	)
 
@@ -0,0 +1,304 @@
+package managedhub
+
+import (
+	"context"
+	"reflect"
+	"sort"
+
+	mchv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+)
+
+var multiClusterEngineListGVK = schema.GroupVersionKind{
+	Group:   "multicluster.openshift.io",
+	Version: "v1",
+	Kind:    "MultiClusterEngineList",
+}
+
+// changedWithClusterId returns whether the bundle should be sent for the change of the inventory, the bundle without
+// the ClusterId isn't sent, the inventory is carried once the ClusterId is set by the ClusterVersion.
+func changedWithClusterId(evtData cluster.HubClusterInfoBundle, changed bool) bool {
+	return changed && evtData.ClusterId != ""
+}
+
+// 3. Use MultiClusterHub to update the enabled components and the MCE version of the HubClusterInfo
+type infoMCHHandler struct {
+	evtData cluster.HubClusterInfoBundle
+	client  client.Client
+}
+
+func (p *infoMCHHandler) Get() interface{} {
+	return p.evtData
+}
+
+func (p *infoMCHHandler) Update(obj client.Object) bool {
+	mch, ok := obj.(*mchv1.MultiClusterHub)
+	if !ok {
+		return false
+	}
+
+	components := []string{}
+	if mch.Spec.Overrides != nil {
+		for _, component := range mch.Spec.Overrides.Components {
+			if component.Enabled {
+				components = append(components, component.Name)
+			}
+		}
+	}
+	sort.Strings(components)
+
+	// the MCE is upgraded by the MCH, so it's refreshed along with the status of the MCH
+	mceVersion, err := p.getMCEVersion()
+	if err != nil {
+		log.Warnw("failed to get the multiclusterengine version", "error", err)
+		mceVersion = p.evtData.MceVersion
+	}
+
+	changed := !reflect.DeepEqual(p.evtData.MchComponents, components) || p.evtData.MceVersion != mceVersion
+	p.evtData.MchComponents = components
+	p.evtData.MceVersion = mceVersion
+	return changedWithClusterId(p.evtData, changed)
+}
+
+func (p *infoMCHHandler) Delete(obj client.Object) bool {
+	changed := len(p.evtData.MchComponents) > 0 || p.evtData.MceVersion != ""
+	p.evtData.MchComponents = nil
+	p.evtData.MceVersion = ""
+	return changedWithClusterId(p.evtData, changed)
+}
+
+func (p *infoMCHHandler) getMCEVersion() (string, error) {
+	mceList := &unstructured.UnstructuredList{}
+	mceList.SetGroupVersionKind(multiClusterEngineListGVK)
+	err := p.client.List(context.Background(), mceList)
+	if meta.IsNoMatchError(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(mceList.Items) == 0 {
+		return "", nil
+	}
+	version, _, err := unstructured.NestedString(mceList.Items[0].Object, "status", "currentVersion")
+	return version, err
+}
+
+// 4. Use Node to update the capacity of the HubClusterInfo
+type nodeAllocatable struct {
+	cpuMillicores int64
+	memoryBytes   int64
+}
+
+type infoNodeHandler struct {
+	evtData cluster.HubClusterInfoBundle
+	nodes   map[string]nodeAllocatable
+}
+
+// nodePredicate skips the status updates of the nodes which don't change the allocatable resources
+var nodePredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldNode, oldOK := e.ObjectOld.(*corev1.Node)
+		newNode, newOK := e.ObjectNew.(*corev1.Node)
+		if !oldOK || !newOK {
+			return true
+		}
+		return !equality.Semantic.DeepEqual(oldNode.Status.Allocatable, newNode.Status.Allocatable)
+	},
+}
+
+func (p *infoNodeHandler) Get() interface{} {
+	return p.evtData
+}
+
+func (p *infoNodeHandler) Update(obj client.Object) bool {
+	node, ok := obj.(*corev1.Node)
+	if !ok {
+		return false
+	}
+	p.nodes[node.Name] = nodeAllocatable{
+		cpuMillicores: node.Status.Allocatable.Cpu().MilliValue(),
+		memoryBytes:   node.Status.Allocatable.Memory().Value(),
+	}
+	return p.refresh()
+}
+
+func (p *infoNodeHandler) Delete(obj client.Object) bool {
+	if _, found := p.nodes[obj.GetName()]; !found {
+		return false
+	}
+	delete(p.nodes, obj.GetName())
+	return p.refresh()
+}
+
+func (p *infoNodeHandler) refresh() bool {
+	capacity := &cluster.HubCapacity{NodeCount: len(p.nodes)}
+	for _, node := range p.nodes {
+		capacity.AllocatableCPUMillicores += node.cpuMillicores
+		capacity.AllocatableMemoryBytes += node.memoryBytes
+	}
+	changed := p.evtData.Capacity == nil || *p.evtData.Capacity != *capacity
+	p.evtData.Capacity = capacity
+	return changedWithClusterId(p.evtData, changed)
+}
+
+// 5. Use ManagedCluster to update the managed cluster counts of the HubClusterInfo
+type infoManagedClusterHandler struct {
+	evtData  cluster.HubClusterInfoBundle
+	clusters map[string]metav1.ConditionStatus
+}
+
+// managedClusterPredicate skips the updates of the managed clusters which don't change the availability
+var managedClusterPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldCluster, oldOK := e.ObjectOld.(*clusterv1.ManagedCluster)
+		newCluster, newOK := e.ObjectNew.(*clusterv1.ManagedCluster)
+		if !oldOK || !newOK {
+			return true
+		}
+		return clusterAvailability(oldCluster) != clusterAvailability(newCluster)
+	},
+}
+
+func clusterAvailability(managedCluster *clusterv1.ManagedCluster) metav1.ConditionStatus {
+	cond := meta.FindStatusCondition(managedCluster.Status.Conditions, clusterv1.ManagedClusterConditionAvailable)
+	if cond == nil {
+		return metav1.ConditionUnknown
+	}
+	return cond.Status
+}
+
+func (p *infoManagedClusterHandler) Get() interface{} {
+	return p.evtData
+}
+
+func (p *infoManagedClusterHandler) Update(obj client.Object) bool {
+	managedCluster, ok := obj.(*clusterv1.ManagedCluster)
+	if !ok {
+		return false
+	}
+	p.clusters[managedCluster.Name] = clusterAvailability(managedCluster)
+	return p.refresh()
+}
+
+func (p *infoManagedClusterHandler) Delete(obj client.Object) bool {
+	if _, found := p.clusters[obj.GetName()]; !found {
+		return false
+	}
+	delete(p.clusters, obj.GetName())
+	return p.refresh()
+}
+
+func (p *infoManagedClusterHandler) refresh() bool {
+	counts := &cluster.ManagedClusterCounts{Total: len(p.clusters)}
+	for _, status := range p.clusters {
+		switch status {
+		case metav1.ConditionTrue:
+			counts.Available++
+		case metav1.ConditionFalse:
+			counts.Unavailable++
+		default:
+			counts.Unknown++
+		}
+	}
+	changed := p.evtData.ManagedClusters == nil || *p.evtData.ManagedClusters != *counts
+	p.evtData.ManagedClusters = counts
+	return changedWithClusterId(p.evtData, changed)
+}
+
+// 6. Use ManagedClusterAddOn to update the addon health of the HubClusterInfo
+type addonHealth string
+
+const (
+	addonAvailable   addonHealth = "available"
+	addonDegraded    addonHealth = "degraded"
+	addonUnavailable addonHealth = "unavailable"
+)
+
+type addonState struct {
+	name   string
+	health addonHealth
+}
+
+type infoAddonHandler struct {
+	evtData cluster.HubClusterInfoBundle
+	// the key is the namespace(the managed cluster name)/name of the addon
+	addons map[string]addonState
+}
+
+// addonPredicate skips the updates of the addons which don't change the health
+var addonPredicate = predicate.Funcs{
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		oldAddon, oldOK := e.ObjectOld.(*addonv1alpha1.ManagedClusterAddOn)
+		newAddon, newOK := e.ObjectNew.(*addonv1alpha1.ManagedClusterAddOn)
+		if !oldOK || !newOK {
+			return true
+		}
+		return getAddonHealth(oldAddon) != getAddonHealth(newAddon)
+	},
+}
+
+// getAddonHealth returns degraded if the addon is degraded, otherwise available if the addon is available
+func getAddonHealth(addon *addonv1alpha1.ManagedClusterAddOn) addonHealth {
+	if meta.IsStatusConditionTrue(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionDegraded) {
+		return addonDegraded
+	}
+	if meta.IsStatusConditionTrue(addon.Status.Conditions, addonv1alpha1.ManagedClusterAddOnConditionAvailable) {
+		return addonAvailable
+	}
+	return addonUnavailable
+}
+
+func (p *infoAddonHandler) Get() interface{} {
+	return p.evtData
+}
+
+func (p *infoAddonHandler) Update(obj client.Object) bool {
+	addon, ok := obj.(*addonv1alpha1.ManagedClusterAddOn)
+	if !ok {
+		return false
+	}
+	p.addons[client.ObjectKeyFromObject(addon).String()] = addonState{name: addon.Name, health: getAddonHealth(addon)}
+	return p.refresh()
+}
+
+func (p *infoAddonHandler) Delete(obj client.Object) bool {
+	key := client.ObjectKeyFromObject(obj).String()
+	if _, found := p.addons[key]; !found {
+		return false
+	}
+	delete(p.addons, key)
+	return p.refresh()
+}
+
+func (p *infoAddonHandler) refresh() bool {
+	addons := map[string]cluster.AddonHealth{}
+	for _, state := range p.addons {
+		health := addons[state.name]
+		health.Total++
+		switch state.health {
+		case addonAvailable:
+			health.Available++
+		case addonDegraded:
+			health.Degraded++
+		default:
+			health.Unavailable++
+		}
+		addons[state.name] = health
+	}
+	changed := !reflect.DeepEqual(p.evtData.Addons, addons)
+	p.evtData.Addons = addons
+	return changedWithClusterId(p.evtData, changed)
+}
@@ -0,0 +1,146 @@
+package managedhub
+
+import (
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func TestInfoNodeHandler(t *testing.T) {
+	evtData := &cluster.HubClusterInfo{}
+	handler := &infoNodeHandler{evtData: evtData, nodes: map[string]nodeAllocatable{}}
+
+	newNode := func(name, cpu, memory string) *corev1.Node {
+		return &corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.NodeStatus{Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			}},
+		}
+	}
+
+	// the capacity is updated, but not sent without the cluster id
+	require.False(t, handler.Update(newNode("node1", "4", "16Gi")))
+	require.Equal(t, &cluster.HubCapacity{
+		NodeCount: 1, AllocatableCPUMillicores: 4000, AllocatableMemoryBytes: 16 << 30,
+	}, evtData.Capacity)
+
+	evtData.ClusterId = "00000000-0000-0000-0000-000000000001"
+	require.True(t, handler.Update(newNode("node2", "3500m", "8Gi")))
+	require.Equal(t, &cluster.HubCapacity{
+		NodeCount: 2, AllocatableCPUMillicores: 7500, AllocatableMemoryBytes: 24 << 30,
+	}, evtData.Capacity)
+	require.False(t, handler.Update(newNode("node2", "3500m", "8Gi")))
+
+	require.True(t, handler.Delete(newNode("node1", "4", "16Gi")))
+	require.False(t, handler.Delete(newNode("node1", "4", "16Gi")))
+	require.Equal(t, &cluster.HubCapacity{
+		NodeCount: 1, AllocatableCPUMillicores: 3500, AllocatableMemoryBytes: 8 << 30,
+	}, evtData.Capacity)
+}
+
+func TestInfoManagedClusterHandler(t *testing.T) {
+	evtData := &cluster.HubClusterInfo{ClusterId: "00000000-0000-0000-0000-000000000001"}
+	handler := &infoManagedClusterHandler{evtData: evtData, clusters: map[string]metav1.ConditionStatus{}}
+
+	newCluster := func(name string, status metav1.ConditionStatus) *clusterv1.ManagedCluster {
+		managedCluster := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if status != "" {
+			managedCluster.Status.Conditions = []metav1.Condition{
+				{Type: clusterv1.ManagedClusterConditionAvailable, Status: status},
+			}
+		}
+		return managedCluster
+	}
+
+	require.True(t, handler.Update(newCluster("cluster1", metav1.ConditionTrue)))
+	require.True(t, handler.Update(newCluster("cluster2", metav1.ConditionFalse)))
+	require.True(t, handler.Update(newCluster("cluster3", "")))
+	require.Equal(t, &cluster.ManagedClusterCounts{Total: 3, Available: 1, Unavailable: 1, Unknown: 1},
+		evtData.ManagedClusters)
+
+	require.True(t, handler.Update(newCluster("cluster2", metav1.ConditionTrue)))
+	require.True(t, handler.Delete(newCluster("cluster3", "")))
+	require.Equal(t, &cluster.ManagedClusterCounts{Total: 2, Available: 2}, evtData.ManagedClusters)
+}
+
+func TestInfoAddonHandler(t *testing.T) {
+	evtData := &cluster.HubClusterInfo{ClusterId: "00000000-0000-0000-0000-000000000001"}
+	handler := &infoAddonHandler{evtData: evtData, addons: map[string]addonState{}}
+
+	newAddon := func(clusterName, name string, conditions ...metav1.Condition) *addonv1alpha1.ManagedClusterAddOn {
+		return &addonv1alpha1.ManagedClusterAddOn{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: clusterName},
+			Status:     addonv1alpha1.ManagedClusterAddOnStatus{Conditions: conditions},
+		}
+	}
+	available := metav1.Condition{
+		Type: addonv1alpha1.ManagedClusterAddOnConditionAvailable, Status: metav1.ConditionTrue,
+	}
+	degraded := metav1.Condition{
+		Type: addonv1alpha1.ManagedClusterAddOnConditionDegraded, Status: metav1.ConditionTrue,
+	}
+
+	require.True(t, handler.Update(newAddon("cluster1", "work-manager", available)))
+	require.True(t, handler.Update(newAddon("cluster2", "work-manager", available, degraded)))
+	require.True(t, handler.Update(newAddon("cluster1", "governance-policy-framework")))
+	require.False(t, handler.Update(newAddon("cluster1", "governance-policy-framework")))
+	require.Equal(t, map[string]cluster.AddonHealth{
+		"work-manager":                {Total: 2, Available: 1, Degraded: 1},
+		"governance-policy-framework": {Total: 1, Unavailable: 1},
+	}, evtData.Addons)
+
+	require.True(t, handler.Delete(newAddon("cluster2", "work-manager")))
+	require.Equal(t, cluster.AddonHealth{Total: 1, Available: 1}, evtData.Addons["work-manager"])
+}
+
+func TestOpenShiftVersion(t *testing.T) {
+	clusterVersion := &configv1.ClusterVersion{
+		Status: configv1.ClusterVersionStatus{
+			Desired: configv1.Release{Version: "4.16.2"},
+			History: []configv1.UpdateHistory{
+				{State: configv1.PartialUpdate, Version: "4.16.2"},
+				{State: configv1.CompletedUpdate, Version: "4.15.10"},
+			},
+		},
+	}
+	// the upgrade is in progress, the current version is the completed one
+	require.Equal(t, "4.15.10", openShiftVersion(clusterVersion))
+
+	clusterVersion.Status.History = nil
+	require.Equal(t, "4.16.2", openShiftVersion(clusterVersion))
+}
+
+func TestInfoTransportSecretHandler(t *testing.T) {
+	evtData := &cluster.HubClusterInfo{}
+	handler := &infoTransportSecretHandler{evtData: evtData}
+
+	newSecret := func(issuer string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{
+			Name:        "transport-config",
+			Annotations: map[string]string{constants.ClientCertIssuerAnnotation: issuer},
+		}}
+	}
+
+	// the issuer is updated, but not sent without the cluster id
+	require.False(t, handler.Update(newSecret("0a1b")))
+	require.Equal(t, "0a1b", evtData.TransportCertIssuer)
+
+	evtData.ClusterId = "00000000-0000-0000-0000-000000000001"
+	require.False(t, handler.Update(newSecret("0a1b")))
+	require.True(t, handler.Update(newSecret("2c3d")))
+	require.Equal(t, "2c3d", evtData.TransportCertIssuer)
+
+	require.False(t, handler.Delete(newSecret("2c3d")))
+	require.Equal(t, "2c3d", evtData.TransportCertIssuer)
+}
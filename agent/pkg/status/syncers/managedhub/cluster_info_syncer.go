@@ -5,6 +5,12 @@ import (
 
 	configv1 "github.com/openshift/api/config/v1"
 	routev1 "github.com/openshift/api/route/v1"
+	mchv1 "github.com/stolostron/multiclusterhub-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
@@ -31,36 +37,71 @@ func LaunchHubClusterInfoSyncer(mgr ctrl.Manager, producer transport.Producer) e
 		configs.SetMCHVersion(mch.Status.CurrentVersion)
 	}
 
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+	if err != nil {
+		return err
+	}
+
 	eventData := &cluster.HubClusterInfo{}
+	controllerHandlers := []generic.ControllerHandler{
+		{
+			Controller: generic.NewGenericController(
+				func() client.Object { return &configv1.ClusterVersion{} },
+				predicate.NewPredicateFuncs(func(object client.Object) bool {
+					return object.GetName() == "version"
+				})),
+			Handler: &infoClusterVersionHandler{evtData: eventData, serverVersion: discoveryClient},
+		},
+		{
+			Controller: generic.NewGenericController(
+				func() client.Object { return &routev1.Route{} },
+				predicate.NewPredicateFuncs(func(object client.Object) bool {
+					if object.GetNamespace() == constants.OpenShiftConsoleNamespace &&
+						object.GetName() == constants.OpenShiftConsoleRouteName {
+						return true
+					}
+					if object.GetNamespace() == constants.ObservabilityNamespace &&
+						object.GetName() == constants.ObservabilityGrafanaRouteName {
+						return true
+					}
+					return false
+				})),
+			Handler: &infoRouteHandler{eventData},
+		},
+		{
+			Controller: generic.NewGenericController(
+				func() client.Object { return &corev1.Node{} }, nodePredicate),
+			Handler: &infoNodeHandler{evtData: eventData, nodes: map[string]nodeAllocatable{}},
+		},
+		{
+			Controller: generic.NewGenericController(
+				func() client.Object { return &clusterv1.ManagedCluster{} }, managedClusterPredicate),
+			Handler: &infoManagedClusterHandler{evtData: eventData, clusters: map[string]metav1.ConditionStatus{}},
+		},
+		{
+			Controller: generic.NewGenericController(
+				func() client.Object { return &addonv1alpha1.ManagedClusterAddOn{} }, addonPredicate),
+			Handler: &infoAddonHandler{evtData: eventData, addons: map[string]addonState{}},
+		},
+		{
+			Controller: generic.NewGenericController(
+				func() client.Object { return &corev1.Secret{} }, transportSecretPredicate),
+			Handler: &infoTransportSecretHandler{evtData: eventData},
+		},
+	}
+	// the components and the MCE version are collected only if the MCH is installed
+	if mch != nil {
+		controllerHandlers = append(controllerHandlers, generic.ControllerHandler{
+			Controller: generic.NewGenericController(
+				func() client.Object { return &mchv1.MultiClusterHub{} }, nil),
+			Handler: &infoMCHHandler{evtData: eventData, client: mgr.GetClient()},
+		})
+	}
+
 	return generic.LaunchMultiObjectSyncer(
 		"status.hub_cluster_info",
 		mgr,
-		[]generic.ControllerHandler{
-			{
-				Controller: generic.NewGenericController(
-					func() client.Object { return &configv1.ClusterVersion{} },
-					predicate.NewPredicateFuncs(func(object client.Object) bool {
-						return object.GetName() == "version"
-					})),
-				Handler: &infoClusterVersionHandler{eventData},
-			},
-			{
-				Controller: generic.NewGenericController(
-					func() client.Object { return &routev1.Route{} },
-					predicate.NewPredicateFuncs(func(object client.Object) bool {
-						if object.GetNamespace() == constants.OpenShiftConsoleNamespace &&
-							object.GetName() == constants.OpenShiftConsoleRouteName {
-							return true
-						}
-						if object.GetNamespace() == constants.ObservabilityNamespace &&
-							object.GetName() == constants.ObservabilityGrafanaRouteName {
-							return true
-						}
-						return false
-					})),
-				Handler: &infoRouteHandler{eventData},
-			},
-		},
+		controllerHandlers,
 		producer,
 		configmap.GetHubClusterInfoDuration,
 		generic.NewGenericEmitter(enum.HubClusterInfoType),
@@ -69,7 +110,8 @@ func LaunchHubClusterInfoSyncer(mgr ctrl.Manager, producer transport.Producer) e
 
 // 1. Use ClusterVersion to update the HubClusterInfo
 type infoClusterVersionHandler struct {
-	evtData cluster.HubClusterInfoBundle
+	evtData       cluster.HubClusterInfoBundle
+	serverVersion discovery.ServerVersionInterface
 }
 
 func (p *infoClusterVersionHandler) Get() interface{} {
@@ -83,16 +125,37 @@ func (p *infoClusterVersionHandler) Update(obj client.Object) bool {
 	}
 
 	oldClusterID := p.evtData.ClusterId
+	oldOpenShiftVersion := p.evtData.OpenShiftVersion
+	oldKubernetesVersion := p.evtData.KubernetesVersion
 
 	if clusterVersion.Name == "version" {
 		p.evtData.ClusterId = string(clusterVersion.Spec.ClusterID)
+		p.evtData.OpenShiftVersion = openShiftVersion(clusterVersion)
+		// the kubernetes version is changed along with the openshift version
+		if info, err := p.serverVersion.ServerVersion(); err != nil {
+			log.Warnw("failed to get the kubernetes version", "error", err)
+		} else {
+			p.evtData.KubernetesVersion = info.GitVersion
+		}
 	}
 	// If no ClusterId, do not send the bundle
 	if p.evtData.ClusterId == "" {
 		return false
 	}
 
-	return oldClusterID != p.evtData.ClusterId
+	return oldClusterID != p.evtData.ClusterId || oldOpenShiftVersion != p.evtData.OpenShiftVersion ||
+		oldKubernetesVersion != p.evtData.KubernetesVersion
+}
+
+// openShiftVersion returns the latest completed version in the update history, or the desired version if no update is
+// completed yet
+func openShiftVersion(clusterVersion *configv1.ClusterVersion) string {
+	for _, history := range clusterVersion.Status.History {
+		if history.State == configv1.CompletedUpdate {
+			return history.Version
+		}
+	}
+	return clusterVersion.Status.Desired.Version
 }
 
 func (p *infoClusterVersionHandler) Delete(obj client.Object) bool {
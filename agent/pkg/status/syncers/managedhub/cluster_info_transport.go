@@ -0,0 +1,60 @@
+package managedhub
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/agent/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+// 7. Use the transport config secret to update the issuer of the transport client certificate of the HubClusterInfo,
+// the issuer is annotated by the transport controller once the agent is reconnected with the certificate
+type infoTransportSecretHandler struct {
+	evtData cluster.HubClusterInfoBundle
+}
+
+func transportConfigSecretName() string {
+	if name := configs.GetAgentConfig().TransportConfigSecretName; name != "" {
+		return name
+	}
+	return constants.GHTransportConfigSecret
+}
+
+// transportSecretPredicate only watches the transport config secret of the agent, and skips the updates which don't
+// change the client certificate issuer
+var transportSecretPredicate = predicate.And(
+	predicate.NewPredicateFuncs(func(object client.Object) bool {
+		return object.GetNamespace() == configs.GetAgentConfig().PodNamespace &&
+			object.GetName() == transportConfigSecretName()
+	}),
+	predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return e.ObjectOld.GetAnnotations()[constants.ClientCertIssuerAnnotation] !=
+				e.ObjectNew.GetAnnotations()[constants.ClientCertIssuerAnnotation]
+		},
+	},
+)
+
+func (p *infoTransportSecretHandler) Get() interface{} {
+	return p.evtData
+}
+
+func (p *infoTransportSecretHandler) Update(obj client.Object) bool {
+	secret, ok := obj.(*corev1.Secret)
+	if !ok {
+		return false
+	}
+	issuer := secret.Annotations[constants.ClientCertIssuerAnnotation]
+	changed := p.evtData.TransportCertIssuer != issuer
+	p.evtData.TransportCertIssuer = issuer
+	return changedWithClusterId(p.evtData, changed)
+}
+
+func (p *infoTransportSecretHandler) Delete(obj client.Object) bool {
+	// keep the issuer, the agent is still connected with the certificate until the secret is recreated
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strconv"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
 )
 
@@ -35,6 +36,7 @@ var AlertsSummeryCountsRequest = stackRoxRequest{
 	Path:        stackRoxAlertsSummaryCountsPath,
 	Body:        "",
 	CacheStruct: &AlertsSummeryCountsResponse{},
+	EventType:   enum.SecurityAlertCountsType,
 	GenerateFromCache: func(values ...any) (any, error) {
 		if len(values) != 4 {
 			return nil, fmt.Errorf("alert summery count cache struct or ACS base URL were not provided")
@@ -1,13 +1,29 @@
 package security
 
+import "github.com/stolostron/multicluster-global-hub/pkg/enum"
+
 type stackRoxRequest struct {
 	Method            string
 	Path              string
 	Body              string
 	CacheStruct       any
 	GenerateFromCache func(...any) (any, error)
+	// EventType is the type of the events produced from the generated structs.
+	EventType enum.EventType
+	// PageSize enables the pagination of the request when it is greater than zero, the pages are requested until a
+	// page with less than PageSize items is returned, and the CacheStruct must implement the stackRoxPagedCache.
+	PageSize int
+}
+
+// stackRoxPagedCache is implemented by the cache structs of the paginated requests, the pages are accumulated into it.
+type stackRoxPagedCache interface {
+	// Reset clears the items accumulated from the previous pages.
+	Reset()
+	// AppendPage unmarshals the response of a page and appends its items, it returns the number of items of the page.
+	AppendPage(response []byte) (int, error)
 }
 
 var stackRoxRequests = []stackRoxRequest{
 	AlertsSummeryCountsRequest,
+	ViolationsRequest,
 }
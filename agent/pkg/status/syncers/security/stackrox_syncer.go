@@ -431,9 +431,16 @@ func (s *StackRoxSyncer) sync(ctx context.Context, data *stackRoxData) error {
 		// 	return nil
 		// }
 
-		s.currentVersion.Incr()
-		if err := s.produce(ctx, messageStruct); err != nil {
-			return fmt.Errorf("failed to produce a message to kafka: %v", err)
+		// The request might generate multiple messages, for example if the payload doesn't fit into one message
+		messages, ok := messageStruct.([]any)
+		if !ok {
+			messages = []any{messageStruct}
+		}
+		for _, message := range messages {
+			s.currentVersion.Incr()
+			if err := s.produce(ctx, request.EventType, message); err != nil {
+				return fmt.Errorf("failed to produce a message to kafka: %v", err)
+			}
 		}
 		s.lastSentData = messageStruct
 	}
@@ -441,11 +448,11 @@ func (s *StackRoxSyncer) sync(ctx context.Context, data *stackRoxData) error {
 	return nil
 }
 
-func (s *StackRoxSyncer) produce(ctx context.Context, messageStruct any) error {
+func (s *StackRoxSyncer) produce(ctx context.Context, eventType enum.EventType, messageStruct any) error {
 	s.dataLock.Lock()
 	defer s.dataLock.Unlock()
 
-	evt := ToEvent(configs.GetLeafHubName(), string(enum.SecurityAlertCountsType), s.currentVersion.String())
+	evt := ToEvent(configs.GetLeafHubName(), string(eventType), s.currentVersion.String())
 	err := evt.SetData(cloudevents.ApplicationJSON, messageStruct)
 	if err != nil {
 		return fmt.Errorf("failed to get CloudEvent instance from event %s: %v", *evt, err)
@@ -464,36 +471,83 @@ func (s *StackRoxSyncer) produce(ctx context.Context, messageStruct any) error {
 }
 
 func (s *StackRoxSyncer) poll(ctx context.Context, data *stackRoxData, request stackRoxRequest) error {
-	response, status, err := data.apiClient.DoRequest(request.Method, request.Path, request.Body)
+	if request.PageSize > 0 {
+		return s.pollPages(ctx, data, request)
+	}
+
+	response, err := s.doRequest(ctx, data, request.Method, request.Path, request.Body)
 	if err != nil {
 		return err
 	}
 
+	err = json.Unmarshal(response, request.CacheStruct)
+	if err != nil {
+		return fmt.Errorf(
+			"failed to unmarshal response (method: %s, path: %s, body: %s): %v",
+			request.Method, request.Path, request.Body, err,
+		)
+	}
+
+	return nil
+}
+
+// pollPages requests the pages of a paginated request until a page isn't full, and accumulates them into the cache.
+func (s *StackRoxSyncer) pollPages(ctx context.Context, data *stackRoxData, request stackRoxRequest) error {
+	cache, ok := request.CacheStruct.(stackRoxPagedCache)
+	if !ok {
+		return fmt.Errorf("cache struct of the paginated request (path: %s) isn't a paged cache", request.Path)
+	}
+	cache.Reset()
+
+	separator := "?"
+	if strings.Contains(request.Path, "?") {
+		separator = "&"
+	}
+	for offset := 0; ; offset += request.PageSize {
+		path := fmt.Sprintf("%s%spagination.limit=%d&pagination.offset=%d", request.Path, separator,
+			request.PageSize, offset)
+		response, err := s.doRequest(ctx, data, request.Method, path, request.Body)
+		if err != nil {
+			return err
+		}
+		count, err := cache.AppendPage(response)
+		if err != nil {
+			return fmt.Errorf(
+				"failed to unmarshal response (method: %s, path: %s, body: %s): %v",
+				request.Method, path, request.Body, err,
+			)
+		}
+		if count < request.PageSize {
+			return nil
+		}
+	}
+}
+
+// doRequest sends the request to the central, and returns the response body.
+func (s *StackRoxSyncer) doRequest(ctx context.Context, data *stackRoxData, method, path, body string,
+) ([]byte, error) {
+	response, status, err := data.apiClient.DoRequest(method, path, body)
+	if err != nil {
+		return nil, err
+	}
+
 	// If the request fails with an error related to authentication, then we refresh the data and try again, only
 	// once:
 	if status != nil && *status == http.StatusUnauthorized || *status == http.StatusForbidden {
 		err = s.refresh(ctx, data)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		response, status, err = data.apiClient.DoRequest(request.Method, request.Path, request.Body)
+		response, status, err = data.apiClient.DoRequest(method, path, body)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		if status != nil && *status != http.StatusOK {
-			return fmt.Errorf("request failed with status code %d", *status)
+			return nil, fmt.Errorf("request failed with status code %d", *status)
 		}
 	}
 
-	err = json.Unmarshal(response, request.CacheStruct)
-	if err != nil {
-		return fmt.Errorf(
-			"failed to unmarshal response (method: %s, path: %s, body: %s): %v",
-			request.Method, request.Path, request.Body, err,
-		)
-	}
-
-	return nil
+	return response, nil
 }
 
 func (s *StackRoxSyncer) Start(ctx context.Context) error {
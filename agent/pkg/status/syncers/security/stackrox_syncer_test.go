@@ -22,6 +22,7 @@ import (
 	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 	crfakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
@@ -214,6 +215,42 @@ var _ = Describe("StackRox syncer", func() {
 				},
 			)
 
+			// RespondViolations sends a valid response with one active violation.
+			RespondViolations := RespondWith(
+				http.StatusOK,
+				`{
+					"alerts": [{
+						"id": "my-alert",
+						"lifecycleStage": "DEPLOY",
+						"time": "2025-01-02T03:04:05Z",
+						"policy": {
+							"id": "my-policy",
+							"name": "Latest tag",
+							"severity": "LOW_SEVERITY",
+							"categories": ["DevOps Best Practices"]
+						},
+						"state": "ACTIVE",
+						"enforcementCount": 0,
+						"enforcementAction": "UNSET_ENFORCEMENT",
+						"commonEntityInfo": {
+							"clusterName": "my-cluster",
+							"clusterId": "my-cluster-id",
+							"namespace": "my-namespace",
+							"resourceType": "DEPLOYMENT"
+						},
+						"deployment": {
+							"id": "my-deployment-id",
+							"name": "my-deployment",
+							"clusterName": "my-cluster",
+							"namespace": "my-namespace"
+						}
+					}]
+				}`,
+				http.Header{
+					"Content-Type": []string{"application/json"},
+				},
+			)
+
 			// RespondUnathorized responds with an authorization error.
 			RespondUnathorized := RespondWith(http.StatusUnauthorized, nil)
 
@@ -290,23 +327,131 @@ var _ = Describe("StackRox syncer", func() {
 						VerifyRequest(http.MethodGet, "/v1/alerts/summary/counts"),
 						RespondOK,
 					),
+					CombineHandlers(
+						VerifyHeaderKV("Authorization", "Bearer my-token"),
+						VerifyRequest(
+							http.MethodGet,
+							"/v1/alerts",
+							"query=Violation+State%3AACTIVE&pagination.limit=500&pagination.offset=0",
+						),
+						RespondViolations,
+					),
 				)
 
 				// Create the producer:
+				var events []cloudevents.Event
 				producer := &transport.ProducerMock{
 					SendEventFunc: func(ctx context.Context, evt cloudevents.Event) error {
-						defer GinkgoRecover()
+						events = append(events, evt)
+						return nil
+					},
+					ReconnectFunc: func(config *transport.TransportInternalConfig) error {
+						return nil
+					},
+				}
 
-						// Verify the message:
-						Expect(evt.Data()).To(MatchJSON(`{
-							"low": 1,
-							"medium": 2,
-							"high": 3,
-							"critical": 4,
-							"detail_url": "https://my-console.com/main/violations",
-							"source": "rhacs-operator/stackrox-central-services"
-						}`))
+				// Create the syncer:
+				syncer, err := NewStackRoxSyncer().
+					SetLogger(logger).
+					SetTopic("my-topic").
+					SetProducer(producer).
+					SetKubernetesClient(client).
+					Build()
+				Expect(err).ToNot(HaveOccurred())
 
+				// Try to synchronize:
+				centralKey := types.NamespacedName{
+					Namespace: "rhacs-operator",
+					Name:      "stackrox-central-services",
+				}
+				err = syncer.Register(ctx, centralKey)
+				Expect(err).ToNot(HaveOccurred())
+				err = syncer.Sync(ctx, centralKey)
+				Expect(err).ToNot(HaveOccurred())
+
+				// Verify the messages:
+				Expect(events).To(HaveLen(3))
+				Expect(events[0].Type()).To(Equal(string(enum.SecurityAlertCountsType)))
+				Expect(events[0].Data()).To(MatchJSON(`{
+					"low": 1,
+					"medium": 2,
+					"high": 3,
+					"critical": 4,
+					"detail_url": "https://my-console.com/main/violations",
+					"source": "rhacs-operator/stackrox-central-services"
+				}`))
+				Expect(events[1].Type()).To(Equal(string(enum.SecurityViolationsType)))
+				Expect(events[1].Data()).To(MatchJSON(`{
+					"resync": [{
+						"id": "my-alert",
+						"policy_id": "my-policy",
+						"policy_name": "Latest tag",
+						"severity": "LOW_SEVERITY",
+						"categories": ["DevOps Best Practices"],
+						"lifecycle_stage": "DEPLOY",
+						"state": "ACTIVE",
+						"cluster_name": "my-cluster",
+						"cluster_id": "my-cluster-id",
+						"namespace": "my-namespace",
+						"deployment_name": "my-deployment",
+						"deployment_id": "my-deployment-id",
+						"resource_type": "DEPLOYMENT",
+						"enforcement_action": "UNSET_ENFORCEMENT",
+						"time": "2025-01-02T03:04:05Z",
+						"detail_url": "https://my-console.com/main/violations/my-alert",
+						"source": "rhacs-operator/stackrox-central-services"
+					}]
+				}`))
+				Expect(events[2].Type()).To(Equal(string(enum.SecurityViolationsType)))
+				Expect(events[2].Data()).To(MatchJSON(`{
+					"resync_metadata": [
+						{
+							"ns": "rhacs-operator/stackrox-central-services"
+						},
+						{
+							"id": "my-alert",
+							"ns": "rhacs-operator/stackrox-central-services"
+						}
+					]
+				}`))
+			})
+
+			It("Requests the violations in pages", func() {
+				var err error
+
+				// Prepare the server so that it returns two full pages and then an empty one:
+				server.AppendHandlers(
+					CombineHandlers(
+						VerifyRequest(
+							http.MethodGet,
+							"/v1/alerts",
+							"query=Violation+State%3AACTIVE&pagination.limit=1&pagination.offset=0",
+						),
+						RespondViolations,
+					),
+					CombineHandlers(
+						VerifyRequest(
+							http.MethodGet,
+							"/v1/alerts",
+							"query=Violation+State%3AACTIVE&pagination.limit=1&pagination.offset=1",
+						),
+						RespondViolations,
+					),
+					CombineHandlers(
+						VerifyRequest(
+							http.MethodGet,
+							"/v1/alerts",
+							"query=Violation+State%3AACTIVE&pagination.limit=1&pagination.offset=2",
+						),
+						RespondWith(http.StatusOK, `{}`),
+					),
+				)
+
+				// Create the producer:
+				var events []cloudevents.Event
+				producer := &transport.ProducerMock{
+					SendEventFunc: func(ctx context.Context, evt cloudevents.Event) error {
+						events = append(events, evt)
 						return nil
 					},
 					ReconnectFunc: func(config *transport.TransportInternalConfig) error {
@@ -314,7 +459,7 @@ var _ = Describe("StackRox syncer", func() {
 					},
 				}
 
-				// Create the syncer:
+				// Create the syncer with only the violations request:
 				syncer, err := NewStackRoxSyncer().
 					SetLogger(logger).
 					SetTopic("my-topic").
@@ -322,6 +467,10 @@ var _ = Describe("StackRox syncer", func() {
 					SetKubernetesClient(client).
 					Build()
 				Expect(err).ToNot(HaveOccurred())
+				request := ViolationsRequest
+				request.CacheStruct = &ListAlertsResponse{}
+				request.PageSize = 1
+				syncer.requests = []stackRoxRequest{request}
 
 				// Try to synchronize:
 				centralKey := types.NamespacedName{
@@ -332,6 +481,52 @@ var _ = Describe("StackRox syncer", func() {
 				Expect(err).ToNot(HaveOccurred())
 				err = syncer.Sync(ctx, centralKey)
 				Expect(err).ToNot(HaveOccurred())
+
+				// Verify that the violations of all the pages are sent:
+				Expect(server.ReceivedRequests()).To(HaveLen(3))
+				Expect(events).To(HaveLen(2))
+				Expect(events[0].Data()).To(MatchJSON(`{
+					"resync": [
+						{
+							"id": "my-alert",
+							"policy_id": "my-policy",
+							"policy_name": "Latest tag",
+							"severity": "LOW_SEVERITY",
+							"categories": ["DevOps Best Practices"],
+							"lifecycle_stage": "DEPLOY",
+							"state": "ACTIVE",
+							"cluster_name": "my-cluster",
+							"cluster_id": "my-cluster-id",
+							"namespace": "my-namespace",
+							"deployment_name": "my-deployment",
+							"deployment_id": "my-deployment-id",
+							"resource_type": "DEPLOYMENT",
+							"enforcement_action": "UNSET_ENFORCEMENT",
+							"time": "2025-01-02T03:04:05Z",
+							"detail_url": "https://my-console.com/main/violations/my-alert",
+							"source": "rhacs-operator/stackrox-central-services"
+						},
+						{
+							"id": "my-alert",
+							"policy_id": "my-policy",
+							"policy_name": "Latest tag",
+							"severity": "LOW_SEVERITY",
+							"categories": ["DevOps Best Practices"],
+							"lifecycle_stage": "DEPLOY",
+							"state": "ACTIVE",
+							"cluster_name": "my-cluster",
+							"cluster_id": "my-cluster-id",
+							"namespace": "my-namespace",
+							"deployment_name": "my-deployment",
+							"deployment_id": "my-deployment-id",
+							"resource_type": "DEPLOYMENT",
+							"enforcement_action": "UNSET_ENFORCEMENT",
+							"time": "2025-01-02T03:04:05Z",
+							"detail_url": "https://my-console.com/main/violations/my-alert",
+							"source": "rhacs-operator/stackrox-central-services"
+						}
+					]
+				}`))
 			})
 
 			It("Polls in a loop", func() {
@@ -349,6 +544,7 @@ var _ = Describe("StackRox syncer", func() {
 						RespondOK,
 					),
 				)
+				server.RouteToHandler(http.MethodGet, "/v1/alerts", RespondViolations)
 
 				// Create the producer that counts the messages sent:
 				messages := &atomic.Int32{}
@@ -414,6 +610,7 @@ var _ = Describe("StackRox syncer", func() {
 						count.Add(1)
 					},
 				)
+				server.RouteToHandler(http.MethodGet, "/v1/alerts", RespondViolations)
 
 				// Create a producer that counts the messages:
 				messages := &atomic.Int32{}
@@ -610,12 +807,19 @@ var _ = Describe("StackRox syncer", func() {
 						VerifyHeaderKV("Authorization", "Bearer new-token"),
 						RespondOK,
 					),
+					CombineHandlers(
+						VerifyHeaderKV("Authorization", "Bearer new-token"),
+						RespondViolations,
+					),
 				)
 
 				// Create the producer:
 				producer := &transport.ProducerMock{
 					SendEventFunc: func(ctx context.Context, evt cloudevents.Event) error {
 						defer GinkgoRecover()
+						if evt.Type() != string(enum.SecurityAlertCountsType) {
+							return nil
+						}
 
 						// Verify the message:
 						Expect(evt.Data()).To(MatchJSON(`{
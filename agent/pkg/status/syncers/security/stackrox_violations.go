@@ -0,0 +1,169 @@
+package security
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+const (
+	stackRoxViolationsPageSize = 500
+	stackRoxAlertsPath         = "/v1/alerts"
+)
+
+// stackRoxViolationsPath lists the active violations, the resolved ones are removed from the list by Central.
+var stackRoxViolationsPath = fmt.Sprintf("%s?query=%s", stackRoxAlertsPath,
+	url.QueryEscape("Violation State:ACTIVE"))
+
+type ListAlertPolicy struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Severity    string   `json:"severity"`
+	Description string   `json:"description"`
+	Categories  []string `json:"categories"`
+}
+
+type ListAlertEntityInfo struct {
+	ClusterName  string `json:"clusterName"`
+	ClusterID    string `json:"clusterId"`
+	Namespace    string `json:"namespace"`
+	ResourceType string `json:"resourceType"`
+}
+
+type ListAlertDeployment struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	ClusterName string `json:"clusterName"`
+	Namespace   string `json:"namespace"`
+}
+
+type ListAlert struct {
+	ID                string               `json:"id"`
+	LifecycleStage    string               `json:"lifecycleStage"`
+	Time              time.Time            `json:"time"`
+	Policy            ListAlertPolicy      `json:"policy"`
+	State             string               `json:"state"`
+	EnforcementCount  int                  `json:"enforcementCount"`
+	EnforcementAction string               `json:"enforcementAction"`
+	CommonEntityInfo  ListAlertEntityInfo  `json:"commonEntityInfo"`
+	Deployment        *ListAlertDeployment `json:"deployment,omitempty"`
+}
+
+type ListAlertsResponse struct {
+	Alerts []ListAlert `json:"alerts"`
+}
+
+// Reset clears the alerts of the previous polling.
+func (r *ListAlertsResponse) Reset() {
+	r.Alerts = nil
+}
+
+// AppendPage appends the alerts of a page to the response.
+func (r *ListAlertsResponse) AppendPage(response []byte) (int, error) {
+	page := &ListAlertsResponse{}
+	if err := json.Unmarshal(response, page); err != nil {
+		return 0, err
+	}
+	r.Alerts = append(r.Alerts, page.Alerts...)
+	return len(page.Alerts), nil
+}
+
+// ViolationsRequest lists the active violations of the Central instance. The violations are split into bundles that
+// fit into a message, and the last bundle contains the metadata of all the violations, so that the manager can resolve
+// the violations that are no longer active.
+var ViolationsRequest = stackRoxRequest{
+	Method:      "GET",
+	Path:        stackRoxViolationsPath,
+	Body:        "",
+	CacheStruct: &ListAlertsResponse{},
+	EventType:   enum.SecurityViolationsType,
+	PageSize:    stackRoxViolationsPageSize,
+	GenerateFromCache: func(values ...any) (any, error) {
+		if len(values) != 4 {
+			return nil, fmt.Errorf("violations cache struct or ACS base URL were not provided")
+		}
+
+		listAlertsResponse, ok := values[0].(*ListAlertsResponse)
+		if !ok {
+			return nil, fmt.Errorf("violations cache struct is not of the right type")
+		}
+
+		acsCentralExternalHostPort, ok := values[1].(string)
+		if !ok {
+			return nil, fmt.Errorf("ACS external URL is not valid")
+		}
+
+		acsCentralNamespace, ok := values[2].(string)
+		if !ok {
+			return nil, fmt.Errorf("ACS Central namespace was not provided")
+		}
+
+		acsCentralName, ok := values[3].(string)
+		if !ok {
+			return nil, fmt.Errorf("ACS Central name was not provided")
+		}
+		source := fmt.Sprintf("%s/%s", acsCentralNamespace, acsCentralName)
+
+		messages := []any{}
+		bundle := generic.NewGenericBundle[wiremodels.SecurityViolation]()
+		// the first metadata only identifies the central, so that the violations of the central are resolved even if
+		// there isn't any active violation
+		metadata := make([]generic.ObjectMetadata, 0, len(listAlertsResponse.Alerts)+1)
+		metadata = append(metadata, generic.ObjectMetadata{Namespace: source})
+		for _, alert := range listAlertsResponse.Alerts {
+			violation := toSecurityViolation(alert, acsCentralExternalHostPort, source)
+			added, err := bundle.AddResync(violation)
+			if err != nil {
+				return nil, fmt.Errorf("failed to add the violation %s to the bundle: %v", alert.ID, err)
+			}
+			if !added {
+				messages = append(messages, bundle)
+				bundle = generic.NewGenericBundle[wiremodels.SecurityViolation]()
+				if _, err := bundle.AddResync(violation); err != nil {
+					return nil, fmt.Errorf("failed to add the violation %s to the bundle: %v", alert.ID, err)
+				}
+			}
+			metadata = append(metadata, generic.ObjectMetadata{ID: alert.ID, Namespace: source})
+		}
+		if !bundle.IsEmpty() {
+			messages = append(messages, bundle)
+		}
+
+		metadataBundle := generic.NewGenericBundle[wiremodels.SecurityViolation]()
+		if err := metadataBundle.AddResyncMetadata(metadata); err != nil {
+			return nil, fmt.Errorf("failed to add the metadata of the violations: %v", err)
+		}
+		return append(messages, metadataBundle), nil
+	},
+}
+
+func toSecurityViolation(alert ListAlert, consoleURL, source string) wiremodels.SecurityViolation {
+	violation := wiremodels.SecurityViolation{
+		ID:                alert.ID,
+		PolicyID:          alert.Policy.ID,
+		PolicyName:        alert.Policy.Name,
+		Severity:          alert.Policy.Severity,
+		Categories:        alert.Policy.Categories,
+		LifecycleStage:    alert.LifecycleStage,
+		State:             alert.State,
+		ClusterName:       alert.CommonEntityInfo.ClusterName,
+		ClusterID:         alert.CommonEntityInfo.ClusterID,
+		Namespace:         alert.CommonEntityInfo.Namespace,
+		ResourceType:      alert.CommonEntityInfo.ResourceType,
+		EnforcementAction: alert.EnforcementAction,
+		EnforcementCount:  alert.EnforcementCount,
+		Time:              alert.Time,
+		DetailURL:         fmt.Sprintf("%s%s/%s", consoleURL, stackRoxAlertsDetailsPath, alert.ID),
+		Source:            source,
+	}
+	if alert.Deployment != nil {
+		violation.DeploymentID = alert.Deployment.ID
+		violation.DeploymentName = alert.Deployment.Name
+	}
+	return violation
+}
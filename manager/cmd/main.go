@@ -24,14 +24,18 @@ import (
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/backup"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/controllers"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/failover"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/migration"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob/task"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis"
 	specsyncer "github.com/stolostron/multicluster-global-hub/manager/pkg/spec"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
 	mgrwebhook "github.com/stolostron/multicluster-global-hub/manager/pkg/webhook"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
@@ -40,6 +44,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/controller"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/jetstream"
 	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
@@ -64,10 +69,13 @@ func parseFlags() *configs.ManagerConfig {
 	managerConfig := &configs.ManagerConfig{
 		SyncerConfig:   &configs.SyncerConfig{},
 		DatabaseConfig: &configs.DatabaseConfig{},
+		BackupConfig:   &configs.BackupConfig{},
+		FailoverConfig: &configs.FailoverConfig{},
 		TransportConfig: &transport.TransportInternalConfig{
 			EnableDatabaseOffset: true,
 		},
 		StatisticsConfig:    &statistics.StatisticsConfig{},
+		ReadyQueueConfig:    &conflator.ReadyQueueConfig{},
 		RestAPIServerConfig: &restapis.RestApiServerConfig{},
 		ElectionConfig:      &commonobjects.LeaderElectionConfig{},
 		LaunchJobNames:      "",
@@ -80,6 +88,9 @@ func parseFlags() *configs.ManagerConfig {
 	pflag.StringVar(&managerConfig.SchedulerInterval, "scheduler-interval", "day",
 		"The job scheduler interval for moving policy compliance history, "+
 			"can be 'month', 'week', 'day', 'hour', 'minute' or 'second', default value is 'day'.")
+	pflag.DurationVar(&managerConfig.ComplianceSnapshotInterval, "compliance-snapshot-interval",
+		task.DefaultLocalComplianceSnapshotInterval,
+		"The interval of the snapshots of the local compliance, the past compliance is rebuilt from them and the events.")
 	pflag.DurationVar(&managerConfig.SyncerConfig.SpecSyncInterval, "spec-sync-interval", 5*time.Second,
 		"The synchronization interval of resources in spec.")
 	pflag.DurationVar(&managerConfig.SyncerConfig.StatusSyncInterval, "status-sync-interval", 5*time.Second,
@@ -92,6 +103,10 @@ func parseFlags() *configs.ManagerConfig {
 		"The URL of database server for the process user.")
 	pflag.StringVar(&managerConfig.DatabaseConfig.TransportBridgeDatabaseURL,
 		"transport-bridge-database-url", "", "The URL of database server for the transport-bridge user.")
+	pflag.StringSliceVar(&managerConfig.DatabaseConfig.ReplicaDatabaseURLs, "replica-database-urls", nil,
+		"The URLs of the read replicas of the database, the read-only queries are routed to the healthy replicas.")
+	pflag.DurationVar(&managerConfig.DatabaseConfig.MaxReplicaLag, "max-replica-lag", database.DefaultMaxReplicaLag,
+		"The read-only queries fall back to the primary database if the replication lag of the replicas exceeds it.")
 	pflag.DurationVar(&managerConfig.TransportConfig.CommitterInterval, "transport-committer-interval",
 		40*time.Second, "The committer interval for transport layer.")
 	pflag.StringVar(&managerConfig.DatabaseConfig.CACertPath, "postgres-ca-path", "/postgres-ca/ca.crt",
@@ -104,11 +119,37 @@ func parseFlags() *configs.ManagerConfig {
 		"/var/run/secrets/kubernetes.io/serviceaccount/ca.crt", "The CA bundle path for cluster API.")
 	pflag.StringVar(&managerConfig.RestAPIServerConfig.ServerBasePath, "server-base-path",
 		"/global-hub-api/v1", "The base path for nonK8s API server.")
+	pflag.StringVar(&managerConfig.RestAPIServerConfig.AuthorizationConfigPath, "authorization-config-path", "",
+		"The file mapping the user groups to the allowed leaf hubs and managed cluster sets of the rest api.")
 	pflag.IntVar(&managerConfig.ElectionConfig.LeaseDuration, "lease-duration", 137, "controller leader lease duration")
 	pflag.IntVar(&managerConfig.ElectionConfig.RenewDeadline, "renew-deadline", 107, "controller leader renew deadline")
 	pflag.IntVar(&managerConfig.ElectionConfig.RetryPeriod, "retry-period", 26, "controller leader retry period")
 	pflag.IntVar(&managerConfig.DatabaseConfig.DataRetention, "data-retention", 18,
 		"data retention indicates how many months the expired data will kept in the database")
+	pflag.StringVar(&managerConfig.DatabaseConfig.RetentionPolicies, "retention-policies", "",
+		"The policies in json overriding the data retention of the tables, the event types and the hubs.")
+	pflag.StringVar(&managerConfig.DatabaseConfig.RetentionArchiveTarget, "retention-archive-target", "",
+		"The object store to archive the expired data before it's deleted, e.g. file:///var/lib/archive.")
+	pflag.StringVar(&managerConfig.BackupConfig.Target, "backup-target", "",
+		"The directory to back up the database, e.g. /var/lib/backup, the backup is disabled if it's empty.")
+	pflag.DurationVar(&managerConfig.BackupConfig.Interval, "backup-interval", time.Hour,
+		"The interval of backing up the database incrementally.")
+	pflag.DurationVar(&managerConfig.BackupConfig.FullInterval, "backup-full-interval", 7*24*time.Hour,
+		"The interval of the full database backups, the backups in between are incremental.")
+	pflag.StringVar(&managerConfig.BackupConfig.Restore, "restore-backup", "",
+		"Restore the backup of the id, or 'latest', from the backup-target into the database and exit.")
+	pflag.StringVar(&managerConfig.FailoverConfig.Role, "failover-role", "",
+		"The failover role of the global hub, 'active' or 'standby', the failover is disabled if it's empty.")
+	pflag.StringVar(&managerConfig.FailoverConfig.Name, "failover-name", "",
+		"The name of the global hub in the failover heartbeats, it must be unique across the regions.")
+	pflag.StringVar(&managerConfig.FailoverConfig.PrimaryDatabaseURL, "primary-database-url", "",
+		"The URL of the database of the active global hub, which is replicated by the standby global hub.")
+	pflag.DurationVar(&managerConfig.FailoverConfig.HeartbeatInterval, "failover-heartbeat-interval", 10*time.Second,
+		"The interval of the heartbeat of the active global hub, and the check of the standby global hub.")
+	pflag.DurationVar(&managerConfig.FailoverConfig.Timeout, "failover-timeout", 2*time.Minute,
+		"The standby global hub takes over if the heartbeat of the active global hub is expired for the duration.")
+	pflag.StringVar(&managerConfig.FailoverConfig.LeaseKubeconfig, "failover-lease-kubeconfig", "",
+		"The kubeconfig of the witness cluster holding the failover lease, it's required by the failover.")
 	pflag.BoolVar(&managerConfig.EnableGlobalResource, "enable-global-resource", false,
 		"enable the global resource feature")
 	pflag.BoolVar(&managerConfig.WithACM, "with-acm", false,
@@ -118,6 +159,27 @@ func parseFlags() *configs.ManagerConfig {
 	pflag.BoolVar(&managerConfig.EnablePprof, "enable-pprof", false, "enable the pprof tool")
 	pflag.IntVar(&managerConfig.TransportConfig.FailureThreshold, "transport-failure-threshold", 10,
 		"Restart the pod if the transport error count exceeds the transport-failure-threshold within 5 minutes.")
+	pflag.StringVar(&managerConfig.TransportConfig.TransportType, "transport-type", string(transport.Kafka),
+		"The transport type, 'kafka' or 'nats'.")
+	pflag.IntVar(&managerConfig.EmbeddedNatsPort, "embedded-nats-port", 0,
+		"The port of the in-process nats(jetstream) server, the server is disabled if it's 0.")
+	pflag.StringVar(&managerConfig.EmbeddedNatsStoreDir, "embedded-nats-store-dir", "/tmp/nats",
+		"The storage directory of the in-process nats(jetstream) server, it should be a volume shared by the "+
+			"replicas since the server runs on the leader only.")
+	pflag.StringVar(&managerConfig.EmbeddedNatsCertDir, "embedded-nats-cert-dir", "/nats-certs",
+		"The directory of the in-process nats(jetstream) server certificate(tls.crt, tls.key) and the CA(ca.crt) "+
+			"issuing the client certificates, the clients must connect with the mutual TLS.")
+	pflag.StringVar(&managerConfig.DeadLetterTopic, "dead-letter-topic", "",
+		"The topic to publish the status events failed to be persisted, the events are stored in database only if "+
+			"it's empty.")
+	pflag.Float64Var(&managerConfig.ReadyQueueConfig.RateLimit, "hub-rate-limit", 0,
+		"The max number of the status events of each hub persisted per second, 0 means unlimited.")
+	pflag.IntVar(&managerConfig.ReadyQueueConfig.RateBurst, "hub-rate-burst", 10,
+		"The burst of the status events of each hub persisted if the hub-rate-limit is set.")
+	pflag.IntVar(&managerConfig.ReadyQueueConfig.MaxInFlight, "hub-max-inflight", 0,
+		"The max number of the status events of each hub persisted at the same time, 0 means unlimited.")
+	pflag.StringToIntVar(&managerConfig.ReadyQueueConfig.Weights, "hub-weights", map[string]int{},
+		"The weights of the hubs for dispatching the status events, e.g. hub1=2,hub2=1. The default weight is 1.")
 	pflag.Parse()
 
 	pflag.Visit(func(f *pflag.Flag) {
@@ -134,6 +196,22 @@ func completeConfig(managerConfig *configs.ManagerConfig) error {
 	if managerConfig.DatabaseConfig.ProcessDatabaseURL == "" {
 		return fmt.Errorf("database url for process user: %w", errFlagParameterEmpty)
 	}
+	failoverConfig := managerConfig.FailoverConfig
+	switch failoverConfig.Role {
+	case "":
+	case constants.FailoverRoleActive, constants.FailoverRoleStandby:
+		if failoverConfig.Name == "" {
+			return fmt.Errorf("failover name: %w", errFlagParameterEmpty)
+		}
+		if failoverConfig.LeaseKubeconfig == "" {
+			return fmt.Errorf("failover lease kubeconfig: %w", errFlagParameterEmpty)
+		}
+		if failoverConfig.Role == constants.FailoverRoleStandby && failoverConfig.PrimaryDatabaseURL == "" {
+			return fmt.Errorf("primary database url for the standby global hub: %w", errFlagParameterEmpty)
+		}
+	default:
+		return fmt.Errorf("invalid failover role: %s", failoverConfig.Role)
+	}
 	// the specified jobs(concatenate multiple jobs with ',') runs when the container starts
 	val, ok := os.LookupEnv(launchJobNamesEnv)
 	if ok && val != "" {
@@ -146,6 +224,7 @@ func createManager(ctx context.Context,
 	restConfig *rest.Config,
 	managerConfig *configs.ManagerConfig,
 	sqlConn *sql.Conn,
+	heartbeater *failover.Heartbeater,
 ) (ctrl.Manager, error) {
 	leaseDuration := time.Duration(managerConfig.ElectionConfig.LeaseDuration) * time.Second
 	renewDeadline := time.Duration(managerConfig.ElectionConfig.RenewDeadline) * time.Second
@@ -204,6 +283,13 @@ func createManager(ctx context.Context,
 		return nil, fmt.Errorf("failed to add configmap controller to manager: %w", err)
 	}
 	configs.SetEnableInventoryAPI(managerConfig.EnableInventoryAPI)
+	if managerConfig.TransportConfig.TransportType == string(transport.Nats) && managerConfig.EmbeddedNatsPort > 0 {
+		err = mgr.Add(jetstream.NewEmbeddedServer("0.0.0.0", managerConfig.EmbeddedNatsPort,
+			managerConfig.EmbeddedNatsStoreDir, managerConfig.EmbeddedNatsCertDir))
+		if err != nil {
+			return nil, fmt.Errorf("failed to add the embedded nats server: %w", err)
+		}
+	}
 	err = controller.NewTransportCtrl(managerConfig.ManagerNamespace, constants.GHTransportConfigSecret,
 		transportCallback(mgr, managerConfig),
 		managerConfig.TransportConfig, true,
@@ -212,6 +298,12 @@ func createManager(ctx context.Context,
 		return nil, fmt.Errorf("failed to add the transport controller")
 	}
 
+	if heartbeater != nil {
+		if err := mgr.Add(heartbeater); err != nil {
+			return nil, fmt.Errorf("failed to add the failover heartbeat: %w", err)
+		}
+	}
+
 	// the cronjob can start without producer and consumer
 	if err := cronjob.AddSchedulerToManager(ctx, mgr, managerConfig, enableSimulation); err != nil {
 		return nil, fmt.Errorf("failed to add scheduler to manager: %w", err)
@@ -226,6 +318,7 @@ func createManager(ctx context.Context,
 		return nil, err
 	}
 	if managerConfig.EnableGlobalResource {
+		managerConfig.RestAPIServerConfig.EnableGlobalResource = true
 		if err := restapis.AddRestApiServer(mgr, managerConfig.RestAPIServerConfig); err != nil {
 			return nil, fmt.Errorf("failed to add non-k8s-api-server: %w", err)
 		}
@@ -251,7 +344,7 @@ func transportCallback(mgr ctrl.Manager, managerConfig *configs.ManagerConfig) c
 			return fmt.Errorf("consumer is not initialized")
 		}
 
-		if err := status.AddStatusSyncers(mgr, consumer, requester, managerConfig); err != nil {
+		if err := status.AddStatusSyncers(mgr, consumer, producer, requester, managerConfig); err != nil {
 			return fmt.Errorf("failed to add transport-to-db syncers: %w", err)
 		}
 
@@ -285,6 +378,9 @@ func doMain(ctx context.Context, restConfig *rest.Config) error {
 		Dialect:    database.PostgresDialect,
 		CaCertPath: managerConfig.DatabaseConfig.CACertPath,
 		PoolSize:   managerConfig.DatabaseConfig.MaxOpenConns,
+		// the replicas are only used by the default gorm instance
+		ReplicaURLs:   managerConfig.DatabaseConfig.ReplicaDatabaseURLs,
+		MaxReplicaLag: managerConfig.DatabaseConfig.MaxReplicaLag,
 	}
 	// Init the default gorm instance, it's used to sync data to db
 	err := database.InitGormInstance(databaseConfig)
@@ -292,6 +388,51 @@ func doMain(ctx context.Context, restConfig *rest.Config) error {
 		return fmt.Errorf("failed to initialize GORM instance %w", err)
 	}
 	defer database.CloseGorm(database.GetSqlDb())
+	defer database.CloseReplicas()
+
+	// restore the database and exit, it runs as a job while the manager is scaled down
+	if managerConfig.BackupConfig.Restore != "" {
+		return restoreBackup(ctx, managerConfig.BackupConfig)
+	}
+
+	// the standby global hub replicates the database of the active one until it takes over
+	var failoverLease *failover.Lease
+	if managerConfig.FailoverConfig.Role != "" {
+		failoverLease, err = failover.NewLeaseFromKubeconfig(managerConfig.FailoverConfig.LeaseKubeconfig,
+			managerConfig.FailoverConfig.Name, managerConfig.FailoverConfig.Timeout)
+		if err != nil {
+			return fmt.Errorf("failed to create the failover lease %w", err)
+		}
+	}
+	if managerConfig.FailoverConfig.Role == constants.FailoverRoleStandby {
+		standby, err := failover.NewStandby(restConfig, managerConfig.ManagerNamespace, managerConfig.FailoverConfig,
+			failoverLease)
+		if err != nil {
+			return fmt.Errorf("failed to create the standby global hub %w", err)
+		}
+		promoted, err := standby.Run(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to run the standby global hub %w", err)
+		}
+		if !promoted {
+			return nil
+		}
+		managerConfig.FailoverConfig.Role = constants.FailoverRoleActive
+	}
+
+	// the database schema is migrated by the operator, don't start until it reaches the version expected by the manager
+	if err := database.CheckSchemaVersion(ctx, database.GetSqlDb()); err != nil {
+		return err
+	}
+
+	// the active global hub doesn't run until it holds the failover lease
+	var heartbeater *failover.Heartbeater
+	if managerConfig.FailoverConfig.Role == constants.FailoverRoleActive {
+		heartbeater = failover.NewHeartbeater(managerConfig.FailoverConfig, failoverLease)
+		if err := heartbeater.Acquire(ctx); err != nil {
+			return fmt.Errorf("failed to acquire the failover lease %w", err)
+		}
+	}
 
 	// Init the backup gorm instance, it's used to add lock when backup database
 	_, sqlBackupConn, err := database.NewGormConn(databaseConfig)
@@ -304,7 +445,12 @@ func doMain(ctx context.Context, restConfig *rest.Config) error {
 	if err != nil {
 		return fmt.Errorf("failed to sql conn instance %w", err)
 	}
-	mgr, err := createManager(ctx, restConfig, managerConfig, sqlConn)
+	// the read-only queries are routed to the replicas only if they're healthy
+	if len(managerConfig.DatabaseConfig.ReplicaDatabaseURLs) > 0 {
+		go database.MonitorReplicas(ctx, database.ReplicaProbeInterval)
+	}
+
+	mgr, err := createManager(ctx, restConfig, managerConfig, sqlConn, heartbeater)
 	if err != nil {
 		return fmt.Errorf("failed to create manager %w", err)
 	}
@@ -324,6 +470,17 @@ func doMain(ctx context.Context, restConfig *rest.Config) error {
 	return nil
 }
 
+func restoreBackup(ctx context.Context, backupConfig *configs.BackupConfig) error {
+	store, err := backup.NewObjectStore(backupConfig.Target)
+	if err != nil {
+		return err
+	}
+	if err := backup.Restore(ctx, store, backupConfig.Restore); err != nil {
+		return fmt.Errorf("failed to restore the backup %s: %w", backupConfig.Restore, err)
+	}
+	return nil
+}
+
 func main() {
 	defer func() { _ = logger.CoreZapLogger().Sync() }()
 	if err := doMain(ctrl.SetupSignalHandler(), ctrl.GetConfigOrDie()); err != nil {
@@ -0,0 +1,266 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package backup
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const (
+	// LatestBackup refers to the latest completed backup
+	LatestBackup = "latest"
+	// incrementalOverlap exports the rows updated a while before the last watermark again, so the rows committed by
+	// the transactions started before the last snapshot aren't missed, they're upserted when restoring
+	incrementalOverlap = 10 * time.Minute
+	// timestampFormat formats the watermark to compare with the timestamp without time zone columns
+	timestampFormat = "2006-01-02 15:04:05.999999"
+
+	dataSuffix = ".jsonl.gz"
+	keysSuffix = ".keys.jsonl.gz"
+)
+
+var log = logger.ZapLogger("database-backup")
+
+// Backupper exports the tables of the schemas into the object store. A full backup exports all the tables, and the
+// incremental backups based on it export the rows updated since the previous backup, and the partitions whose content
+// is changed since the previous backup, until the full backup is older than the full interval.
+type Backupper struct {
+	store        ObjectStore
+	fullInterval time.Duration
+	schemas      []string
+}
+
+func NewBackupper(store ObjectStore, fullInterval time.Duration) *Backupper {
+	return &Backupper{store: store, fullInterval: fullInterval, schemas: Schemas}
+}
+
+// Backup runs a backup, or restarts the latest backup if it isn't completed. All the tables are exported in a
+// repeatable read transaction, so they're consistent with the transport offsets exported first. The interrupted backup
+// isn't resumed from the next table, since the tables exported by the interrupted run are in another snapshot.
+func (b *Backupper) Backup(ctx context.Context) (*Manifest, error) {
+	manifest, previous, err := b.nextBackup(ctx)
+	if err != nil {
+		return nil, err
+	}
+	previousTables := map[string]*TableBackup{}
+	if previous != nil {
+		previousTables = previous.Tables
+	}
+	// record the backup, so it's restarted with the same id if it's interrupted
+	if err := putManifest(ctx, b.store, manifest); err != nil {
+		return nil, err
+	}
+
+	err = database.GetGorm().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET TRANSACTION ISOLATION LEVEL REPEATABLE READ, READ ONLY").Error; err != nil {
+			return err
+		}
+		var snapshotAt time.Time
+		if err := tx.Raw("SELECT LOCALTIMESTAMP").Scan(&snapshotAt).Error; err != nil {
+			return err
+		}
+		tables, err := listTables(tx, b.schemas)
+		if err != nil {
+			return fmt.Errorf("failed to list the tables: %w", err)
+		}
+		sort.SliceStable(tables, func(i, j int) bool {
+			return tables[i].fullName() == transportTable && tables[j].fullName() != transportTable
+		})
+
+		for _, t := range tables {
+			entry, err := b.backupTable(ctx, tx, manifest.ID, t, previousTables[t.fullName()], snapshotAt)
+			if err != nil {
+				return fmt.Errorf("failed to back up the table %s: %w", t.fullName(), err)
+			}
+			manifest.Tables[t.fullName()] = entry
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	completedAt := time.Now()
+	manifest.CompletedAt = &completedAt
+	if err := putManifest(ctx, b.store, manifest); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// nextBackup returns the manifest of the backup to run, and the previous backup it's based on, which is nil for the
+// full backup.
+func (b *Backupper) nextBackup(ctx context.Context) (*Manifest, *Manifest, error) {
+	manifests, err := ListBackups(ctx, b.store)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list the backups: %w", err)
+	}
+
+	var manifest, previous *Manifest
+	if len(manifests) > 0 && !manifests[0].completed() {
+		manifest = manifests[0]
+		log.Infow("restart the interrupted backup", "id", manifest.ID)
+		manifest.Tables = map[string]*TableBackup{}
+		if manifest.Parent != "" {
+			if previous, err = getManifest(ctx, b.store, manifest.Parent); err != nil {
+				return nil, nil, err
+			}
+		}
+		return manifest, previous, nil
+	}
+
+	now := time.Now()
+	manifest = &Manifest{ID: newBackupID(now), StartedAt: now, Tables: map[string]*TableBackup{}}
+	manifest.Base = manifest.ID
+	for _, m := range manifests {
+		if !m.completed() {
+			continue
+		}
+		if baseTime, err := time.Parse(backupIDFormat, m.Base); err == nil && now.Sub(baseTime) < b.fullInterval {
+			manifest.Base, manifest.Parent, previous = m.Base, m.ID, m
+		}
+		break
+	}
+	return manifest, previous, nil
+}
+
+func (b *Backupper) backupTable(ctx context.Context, tx *gorm.DB, backupID string, t *table,
+	previous *TableBackup, snapshotAt time.Time,
+) (*TableBackup, error) {
+	columns, err := listColumns(tx, t.quotedName())
+	if err != nil {
+		return nil, err
+	}
+	primaryKeys, err := listPrimaryKeys(tx, t.quotedName())
+	if err != nil {
+		return nil, err
+	}
+
+	entry := &TableBackup{
+		Schema:         t.Schema,
+		Name:           t.Name,
+		Parent:         t.Parent,
+		PartitionBound: t.PartitionBound,
+		Mode:           tableMode(t, columns, primaryKeys),
+	}
+	if previous != nil && previous.Mode != entry.Mode {
+		previous = nil
+	}
+	selectRows := "SELECT row_to_json(t)::text FROM " + t.quotedName() + " t"
+
+	if entry.Mode == ModeFull {
+		if entry.Fingerprint, err = tableFingerprint(tx, t.quotedName()); err != nil {
+			return nil, err
+		}
+		if previous != nil && previous.Fingerprint == entry.Fingerprint {
+			entry.Objects = previous.Objects
+			return entry, nil
+		}
+		object, err := b.export(ctx, tx, objectKey(backupID, t.fullName(), dataSuffix), selectRows)
+		if err != nil {
+			return nil, err
+		}
+		entry.Objects = []ObjectRef{*object}
+		return entry, nil
+	}
+
+	entry.Watermark = &snapshotAt
+	if previous == nil || previous.Watermark == nil {
+		object, err := b.export(ctx, tx, objectKey(backupID, t.fullName(), dataSuffix), selectRows)
+		if err != nil {
+			return nil, err
+		}
+		entry.Objects = []ObjectRef{*object}
+		return entry, nil
+	}
+
+	since := previous.Watermark.Add(-incrementalOverlap).Format(timestampFormat)
+	object, err := b.export(ctx, tx, objectKey(backupID, t.fullName(), dataSuffix),
+		selectRows+" WHERE "+quoteIdent(updatedAtColumn)+" >= ?::timestamp", since)
+	if err != nil {
+		return nil, err
+	}
+	entry.Objects = append(append([]ObjectRef{}, previous.Objects...), *object)
+
+	keyPairs := []string{}
+	for _, key := range primaryKeys {
+		keyPairs = append(keyPairs, fmt.Sprintf("'%s', t.%s", strings.ReplaceAll(key, "'", "''"), quoteIdent(key)))
+	}
+	entry.Keys, err = b.export(ctx, tx, objectKey(backupID, t.fullName(), keysSuffix),
+		"SELECT json_build_object("+strings.Join(keyPairs, ", ")+")::text FROM "+t.quotedName()+" t")
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (b *Backupper) export(ctx context.Context, tx *gorm.DB, key, query string, args ...interface{},
+) (*ObjectRef, error) {
+	return Export(ctx, b.store, tx, key, query, args...)
+}
+
+// Export writes the json rows of the query into the gzip object of the store, and returns the object with the
+// checksum. Each row of the query is a json text, e.g. SELECT row_to_json(t)::text FROM <table> t.
+func Export(ctx context.Context, store ObjectStore, tx *gorm.DB, key, query string, args ...interface{},
+) (*ObjectRef, error) {
+	rows, err := tx.Raw(query, args...).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	object := &ObjectRef{Key: key}
+	hash := sha256.New()
+	reader, writer := io.Pipe()
+	exported := make(chan error, 1)
+	go func() {
+		err := writeRows(rows, gzip.NewWriter(io.MultiWriter(writer, hash)), &object.Rows)
+		// the object store reads to the end once the rows are exported, or fails with the error
+		_ = writer.CloseWithError(err)
+		exported <- err
+	}()
+
+	putErr := store.Put(ctx, key, reader)
+	// stop the export if the object store fails before reading to the end
+	_ = reader.CloseWithError(putErr)
+	if err := <-exported; err != nil {
+		return nil, fmt.Errorf("failed to export the rows: %w", err)
+	}
+	if putErr != nil {
+		return nil, fmt.Errorf("failed to put the object %s: %w", key, putErr)
+	}
+	object.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return object, nil
+}
+
+func writeRows(rows *sql.Rows, writer *gzip.Writer, count *int64) error {
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(writer, line+"\n"); err != nil {
+			return err
+		}
+		*count++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,121 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileObjectStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewObjectStore("file://" + t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, store.Put(ctx, "b/2.json", strings.NewReader("2")))
+	require.NoError(t, store.Put(ctx, "a/1.json", strings.NewReader("1")))
+	require.NoError(t, store.Put(ctx, "a/1.json", strings.NewReader("11")))
+
+	reader, err := store.Get(ctx, "a/1.json")
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	require.NoError(t, reader.Close())
+	assert.Equal(t, "11", string(data))
+
+	_, err = store.Get(ctx, "c/3.json")
+	assert.ErrorIs(t, err, ErrObjectNotFound)
+
+	keys, err := store.List(ctx, "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a/1.json", "b/2.json"}, keys)
+
+	keys, err = store.List(ctx, "b/")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b/2.json"}, keys)
+
+	// the target is the directory only
+	_, err = NewObjectStore(t.TempDir())
+	assert.NoError(t, err)
+	_, err = NewObjectStore("s3://bucket")
+	assert.Error(t, err)
+	_, err = NewObjectStore("backup")
+	assert.Error(t, err)
+}
+
+func TestGetCompletedManifest(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewObjectStore("file://" + t.TempDir())
+	require.NoError(t, err)
+
+	_, err = getCompletedManifest(ctx, store, LatestBackup)
+	assert.Error(t, err)
+
+	now := time.Now()
+	for i, completed := range []bool{true, true, false} {
+		manifest := &Manifest{ID: newBackupID(now.Add(time.Duration(i) * time.Hour)), Tables: map[string]*TableBackup{}}
+		if completed {
+			manifest.CompletedAt = &now
+		}
+		require.NoError(t, putManifest(ctx, store, manifest))
+	}
+
+	manifests, err := ListBackups(ctx, store)
+	require.NoError(t, err)
+	require.Len(t, manifests, 3)
+	assert.Equal(t, newBackupID(now.Add(2*time.Hour)), manifests[0].ID)
+
+	manifest, err := getCompletedManifest(ctx, store, LatestBackup)
+	require.NoError(t, err)
+	assert.Equal(t, newBackupID(now.Add(time.Hour)), manifest.ID)
+
+	_, err = getCompletedManifest(ctx, store, manifests[0].ID)
+	assert.Error(t, err)
+}
+
+func TestTableMode(t *testing.T) {
+	columns := []column{{Name: "id"}, {Name: updatedAtColumn}}
+	assert.Equal(t, ModeIncremental, tableMode(&table{Schema: "status", Name: "leaf_hubs"}, columns, []string{"id"}))
+	assert.Equal(t, ModeFull, tableMode(&table{Schema: "status", Name: "leaf_hubs"}, columns, nil))
+	assert.Equal(t, ModeFull, tableMode(&table{Schema: "status", Name: "leaf_hubs"}, columns[:1], []string{"id"}))
+	assert.Equal(t, ModeFull, tableMode(&table{
+		Schema: "event", Name: "managed_clusters_2025_01", Parent: "event.managed_clusters",
+	}, columns, []string{"id"}))
+}
+
+func TestUpsertClause(t *testing.T) {
+	assert.Equal(t, ` ON CONFLICT ("id") DO UPDATE SET "payload" = EXCLUDED."payload"`,
+		upsertClause([]string{"id"}, []string{"id", "payload"}))
+	assert.Equal(t, ` ON CONFLICT ("id", "name") DO NOTHING`,
+		upsertClause([]string{"id", "name"}, []string{"id", "name"}))
+}
+
+func TestApplyObject(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewObjectStore("file://" + t.TempDir())
+	require.NoError(t, err)
+
+	buffer := &bytes.Buffer{}
+	writer := gzip.NewWriter(buffer)
+	for i := 0; i < restoreBatchSize+1; i++ {
+		_, err := io.WriteString(writer, `{"id":1}`+"\n")
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	require.NoError(t, store.Put(ctx, "rows.jsonl.gz", buffer))
+
+	batches := []int{}
+	err = applyObject(ctx, store, ObjectRef{Key: "rows.jsonl.gz"}, func(batch string) error {
+		batches = append(batches, strings.Count(batch, `{"id":1}`))
+		assert.True(t, strings.HasPrefix(batch, "[") && strings.HasSuffix(batch, "]"))
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{restoreBatchSize, 1}, batches)
+}
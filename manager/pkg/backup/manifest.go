@@ -0,0 +1,162 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package backup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	manifestName = "manifest.json"
+	// backupIDFormat makes the backup ids sorted by the time
+	backupIDFormat = "20060102T150405Z"
+)
+
+// the modes of exporting a table
+const (
+	// ModeFull exports all the rows of the table, it's reused by the next backup if the table isn't modified
+	ModeFull = "full"
+	// ModeIncremental exports the rows updated since the last backup, and all the primary keys to replay the
+	// deletions
+	ModeIncremental = "incremental"
+)
+
+// Manifest describes a backup, it's self-contained, i.e. all the objects to restore the tables at the time of the
+// backup are listed, which may be exported by the previous backups based on the same full backup.
+type Manifest struct {
+	ID string `json:"id"`
+	// Base is the id of the full backup the incremental backup is based on, it equals the id of the full backup
+	Base        string                  `json:"base"`
+	Parent      string                  `json:"parent,omitempty"`
+	StartedAt   time.Time               `json:"startedAt"`
+	CompletedAt *time.Time              `json:"completedAt,omitempty"`
+	Tables      map[string]*TableBackup `json:"tables"`
+}
+
+// TableBackup is the backup of a table or a partition
+type TableBackup struct {
+	Schema string `json:"schema"`
+	Name   string `json:"name"`
+	// Parent and PartitionBound are used to recreate the partition when restoring
+	Parent         string `json:"parent,omitempty"`
+	PartitionBound string `json:"partitionBound,omitempty"`
+	Mode           string `json:"mode"`
+	// Objects are applied in order to restore the table
+	Objects []ObjectRef `json:"objects"`
+	// Keys are the primary keys of the incremental table, the rows not in them are deleted after applying the objects
+	Keys *ObjectRef `json:"keys,omitempty"`
+	// Watermark is the database time of the snapshot, the rows updated since it are exported by the next backup
+	Watermark *time.Time `json:"watermark,omitempty"`
+	// Fingerprint is the number and the hash sum of the rows of the full table, it's exported again only if the
+	// fingerprint is changed
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// ObjectRef refers to an exported object with the checksum of its content
+type ObjectRef struct {
+	Key    string `json:"key"`
+	SHA256 string `json:"sha256"`
+	Rows   int64  `json:"rows"`
+}
+
+func (t *TableBackup) fullName() string {
+	return t.Schema + "." + t.Name
+}
+
+func newBackupID(now time.Time) string {
+	return now.UTC().Format(backupIDFormat)
+}
+
+func manifestKey(backupID string) string {
+	return path.Join(backupID, manifestName)
+}
+
+func objectKey(backupID, table, suffix string) string {
+	return path.Join(backupID, table+suffix)
+}
+
+func (m *Manifest) completed() bool {
+	return m.CompletedAt != nil
+}
+
+func putManifest(ctx context.Context, store ObjectStore, manifest *Manifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return store.Put(ctx, manifestKey(manifest.ID), bytes.NewReader(data))
+}
+
+func getManifest(ctx context.Context, store ObjectStore, backupID string) (*Manifest, error) {
+	reader, err := store.Get(ctx, manifestKey(backupID))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	manifest := &Manifest{}
+	if err := json.NewDecoder(reader).Decode(manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode the manifest of the backup %s: %w", backupID, err)
+	}
+	return manifest, nil
+}
+
+// ListBackups returns the manifests of the backups, the latest first
+func ListBackups(ctx context.Context, store ObjectStore) ([]*Manifest, error) {
+	keys, err := store.List(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	for _, key := range keys {
+		if id, found := strings.CutSuffix(key, "/"+manifestName); found && !strings.Contains(id, "/") {
+			ids = append(ids, id)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(ids)))
+
+	manifests := []*Manifest{}
+	for _, id := range ids {
+		manifest, err := getManifest(ctx, store, id)
+		if err != nil {
+			return nil, err
+		}
+		manifests = append(manifests, manifest)
+	}
+	return manifests, nil
+}
+
+// getCompletedManifest returns the manifest of the completed backup, or the latest completed one if the id is
+// "latest".
+func getCompletedManifest(ctx context.Context, store ObjectStore, backupID string) (*Manifest, error) {
+	if backupID != LatestBackup {
+		manifest, err := getManifest(ctx, store, backupID)
+		if err != nil {
+			return nil, err
+		}
+		if !manifest.completed() {
+			return nil, fmt.Errorf("the backup %s isn't completed", backupID)
+		}
+		return manifest, nil
+	}
+
+	manifests, err := ListBackups(ctx, store)
+	if err != nil {
+		return nil, err
+	}
+	for _, manifest := range manifests {
+		if manifest.completed() {
+			return manifest, nil
+		}
+	}
+	return nil, errors.New("no completed backup is found")
+}
@@ -0,0 +1,332 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+const (
+	restoresPrefix = "restores"
+	// restoreBatchSize is the number of the rows inserted by a statement
+	restoreBatchSize = 500
+)
+
+// restoreProgress records the restored tables, so the restore is resumed from the next table if it's interrupted
+type restoreProgress struct {
+	BackupID    string     `json:"backupId"`
+	Tables      []string   `json:"tables"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
+
+func restoreProgressKey(backupID string) string {
+	return path.Join(restoresPrefix, backupID+".json")
+}
+
+// Restore restores the tables of the backup, or the latest completed backup if the id is "latest". The tables which
+// aren't in the backup are cleared, and the transport offsets are restored at last, so the status consumer replays
+// the events since the backup once the manager is started. It should run while the manager isn't running.
+func Restore(ctx context.Context, store ObjectStore, backupID string) error {
+	manifest, err := getCompletedManifest(ctx, store, backupID)
+	if err != nil {
+		return err
+	}
+	progress, err := getRestoreProgress(ctx, store, manifest.ID)
+	if err != nil {
+		return err
+	}
+	restored := map[string]bool{}
+	for _, name := range progress.Tables {
+		restored[name] = true
+	}
+	log.Infow("restore the backup", "id", manifest.ID, "base", manifest.Base, "restoredTables", len(restored))
+
+	names := []string{}
+	for name := range manifest.Tables {
+		if name != transportTable {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	db := database.GetGorm().WithContext(ctx)
+	for _, name := range names {
+		if restored[name] {
+			continue
+		}
+		if err := restoreTable(ctx, db, store, manifest.Tables[name]); err != nil {
+			return fmt.Errorf("failed to restore the table %s: %w", name, err)
+		}
+		progress.Tables = append(progress.Tables, name)
+		if err := putJSON(ctx, store, restoreProgressKey(manifest.ID), progress); err != nil {
+			return err
+		}
+		log.Infow("table restored", "name", name)
+	}
+
+	if err := clearTables(db, manifest); err != nil {
+		return err
+	}
+	if entry, found := manifest.Tables[transportTable]; found {
+		if err := restoreTable(ctx, db, store, entry); err != nil {
+			return fmt.Errorf("failed to rewind the transport offsets: %w", err)
+		}
+	}
+
+	completedAt := time.Now()
+	progress.CompletedAt = &completedAt
+	if err := putJSON(ctx, store, restoreProgressKey(manifest.ID), progress); err != nil {
+		return err
+	}
+	log.Infow("backup restored", "id", manifest.ID)
+	return nil
+}
+
+// getRestoreProgress returns the progress of the interrupted restore, or a new one if the backup hasn't been
+// restored, or has been restored completely.
+func getRestoreProgress(ctx context.Context, store ObjectStore, backupID string) (*restoreProgress, error) {
+	progress := &restoreProgress{BackupID: backupID, Tables: []string{}}
+	reader, err := store.Get(ctx, restoreProgressKey(backupID))
+	if errors.Is(err, ErrObjectNotFound) {
+		return progress, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	previous := &restoreProgress{}
+	if err := json.NewDecoder(reader).Decode(previous); err != nil {
+		return nil, fmt.Errorf("failed to decode the restore progress of the backup %s: %w", backupID, err)
+	}
+	if previous.CompletedAt != nil {
+		return progress, nil
+	}
+	return previous, nil
+}
+
+func putJSON(ctx context.Context, store ObjectStore, key string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return store.Put(ctx, key, bytes.NewReader(data))
+}
+
+// restoreTable replaces the rows of the table by the objects in a transaction, the triggers are disabled, so the
+// history and events aren't generated again by the restored rows.
+func restoreTable(ctx context.Context, db *gorm.DB, store ObjectStore, entry *TableBackup) error {
+	for _, object := range entry.objectRefs() {
+		if err := verifyObject(ctx, store, object); err != nil {
+			return err
+		}
+	}
+
+	t := &table{Schema: entry.Schema, Name: entry.Name}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SET LOCAL session_replication_role = replica").Error; err != nil {
+			return err
+		}
+		if parentSchema, parent, found := strings.Cut(entry.Parent, "."); found {
+			err := tx.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s.%s %s", t.quotedName(),
+				quoteIdent(parentSchema), quoteIdent(parent), entry.PartitionBound)).Error
+			if err != nil {
+				return fmt.Errorf("failed to create the partition: %w", err)
+			}
+		}
+
+		columns, err := listColumns(tx, t.quotedName())
+		if err != nil {
+			return err
+		}
+		primaryKeys, err := listPrimaryKeys(tx, t.quotedName())
+		if err != nil {
+			return err
+		}
+		if err := tx.Exec("DELETE FROM " + t.quotedName()).Error; err != nil {
+			return err
+		}
+
+		insertColumns := quoteIdents(insertableColumns(columns))
+		insert := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM json_populate_recordset(NULL::%s, ?::json)",
+			t.quotedName(), insertColumns, insertColumns, t.quotedName())
+		if entry.Mode == ModeIncremental && len(primaryKeys) > 0 {
+			insert += upsertClause(primaryKeys, insertableColumns(columns))
+		}
+		for _, object := range entry.Objects {
+			if err := applyObject(ctx, store, object, func(batch string) error {
+				return tx.Exec(insert, batch).Error
+			}); err != nil {
+				return err
+			}
+		}
+
+		if entry.Keys != nil && len(primaryKeys) > 0 {
+			if err := deleteByKeys(ctx, tx, store, t, primaryKeys, *entry.Keys); err != nil {
+				return err
+			}
+		}
+		return resetSequences(tx, t, columns)
+	})
+}
+
+func (t *TableBackup) objectRefs() []ObjectRef {
+	if t.Keys == nil {
+		return t.Objects
+	}
+	return append(append([]ObjectRef{}, t.Objects...), *t.Keys)
+}
+
+func upsertClause(primaryKeys, columns []string) string {
+	updates := []string{}
+	for _, c := range columns {
+		if !utils.ContainsString(primaryKeys, c) {
+			updates = append(updates, fmt.Sprintf("%s = EXCLUDED.%s", quoteIdent(c), quoteIdent(c)))
+		}
+	}
+	if len(updates) == 0 {
+		return fmt.Sprintf(" ON CONFLICT (%s) DO NOTHING", quoteIdents(primaryKeys))
+	}
+	return fmt.Sprintf(" ON CONFLICT (%s) DO UPDATE SET %s", quoteIdents(primaryKeys), strings.Join(updates, ", "))
+}
+
+// deleteByKeys deletes the rows which aren't in the primary keys of the backup, i.e. deleted before the backup
+func deleteByKeys(ctx context.Context, tx *gorm.DB, store ObjectStore, t *table, primaryKeys []string,
+	keys ObjectRef,
+) error {
+	keyColumns := quoteIdents(primaryKeys)
+	err := tx.Exec(fmt.Sprintf("CREATE TEMP TABLE restore_keys ON COMMIT DROP AS SELECT %s FROM %s WITH NO DATA",
+		keyColumns, t.quotedName())).Error
+	if err != nil {
+		return err
+	}
+	err = applyObject(ctx, store, keys, func(batch string) error {
+		return tx.Exec(fmt.Sprintf("INSERT INTO restore_keys SELECT %s FROM json_populate_recordset(NULL::restore_keys, "+
+			"?::json)", keyColumns), batch).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	conditions := []string{}
+	for _, key := range primaryKeys {
+		conditions = append(conditions, fmt.Sprintf("k.%s = t.%s", quoteIdent(key), quoteIdent(key)))
+	}
+	return tx.Exec(fmt.Sprintf("DELETE FROM %s t WHERE NOT EXISTS (SELECT 1 FROM restore_keys k WHERE %s)",
+		t.quotedName(), strings.Join(conditions, " AND "))).Error
+}
+
+// resetSequences moves the sequences of the serial columns after the restored rows
+func resetSequences(tx *gorm.DB, t *table, columns []column) error {
+	for _, c := range columns {
+		var sequence *string
+		if err := tx.Raw("SELECT pg_get_serial_sequence(?, ?)", t.quotedName(), c.Name).
+			Scan(&sequence).Error; err != nil {
+			return err
+		}
+		if sequence == nil {
+			continue
+		}
+		err := tx.Exec(fmt.Sprintf("SELECT setval(?, COALESCE((SELECT MAX(%s) FROM %s), 0) + 1, false)",
+			quoteIdent(c.Name), t.quotedName()), *sequence).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// clearTables deletes the rows of the tables which don't exist when the backup is taken, e.g. the new partitions
+func clearTables(db *gorm.DB, manifest *Manifest) error {
+	tables, err := listTables(db, Schemas)
+	if err != nil {
+		return err
+	}
+	for _, t := range tables {
+		if _, found := manifest.Tables[t.fullName()]; found {
+			continue
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec("SET LOCAL session_replication_role = replica").Error; err != nil {
+				return err
+			}
+			return tx.Exec("DELETE FROM " + t.quotedName()).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to clear the table %s: %w", t.fullName(), err)
+		}
+		log.Infow("table cleared since it isn't in the backup", "name", t.fullName())
+	}
+	return nil
+}
+
+// verifyObject checks the checksum of the object before applying it
+func verifyObject(ctx context.Context, store ObjectStore, object ObjectRef) error {
+	reader, err := store.Get(ctx, object.Key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, reader); err != nil {
+		return err
+	}
+	if checksum := hex.EncodeToString(hash.Sum(nil)); checksum != object.SHA256 {
+		return fmt.Errorf("the checksum of the object %s mismatches: expected %s, got %s", object.Key,
+			object.SHA256, checksum)
+	}
+	return nil
+}
+
+// applyObject calls the apply with the json arrays of the rows in the object batch by batch
+func applyObject(ctx context.Context, store ObjectStore, object ObjectRef, apply func(batch string) error) error {
+	reader, err := store.Get(ctx, object.Key)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = reader.Close() }()
+	gzipReader, err := gzip.NewReader(reader)
+	if err != nil {
+		return fmt.Errorf("failed to read the object %s: %w", object.Key, err)
+	}
+
+	lineReader := bufio.NewReader(gzipReader)
+	batch := []string{}
+	for {
+		line, err := lineReader.ReadString('\n')
+		if line = strings.TrimSuffix(line, "\n"); line != "" {
+			batch = append(batch, line)
+		}
+		if len(batch) > 0 && (len(batch) == restoreBatchSize || err == io.EOF) {
+			if applyErr := apply("[" + strings.Join(batch, ",") + "]"); applyErr != nil {
+				return applyErr
+			}
+			batch = batch[:0]
+		}
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read the object %s: %w", object.Key, err)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var ErrObjectNotFound = errors.New("object not found")
+
+// ObjectStore is the object storage the backups are written to, the keys are slash separated like the S3 API.
+type ObjectStore interface {
+	Put(ctx context.Context, key string, body io.Reader) error
+	// Get returns the ErrObjectNotFound if the object doesn't exist
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// List returns the sorted keys with the prefix
+	List(ctx context.Context, prefix string) ([]string, error)
+}
+
+// NewObjectStore creates the object store in the directory of the target, e.g. /var/lib/backup or
+// file:///var/lib/backup. Only the directory is supported, it stands in for the S3 compatible bucket, e.g. a bucket
+// mounted by the CSI driver, or a persistent volume.
+func NewObjectStore(target string) (ObjectStore, error) {
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup target %s: %w", target, err)
+	}
+	if targetURL.Scheme != "" && targetURL.Scheme != "file" {
+		return nil, fmt.Errorf("the backup target %s isn't a directory, only the directory is supported", target)
+	}
+	if !filepath.IsAbs(targetURL.Path) {
+		return nil, fmt.Errorf("the backup target %s isn't an absolute path", target)
+	}
+	return &fileObjectStore{root: targetURL.Path}, nil
+}
+
+// fileObjectStore stores the objects as the files under the root directory
+type fileObjectStore struct {
+	root string
+}
+
+func (s *fileObjectStore) path(key string) string {
+	return filepath.Join(s.root, filepath.FromSlash(key))
+}
+
+// Put writes the object into a temporary file first, so the object is either fully written or not at all.
+func (s *fileObjectStore) Put(ctx context.Context, key string, body io.Reader) error {
+	path := s.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return err
+	}
+	file, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	if _, err := io.Copy(file, body); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return os.Rename(file.Name(), path)
+}
+
+func (s *fileObjectStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	file, err := os.Open(s.path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrObjectNotFound, key)
+	}
+	return file, err
+}
+
+func (s *fileObjectStore) List(ctx context.Context, prefix string) ([]string, error) {
+	keys := []string{}
+	err := filepath.WalkDir(s.root, func(path string, entry fs.DirEntry, err error) error {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".tmp-") {
+			return nil
+		}
+		relPath, err := filepath.Rel(s.root, path)
+		if err != nil {
+			return err
+		}
+		if key := filepath.ToSlash(relPath); strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+		return nil
+	})
+	sort.Strings(keys)
+	return keys, err
+}
@@ -0,0 +1,121 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package backup
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Schemas are backed up with all the tables and partitions in them. The local_status is backed up together with the
+// local_spec, since the local compliance refers to the local policies.
+var Schemas = []string{"status", "local_spec", "local_status", "history", "event", "security"}
+
+// transportTable keeps the offsets of the transport, it's exported before and restored after all the other tables,
+// so the consumer resumes from the offsets which aren't beyond the restored data.
+const transportTable = "status.transport"
+
+const updatedAtColumn = "updated_at"
+
+type table struct {
+	Schema         string
+	Name           string
+	Parent         string
+	PartitionBound string
+}
+
+func (t *table) fullName() string {
+	return t.Schema + "." + t.Name
+}
+
+func (t *table) quotedName() string {
+	return quoteIdent(t.Schema) + "." + quoteIdent(t.Name)
+}
+
+type column struct {
+	Name      string
+	Generated bool
+}
+
+// listTables returns the tables and the partitions of the schemas, the partitioned tables are skipped since the rows
+// are stored in the partitions.
+func listTables(db *gorm.DB, schemas []string) ([]*table, error) {
+	tables := []*table{}
+	err := db.Raw(`SELECT n.nspname AS schema, c.relname AS name,
+			COALESCE(pn.nspname || '.' || pc.relname, '') AS parent,
+			COALESCE(pg_get_expr(c.relpartbound, c.oid), '') AS partition_bound
+		FROM pg_class c
+		INNER JOIN pg_namespace n ON n.oid = c.relnamespace
+		LEFT JOIN pg_inherits i ON i.inhrelid = c.oid
+		LEFT JOIN pg_class pc ON pc.oid = i.inhparent
+		LEFT JOIN pg_namespace pn ON pn.oid = pc.relnamespace
+		WHERE c.relkind = 'r' AND n.nspname IN ?
+		ORDER BY n.nspname, c.relname`, schemas).Scan(&tables).Error
+	return tables, err
+}
+
+// tableFingerprint returns the number of the rows and the sum of the hashes of them in the snapshot, it's independent
+// of the row order, so the full table is exported again only if the content is changed. The statistics counters, e.g.
+// pg_stat_user_tables, aren't used since they're reset by the crash recovery and not replicated to the standby.
+func tableFingerprint(db *gorm.DB, quotedName string) (string, error) {
+	var fingerprint string
+	err := db.Raw(`SELECT count(*) || ':' || COALESCE(sum(hashtextextended(t::text, 0)), 0) FROM ` + quotedName +
+		` t`).Scan(&fingerprint).Error
+	return fingerprint, err
+}
+
+func listColumns(db *gorm.DB, quotedName string) ([]column, error) {
+	columns := []column{}
+	err := db.Raw(`SELECT attname AS name, attgenerated <> '' AS generated FROM pg_attribute
+		WHERE attrelid = ?::regclass AND attnum > 0 AND NOT attisdropped ORDER BY attnum`, quotedName).
+		Scan(&columns).Error
+	return columns, err
+}
+
+func listPrimaryKeys(db *gorm.DB, quotedName string) ([]string, error) {
+	keys := []string{}
+	err := db.Raw(`SELECT a.attname FROM pg_index i
+		INNER JOIN pg_attribute a ON a.attrelid = i.indrelid AND a.attnum = ANY(i.indkey)
+		WHERE i.indrelid = ?::regclass AND i.indisprimary ORDER BY a.attnum`, quotedName).
+		Pluck("attname", &keys).Error
+	return keys, err
+}
+
+// tableMode returns the incremental mode for the tables with the primary keys and the updated_at column, except the
+// partitions, which are exported fully once they're modified.
+func tableMode(t *table, columns []column, primaryKeys []string) string {
+	if t.Parent != "" || len(primaryKeys) == 0 {
+		return ModeFull
+	}
+	for _, c := range columns {
+		if c.Name == updatedAtColumn {
+			return ModeIncremental
+		}
+	}
+	return ModeFull
+}
+
+func quoteIdent(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func quoteIdents(names []string) string {
+	quoted := make([]string, 0, len(names))
+	for _, name := range names {
+		quoted = append(quoted, quoteIdent(name))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// insertableColumns returns the columns except the generated ones
+func insertableColumns(columns []column) []string {
+	names := []string{}
+	for _, c := range columns {
+		if !c.Generated {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
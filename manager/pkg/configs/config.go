@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
 	commonobjects "github.com/stolostron/multicluster-global-hub/pkg/objects"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -18,8 +19,11 @@ type ManagerConfig struct {
 	SchedulerInterval    string
 	SyncerConfig         *SyncerConfig
 	DatabaseConfig       *DatabaseConfig
+	BackupConfig         *BackupConfig
+	FailoverConfig       *FailoverConfig
 	TransportConfig      *transport.TransportInternalConfig
 	StatisticsConfig     *statistics.StatisticsConfig
+	ReadyQueueConfig     *conflator.ReadyQueueConfig
 	RestAPIServerConfig  *restapis.RestApiServerConfig
 	ElectionConfig       *commonobjects.LeaderElectionConfig
 	EnableGlobalResource bool
@@ -27,6 +31,13 @@ type ManagerConfig struct {
 	WithACM              bool
 	LaunchJobNames       string
 	EnablePprof          bool
+	EmbeddedNatsPort     int
+	EmbeddedNatsStoreDir string
+	EmbeddedNatsCertDir  string
+	DeadLetterTopic      string
+
+	// ComplianceSnapshotInterval is the interval of the snapshots of the local compliance
+	ComplianceSnapshotInterval time.Duration
 }
 
 type SyncerConfig struct {
@@ -41,6 +52,38 @@ type DatabaseConfig struct {
 	CACertPath                 string
 	MaxOpenConns               int
 	DataRetention              int
+	// RetentionPolicies override the data retention of the tables, the event types and the hubs, in json
+	RetentionPolicies string
+	// RetentionArchiveTarget is the object store the expired data is archived to before it's deleted
+	RetentionArchiveTarget string
+	// ReplicaDatabaseURLs are the read replicas serving the read-only queries, e.g. the rest apis
+	ReplicaDatabaseURLs []string
+	MaxReplicaLag       time.Duration
+}
+
+type BackupConfig struct {
+	// Target is the directory of the backups, e.g. /var/lib/backup, the backup is disabled if it's empty
+	Target       string
+	Interval     time.Duration
+	FullInterval time.Duration
+	// Restore is the backup id, or "latest", to restore before exiting the manager
+	Restore string
+}
+
+type FailoverConfig struct {
+	// Role is "active" or "standby", the failover is disabled if it's empty
+	Role string
+	// Name identifies the global hub in the heartbeats, it's unique across the regions
+	Name string
+	// PrimaryDatabaseURL is the database of the active global hub replicated by the standby one
+	PrimaryDatabaseURL string
+	HeartbeatInterval  time.Duration
+	// Timeout is the duration without the heartbeat of the active global hub before the standby one takes over, it's
+	// also the duration of the failover lease
+	Timeout time.Duration
+	// LeaseKubeconfig is the kubeconfig of the witness cluster holding the failover lease, which is reachable by both
+	// the active and the standby global hubs
+	LeaseKubeconfig string
 }
 
 var enableInventoryAPI bool
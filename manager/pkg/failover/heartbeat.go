@@ -0,0 +1,208 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package failover
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/backup"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const (
+	// publicationName is both the publication of the active database and the subscription of the standby one
+	publicationName  = "global_hub_failover"
+	subscriptionName = "global_hub_failover"
+)
+
+// Schemas are replicated from the active global hub to the standby one, which are the same as the backed up ones. The
+// consumer offsets in the status.transport are replicated too, but they're committed with the identity of the Kafka
+// cluster, so the promoted manager resumes from them only if it consumes the same Kafka cluster, otherwise it starts
+// with its own transport, and the events left in the lost transport are resent by the resync of the hubs.
+var Schemas = backup.Schemas
+
+var log = logger.ZapLogger("global-hub-failover")
+
+// Heartbeater runs in the leader of the active global hub managers, it publishes the tables to the standby global hub,
+// refreshes the heartbeat, which is replicated to the standby global hub to detect the health loss, and renews the
+// failover lease. It steps down the active global hub by returning the ErrFenced, which stops the manager, once the
+// lease isn't renewed for half of the failover timeout, so it steps down before the standby one can acquire the lease,
+// or the lease is held by another global hub, or another global hub is promoted.
+type Heartbeater struct {
+	name      string
+	interval  time.Duration
+	timeout   time.Duration
+	lease     *Lease
+	renewedAt time.Time
+}
+
+func NewHeartbeater(config *configs.FailoverConfig, lease *Lease) *Heartbeater {
+	return &Heartbeater{name: config.Name, interval: config.HeartbeatInterval, timeout: config.Timeout, lease: lease}
+}
+
+// Acquire blocks until the failover lease is acquired, so the global hub doesn't run as the active one without it. It
+// returns the ErrFenced if the global hub has stepped down, or the lease is held by another global hub.
+func (h *Heartbeater) Acquire(ctx context.Context) error {
+	if err := h.fenced(ctx); err != nil {
+		return err
+	}
+	for {
+		acquired, err := h.lease.TryAcquire(ctx)
+		if err == nil && acquired {
+			h.renewedAt = time.Now()
+			log.Infow("acquire the failover lease", "name", h.name)
+			return nil
+		}
+		if err == nil {
+			return h.stepDown(ctx, "the failover lease is held by another global hub")
+		}
+		log.Warnw("failed to acquire the failover lease, retrying", "error", err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(h.interval):
+		}
+	}
+}
+
+func (h *Heartbeater) Start(ctx context.Context) error {
+	log.Infow("start the global hub heartbeat", "name", h.name, "interval", h.interval)
+	if h.renewedAt.IsZero() {
+		if err := h.Acquire(ctx); err != nil {
+			return err
+		}
+	}
+	ticker := time.NewTicker(h.interval)
+	defer ticker.Stop()
+	for {
+		if err := h.beat(ctx); err != nil {
+			log.Warnw("failed to refresh the global hub heartbeat", "error", err)
+		}
+		if err := h.renew(ctx); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *Heartbeater) beat(ctx context.Context) error {
+	db := database.GetGorm().WithContext(ctx)
+	if err := ensurePublication(db); err != nil {
+		return fmt.Errorf("failed to publish the tables: %w", err)
+	}
+	return db.Exec(`INSERT INTO status.global_hub_heartbeats (name, role, heartbeat_at) VALUES (?, ?, LOCALTIMESTAMP)
+		ON CONFLICT (name) DO UPDATE SET role = EXCLUDED.role, heartbeat_at = EXCLUDED.heartbeat_at
+		WHERE status.global_hub_heartbeats.role = EXCLUDED.role`,
+		h.name, constants.FailoverRoleActive).Error
+}
+
+// renew renews the failover lease, and steps down if the global hub is fenced
+func (h *Heartbeater) renew(ctx context.Context) error {
+	if err := h.fenced(ctx); err != nil {
+		return err
+	}
+	acquired, err := h.lease.TryAcquire(ctx)
+	if err == nil && acquired {
+		h.renewedAt = time.Now()
+		return nil
+	}
+	if err == nil {
+		return h.stepDown(ctx, "the failover lease is held by another global hub")
+	}
+	log.Warnw("failed to renew the failover lease", "renewedAt", h.renewedAt, "error", err)
+	if time.Since(h.renewedAt) > h.timeout/2 {
+		// the witness cluster may be unreachable only, the global hub isn't marked as stepped down, so it runs as the
+		// active one again once it acquires the lease after the restart
+		return fmt.Errorf("%w: the failover lease isn't renewed since %s", ErrFenced, h.renewedAt)
+	}
+	return nil
+}
+
+// fenced returns the ErrFenced if the global hub has stepped down, or another global hub is promoted after it, e.g.
+// the heartbeat is written into the database by the standby global hub which is promoted in a network partition.
+func (h *Heartbeater) fenced(ctx context.Context) error {
+	db := database.GetGorm().WithContext(ctx)
+	var count int64
+	if err := db.Model(&models.GlobalHubHeartbeat{}).Where("name = ? AND role = ?", h.name,
+		constants.FailoverRoleStandby).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: the global hub %s has stepped down", ErrFenced, h.name)
+	}
+	promoted := []string{}
+	if err := db.Model(&models.GlobalHubHeartbeat{}).Where(`name <> ? AND role = ? AND promoted_at >
+		COALESCE((SELECT promoted_at FROM status.global_hub_heartbeats WHERE name = ?), '-infinity')`,
+		h.name, constants.FailoverRoleActive, h.name).Pluck("name", &promoted).Error; err != nil {
+		return err
+	}
+	if len(promoted) > 0 {
+		return h.stepDown(ctx, fmt.Sprintf("the global hub %v is promoted", promoted))
+	}
+	return nil
+}
+
+// stepDown marks the global hub as the standby one, so it doesn't run as the active one again after the restart, it
+// must be reinstalled as the standby of the promoted global hub.
+func (h *Heartbeater) stepDown(ctx context.Context, reason string) error {
+	log.Warnw("step down the active global hub", "name", h.name, "reason", reason)
+	err := database.GetGorm().WithContext(ctx).Exec(`INSERT INTO status.global_hub_heartbeats (name, role,
+		heartbeat_at) VALUES (?, ?, LOCALTIMESTAMP) ON CONFLICT (name) DO UPDATE SET role = EXCLUDED.role`,
+		h.name, constants.FailoverRoleStandby).Error
+	if err != nil {
+		log.Errorw("failed to mark the global hub as stepped down", "name", h.name, "error", err)
+	}
+	return fmt.Errorf("%w: %s", ErrFenced, reason)
+}
+
+// ensurePublication publishes the tables of the schemas, the partitions are published via their roots, so the rows are
+// routed to the partitions of the standby database. The tables without the primary key are set with the full replica
+// identity, otherwise their updates and deletions are rejected once they're published, it's checked in each heartbeat
+// since the partitions are created monthly.
+func ensurePublication(db *gorm.DB) error {
+	var count int64
+	if err := db.Raw("SELECT COUNT(*) FROM pg_publication WHERE pubname = ?", publicationName).
+		Scan(&count).Error; err != nil {
+		return err
+	}
+
+	tables := []string{}
+	err := db.Raw(`SELECT quote_ident(n.nspname) || '.' || quote_ident(c.relname) FROM pg_class c
+		INNER JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE c.relkind IN ('r', 'p') AND c.relreplident = 'd' AND n.nspname IN ?
+			AND NOT EXISTS (SELECT 1 FROM pg_index i WHERE i.indrelid = c.oid AND i.indisprimary)`, Schemas).
+		Scan(&tables).Error
+	if err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if err := db.Exec(fmt.Sprintf("ALTER TABLE %s REPLICA IDENTITY FULL", table)).Error; err != nil {
+			return err
+		}
+	}
+
+	if count > 0 {
+		return nil
+	}
+	err = db.Exec(fmt.Sprintf("CREATE PUBLICATION %s FOR TABLES IN SCHEMA %s WITH (publish_via_partition_root = true)",
+		publicationName, strings.Join(Schemas, ", "))).Error
+	if err != nil {
+		return err
+	}
+	log.Infow("publish the tables to the standby global hub", "publication", publicationName, "schemas", Schemas)
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package failover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// leaseName is the lease in the witness cluster held by the active global hub
+const leaseName = "multicluster-global-hub-failover"
+
+// ErrFenced is returned once the active global hub loses the failover lease, or another global hub is promoted, then
+// the active global hub steps down, so there aren't two active global hubs at the same time.
+var ErrFenced = errors.New("the global hub is fenced")
+
+// Lease is the failover lease in the witness cluster, which is reachable by both the active and the standby global
+// hubs. The active global hub renews it with the heartbeat, and the standby global hub is promoted only if it acquires
+// the expired lease, so a network partition between the global hubs doesn't promote the standby one while the active
+// one is still running.
+type Lease struct {
+	client    client.Client
+	namespace string
+	holder    string
+	duration  time.Duration
+}
+
+func NewLease(c client.Client, namespace, holder string, duration time.Duration) *Lease {
+	return &Lease{client: c, namespace: namespace, holder: holder, duration: duration}
+}
+
+// NewLeaseFromKubeconfig creates the lease in the namespace of the current context of the witness cluster kubeconfig
+func NewLeaseFromKubeconfig(kubeconfig, holder string, duration time.Duration) (*Lease, error) {
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig}, &clientcmd.ConfigOverrides{})
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the kubeconfig of the witness cluster: %w", err)
+	}
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the namespace of the witness cluster: %w", err)
+	}
+	c, err := client.New(restConfig, client.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return NewLease(c, namespace, holder, duration), nil
+}
+
+// TryAcquire acquires or renews the lease for the holder, it returns false only if the lease is held by another global
+// hub and isn't expired. The expiration is decided by the local clock, so the clocks of the global hubs are expected
+// to be synchronized.
+func (l *Lease) TryAcquire(ctx context.Context) (bool, error) {
+	now := metav1.NewMicroTime(time.Now())
+	seconds := int32(l.duration.Seconds())
+
+	lease := &coordinationv1.Lease{}
+	err := l.client.Get(ctx, types.NamespacedName{Namespace: l.namespace, Name: leaseName}, lease)
+	if apierrors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: leaseName, Namespace: l.namespace},
+			Spec: coordinationv1.LeaseSpec{
+				HolderIdentity:       ptr.To(l.holder),
+				LeaseDurationSeconds: ptr.To(seconds),
+				AcquireTime:          &now,
+				RenewTime:            &now,
+			},
+		}
+		if err := l.client.Create(ctx, lease); err != nil {
+			return false, err
+		}
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if ptr.Deref(lease.Spec.HolderIdentity, "") != l.holder {
+		if !expired(lease, now.Time) {
+			return false, nil
+		}
+		log.Infow("acquire the expired failover lease", "holder", ptr.Deref(lease.Spec.HolderIdentity, ""))
+		lease.Spec.HolderIdentity = ptr.To(l.holder)
+		lease.Spec.AcquireTime = &now
+		lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+	}
+	lease.Spec.LeaseDurationSeconds = ptr.To(seconds)
+	lease.Spec.RenewTime = &now
+	// the update is rejected by the resource version if the lease is changed in between, e.g. by another manager
+	// replica, the conflict is returned as the error, so it's retried instead of being handled as held by another one
+	if err := l.client.Update(ctx, lease); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func expired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	duration := time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second
+	return now.After(lease.Spec.RenewTime.Add(duration))
+}
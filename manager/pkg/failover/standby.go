@@ -0,0 +1,394 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package failover
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// failoverLockKey serializes the promotion between the standby manager replicas
+const failoverLockKey = "multicluster-global-hub-failover"
+
+var mghListGVK = schema.GroupVersionKind{
+	Group:   "operator.open-cluster-management.io",
+	Version: "v1alpha4",
+	Kind:    "MulticlusterGlobalHubList",
+}
+
+// Standby replicates the database of the active global hub by the logical replication, and takes over once the
+// heartbeat of the active global hub is expired, the active database is unhealthy, and the failover lease is acquired.
+type Standby struct {
+	config     *configs.FailoverConfig
+	namespace  string
+	client     client.Client
+	lease      *Lease
+	primary    *gorm.DB
+	primarySQL *sql.DB
+}
+
+func NewStandby(restConfig *rest.Config, namespace string, config *configs.FailoverConfig, lease *Lease,
+) (*Standby, error) {
+	c, err := client.New(restConfig, client.Options{})
+	if err != nil {
+		return nil, err
+	}
+	primary, primarySQL, err := database.NewGormConn(&database.DatabaseConfig{
+		URL:     config.PrimaryDatabaseURL,
+		Dialect: database.PostgresDialect,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the primary database: %w", err)
+	}
+	primarySQL.SetMaxOpenConns(1)
+	return &Standby{
+		config:     config,
+		namespace:  namespace,
+		client:     c,
+		lease:      lease,
+		primary:    primary,
+		primarySQL: primarySQL,
+	}, nil
+}
+
+// Run blocks until the standby global hub is promoted, it returns false if the context is done before that.
+func (s *Standby) Run(ctx context.Context) (bool, error) {
+	defer database.CloseGorm(s.primarySQL)
+
+	promoted, err := s.promoted(ctx)
+	if err != nil {
+		return false, err
+	}
+	if promoted {
+		log.Infow("the global hub has been promoted", "name", s.config.Name)
+		return true, s.activate(ctx)
+	}
+
+	log.Infow("start the standby global hub", "name", s.config.Name, "timeout", s.config.Timeout)
+	ticker := time.NewTicker(s.config.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		takeover, err := s.sync(ctx)
+		if err != nil {
+			log.Warnw("failed to sync with the active global hub", "error", err)
+		}
+		if takeover {
+			if err := s.promote(ctx); err != nil {
+				log.Errorw("failed to promote the standby global hub", "error", err)
+			} else {
+				s.fencePrimary(ctx)
+				return true, s.activate(ctx)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return false, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// promoted returns true if the database has been promoted, e.g. the manager restarts before the operator switches the
+// role, so it doesn't subscribe to the previous active global hub again.
+func (s *Standby) promoted(ctx context.Context) (bool, error) {
+	var count int64
+	err := database.GetGorm().WithContext(ctx).Model(&models.GlobalHubHeartbeat{}).
+		Where("name = ? AND role = ?", s.config.Name, constants.FailoverRoleActive).Count(&count).Error
+	return count > 0, err
+}
+
+// sync mirrors the partitions of the active database, ensures the subscription, and returns true if the standby global
+// hub should take over.
+func (s *Standby) sync(ctx context.Context) (bool, error) {
+	db := database.GetGorm().WithContext(ctx)
+	primaryCtx, cancel := context.WithTimeout(ctx, s.config.HeartbeatInterval)
+	defer cancel()
+	primary := s.primary.WithContext(primaryCtx)
+
+	if err := syncPartitions(primary, db); err != nil {
+		log.Debugw("failed to sync the partitions from the active global hub", "error", err)
+	}
+	if err := ensureSubscription(db, s.config.PrimaryDatabaseURL); err != nil {
+		return false, fmt.Errorf("failed to subscribe to the active global hub: %w", err)
+	}
+
+	lag, err := heartbeatLag(db, s.config.Name)
+	if err != nil {
+		return false, err
+	}
+	// the heartbeat isn't replicated yet, it doesn't take over until the active global hub is seen
+	if lag == nil {
+		log.Debug("waiting for the heartbeat of the active global hub")
+		return false, nil
+	}
+	if *lag < s.config.Timeout {
+		return false, nil
+	}
+
+	// the heartbeat may expire because of the replication failure, take over only if the active one is unhealthy too
+	primaryLag, err := heartbeatLag(primary, s.config.Name)
+	if err == nil && primaryLag != nil && *primaryLag < s.config.Timeout {
+		log.Warnw("the heartbeat of the active global hub isn't replicated", "lag", lag.String())
+		return false, nil
+	}
+	log.Infow("the heartbeat of the active global hub is expired", "lag", lag.String(), "primaryError", err)
+	return true, nil
+}
+
+// heartbeatLag returns the duration since the latest heartbeat of the other global hubs, or nil if there isn't. The
+// global hub stepped down is included, e.g. the active one steps down once the lease is acquired by the standby one
+// but the promotion fails, its heartbeat is still replicated until it expires, then the standby one is promoted.
+func heartbeatLag(db *gorm.DB, name string) (*time.Duration, error) {
+	var seconds *float64
+	err := db.Raw(`SELECT EXTRACT(EPOCH FROM LOCALTIMESTAMP - MAX(heartbeat_at))::float8
+		FROM status.global_hub_heartbeats WHERE name <> ?`, name).
+		Scan(&seconds).Error
+	if err != nil || seconds == nil {
+		return nil, err
+	}
+	lag := time.Duration(*seconds * float64(time.Second))
+	return &lag, nil
+}
+
+type partition struct {
+	Name   string
+	Parent string
+	Bound  string
+}
+
+func listPartitions(db *gorm.DB) (map[string]partition, error) {
+	partitions := []partition{}
+	err := db.Raw(`SELECT quote_ident(n.nspname) || '.' || quote_ident(c.relname) AS name,
+			quote_ident(pn.nspname) || '.' || quote_ident(pc.relname) AS parent,
+			pg_get_expr(c.relpartbound, c.oid) AS bound
+		FROM pg_class c
+		INNER JOIN pg_namespace n ON n.oid = c.relnamespace
+		INNER JOIN pg_inherits i ON i.inhrelid = c.oid
+		INNER JOIN pg_class pc ON pc.oid = i.inhparent
+		INNER JOIN pg_namespace pn ON pn.oid = pc.relnamespace
+		WHERE c.relispartition AND n.nspname IN ?`, Schemas).Scan(&partitions).Error
+	if err != nil {
+		return nil, err
+	}
+	partitionMap := map[string]partition{}
+	for _, p := range partitions {
+		partitionMap[p.Name] = p
+	}
+	return partitionMap, nil
+}
+
+// syncPartitions creates the partitions of the active database in the standby one, and drops the expired ones, since
+// the rows published via the partition roots are routed to the local partitions, and the DDL isn't replicated.
+func syncPartitions(primary, db *gorm.DB) error {
+	expected, err := listPartitions(primary)
+	if err != nil {
+		return err
+	}
+	existing, err := listPartitions(db)
+	if err != nil {
+		return err
+	}
+	for name, p := range expected {
+		if _, found := existing[name]; found {
+			continue
+		}
+		if err := db.Exec(fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s %s", p.Name, p.Parent,
+			p.Bound)).Error; err != nil {
+			return err
+		}
+		log.Infow("create the partition of the active global hub", "name", name)
+	}
+	for name := range existing {
+		if _, found := expected[name]; found {
+			continue
+		}
+		if err := db.Exec("DROP TABLE IF EXISTS " + name).Error; err != nil {
+			return err
+		}
+		log.Infow("drop the partition which is dropped by the active global hub", "name", name)
+	}
+	return nil
+}
+
+// ensureSubscription subscribes to the publication of the active database, the existing rows are copied first.
+func ensureSubscription(db *gorm.DB, primaryURL string) error {
+	var count int64
+	if err := db.Raw("SELECT COUNT(*) FROM pg_subscription WHERE subname = ?", subscriptionName).
+		Scan(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	// the connection can't be a parameter of the statement, and it can't be run in a transaction
+	err := db.Exec(fmt.Sprintf("CREATE SUBSCRIPTION %s CONNECTION '%s' PUBLICATION %s", subscriptionName,
+		strings.ReplaceAll(primaryURL, "'", "''"), publicationName)).Error
+	if err != nil {
+		return err
+	}
+	log.Infow("subscribe to the active global hub", "subscription", subscriptionName)
+	return nil
+}
+
+// promote stops the replication and makes the local database the active one, the role of the MulticlusterGlobalHub is
+// switched after that, or by the next start of the manager if it fails. It's serialized by the advisory lock
+// between the manager replicas, the later one finds the subscription is dropped and only switches the role. The
+// expired heartbeat and the unreachable active database can't tell the lost active global hub from a network
+// partition, so it's promoted only if the failover lease is acquired, which the running active global hub renews.
+func (s *Standby) promote(ctx context.Context) error {
+	acquired, err := s.lease.TryAcquire(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire the failover lease: %w", err)
+	}
+	if !acquired {
+		return fmt.Errorf("the failover lease is held by the active global hub")
+	}
+
+	replicated := false
+	err = database.GetGorm().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", failoverLockKey).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Raw("SELECT COUNT(*) FROM pg_subscription WHERE subname = ?", subscriptionName).
+			Scan(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			replicated = true
+			for _, statement := range []string{
+				fmt.Sprintf("ALTER SUBSCRIPTION %s DISABLE", subscriptionName),
+				// the replication slot isn't dropped since the active database is unreachable
+				fmt.Sprintf("ALTER SUBSCRIPTION %s SET (slot_name = NONE)", subscriptionName),
+				fmt.Sprintf("DROP SUBSCRIPTION %s", subscriptionName),
+			} {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if err := resetSequences(tx); err != nil {
+			return fmt.Errorf("failed to reset the sequences: %w", err)
+		}
+		if err := tx.Exec("UPDATE status.global_hub_heartbeats SET role = ? WHERE name <> ?",
+			constants.FailoverRoleStandby, s.config.Name).Error; err != nil {
+			return err
+		}
+		return tx.Exec(`INSERT INTO status.global_hub_heartbeats (name, role, heartbeat_at, promoted_at)
+			VALUES (?, ?, LOCALTIMESTAMP, LOCALTIMESTAMP) ON CONFLICT (name) DO UPDATE SET role = EXCLUDED.role,
+			heartbeat_at = EXCLUDED.heartbeat_at, promoted_at = EXCLUDED.promoted_at`,
+			s.config.Name, constants.FailoverRoleActive).Error
+	})
+	if err != nil {
+		return err
+	}
+	log.Infow("the standby global hub is promoted", "name", s.config.Name)
+
+	if replicated {
+		// the events left in the transport of the previous active global hub are lost, request the hubs to resend
+		if err := requestResync(ctx); err != nil {
+			log.Warnw("failed to request the hubs to resync", "error", err)
+		}
+	}
+	return nil
+}
+
+// fencePrimary writes the heartbeat of the promoted global hub into the active database if it's still reachable, so the
+// previous active global hub steps down once it sees the newer promotion, before its failover lease is lost.
+func (s *Standby) fencePrimary(ctx context.Context) {
+	primaryCtx, cancel := context.WithTimeout(ctx, s.config.HeartbeatInterval)
+	defer cancel()
+	err := s.primary.WithContext(primaryCtx).Exec(`INSERT INTO status.global_hub_heartbeats (name, role, heartbeat_at,
+		promoted_at) VALUES (?, ?, LOCALTIMESTAMP, LOCALTIMESTAMP) ON CONFLICT (name) DO UPDATE SET
+		role = EXCLUDED.role, heartbeat_at = EXCLUDED.heartbeat_at, promoted_at = EXCLUDED.promoted_at`,
+		s.config.Name, constants.FailoverRoleActive).Error
+	if err != nil {
+		log.Infow("the promotion isn't written into the active database, it's fenced by the failover lease",
+			"error", err)
+	}
+}
+
+// resetSequences moves the sequences after the replicated rows, since the sequences aren't replicated
+func resetSequences(tx *gorm.DB) error {
+	sequences := []struct {
+		Sequence   string
+		TableName  string
+		ColumnName string
+	}{}
+	err := tx.Raw(`SELECT s.oid::regclass::text AS sequence,
+			quote_ident(n.nspname) || '.' || quote_ident(c.relname) AS table_name,
+			quote_ident(a.attname) AS column_name
+		FROM pg_depend d
+		INNER JOIN pg_class s ON s.oid = d.objid AND s.relkind = 'S'
+		INNER JOIN pg_class c ON c.oid = d.refobjid
+		INNER JOIN pg_namespace n ON n.oid = c.relnamespace
+		INNER JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum = d.refobjsubid
+		WHERE d.classid = 'pg_class'::regclass AND d.refclassid = 'pg_class'::regclass
+			AND d.deptype IN ('a', 'i') AND n.nspname IN ?`, Schemas).Scan(&sequences).Error
+	if err != nil {
+		return err
+	}
+	for _, s := range sequences {
+		err := tx.Exec(fmt.Sprintf("SELECT setval(?, COALESCE((SELECT MAX(%s) FROM %s), 0) + 1, false)",
+			s.ColumnName, s.TableName), s.Sequence).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func requestResync(ctx context.Context) error {
+	hubNames := []string{}
+	err := database.GetGorm().WithContext(ctx).Model(&models.LeafHubHeartbeat{}).
+		Where("status <> ?", hubmanagement.HubInactive).Pluck("leaf_hub_name", &hubNames).Error
+	if err != nil {
+		return err
+	}
+	eventTypes := []string{}
+	for _, eventType := range hubmanagement.ResyncEventTypes {
+		eventTypes = append(eventTypes, string(eventType))
+	}
+	_, err = hubmanagement.RequestResync(ctx, hubNames, eventTypes, "failover")
+	return err
+}
+
+// activate switches the failover role of the MulticlusterGlobalHub to active, so the operator renders the manager as
+// the active one, and switches the transport of the agents to the global hub.
+func (s *Standby) activate(ctx context.Context) error {
+	mghList := &unstructured.UnstructuredList{}
+	mghList.SetGroupVersionKind(mghListGVK)
+	if err := s.client.List(ctx, mghList, client.InNamespace(s.namespace)); err != nil {
+		return fmt.Errorf("failed to list the multiclusterglobalhubs: %w", err)
+	}
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, constants.GHFailoverRoleAnnotation,
+		constants.FailoverRoleActive)
+	for i := range mghList.Items {
+		mgh := &mghList.Items[i]
+		if mgh.GetAnnotations()[constants.GHFailoverRoleAnnotation] == constants.FailoverRoleActive {
+			continue
+		}
+		if err := s.client.Patch(ctx, mgh, client.RawPatch(types.MergePatchType, []byte(patch))); err != nil {
+			return fmt.Errorf("failed to switch the multiclusterglobalhub %s to active: %w", mgh.GetName(), err)
+		}
+		log.Infow("switch the multiclusterglobalhub to active", "name", mgh.GetName())
+	}
+	return nil
+}
@@ -29,7 +29,7 @@ func (m *ClusterMigrationController) cleaning(ctx context.Context,
 	}
 
 	if meta.IsStatusConditionTrue(mcm.Status.Conditions, migrationv1alpha1.ConditionTypeCleaned) ||
-		mcm.Status.Phase != migrationv1alpha1.PhaseCleaning {
+		mcm.Status.Phase != migrationv1alpha1.PhaseCleaning || isWaveCleaned(mcm) {
 		return false, nil
 	}
 
@@ -57,9 +57,9 @@ func (m *ClusterMigrationController) cleaning(ctx context.Context,
 
 	// cleanup the source hub: cleaning or failed state, if registering is executed, cleaning the ready clusters
 	fromHub := mcm.Spec.From
-	cleaningClusters := GetClusterList(string(mcm.UID))
+	cleaningClusters := getMigratingClusters(mcm)
 	if meta.FindStatusCondition(mcm.Status.Conditions, migrationv1alpha1.ConditionTypeRegistered) != nil {
-		successClusters, err := m.GetSuccessClusters(ctx, mcm)
+		successClusters, err := m.getMigratingSuccessClusters(ctx, mcm)
 		if err != nil {
 			log.Errorf("failed to get success clusters: %v", err)
 			return false, err
@@ -162,6 +162,9 @@ func (m *ClusterMigrationController) handleCleaningStatus(ctx context.Context,
 	if condition.Reason != ConditionReasonWaiting {
 		if meta.FindStatusCondition(mcm.Status.Conditions, migrationv1alpha1.ConditionTypeRolledBack) != nil {
 			*nextPhase = migrationv1alpha1.PhaseFailed
+		} else if hasNextWave(mcm) {
+			// keep cleaning until the next wave is started
+			*nextPhase = migrationv1alpha1.PhaseCleaning
 		} else {
 			// Ensure cleaning always ends in Completed phase, regardless of cleaning condition status
 			*nextPhase = migrationv1alpha1.PhaseCompleted
@@ -182,6 +182,12 @@ func (m *ClusterMigrationController) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{}, err
 	}
 
+	// split the clusters into waves if the strategy is specified
+	if err := m.planWaves(ctx, mcm); err != nil {
+		log.Errorf("failed to plan the waves %v", err)
+		return ctrl.Result{}, err
+	}
+
 	// initializing
 	requeue, err = m.initializing(ctx, mcm)
 	if err != nil {
@@ -227,6 +233,15 @@ func (m *ClusterMigrationController) Reconcile(ctx context.Context, req ctrl.Req
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 
+	// waving: start the next wave once the current wave is cleaned
+	requeue, err = m.waving(ctx, mcm)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if requeue {
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -259,6 +274,7 @@ func (m *ClusterMigrationController) sendEventToTargetHub(ctx context.Context,
 		// the timeout in agent part should less than manager part,
 		// the event in agent need time to send event to manager
 		RegisteringTimeoutMinutes: int((registeringTimeout - 2*time.Minute).Minutes()),
+		DryRun:                    migration.Spec.DryRun,
 	}
 
 	// namespace
@@ -32,6 +32,10 @@ func (m *ClusterMigrationController) deploying(ctx context.Context,
 	}
 	log.Infof("start deploying: %s (uid: %s)", mcm.Name, mcm.UID)
 
+	if mcm.Spec.DryRun {
+		return m.dryRunDeploying(ctx, mcm)
+	}
+
 	condition := metav1.Condition{
 		Type:    migrationv1alpha1.ConditionTypeDeployed,
 		Status:  metav1.ConditionFalse,
@@ -43,7 +47,7 @@ func (m *ClusterMigrationController) deploying(ctx context.Context,
 	defer m.handleStatusWithRollback(ctx, mcm, &condition, &nextPhase, getTimeout(migrationv1alpha1.PhaseDeploying))
 
 	fromHub := mcm.Spec.From
-	clusters := GetClusterList(string(mcm.UID))
+	clusters := getMigratingClusters(mcm)
 
 	// 1. source hub: start and wait the confirmation
 	//    Target hub: no need to send events to trigger deploying. This handles target hub restarts where resources may
@@ -0,0 +1,215 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
+)
+
+const (
+	ConditionReasonDryRunInitialized = "DryRunInitialized"
+	ConditionReasonDryRunDeployed    = "DryRunDeployed"
+)
+
+// dryRunInitializing previews the initializing in the target hub:
+//  1. Global Hub: the managedserviceaccount and bootstrap secret are not created
+//  2. Target Hub: report the rbac that would be created or updated for the registration, and the conflicts
+//  3. Source Hub: nothing changes, the klusterletconfig isn't attached to the clusters
+func (m *ClusterMigrationController) dryRunInitializing(ctx context.Context,
+	mcm *migrationv1alpha1.ManagedClusterMigration,
+) (bool, error) {
+	condition := metav1.Condition{
+		Type:    migrationv1alpha1.ConditionTypeInitialized,
+		Status:  metav1.ConditionFalse,
+		Reason:  ConditionReasonWaiting,
+		Message: "Waiting for the target hub to preview the initialization",
+	}
+	nextPhase := migrationv1alpha1.PhaseInitializing
+
+	defer m.handleDryRunStatus(ctx, mcm, &condition, &nextPhase, getTimeout(migrationv1alpha1.PhaseInitializing))
+
+	clusters := GetClusterList(string(mcm.UID))
+	if !GetStarted(string(mcm.GetUID()), mcm.Spec.To, migrationv1alpha1.PhaseInitializing) {
+		if err := m.sendEventToTargetHub(ctx, mcm, migrationv1alpha1.PhaseInitializing, clusters, ""); err != nil {
+			condition.Message = err.Error()
+			condition.Reason = ConditionReasonError
+			return false, err
+		}
+		log.Infof("dry run initializing target hub: %s (uid: %s)", mcm.Spec.To, mcm.UID)
+		SetStarted(string(mcm.GetUID()), mcm.Spec.To, migrationv1alpha1.PhaseInitializing)
+	}
+
+	if errMsg := GetErrorMessage(string(mcm.GetUID()), mcm.Spec.To, migrationv1alpha1.PhaseInitializing); errMsg != "" {
+		condition.Message = fmt.Sprintf("dry run initializing target hub %s with err :%s", mcm.Spec.To, errMsg)
+		condition.Reason = ConditionReasonError
+		return false, nil
+	}
+
+	if !GetFinished(string(mcm.GetUID()), mcm.Spec.To, migrationv1alpha1.PhaseInitializing) {
+		condition.Message = fmt.Sprintf("waiting for target hub %s to preview the initialization", mcm.Spec.To)
+		setRetry(mcm, migrationv1alpha1.PhaseInitializing, migrationv1alpha1.ConditionTypeInitialized, mcm.Spec.To)
+		return true, nil
+	}
+
+	if err := m.updateDryRunReport(ctx, mcm, mcm.Spec.To, migrationv1alpha1.PhaseInitializing); err != nil {
+		return false, err
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = ConditionReasonDryRunInitialized
+	condition.Message = "The initialization of the target hub has been previewed"
+	nextPhase = migrationv1alpha1.PhaseDeploying
+
+	log.Infof("finish dry run initializing: %s (uid: %s)", mcm.Name, mcm.UID)
+	return false, nil
+}
+
+// dryRunDeploying previews the deploying:
+//  1. Source Hub: collect the resources of the clusters, and send them to the target hub with the dry run flag
+//  2. Target Hub: report the resources that would be applied, and the conflicts with the existing resources
+func (m *ClusterMigrationController) dryRunDeploying(ctx context.Context,
+	mcm *migrationv1alpha1.ManagedClusterMigration,
+) (bool, error) {
+	condition := metav1.Condition{
+		Type:    migrationv1alpha1.ConditionTypeDeployed,
+		Status:  metav1.ConditionFalse,
+		Reason:  ConditionReasonWaiting,
+		Message: "Waiting for the target hub to preview the resources",
+	}
+	nextPhase := migrationv1alpha1.PhaseDeploying
+
+	defer m.handleDryRunStatus(ctx, mcm, &condition, &nextPhase, getTimeout(migrationv1alpha1.PhaseDeploying))
+
+	fromHub := mcm.Spec.From
+	clusters := GetClusterList(string(mcm.UID))
+
+	if !GetStarted(string(mcm.GetUID()), fromHub, migrationv1alpha1.PhaseDeploying) ||
+		!GetStarted(string(mcm.GetUID()), mcm.Spec.To, migrationv1alpha1.PhaseDeploying) {
+		err := m.sendEventToSourceHub(ctx, fromHub, mcm, migrationv1alpha1.PhaseDeploying, clusters, nil, "")
+		if err != nil {
+			condition.Message = err.Error()
+			condition.Reason = ConditionReasonError
+			return false, err
+		}
+		log.Infof("dry run deploying to source hub(%s): %s (uid: %s)", fromHub, mcm.Name, mcm.UID)
+		SetStarted(string(mcm.GetUID()), fromHub, migrationv1alpha1.PhaseDeploying)
+		SetStarted(string(mcm.GetUID()), mcm.Spec.To, migrationv1alpha1.PhaseDeploying)
+	}
+
+	for _, hub := range []string{fromHub, mcm.Spec.To} {
+		if errMsg := GetErrorMessage(string(mcm.GetUID()), hub, migrationv1alpha1.PhaseDeploying); errMsg != "" {
+			condition.Message = fmt.Sprintf("dry run deploying hub %s error: %s", hub, errMsg)
+			condition.Reason = ConditionReasonError
+			return false, nil
+		}
+		if !GetFinished(string(mcm.GetUID()), hub, migrationv1alpha1.PhaseDeploying) {
+			condition.Message = fmt.Sprintf("waiting for resources to be previewed in the hub %s", hub)
+			// the source hub resends the resources to the target hub on retry
+			setRetry(mcm, migrationv1alpha1.PhaseDeploying, migrationv1alpha1.ConditionTypeDeployed, fromHub)
+			return true, nil
+		}
+	}
+
+	if err := m.updateDryRunReport(ctx, mcm, mcm.Spec.To, migrationv1alpha1.PhaseDeploying); err != nil {
+		return false, err
+	}
+
+	condition.Status = metav1.ConditionTrue
+	condition.Reason = ConditionReasonDryRunDeployed
+	condition.Message = "The resources have been previewed in the target hub, get the details in the dry run report"
+	nextPhase = migrationv1alpha1.PhaseCompleted
+
+	log.Infof("finish dry run deploying: %s (uid: %s)", mcm.Name, mcm.UID)
+	return false, nil
+}
+
+// handleDryRunStatus updates the condition and phase, the dry run is failed rather than rollbacked on error or
+// timeout, since nothing is changed in the hubs
+func (m *ClusterMigrationController) handleDryRunStatus(ctx context.Context,
+	mcm *migrationv1alpha1.ManagedClusterMigration,
+	condition *metav1.Condition,
+	nextPhase *string,
+	stageTimeout time.Duration,
+) {
+	if updateConditionWithTimeout(mcm, condition, stageTimeout, "") {
+		*nextPhase = migrationv1alpha1.PhaseFailed
+	}
+
+	if condition.Reason == ConditionReasonError {
+		*nextPhase = migrationv1alpha1.PhaseFailed
+	}
+
+	err := m.UpdateStatusWithRetry(ctx, mcm, *condition, *nextPhase)
+	if err != nil {
+		log.Errorf("failed to update the %s condition: %v", condition.Type, err)
+	}
+}
+
+// updateDryRunReport merges the preview reported by the hub for the stage into the status of the migration
+func (m *ClusterMigrationController) updateDryRunReport(ctx context.Context,
+	mcm *migrationv1alpha1.ManagedClusterMigration, hub, phase string,
+) error {
+	report := GetDryRunReport(string(mcm.GetUID()), hub, phase)
+	if report == nil {
+		return nil
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := m.Get(ctx, client.ObjectKeyFromObject(mcm), mcm); err != nil {
+			return err
+		}
+		merged := mergeDryRunReport(mcm.Status.DryRunReport, report)
+		if equality.Semantic.DeepEqual(merged, mcm.Status.DryRunReport) {
+			return nil
+		}
+		mcm.Status.DryRunReport = merged
+		return m.Status().Update(ctx, mcm)
+	})
+}
+
+// mergeDryRunReport returns the union of the reports, the items are deduplicated so the merge is idempotent
+func mergeDryRunReport(current, report *migrationv1alpha1.DryRunReport) *migrationv1alpha1.DryRunReport {
+	merged := &migrationv1alpha1.DryRunReport{}
+	if current != nil {
+		merged = current.DeepCopy()
+	}
+	merged.RBAC = appendMissing(merged.RBAC, report.RBAC...)
+	merged.Conflicts = appendMissing(merged.Conflicts, report.Conflicts...)
+	for _, clusterResources := range report.Resources {
+		found := false
+		for i := range merged.Resources {
+			if merged.Resources[i].ClusterName == clusterResources.ClusterName {
+				merged.Resources[i].Resources = appendMissing(merged.Resources[i].Resources,
+					clusterResources.Resources...)
+				found = true
+				break
+			}
+		}
+		if !found {
+			merged.Resources = append(merged.Resources, *clusterResources.DeepCopy())
+		}
+	}
+	return merged
+}
+
+func appendMissing(items []string, newItems ...string) []string {
+	for _, newItem := range newItems {
+		found := false
+		for _, item := range items {
+			if item == newItem {
+				found = true
+				break
+			}
+		}
+		if !found {
+			items = append(items, newItem)
+		}
+	}
+	return items
+}
@@ -0,0 +1,182 @@
+package migration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+func TestDryRun(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = migrationv1alpha1.AddToScheme(scheme)
+
+	tests := []struct {
+		name            string
+		phase           string
+		setupState      func(migrationID string)
+		expectedRequeue bool
+		expectedPhase   string
+		expectedCond    string
+		expectedReason  string
+		expectedReport  *migrationv1alpha1.DryRunReport
+	}{
+		{
+			name:  "Should move to deploying once the target hub previewed the initialization",
+			phase: migrationv1alpha1.PhaseInitializing,
+			setupState: func(migrationID string) {
+				SetStarted(migrationID, "target-hub", migrationv1alpha1.PhaseInitializing)
+				SetDryRunReport(migrationID, "target-hub", migrationv1alpha1.PhaseInitializing,
+					&migrationv1alpha1.DryRunReport{RBAC: []string{"create ClusterRole/global-hub-migration-test-sar"}})
+				SetFinished(migrationID, "target-hub", migrationv1alpha1.PhaseInitializing)
+			},
+			expectedPhase:  migrationv1alpha1.PhaseDeploying,
+			expectedCond:   migrationv1alpha1.ConditionTypeInitialized,
+			expectedReason: ConditionReasonDryRunInitialized,
+			expectedReport: &migrationv1alpha1.DryRunReport{
+				RBAC: []string{"create ClusterRole/global-hub-migration-test-sar"},
+			},
+		},
+		{
+			name:  "Should wait for the target hub to preview the resources",
+			phase: migrationv1alpha1.PhaseDeploying,
+			setupState: func(migrationID string) {
+				SetStarted(migrationID, "source-hub", migrationv1alpha1.PhaseDeploying)
+				SetStarted(migrationID, "target-hub", migrationv1alpha1.PhaseDeploying)
+				SetFinished(migrationID, "source-hub", migrationv1alpha1.PhaseDeploying)
+			},
+			expectedRequeue: true,
+			expectedPhase:   migrationv1alpha1.PhaseDeploying,
+			expectedCond:    migrationv1alpha1.ConditionTypeDeployed,
+			expectedReason:  ConditionReasonWaiting,
+		},
+		{
+			name:  "Should complete once the target hub previewed the resources",
+			phase: migrationv1alpha1.PhaseDeploying,
+			setupState: func(migrationID string) {
+				SetStarted(migrationID, "source-hub", migrationv1alpha1.PhaseDeploying)
+				SetStarted(migrationID, "target-hub", migrationv1alpha1.PhaseDeploying)
+				SetFinished(migrationID, "source-hub", migrationv1alpha1.PhaseDeploying)
+				SetDryRunReport(migrationID, "target-hub", migrationv1alpha1.PhaseDeploying,
+					&migrationv1alpha1.DryRunReport{
+						Resources: []migrationv1alpha1.DryRunClusterResources{
+							{ClusterName: "cluster1", Resources: []string{"ManagedCluster//cluster1"}},
+						},
+						Conflicts: []string{"ManagedCluster//cluster1 already exists in the target hub"},
+					})
+				SetFinished(migrationID, "target-hub", migrationv1alpha1.PhaseDeploying)
+			},
+			expectedPhase:  migrationv1alpha1.PhaseCompleted,
+			expectedCond:   migrationv1alpha1.ConditionTypeDeployed,
+			expectedReason: ConditionReasonDryRunDeployed,
+			expectedReport: &migrationv1alpha1.DryRunReport{
+				Resources: []migrationv1alpha1.DryRunClusterResources{
+					{ClusterName: "cluster1", Resources: []string{"ManagedCluster//cluster1"}},
+				},
+				Conflicts: []string{"ManagedCluster//cluster1 already exists in the target hub"},
+			},
+		},
+		{
+			name:  "Should fail rather than rollback on error",
+			phase: migrationv1alpha1.PhaseDeploying,
+			setupState: func(migrationID string) {
+				SetStarted(migrationID, "source-hub", migrationv1alpha1.PhaseDeploying)
+				SetStarted(migrationID, "target-hub", migrationv1alpha1.PhaseDeploying)
+				SetErrorMessage(migrationID, "source-hub", migrationv1alpha1.PhaseDeploying, "failed to list resources")
+			},
+			expectedPhase:  migrationv1alpha1.PhaseFailed,
+			expectedCond:   migrationv1alpha1.ConditionTypeDeployed,
+			expectedReason: ConditionReasonError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mcm := &migrationv1alpha1.ManagedClusterMigration{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test",
+					Namespace: utils.GetDefaultNamespace(),
+					UID:       types.UID(tt.name),
+				},
+				Spec: migrationv1alpha1.ManagedClusterMigrationSpec{
+					From:                    "source-hub",
+					To:                      "target-hub",
+					IncludedManagedClusters: []string{"cluster1"},
+					DryRun:                  true,
+				},
+				Status: migrationv1alpha1.ManagedClusterMigrationStatus{
+					Phase: tt.phase,
+				},
+			}
+			migrationID := string(mcm.GetUID())
+			AddMigrationStatus(migrationID)
+			defer RemoveMigrationStatus(migrationID)
+			tt.setupState(migrationID)
+
+			controller := &ClusterMigrationController{
+				Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(mcm).
+					WithStatusSubresource(&migrationv1alpha1.ManagedClusterMigration{}).Build(),
+				Producer: &MockProducer{},
+				Scheme:   scheme,
+			}
+
+			var requeue bool
+			var err error
+			if tt.phase == migrationv1alpha1.PhaseInitializing {
+				requeue, err = controller.initializing(context.TODO(), mcm)
+			} else {
+				requeue, err = controller.deploying(context.TODO(), mcm)
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectedRequeue, requeue)
+			assert.Equal(t, tt.expectedPhase, mcm.Status.Phase)
+
+			cond := meta.FindStatusCondition(mcm.Status.Conditions, tt.expectedCond)
+			require.NotNil(t, cond)
+			assert.Equal(t, tt.expectedReason, cond.Reason)
+			assert.Equal(t, tt.expectedReport, mcm.Status.DryRunReport)
+		})
+	}
+}
+
+func TestMergeDryRunReport(t *testing.T) {
+	current := &migrationv1alpha1.DryRunReport{
+		Resources: []migrationv1alpha1.DryRunClusterResources{
+			{ClusterName: "cluster1", Resources: []string{"ManagedCluster//cluster1"}},
+		},
+		Conflicts: []string{"managed cluster cluster1 already exists in target hub"},
+	}
+	report := &migrationv1alpha1.DryRunReport{
+		Resources: []migrationv1alpha1.DryRunClusterResources{
+			{ClusterName: "cluster1", Resources: []string{"ManagedCluster//cluster1", "Secret/cluster1/admin"}},
+			{ClusterName: "cluster2", Resources: []string{"ManagedCluster//cluster2"}},
+		},
+		RBAC:      []string{"create ClusterRoleBinding/global-hub-migration-test-registration"},
+		Conflicts: []string{"managed cluster cluster1 already exists in target hub"},
+	}
+
+	merged := mergeDryRunReport(current, report)
+	expected := &migrationv1alpha1.DryRunReport{
+		Resources: []migrationv1alpha1.DryRunClusterResources{
+			{ClusterName: "cluster1", Resources: []string{"ManagedCluster//cluster1", "Secret/cluster1/admin"}},
+			{ClusterName: "cluster2", Resources: []string{"ManagedCluster//cluster2"}},
+		},
+		RBAC:      []string{"create ClusterRoleBinding/global-hub-migration-test-registration"},
+		Conflicts: []string{"managed cluster cluster1 already exists in target hub"},
+	}
+	assert.Equal(t, expected, merged)
+	// the merge is idempotent, and the current report isn't changed
+	assert.Equal(t, expected, mergeDryRunReport(merged, report))
+	assert.Len(t, current.Resources[0].Resources, 1)
+}
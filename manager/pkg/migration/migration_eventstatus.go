@@ -5,6 +5,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
 )
 
 var (
@@ -23,6 +25,7 @@ type StageState struct {
 	error         string
 	clusterErrors map[string]string // cluster name -> error message
 	lastStartTime time.Time         // the last start time of the stage
+	dryRunReport  *migrationv1alpha1.DryRunReport
 }
 
 // AddMigrationStatus init the migration status for the migrationId
@@ -72,6 +75,25 @@ func RemoveMigrationStatus(migrationId string) {
 	log.Infof("clean up migration status for migrationId: %s", migrationId)
 }
 
+// ResetStageStates clears the stage states of all the hubs for the migrationId, the cluster list is kept. It's
+// invoked when the next wave is started, so that the stages are executed again for the clusters of the wave
+func ResetStageStates(migrationId string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if status, ok := migrationStatuses[migrationId]; ok {
+		status.HubState = make(map[string]*StageState)
+	}
+}
+
+// ResetStageState clears the state of the stage for the hub, so that the stage is executed again
+func ResetStageState(migrationId, hub, phase string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if status, ok := migrationStatuses[migrationId]; ok {
+		delete(status.HubState, hubPhaseKey(hub, phase))
+	}
+}
+
 func hubPhaseKey(hub, phase string) string {
 	return fmt.Sprintf("%s-%s", hub, phase)
 }
@@ -131,6 +153,15 @@ func SetClusterErrorMessage(migrationId string, hub, phase string, clusterErrors
 	}
 }
 
+// SetDryRunReport sets the preview reported by the hub for the given stage of the dry run migration
+func SetDryRunReport(migrationId, hub, phase string, report *migrationv1alpha1.DryRunReport) {
+	mu.Lock()
+	defer mu.Unlock()
+	if p := getStageState(migrationId, hub, phase); p != nil {
+		p.dryRunReport = report
+	}
+}
+
 func SetErrorMessage(migrationId, hub, phase, errMessage string) {
 	mu.Lock()
 	defer mu.Unlock()
@@ -168,6 +199,16 @@ func GetErrorMessage(migrationId, hub, phase string) string {
 	return ""
 }
 
+// GetDryRunReport returns the preview reported by the hub for the given stage of the dry run migration
+func GetDryRunReport(migrationId, hub, phase string) *migrationv1alpha1.DryRunReport {
+	mu.RLock()
+	defer mu.RUnlock()
+	if p := getStageState(migrationId, hub, phase); p != nil {
+		return p.dryRunReport
+	}
+	return nil
+}
+
 // GetClusterList returns the managed clusters list for the given migration stage
 func GetClusterList(migrationId string) []string {
 	mu.RLock()
@@ -50,6 +50,10 @@ func (m *ClusterMigrationController) initializing(ctx context.Context,
 	}
 	log.Infof("start initializing: %s (uid: %s)", mcm.Name, mcm.UID)
 
+	if mcm.Spec.DryRun {
+		return m.dryRunInitializing(ctx, mcm)
+	}
+
 	condition := metav1.Condition{
 		Type:    migrationv1alpha1.ConditionTypeInitialized,
 		Status:  metav1.ConditionFalse,
@@ -100,7 +104,7 @@ func (m *ClusterMigrationController) initializing(ctx context.Context,
 
 	// 3. Send event to Source Hub
 	fromHub := mcm.Spec.From
-	clusters := GetClusterList(string(mcm.UID))
+	clusters := getMigratingClusters(mcm)
 
 	if !GetStarted(string(mcm.GetUID()), fromHub, migrationv1alpha1.PhaseInitializing) {
 		err := m.sendEventToSourceHub(ctx, fromHub, mcm, migrationv1alpha1.PhaseInitializing, clusters,
@@ -196,6 +200,7 @@ func (m *ClusterMigrationController) sendEventToSourceHub(ctx context.Context, f
 		// the timeout in agent part should less than manager part,
 		// the event in agent need time to send event to manager
 		RollbackingTimeoutMinutes: int((rollbackingTimeout - 2*time.Minute).Minutes()),
+		DryRun:                    migration.Spec.DryRun,
 	}
 
 	payloadBytes, err := json.Marshal(managedClusterMigrationFromEvent)
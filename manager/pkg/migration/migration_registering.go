@@ -44,7 +44,7 @@ func (m *ClusterMigrationController) registering(ctx context.Context,
 	defer m.handleStatusWithRollback(ctx, mcm, &condition, &nextPhase, getTimeout(migrationv1alpha1.PhaseRegistering))
 
 	fromHub := mcm.Spec.From
-	clusters := GetClusterList(string(mcm.UID))
+	clusters := getRegisteringClusters(mcm)
 
 	if !GetStarted(string(mcm.GetUID()), fromHub, migrationv1alpha1.PhaseRegistering) {
 		// notify the source hub to start registering
@@ -90,11 +90,16 @@ func (m *ClusterMigrationController) registering(ctx context.Context,
 		condition.Message = fmt.Sprintf("registering to hub %s error: %s", mcm.Spec.To, errMessage)
 		condition.Reason = ConditionReasonError
 
-		registeringReadyClusters := GetReadyClusters(string(mcm.UID), mcm.Spec.To, migrationv1alpha1.PhaseRegistering)
-		if err := m.UpdateSuccessClustersToConfigMap(ctx, mcm, registeringReadyClusters); err != nil {
+		registeredClusters := getRegisteredClusters(mcm, clusters)
+		if err := m.UpdateSuccessClustersToConfigMap(ctx, mcm, registeredClusters); err != nil {
 			log.Errorf("failed to store clusters to ConfigMap: %w", err)
 			return false, err
 		}
+		if currentWave(mcm) != nil {
+			if err := m.registerWaveClusters(ctx, mcm, intersectClusters(clusters, registeredClusters)); err != nil {
+				return false, err
+			}
+		}
 		return false, nil
 	}
 
@@ -105,12 +110,26 @@ func (m *ClusterMigrationController) registering(ctx context.Context,
 		return true, nil
 	}
 
-	registeringReadyClusters := GetReadyClusters(string(mcm.UID), mcm.Spec.To, migrationv1alpha1.PhaseRegistering)
-	if err := m.UpdateSuccessClustersToConfigMap(ctx, mcm, registeringReadyClusters); err != nil {
+	registeredClusters := getRegisteredClusters(mcm, clusters)
+	if err := m.UpdateSuccessClustersToConfigMap(ctx, mcm, registeredClusters); err != nil {
 		log.Errorf("failed to store clusters to ConfigMap: %w", err)
 		return false, err
 	}
 
+	// register the next clusters of the wave, limited by the max concurrency
+	if currentWave(mcm) != nil {
+		if err := m.registerWaveClusters(ctx, mcm, clusters); err != nil {
+			return false, err
+		}
+	}
+	if wave := currentWave(mcm); wave != nil && len(wave.RegisteredClusters) < len(wave.Clusters) {
+		ResetStageState(string(mcm.GetUID()), fromHub, migrationv1alpha1.PhaseRegistering)
+		ResetStageState(string(mcm.GetUID()), mcm.Spec.To, migrationv1alpha1.PhaseRegistering)
+		condition.Message = waveProgressMessage(mcm)
+		log.Info(condition.Message)
+		return true, nil
+	}
+
 	condition.Status = metav1.ConditionTrue
 	condition.Reason = ConditionReasonClusterRegistered
 	condition.Message = "All migrated clusters have been successfully registered"
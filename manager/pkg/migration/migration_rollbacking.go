@@ -48,7 +48,7 @@ func (m *ClusterMigrationController) rollbacking(ctx context.Context,
 
 	// 1. Send rollback events to source hubs to restore original configurations
 	fromHub := mcm.Spec.From
-	rollbackingClusters := GetClusterList(string(mcm.UID))
+	rollbackingClusters := getMigratingClusters(mcm)
 
 	if meta.FindStatusCondition(mcm.Status.Conditions, migrationv1alpha1.ConditionTypeRegistered) != nil {
 		failureClusters, err := m.GetFailureClusters(ctx, mcm)
@@ -168,7 +168,7 @@ func (m *ClusterMigrationController) handleRollbackStatus(ctx context.Context,
 
 		// if registering, cleaning the ready clusters
 		if failedStage == migrationv1alpha1.PhaseRegistering {
-			successClusters, err := m.GetSuccessClusters(ctx, mcm)
+			successClusters, err := m.getMigratingSuccessClusters(ctx, mcm)
 			if err != nil {
 				log.Errorf("failed to get success clusters: %v", err)
 				// if the condition if true, update the error message into the condition
@@ -93,6 +93,7 @@ func (m *ClusterMigrationController) UpdateStatusWithRetry(ctx context.Context,
 
 		if meta.SetStatusCondition(&mcm.Status.Conditions, condition) || mcm.Status.Phase != phase {
 			mcm.Status.Phase = phase
+			updateWavePhase(mcm, phase)
 
 			// reason and status
 			log.Infof("updating phase(%s), condition(%s): %s - %s", phase, condition.Type, condition.Reason, condition.Status)
@@ -215,7 +216,7 @@ func (m *ClusterMigrationController) GetSuccessClusters(ctx context.Context,
 }
 
 // GetFailureClusters retrieves the list of failure clusters. It only can be invoked after the registering
-// phase is executed. And it infer the failure clusters from the all clusters and the success clusters.
+// phase is executed. And it infer the failure clusters from the migrating clusters and the success clusters.
 // Note: Not from the configmap directly. Cause the failed clusters only be updated after the failed stage!
 func (m *ClusterMigrationController) GetFailureClusters(ctx context.Context,
 	mcm *migrationv1alpha1.ManagedClusterMigration,
@@ -224,7 +225,7 @@ func (m *ClusterMigrationController) GetFailureClusters(ctx context.Context,
 	if err != nil {
 		return nil, err
 	}
-	allClusters := GetClusterList(string(mcm.UID))
+	allClusters := getMigratingClusters(mcm)
 	if len(successClusters) == 0 {
 		return allClusters, nil
 	}
@@ -162,6 +162,13 @@ func (m *ClusterMigrationController) validateMigrationClusters(
 		return true, nil
 	}
 
+	// the clusters existing in the target hub are reported as the conflicts of the dry run
+	if mcm.Spec.DryRun {
+		if err := m.updateDryRunReport(ctx, mcm, mcm.Spec.To, migrationv1alpha1.PhaseValidating); err != nil {
+			return false, err
+		}
+	}
+
 	return false, nil
 }
 
@@ -0,0 +1,292 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"open-cluster-management.io/managed-serviceaccount/apis/authentication/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+const (
+	WavePhasePending = "Pending"
+)
+
+// planWaves splits the migrating clusters into waves by the batch size of the strategy, it's invoked once the
+// migration is validated, and the first wave is started
+func (m *ClusterMigrationController) planWaves(ctx context.Context,
+	mcm *migrationv1alpha1.ManagedClusterMigration,
+) error {
+	if mcm.Spec.Strategy == nil || mcm.Spec.DryRun || len(mcm.Status.Waves) > 0 ||
+		mcm.Status.Phase != migrationv1alpha1.PhaseInitializing {
+		return nil
+	}
+
+	clusters := GetClusterList(string(mcm.UID))
+	batchSize := mcm.Spec.Strategy.BatchSize
+	if batchSize <= 0 {
+		batchSize = len(clusters)
+	}
+
+	waves := []migrationv1alpha1.MigrationWaveStatus{}
+	for start := 0; start < len(clusters); start += batchSize {
+		end := min(start+batchSize, len(clusters))
+		waves = append(waves, migrationv1alpha1.MigrationWaveStatus{
+			Phase:    WavePhasePending,
+			Clusters: clusters[start:end],
+		})
+	}
+	if len(waves) == 0 {
+		return nil
+	}
+	waves[0].Phase = migrationv1alpha1.PhaseInitializing
+	waves[0].StartTime = &metav1.Time{Time: time.Now()}
+
+	log.Infof("planned %d waves for the migration %s with the batch size %d", len(waves), mcm.Name, batchSize)
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := m.Get(ctx, client.ObjectKeyFromObject(mcm), mcm); err != nil {
+			return err
+		}
+		if len(mcm.Status.Waves) > 0 {
+			return nil
+		}
+		mcm.Status.CurrentWave = 0
+		mcm.Status.Waves = waves
+		return m.Status().Update(ctx, mcm)
+	})
+}
+
+// waving starts the next wave once the current wave is cleaned up and the pause between waves is elapsed. It resets
+// the stage conditions and states, so that the next wave goes through the initializing, deploying, registering and
+// cleaning stages again
+func (m *ClusterMigrationController) waving(ctx context.Context,
+	mcm *migrationv1alpha1.ManagedClusterMigration,
+) (bool, error) {
+	if mcm.DeletionTimestamp != nil {
+		return false, nil
+	}
+
+	if mcm.Status.Phase != migrationv1alpha1.PhaseCleaning || !isWaveCleaned(mcm) || !hasNextWave(mcm) {
+		return false, nil
+	}
+
+	wave := currentWave(mcm)
+	if wave.Phase != migrationv1alpha1.PhaseCompleted {
+		if err := m.updateWavesWithRetry(ctx, mcm, func(mcm *migrationv1alpha1.ManagedClusterMigration) {
+			wave := currentWave(mcm)
+			wave.Phase = migrationv1alpha1.PhaseCompleted
+			wave.CompletionTime = &metav1.Time{Time: time.Now()}
+		}); err != nil {
+			return false, err
+		}
+		log.Infof("wave %d is completed: %s (uid: %s)", mcm.Status.CurrentWave, mcm.Name, mcm.UID)
+		wave = currentWave(mcm)
+	}
+
+	if pause := mcm.Spec.Strategy.PauseBetweenWaves; pause != nil && wave.CompletionTime != nil &&
+		time.Since(wave.CompletionTime.Time) < pause.Duration {
+		log.Debugf("pausing before the wave %d: %s (uid: %s)", mcm.Status.CurrentWave+1, mcm.Name, mcm.UID)
+		return true, nil
+	}
+
+	// the managedserviceaccount of the previous wave is deleted in the cleaning stage, wait for it and the token to be
+	// removed, otherwise the next wave might get the revoked bootstrap kubeconfig
+	removed, err := m.isManagedServiceAccountRemoved(ctx, mcm)
+	if err != nil {
+		return false, err
+	}
+	if !removed {
+		log.Infof("waiting for the managedserviceaccount %s/%s to be removed", mcm.Spec.To, mcm.Name)
+		return true, nil
+	}
+
+	ResetStageStates(string(mcm.GetUID()))
+	err = m.updateWavesWithRetry(ctx, mcm, func(mcm *migrationv1alpha1.ManagedClusterMigration) {
+		for _, condType := range []string{
+			migrationv1alpha1.ConditionTypeInitialized,
+			migrationv1alpha1.ConditionTypeDeployed,
+			migrationv1alpha1.ConditionTypeRegistered,
+			migrationv1alpha1.ConditionTypeCleaned,
+		} {
+			meta.RemoveStatusCondition(&mcm.Status.Conditions, condType)
+		}
+		mcm.Status.CurrentWave++
+		wave := currentWave(mcm)
+		wave.Phase = migrationv1alpha1.PhaseInitializing
+		wave.StartTime = &metav1.Time{Time: time.Now()}
+		mcm.Status.Phase = migrationv1alpha1.PhaseInitializing
+	})
+	if err != nil {
+		return false, err
+	}
+
+	log.Infof("start wave %d/%d: %s (uid: %s)", mcm.Status.CurrentWave+1, len(mcm.Status.Waves), mcm.Name, mcm.UID)
+	return true, nil
+}
+
+// updateWavesWithRetry updates the status of the migration by the mutate function with retry on conflict
+func (m *ClusterMigrationController) updateWavesWithRetry(ctx context.Context,
+	mcm *migrationv1alpha1.ManagedClusterMigration, mutate func(*migrationv1alpha1.ManagedClusterMigration),
+) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := m.Get(ctx, client.ObjectKeyFromObject(mcm), mcm); err != nil {
+			return err
+		}
+		mutate(mcm)
+		return m.Status().Update(ctx, mcm)
+	})
+}
+
+func (m *ClusterMigrationController) isManagedServiceAccountRemoved(ctx context.Context,
+	mcm *migrationv1alpha1.ManagedClusterMigration,
+) (bool, error) {
+	key := types.NamespacedName{Name: mcm.Name, Namespace: mcm.Spec.To}
+	for _, obj := range []client.Object{&v1beta1.ManagedServiceAccount{}, &corev1.Secret{}} {
+		err := m.Get(ctx, key, obj)
+		if err == nil {
+			return false, nil
+		}
+		if !apierrors.IsNotFound(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// registerWaveClusters records the clusters registered in the current wave, and removes the registered condition, so
+// that the next clusters of the wave start registering with a new timeout
+func (m *ClusterMigrationController) registerWaveClusters(ctx context.Context,
+	mcm *migrationv1alpha1.ManagedClusterMigration, clusters []string,
+) error {
+	return m.updateWavesWithRetry(ctx, mcm, func(mcm *migrationv1alpha1.ManagedClusterMigration) {
+		wave := currentWave(mcm)
+		for _, cluster := range clusters {
+			if !utils.ContainsString(wave.RegisteredClusters, cluster) {
+				wave.RegisteredClusters = append(wave.RegisteredClusters, cluster)
+			}
+		}
+		meta.RemoveStatusCondition(&mcm.Status.Conditions, migrationv1alpha1.ConditionTypeRegistered)
+	})
+}
+
+// currentWave returns the wave in progress, it returns nil if the migration isn't migrated in waves
+func currentWave(mcm *migrationv1alpha1.ManagedClusterMigration) *migrationv1alpha1.MigrationWaveStatus {
+	if mcm.Status.CurrentWave < 0 || mcm.Status.CurrentWave >= len(mcm.Status.Waves) {
+		return nil
+	}
+	return &mcm.Status.Waves[mcm.Status.CurrentWave]
+}
+
+func hasNextWave(mcm *migrationv1alpha1.ManagedClusterMigration) bool {
+	return len(mcm.Status.Waves) > 0 && mcm.Status.CurrentWave < len(mcm.Status.Waves)-1
+}
+
+// isWaveCleaned returns true if the cleaning of the current wave is finished, whether it's successful or with warnings
+func isWaveCleaned(mcm *migrationv1alpha1.ManagedClusterMigration) bool {
+	if len(mcm.Status.Waves) == 0 ||
+		meta.FindStatusCondition(mcm.Status.Conditions, migrationv1alpha1.ConditionTypeRolledBack) != nil {
+		return false
+	}
+	cond := meta.FindStatusCondition(mcm.Status.Conditions, migrationv1alpha1.ConditionTypeCleaned)
+	return cond != nil && cond.Reason != ConditionReasonWaiting
+}
+
+// updateWavePhase makes the current wave follow the phase of the migration
+func updateWavePhase(mcm *migrationv1alpha1.ManagedClusterMigration, phase string) {
+	wave := currentWave(mcm)
+	if wave == nil {
+		return
+	}
+	wave.Phase = phase
+	if phase == migrationv1alpha1.PhaseCompleted || phase == migrationv1alpha1.PhaseFailed {
+		wave.CompletionTime = &metav1.Time{Time: time.Now()}
+	}
+}
+
+// getMigratingClusters returns the clusters migrating in the current wave, or all the clusters of the migration if
+// it isn't migrated in waves
+func getMigratingClusters(mcm *migrationv1alpha1.ManagedClusterMigration) []string {
+	if wave := currentWave(mcm); wave != nil {
+		return wave.Clusters
+	}
+	return GetClusterList(string(mcm.UID))
+}
+
+// getRegisteringClusters returns the clusters to register into the target hub at the same time. In a wave, they're
+// the next clusters of the wave limited by the max concurrency of the strategy
+func getRegisteringClusters(mcm *migrationv1alpha1.ManagedClusterMigration) []string {
+	wave := currentWave(mcm)
+	if wave == nil || mcm.Spec.Strategy == nil {
+		return getMigratingClusters(mcm)
+	}
+
+	pendingClusters := []string{}
+	for _, cluster := range wave.Clusters {
+		if !utils.ContainsString(wave.RegisteredClusters, cluster) {
+			pendingClusters = append(pendingClusters, cluster)
+		}
+	}
+	if concurrency := mcm.Spec.Strategy.MaxConcurrency; concurrency > 0 && concurrency < len(pendingClusters) {
+		return pendingClusters[:concurrency]
+	}
+	return pendingClusters
+}
+
+// getRegisteredClusters returns the clusters registered into the target hub: the clusters registered in the waves,
+// and the clusters which are registering without errors
+func getRegisteredClusters(mcm *migrationv1alpha1.ManagedClusterMigration, registeringClusters []string) []string {
+	registeredClusters := []string{}
+	for _, wave := range mcm.Status.Waves {
+		registeredClusters = append(registeredClusters, wave.RegisteredClusters...)
+	}
+	clusterErrors := GetClusterErrors(string(mcm.GetUID()), mcm.Spec.To, migrationv1alpha1.PhaseRegistering)
+	for _, cluster := range registeringClusters {
+		if _, found := clusterErrors[cluster]; !found && !utils.ContainsString(registeredClusters, cluster) {
+			registeredClusters = append(registeredClusters, cluster)
+		}
+	}
+	return registeredClusters
+}
+
+// getMigratingSuccessClusters returns the success clusters of the current wave, the clusters migrated in the
+// previous waves are excluded
+func (m *ClusterMigrationController) getMigratingSuccessClusters(ctx context.Context,
+	mcm *migrationv1alpha1.ManagedClusterMigration,
+) ([]string, error) {
+	successClusters, err := m.GetSuccessClusters(ctx, mcm)
+	if err != nil || len(mcm.Status.Waves) == 0 {
+		return successClusters, err
+	}
+	return intersectClusters(successClusters, getMigratingClusters(mcm)), nil
+}
+
+// intersectClusters returns the clusters which are also in the included clusters
+func intersectClusters(clusters, includedClusters []string) []string {
+	result := []string{}
+	for _, cluster := range clusters {
+		if utils.ContainsString(includedClusters, cluster) {
+			result = append(result, cluster)
+		}
+	}
+	return result
+}
+
+// waveProgressMessage describes the registering progress of the current wave
+func waveProgressMessage(mcm *migrationv1alpha1.ManagedClusterMigration) string {
+	wave := currentWave(mcm)
+	if wave == nil {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d clusters of the wave %d/%d have been registered, registering the next clusters",
+		len(wave.RegisteredClusters), len(wave.Clusters), mcm.Status.CurrentWave+1, len(mcm.Status.Waves))
+}
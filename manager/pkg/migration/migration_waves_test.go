@@ -0,0 +1,218 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"open-cluster-management.io/managed-serviceaccount/apis/authentication/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+func newWavesController(t *testing.T, mcm *migrationv1alpha1.ManagedClusterMigration) *ClusterMigrationController {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = migrationv1alpha1.AddToScheme(scheme)
+	_ = v1beta1.AddToScheme(scheme)
+	return &ClusterMigrationController{
+		Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(mcm).
+			WithStatusSubresource(&migrationv1alpha1.ManagedClusterMigration{}).Build(),
+		Producer: &MockProducer{},
+		Scheme:   scheme,
+	}
+}
+
+func newWavesMigration(uid string, strategy *migrationv1alpha1.MigrationStrategy,
+	status migrationv1alpha1.ManagedClusterMigrationStatus,
+) *migrationv1alpha1.ManagedClusterMigration {
+	return &migrationv1alpha1.ManagedClusterMigration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-migration",
+			Namespace: utils.GetDefaultNamespace(),
+			UID:       types.UID(uid),
+		},
+		Spec: migrationv1alpha1.ManagedClusterMigrationSpec{
+			From:                    "source-hub",
+			To:                      "target-hub",
+			IncludedManagedClusters: []string{"cluster1", "cluster2", "cluster3", "cluster4", "cluster5"},
+			Strategy:                strategy,
+		},
+		Status: status,
+	}
+}
+
+func TestPlanWaves(t *testing.T) {
+	mcm := newWavesMigration("test-plan-waves", &migrationv1alpha1.MigrationStrategy{BatchSize: 2},
+		migrationv1alpha1.ManagedClusterMigrationStatus{Phase: migrationv1alpha1.PhaseInitializing})
+	migrationID := string(mcm.GetUID())
+	AddMigrationStatus(migrationID)
+	defer RemoveMigrationStatus(migrationID)
+	SetClusterList(migrationID, mcm.Spec.IncludedManagedClusters)
+
+	controller := newWavesController(t, mcm)
+	require.NoError(t, controller.planWaves(context.TODO(), mcm))
+
+	require.Len(t, mcm.Status.Waves, 3)
+	assert.Equal(t, 0, mcm.Status.CurrentWave)
+	assert.Equal(t, []string{"cluster1", "cluster2"}, mcm.Status.Waves[0].Clusters)
+	assert.Equal(t, []string{"cluster3", "cluster4"}, mcm.Status.Waves[1].Clusters)
+	assert.Equal(t, []string{"cluster5"}, mcm.Status.Waves[2].Clusters)
+	assert.Equal(t, migrationv1alpha1.PhaseInitializing, mcm.Status.Waves[0].Phase)
+	assert.NotNil(t, mcm.Status.Waves[0].StartTime)
+	assert.Equal(t, WavePhasePending, mcm.Status.Waves[1].Phase)
+	assert.Nil(t, mcm.Status.Waves[1].StartTime)
+
+	// the stages only migrate the clusters of the current wave
+	assert.Equal(t, []string{"cluster1", "cluster2"}, getMigratingClusters(mcm))
+
+	// the waves are planned only once
+	SetClusterList(migrationID, []string{"cluster1"})
+	require.NoError(t, controller.planWaves(context.TODO(), mcm))
+	assert.Len(t, mcm.Status.Waves, 3)
+}
+
+func TestWaving(t *testing.T) {
+	mcm := newWavesMigration("test-waving", &migrationv1alpha1.MigrationStrategy{
+		BatchSize:         3,
+		PauseBetweenWaves: &metav1.Duration{Duration: time.Hour},
+	}, migrationv1alpha1.ManagedClusterMigrationStatus{
+		Phase: migrationv1alpha1.PhaseCleaning,
+		Conditions: []metav1.Condition{
+			{Type: migrationv1alpha1.ConditionTypeValidated, Status: metav1.ConditionTrue},
+			{Type: migrationv1alpha1.ConditionTypeInitialized, Status: metav1.ConditionTrue},
+			{Type: migrationv1alpha1.ConditionTypeDeployed, Status: metav1.ConditionTrue},
+			{Type: migrationv1alpha1.ConditionTypeRegistered, Status: metav1.ConditionTrue},
+			{
+				Type:   migrationv1alpha1.ConditionTypeCleaned,
+				Status: metav1.ConditionTrue,
+				Reason: ConditionReasonResourceCleaned,
+			},
+		},
+		Waves: []migrationv1alpha1.MigrationWaveStatus{
+			{Phase: migrationv1alpha1.PhaseCleaning, Clusters: []string{"cluster1", "cluster2", "cluster3"}},
+			{Phase: WavePhasePending, Clusters: []string{"cluster4", "cluster5"}},
+		},
+	})
+	migrationID := string(mcm.GetUID())
+	AddMigrationStatus(migrationID)
+	defer RemoveMigrationStatus(migrationID)
+	SetStarted(migrationID, "source-hub", migrationv1alpha1.PhaseCleaning)
+	SetFinished(migrationID, "source-hub", migrationv1alpha1.PhaseCleaning)
+
+	controller := newWavesController(t, mcm)
+
+	// the current wave is completed, and pausing before the next wave
+	requeue, err := controller.waving(context.TODO(), mcm)
+	require.NoError(t, err)
+	assert.True(t, requeue)
+	assert.Equal(t, migrationv1alpha1.PhaseCleaning, mcm.Status.Phase)
+	assert.Equal(t, 0, mcm.Status.CurrentWave)
+	assert.Equal(t, migrationv1alpha1.PhaseCompleted, mcm.Status.Waves[0].Phase)
+	require.NotNil(t, mcm.Status.Waves[0].CompletionTime)
+
+	// the cleaning isn't executed again for the cleaned wave
+	requeue, err = controller.cleaning(context.TODO(), mcm)
+	require.NoError(t, err)
+	assert.False(t, requeue)
+
+	// start the next wave once the pause is elapsed
+	mcm.Spec.Strategy.PauseBetweenWaves = &metav1.Duration{Duration: time.Millisecond}
+	time.Sleep(2 * time.Millisecond)
+	requeue, err = controller.waving(context.TODO(), mcm)
+	require.NoError(t, err)
+	assert.True(t, requeue)
+	assert.Equal(t, migrationv1alpha1.PhaseInitializing, mcm.Status.Phase)
+	assert.Equal(t, 1, mcm.Status.CurrentWave)
+	assert.Equal(t, migrationv1alpha1.PhaseInitializing, mcm.Status.Waves[1].Phase)
+	assert.NotNil(t, mcm.Status.Waves[1].StartTime)
+	assert.Equal(t, []string{"cluster4", "cluster5"}, getMigratingClusters(mcm))
+	assert.NotNil(t, meta.FindStatusCondition(mcm.Status.Conditions, migrationv1alpha1.ConditionTypeValidated))
+	assert.Nil(t, meta.FindStatusCondition(mcm.Status.Conditions, migrationv1alpha1.ConditionTypeInitialized))
+	assert.Nil(t, meta.FindStatusCondition(mcm.Status.Conditions, migrationv1alpha1.ConditionTypeCleaned))
+	assert.False(t, GetStarted(migrationID, "source-hub", migrationv1alpha1.PhaseCleaning))
+
+	// the last wave completes the migration
+	mcm.Status.Phase = migrationv1alpha1.PhaseCleaning
+	meta.SetStatusCondition(&mcm.Status.Conditions, metav1.Condition{
+		Type:   migrationv1alpha1.ConditionTypeCleaned,
+		Status: metav1.ConditionTrue,
+		Reason: ConditionReasonResourceCleaned,
+	})
+	requeue, err = controller.waving(context.TODO(), mcm)
+	require.NoError(t, err)
+	assert.False(t, requeue)
+}
+
+func TestRegisteringWaves(t *testing.T) {
+	mcm := newWavesMigration("test-registering-waves", &migrationv1alpha1.MigrationStrategy{
+		BatchSize:      3,
+		MaxConcurrency: 2,
+	}, migrationv1alpha1.ManagedClusterMigrationStatus{
+		Phase: migrationv1alpha1.PhaseRegistering,
+		Waves: []migrationv1alpha1.MigrationWaveStatus{
+			{
+				Phase:              migrationv1alpha1.PhaseCompleted,
+				Clusters:           []string{"cluster4", "cluster5"},
+				RegisteredClusters: []string{"cluster4", "cluster5"},
+			},
+			{Phase: migrationv1alpha1.PhaseRegistering, Clusters: []string{"cluster1", "cluster2", "cluster3"}},
+		},
+		CurrentWave: 1,
+	})
+	migrationID := string(mcm.GetUID())
+	AddMigrationStatus(migrationID)
+	defer RemoveMigrationStatus(migrationID)
+
+	registered := func() {
+		for _, hub := range []string{"source-hub", "target-hub"} {
+			SetStarted(migrationID, hub, migrationv1alpha1.PhaseRegistering)
+			SetFinished(migrationID, hub, migrationv1alpha1.PhaseRegistering)
+		}
+	}
+
+	controller := newWavesController(t, mcm)
+	assert.Equal(t, []string{"cluster1", "cluster2"}, getRegisteringClusters(mcm))
+
+	// the first clusters of the wave are registered, then register the next clusters
+	registered()
+	requeue, err := controller.registering(context.TODO(), mcm)
+	require.NoError(t, err)
+	assert.True(t, requeue)
+	assert.Equal(t, migrationv1alpha1.PhaseRegistering, mcm.Status.Phase)
+	assert.Equal(t, []string{"cluster1", "cluster2"}, mcm.Status.Waves[1].RegisteredClusters)
+	assert.Equal(t, []string{"cluster3"}, getRegisteringClusters(mcm))
+	assert.False(t, GetStarted(migrationID, "target-hub", migrationv1alpha1.PhaseRegistering))
+	cond := meta.FindStatusCondition(mcm.Status.Conditions, migrationv1alpha1.ConditionTypeRegistered)
+	require.NotNil(t, cond)
+	assert.Equal(t, ConditionReasonWaiting, cond.Reason)
+
+	// all the clusters of the wave are registered
+	registered()
+	requeue, err = controller.registering(context.TODO(), mcm)
+	require.NoError(t, err)
+	assert.False(t, requeue)
+	assert.Equal(t, migrationv1alpha1.PhaseCleaning, mcm.Status.Phase)
+	assert.Equal(t, []string{"cluster1", "cluster2", "cluster3"}, mcm.Status.Waves[1].RegisteredClusters)
+	assert.True(t, meta.IsStatusConditionTrue(mcm.Status.Conditions, migrationv1alpha1.ConditionTypeRegistered))
+
+	// the success clusters include the clusters of the previous waves
+	successClusters, err := controller.GetSuccessClusters(context.TODO(), mcm)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"cluster1", "cluster2", "cluster3", "cluster4", "cluster5"}, successClusters)
+
+	// while only the clusters of the current wave are cleaned up
+	waveSuccessClusters, err := controller.getMigratingSuccessClusters(context.TODO(), mcm)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cluster1", "cluster2", "cluster3"}, waveSuccessClusters)
+}
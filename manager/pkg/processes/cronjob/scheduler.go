@@ -8,9 +8,11 @@ import (
 	"github.com/go-co-op/gocron"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/backup"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob/task"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/retention"
 )
 
 const (
@@ -65,23 +67,62 @@ func AddSchedulerToManager(ctx context.Context, mgr ctrl.Manager,
 	}
 	log.Infow("set SyncLocalCompliance job", "scheduleAt", complianceHistoryJob.ScheduledAtTime())
 
+	snapshotInterval := managerConfig.ComplianceSnapshotInterval
+	if snapshotInterval <= 0 {
+		snapshotInterval = task.DefaultLocalComplianceSnapshotInterval
+	}
+	complianceSnapshotJob, err := scheduler.
+		Every(snapshotInterval).
+		Tag(task.LocalComplianceSnapshotTaskName).
+		DoWithJobDetails(task.LocalComplianceSnapshot, ctx)
+	if err != nil {
+		return err
+	}
+	log.Infow("set LocalComplianceSnapshot job", "scheduleAt", complianceSnapshotJob.ScheduledAtTime())
+
+	policies, err := retention.ParsePolicies(managerConfig.DatabaseConfig.RetentionPolicies)
+	if err != nil {
+		return err
+	}
+	retentionOptions := &task.RetentionOptions{Policies: policies}
+	if target := managerConfig.DatabaseConfig.RetentionArchiveTarget; target != "" {
+		if retentionOptions.Archive, err = backup.NewObjectStore(target); err != nil {
+			return err
+		}
+	}
 	dataRetentionJob1, err := scheduler.
 		Every(1).Month(1, 15).At("00:00").
 		Tag(task.RetentionTaskName).
-		DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention)
+		DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention, retentionOptions)
 	if err != nil {
 		return err
 	}
 	dataRetentionJob2, err := scheduler.
 		Every(1).MonthLastDay().At("00:00").
 		Tag(task.RetentionTaskName).
-		DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention)
+		DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention, retentionOptions)
 	if err != nil {
 		return err
 	}
 	log.Info("set DataRetention job", "scheduleAt1", dataRetentionJob1.ScheduledAtTime(),
 		"scheduleAt2", dataRetentionJob2.ScheduledAtTime())
 
+	backupConfig := managerConfig.BackupConfig
+	if backupConfig != nil && backupConfig.Target != "" {
+		store, err := backup.NewObjectStore(backupConfig.Target)
+		if err != nil {
+			return err
+		}
+		backupJob, err := scheduler.
+			Every(backupConfig.Interval).SingletonMode().
+			Tag(task.DatabaseBackupTaskName).
+			DoWithJobDetails(task.DatabaseBackup, ctx, backup.NewBackupper(store, backupConfig.FullInterval))
+		if err != nil {
+			return err
+		}
+		log.Infow("set DatabaseBackup job", "scheduleAt", backupJob.ScheduledAtTime())
+	}
+
 	// register the metrics before starting the jobs
 	task.RegisterMetrics()
 
@@ -95,6 +136,11 @@ func (s *GlobalHubJobScheduler) Start(ctx context.Context) error {
 	// Set the status of the job to 0 (success) when the job is started.
 	task.GlobalHubCronJobGaugeVec.WithLabelValues(task.RetentionTaskName).Set(0)
 	task.GlobalHubCronJobGaugeVec.WithLabelValues(task.LocalComplianceTaskName).Set(0)
+	task.GlobalHubCronJobGaugeVec.WithLabelValues(task.LocalComplianceSnapshotTaskName).Set(0)
+	// the database backup is scheduled only if the backup target is set
+	if _, err := s.scheduler.FindJobsByTag(task.DatabaseBackupTaskName); err == nil {
+		task.GlobalHubCronJobGaugeVec.WithLabelValues(task.DatabaseBackupTaskName).Set(0)
+	}
 	s.scheduler.StartAsync()
 
 	// Always run data-retention job on startup to ensure partition tables exist
@@ -117,7 +163,7 @@ func (s *GlobalHubJobScheduler) Start(ctx context.Context) error {
 func (s *GlobalHubJobScheduler) ExecJobs() error {
 	for _, job := range s.launchJobs {
 		switch job {
-		case task.RetentionTaskName:
+		case task.RetentionTaskName, task.LocalComplianceSnapshotTaskName, task.DatabaseBackupTaskName:
 			log.Infow("launch the job", "name", job)
 			if err := s.scheduler.RunByTag(job); err != nil {
 				return err
@@ -7,44 +7,46 @@ import (
 	"time"
 
 	"github.com/go-co-op/gocron"
+	"gorm.io/gorm"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/backup"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/retention"
 )
 
 var (
 	// The main tasks of this job are:
-	// 1. ensure current month partition exists (handles operator restart scenarios)
-	// 2. create partition tables for the next month
-	// 3. delete partition tables that are no longer needed (beyond retention period)
-	// 4. completely delete the soft deleted records from database after retainedMonths
+	// 1. ensure the partitions of the current and the next month exist (handles operator restart scenarios)
+	// 2. archive and delete the partition tables that are no longer needed (beyond retention period)
+	// 3. archive and delete the expired rows of the event types and the hubs retained shorter than the partitions
+	// 4. completely delete the soft deleted records and the dead letters from database after the retention
 	RetentionTaskName = "data-retention"
 
 	// after the record is marked as deleted, retentionMonth is used to indicate how long it will be retained
 	// before it is completely deleted from database
 	// retentionMonth  = 18
-	RetentionTables = []string{
-		"status.managed_clusters",
-		"status.leaf_hubs",
-		"local_spec.policies",
-	}
+	RetentionTables = retention.TableNames(false)
 
 	// partition by month
 	PartitionDateFormat = "2006_01"
 	// the following data tables will generate records over time, so it is necessary to split them into small tables to
 	// achieve better scanning and writing performance.
-	PartitionTables = []string{
-		"event.local_policies",
-		"event.local_root_policies",
-		"history.local_compliance",
-		"event.managed_clusters",
-	}
-	retentionLog = logger.ZapLogger(RetentionTaskName)
+	PartitionTables = retention.TableNames(true)
+	retentionLog    = logger.ZapLogger(RetentionTaskName)
 )
 
-func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
+// RetentionOptions overrides the default retention month of the data retention job
+type RetentionOptions struct {
+	// Policies override the retention of the tables, the event types and the hubs
+	Policies []retention.Policy
+	// Archive is the object store the expired data is exported to before it's deleted, it isn't archived if nil
+	Archive backup.ObjectStore
+}
+
+func DataRetention(ctx context.Context, retentionMonth int, options *RetentionOptions, job gocron.Job) {
 	now := time.Now()
 	currentMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 
@@ -66,43 +68,44 @@ func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
 		}
 	}()
 
-	// Ensure current month partition exists to handle operator restart scenario
+	plans, err := retention.Resolve(now, retention.Period{Months: retentionMonth}, options.Policies)
+	if err != nil {
+		retentionLog.Error(err, "failed to resolve the retention policies")
+		return
+	}
+
+	// Ensure the partitions of the current and the next month exist to handle operator restart scenario
 	// This fixes the issue where operator restart between the 28th and month-end
 	// could result in missing current month partitions after month rollover
-	for _, tableName := range PartitionTables {
-		err = ensurePartitionExists(tableName, currentMonth)
+	for _, plan := range plans {
+		if !plan.Table.Partitioned {
+			continue
+		}
+		err = ensurePartitions(plan, currentMonth, currentMonth.AddDate(0, 2, 0))
 		if err != nil {
-			retentionLog.Error(err, "failed to ensure current month partition exists")
+			retentionLog.Error(err, "failed to ensure the partitions exist")
 			return
 		}
 	}
 
-	// Create next month partition and delete expired partitions
-	createMonth := currentMonth.AddDate(0, 1, 0)
-	deleteMonth := currentMonth.AddDate(0, -(retentionMonth + 1), 0)
-	for _, tableName := range PartitionTables {
-		err = updatePartitionTables(tableName, createMonth, deleteMonth)
-		if e := traceDataRetentionLog(tableName, currentMonth, err, true); e != nil {
-			retentionLog.Error(e, "failed to trace data retention log")
+	// delete the expired partitions and rows, and the soft deleted records from database
+	for _, plan := range plans {
+		if plan.Table.Partitioned {
+			err = deleteExpiredPartitions(ctx, plan, options.Archive)
 		}
-		if err != nil {
-			retentionLog.Error(err, "failed to update partition tables")
-			return
+		if err == nil {
+			err = deleteExpiredRows(ctx, plan, options.Archive, now)
 		}
-	}
-
-	// delete the soft deleted records from database
-	minTime := currentMonth.AddDate(0, -retentionMonth, 0)
-	for _, tableName := range RetentionTables {
-		err = deleteExpiredRecords(tableName, minTime)
-		if e := traceDataRetentionLog(tableName, currentMonth, err, false); e != nil {
+		if e := traceDataRetentionLog(plan.Table, currentMonth, err); e != nil {
 			retentionLog.Error(e, "failed to trace data retention log")
 		}
 		if err != nil {
-			retentionLog.Error(err, "failed to delete soft deleted records")
+			retentionLog.Error(err, "failed to delete the expired data")
 			return
 		}
 	}
+
+	minTime := currentMonth.AddDate(0, -retentionMonth, 0)
 	err = db.Where("last_timestamp < ? AND status = ?", minTime, hubmanagement.HubInactive).
 		Delete(&models.LeafHubHeartbeat{}).Error
 	if err != nil {
@@ -112,59 +115,146 @@ func DataRetention(ctx context.Context, retentionMonth int, job gocron.Job) {
 	retentionLog.Info("finish running", "nextRun", job.NextRun().Format(TimeFormat))
 }
 
-// ensurePartitionExists ensures a partition table exists for the given month
-// This is idempotent and safe to call multiple times
-func ensurePartitionExists(tableName string, createTime time.Time) error {
+// ensurePartitions creates the partitions of the granularity to cover [from, to), the ranges covered by the existing
+// partitions are skipped. This is idempotent and safe to call multiple times
+func ensurePartitions(plan retention.Plan, from, to time.Time) error {
 	db := database.GetGorm()
 
-	startTime := time.Date(createTime.Year(), createTime.Month(), 1, 0, 0, 0, 0, createTime.Location())
-	endTime := startTime.AddDate(0, 1, 0)
-	createPartitionTableName := fmt.Sprintf("%s_%s", tableName, startTime.Format(PartitionDateFormat))
-
-	creationSql := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')",
-		createPartitionTableName, tableName, startTime.Format(DateFormat), endTime.Format(DateFormat))
-	if result := db.Exec(creationSql); result.Error != nil {
-		return fmt.Errorf("failed to ensure partition table %s exists: %w", createPartitionTableName, result.Error)
+	existing, err := listPartitions(plan.Table.Name)
+	if err != nil {
+		return err
+	}
+	for _, partition := range retention.Missing(plan.Table.Name, plan.Granularity, from, to, existing) {
+		creationSql := fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')",
+			partition.Name, plan.Table.Name, partition.From.Format(retention.TimeFormat),
+			partition.To.Format(retention.TimeFormat))
+		if result := db.Exec(creationSql); result.Error != nil {
+			return fmt.Errorf("failed to ensure partition table %s exists: %w", partition.Name, result.Error)
+		}
+		retentionLog.Info("create partition table", "table", partition.Name, "start",
+			partition.From.Format(DateFormat), "end", partition.To.Format(DateFormat))
 	}
-	retentionLog.Info("create partition table", "table", createPartitionTableName, "start", startTime.Format(DateFormat),
-		"end", endTime.Format(DateFormat))
 	return nil
 }
 
-func updatePartitionTables(tableName string, createTime, deleteTime time.Time) error {
+// deleteExpiredPartitions drops the partitions ending before the retention of all the rows in them
+func deleteExpiredPartitions(ctx context.Context, plan retention.Plan, archive backup.ObjectStore) error {
 	db := database.GetGorm()
 
-	// create the partition tables for the next month
-	err := ensurePartitionExists(tableName, createTime)
+	partitions, err := listPartitions(plan.Table.Name)
 	if err != nil {
-		return fmt.Errorf("failed to create partition table %s: %w", tableName, err)
+		return err
 	}
+	for _, partition := range partitions {
+		if partition.To.After(plan.DropBefore) {
+			continue
+		}
+		if archive != nil {
+			key := archiveKey(plan.Table.Name, strings.TrimPrefix(partition.Name, plan.Table.Name+"_"))
+			if err := archiveRows(ctx, db, archive, key, partition.Name, "TRUE"); err != nil {
+				return fmt.Errorf("failed to archive partition table %s: %w", partition.Name, err)
+			}
+		}
+		if result := db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s", partition.Name)); result.Error != nil {
+			return fmt.Errorf("failed to delete partition table %s: %w", partition.Name, result.Error)
+		}
+		retentionLog.Info("delete partition table", "table", partition.Name)
+	}
+	return nil
+}
 
-	// delete the partition tables that are expired
-	deletePartitionTableName := fmt.Sprintf("%s_%s", tableName, deleteTime.Format(PartitionDateFormat))
-	deletionSql := fmt.Sprintf("DROP TABLE IF EXISTS %s", deletePartitionTableName)
-	if result := db.Exec(deletionSql); result.Error != nil {
-		return fmt.Errorf("failed to delete partition table %s: %w", tableName, result.Error)
+// deleteExpiredRows deletes the expired rows by the rules of the plan, e.g. the soft deleted records, and the rows of
+// the hubs retained shorter than the partitions
+func deleteExpiredRows(ctx context.Context, plan retention.Plan, archive backup.ObjectStore, now time.Time) error {
+	for i, rule := range plan.Rules {
+		where, args := rule.Where(plan.Table)
+		err := database.GetGorm().Transaction(func(tx *gorm.DB) error {
+			if archive != nil {
+				key := archiveKey(plan.Table.Name, fmt.Sprintf("rows_%s_%d", now.Format("20060102T150405"), i))
+				if err := archiveRows(ctx, tx, archive, key, plan.Table.Name, where, args...); err != nil {
+					return err
+				}
+			}
+			return tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE %s", plan.Table.Name, where), args...).Error
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete records before %s from %s: %w",
+				rule.Cutoff.Format(DateFormat), plan.Table.Name, err)
+		}
+		retentionLog.Info("delete records", "table", plan.Table.Name, "before", rule.Cutoff.Format(DateFormat),
+			"hub", rule.Hub, "eventType", rule.EventType)
 	}
-	retentionLog.Info("delete partition table", "table", deletePartitionTableName)
 	return nil
 }
 
-func deleteExpiredRecords(tableName string, minDate time.Time) error {
-	sql := fmt.Sprintf("DELETE FROM %s WHERE deleted_at < '%s'", tableName, minDate.Format(DateFormat))
-	db := database.GetGorm()
-	if result := db.Exec(sql); result.Error != nil {
-		return fmt.Errorf("failed to delete records before %s from %s: %w",
-			minDate.Format(DateFormat), tableName, result.Error)
+// archiveRows exports the rows of the table matching the condition as gzipped json lines, the empty ones are skipped
+func archiveRows(ctx context.Context, tx *gorm.DB, archive backup.ObjectStore, key, table, where string,
+	args ...interface{},
+) error {
+	var exists bool
+	if err := tx.Raw(fmt.Sprintf("SELECT EXISTS (SELECT 1 FROM %s WHERE %s)", table, where), args...).
+		Scan(&exists).Error; err != nil {
+		return err
+	}
+	if !exists {
+		return nil
 	}
-	retentionLog.Info("delete records", "table", tableName, "before", minDate.Format(DateFormat))
+	object, err := backup.Export(ctx, archive, tx,
+		key, fmt.Sprintf("SELECT row_to_json(t)::text FROM %s t WHERE %s", table, where), args...)
+	if err != nil {
+		return err
+	}
+	retentionLog.Info("archive records", "table", table, "key", object.Key, "rows", object.Rows,
+		"sha256", object.SHA256)
 	return nil
 }
 
-func traceDataRetentionLog(tableName string, startTime time.Time, err error, partition bool) error {
+// archiveKey returns the key of the archived data of the table, e.g. event.managed_clusters/2025_01.jsonl.gz
+func archiveKey(table, name string) string {
+	return fmt.Sprintf("%s/%s.jsonl.gz", table, name)
+}
+
+// listPartitions returns the partitions of the table with their ranges, the default partition is skipped
+func listPartitions(tableName string) ([]retention.Partition, error) {
+	schemaTable := strings.Split(tableName, ".")
+	if len(schemaTable) != 2 {
+		return nil, fmt.Errorf("invalid table name: %s", tableName)
+	}
+	var bounds []struct {
+		Name  string
+		Bound string
+	}
+	result := database.GetGorm().Raw(`
+		SELECT
+			nmsp_child.nspname || '.' || child.relname AS name,
+			pg_get_expr(child.relpartbound, child.oid) AS bound
+		FROM
+			pg_inherits
+			JOIN pg_class parent ON pg_inherits.inhparent = parent.oid
+			JOIN pg_class child ON pg_inherits.inhrelid = child.oid
+			JOIN pg_namespace nmsp_parent ON nmsp_parent.oid = parent.relnamespace
+			JOIN pg_namespace nmsp_child ON nmsp_child.oid = child.relnamespace
+		WHERE
+			nmsp_parent.nspname = ?
+			AND parent.relname = ?
+		ORDER BY
+			child.relname ASC`, schemaTable[0], schemaTable[1]).Scan(&bounds)
+	if result.Error != nil {
+		return nil, fmt.Errorf("failed to list the partitions of %s: %w", tableName, result.Error)
+	}
+	partitions := []retention.Partition{}
+	for _, b := range bounds {
+		if partition, ok := retention.ParsePartitionBound(b.Name, b.Bound, time.Local); ok {
+			partitions = append(partitions, partition)
+		}
+	}
+	return partitions, nil
+}
+
+func traceDataRetentionLog(table retention.Table, startTime time.Time, err error) error {
 	db := database.GetGorm()
 	dataRetentionLog := &models.DataRetentionJobLog{
-		Name:    tableName,
+		Name:    table.Name,
 		StartAt: startTime,
 		EndAt:   time.Now(),
 		Error:   "none",
@@ -173,15 +263,15 @@ func traceDataRetentionLog(tableName string, startTime time.Time, err error, par
 		dataRetentionLog.Error = err.Error()
 	}
 
-	if partition {
-		minPartition, maxPartition, err := getMinMaxPartitions(tableName)
+	if table.Partitioned {
+		minPartition, maxPartition, err := getMinMaxPartitions(table.Name)
 		if err != nil {
 			return err
 		}
 		dataRetentionLog.MinPartition = minPartition
 		dataRetentionLog.MaxPartition = maxPartition
 	} else {
-		if minDeletionTime, err := getMinDeletionTime(tableName); err == nil && !minDeletionTime.IsZero() {
+		if minDeletionTime, err := getMinDeletionTime(table); err == nil && !minDeletionTime.IsZero() {
 			dataRetentionLog.MinDeletion = minDeletionTime
 		}
 	}
@@ -224,11 +314,11 @@ func getMinMaxPartitions(tableName string) (string, string, error) {
 	return tables[0].Table, tables[len(tables)-1].Table, nil
 }
 
-func getMinDeletionTime(tableName string) (time.Time, error) {
+func getMinDeletionTime(table retention.Table) (time.Time, error) {
 	db := database.GetGorm()
 	minDeletion := &models.Time{}
 
-	result := db.Raw(fmt.Sprintf("SELECT MIN(deleted_at) as time FROM %s", tableName)).Find(minDeletion)
+	result := db.Raw(fmt.Sprintf("SELECT MIN(%s) as time FROM %s", table.TimeColumn, table.Name)).Find(minDeletion)
 	if result.Error != nil {
 		return minDeletion.Time, fmt.Errorf("failed to get min deletion time: %w", result.Error)
 	}
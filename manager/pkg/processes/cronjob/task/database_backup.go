@@ -0,0 +1,30 @@
+package task
+
+import (
+	"context"
+
+	"github.com/go-co-op/gocron"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/backup"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// DatabaseBackupTaskName exports the database into the backup target periodically, the backup is incremental until
+// the full backup it's based on is older than the full interval.
+var DatabaseBackupTaskName = "database-backup"
+
+func DatabaseBackup(ctx context.Context, backupper *backup.Backupper, job gocron.Job) {
+	backupLog := logger.ZapLogger(DatabaseBackupTaskName)
+	backupLog.Infow("start running", "currentRun", job.LastRun().Format(TimeFormat))
+
+	manifest, err := backupper.Backup(ctx)
+	if err != nil {
+		GlobalHubCronJobGaugeVec.WithLabelValues(DatabaseBackupTaskName).Set(1)
+		backupLog.Errorw("failed to back up the database", "error", err)
+		return
+	}
+	GlobalHubCronJobGaugeVec.WithLabelValues(DatabaseBackupTaskName).Set(0)
+
+	backupLog.Infow("finish running", "id", manifest.ID, "base", manifest.Base, "tables", len(manifest.Tables),
+		"nextRun", job.NextRun().Format(TimeFormat))
+}
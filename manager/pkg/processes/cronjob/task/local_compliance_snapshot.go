@@ -0,0 +1,89 @@
+package task
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-co-op/gocron"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// LocalComplianceSnapshotTaskName materializes the local compliance periodically, so that rebuilding the compliance at
+// a past time only replays the events after the latest snapshot before that time.
+var LocalComplianceSnapshotTaskName = "local-compliance-snapshot"
+
+// DefaultLocalComplianceSnapshotInterval is the default interval of the snapshots, each of them copies the compliance
+// of all the local policies, and they are expired by the data retention job.
+const DefaultLocalComplianceSnapshotInterval = 24 * time.Hour
+
+func LocalComplianceSnapshot(ctx context.Context, job gocron.Job) {
+	snapshotLog := logger.ZapLogger(LocalComplianceSnapshotTaskName)
+	snapshotLog.Infow("start running", "currentRun", job.LastRun().Format(TimeFormat))
+
+	var err error
+	defer func() {
+		if err != nil {
+			GlobalHubCronJobGaugeVec.WithLabelValues(LocalComplianceSnapshotTaskName).Set(1)
+		} else {
+			GlobalHubCronJobGaugeVec.WithLabelValues(LocalComplianceSnapshotTaskName).Set(0)
+		}
+	}()
+
+	start := time.Now()
+	inserted, err := snapshotLocalCompliance(ctx)
+	if e := traceComplianceHistoryLog(LocalComplianceSnapshotTaskName, inserted, 0, inserted, start, err); e != nil {
+		snapshotLog.Warnw("failed to trace local compliance snapshot job", "error", e)
+	}
+	if err != nil {
+		snapshotLog.Error(err, "snapshot local_status.compliance to history.local_compliance_snapshots failed")
+		return
+	}
+
+	snapshotLog.Infow("finish running", "inserted", inserted, "nextRun", job.NextRun().Format(TimeFormat))
+}
+
+// snapshotLocalCompliance copies the current local compliance into the snapshots table. The snapshot time of a hub is
+// the creation time of the latest compliance event received from it, so the snapshots and the events replayed after
+// them are ordered by the same clock of the hub. The database time is only used if the hub hasn't sent any event. The
+// compliance of the hub isn't copied again until it sends a new event, since the snapshot time is unchanged.
+func snapshotLocalCompliance(ctx context.Context) (int64, error) {
+	ret := database.GetGorm().WithContext(ctx).Exec(`
+		WITH compliance AS (
+			SELECT
+				c.policy_id,
+				c.cluster_id,
+				c.cluster_name,
+				c.leaf_hub_name,
+				c.compliance,
+				(
+					SELECT max(e.created_at) FROM event.local_policies e
+					WHERE e.policy_id = c.policy_id AND e.cluster_id = c.cluster_id
+				) AS event_at
+			FROM
+				local_status.compliance c
+			WHERE
+				c.cluster_id IS NOT NULL
+		)
+		INSERT INTO history.local_compliance_snapshots (
+			snapshot_at,
+			policy_id,
+			cluster_id,
+			cluster_name,
+			leaf_hub_name,
+			compliance
+		)
+		SELECT
+			COALESCE(max(event_at) OVER (PARTITION BY leaf_hub_name), LOCALTIMESTAMP),
+			policy_id,
+			cluster_id,
+			cluster_name,
+			leaf_hub_name,
+			compliance
+		FROM
+			compliance
+		ON CONFLICT (snapshot_at, leaf_hub_name, policy_id, cluster_id) DO NOTHING
+	`)
+	return ret.RowsAffected, ret.Error
+}
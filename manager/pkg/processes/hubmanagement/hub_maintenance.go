@@ -0,0 +1,106 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubmanagement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// ErrHubNotFound is returned when the hub has never sent the heartbeat to the global hub
+var ErrHubNotFound = errors.New("the hub is not found")
+
+// maintenanceRequester is the requester of the resync requests created on resuming the hub from the maintenance
+const maintenanceRequester = "maintenance"
+
+// EnterMaintenance puts the hub into the planned maintenance. The inactivity handling, the labels pushed to the hub and
+// the status handling of the hub are paused, and the last known status of the hub is kept but marked as stale until
+// the maintenance is exited. The spec broadcast to all the hubs, e.g. the global resources, isn't paused.
+func EnterMaintenance(ctx context.Context, hubName string) error {
+	return switchMaintenance(ctx, hubName, HubMaintenance, HubActive, HubInactive, HubResuming)
+}
+
+// ExitMaintenance requests to resume the hub from the maintenance, the hub is resynced and activated asynchronously
+// by the hub management of the leader manager.
+func ExitMaintenance(ctx context.Context, hubName string) error {
+	return switchMaintenance(ctx, hubName, HubResuming, HubMaintenance)
+}
+
+func switchMaintenance(ctx context.Context, hubName, status string, fromStatuses ...string) error {
+	db := database.GetGorm().WithContext(ctx)
+	result := db.Model(&models.LeafHubHeartbeat{}).
+		Where("leaf_hub_name = ? AND status IN ?", hubName, fromStatuses).
+		Update("status", status)
+	if result.Error != nil {
+		return fmt.Errorf("failed to switch the hub %s to %s: %w", hubName, status, result.Error)
+	}
+	if result.RowsAffected > 0 {
+		log.Infow("switch the hub maintenance status", "name", hubName, "status", status)
+		return nil
+	}
+
+	// the hub is already in the status, or it doesn't exist
+	err := db.Select("leaf_hub_name").Where("leaf_hub_name = ?", hubName).Take(&models.LeafHubHeartbeat{}).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrHubNotFound
+	}
+	return err
+}
+
+// IsStatusPaused returns whether the status handling of the hub is paused, the status bundles of the hub in the
+// maintenance or not resumed yet are skipped, so the last known status is kept until the hub is resynced on the exit.
+func IsStatusPaused(ctx context.Context, hubName string) (bool, error) {
+	var count int64
+	err := database.GetGorm().WithContext(ctx).Model(&models.LeafHubHeartbeat{}).
+		Where("leaf_hub_name = ? AND status IN ?", hubName, []string{HubMaintenance, HubResuming}).
+		Count(&count).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to get the status of the hub %s: %w", hubName, err)
+	}
+	return count > 0, nil
+}
+
+// resume activates the hubs exited from the maintenance with the heartbeat refreshed, so the hub has the active
+// timeout to come back before it's handled as inactive. The spec and status paused in the maintenance aren't resent
+// here, the labels are touched to be sent again by the syncer, and the status is requested to resync by the resync
+// requests, which are sent to the hub asynchronously once it's active, so the update of the other hubs isn't blocked.
+func (h *HubManagement) resume(ctx context.Context, hubs []models.LeafHubHeartbeat) error {
+	db := database.GetGorm().WithContext(ctx)
+	for _, hub := range hubs {
+		log.Infow("resume the hub from maintenance", "name", hub.Name)
+		err := db.Transaction(func(tx *gorm.DB) error {
+			// touch the labels of the hub, so the labels bundle skipped in the maintenance is sent again
+			if err := tx.Exec(`UPDATE spec.managed_clusters_labels SET updated_at = now()
+				WHERE leaf_hub_name = ?`, hub.Name).Error; err != nil {
+				return err
+			}
+			requests := []models.ResyncRequest{}
+			for _, eventType := range hubResyncEventTypes {
+				requests = append(requests, models.ResyncRequest{
+					LeafHubName: hub.Name,
+					EventType:   eventType,
+					RequestedBy: maintenanceRequester,
+				})
+			}
+			if err := tx.Create(&requests).Error; err != nil {
+				return err
+			}
+			return tx.Model(&models.LeafHubHeartbeat{}).
+				Where("leaf_hub_name = ? AND status = ?", hub.Name, HubResuming).
+				Updates(map[string]interface{}{"status": HubActive, "last_timestamp": time.Now()}).Error
+		})
+		if err != nil {
+			// the hub is still resuming, so it's retried in the next update
+			log.Warnw("failed to resume the hub", "name", hub.Name, "error", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubmanagement
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+type hubMaintenanceController struct {
+	client client.Client
+}
+
+// AddHubMaintenanceController switches the maintenance of the hub by the maintenance annotation of the managed hub
+// cluster. It only reacts to the change of the annotation, so the maintenance switched by the REST API isn't reverted.
+func AddHubMaintenanceController(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("hub-maintenance-controller").
+		For(&clusterv1.ManagedCluster{}).
+		WithEventFilter(maintenanceAnnotationPredicate()).
+		Complete(&hubMaintenanceController{client: mgr.GetClient()})
+}
+
+func maintenanceAnnotationPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return inMaintenance(e.Object)
+		},
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return inMaintenance(e.ObjectOld) != inMaintenance(e.ObjectNew)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+func inMaintenance(obj client.Object) bool {
+	return obj.GetAnnotations()[constants.HubMaintenanceAnnotation] == "true"
+}
+
+func (c *hubMaintenanceController) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	cluster := &clusterv1.ManagedCluster{}
+	if err := c.client.Get(ctx, request.NamespacedName, cluster); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	var err error
+	if inMaintenance(cluster) {
+		err = EnterMaintenance(ctx, cluster.Name)
+	} else {
+		err = ExitMaintenance(ctx, cluster.Name)
+	}
+	if errors.Is(err, ErrHubNotFound) {
+		log.Infow("the hub hasn't sent the heartbeat, skip switching the maintenance", "name", cluster.Name)
+		return ctrl.Result{}, nil
+	}
+	if err != nil {
+		return ctrl.Result{Requeue: true, RequeueAfter: 5 * time.Second}, err
+	}
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,31 @@
+package hubmanagement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func TestMaintenanceAnnotationPredicate(t *testing.T) {
+	cluster := func(annotations map[string]string) *clusterv1.ManagedCluster {
+		return &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "hub1", Annotations: annotations}}
+	}
+	maintenance := map[string]string{constants.HubMaintenanceAnnotation: "true"}
+	other := map[string]string{"foo": "bar"}
+
+	p := maintenanceAnnotationPredicate()
+	assert.True(t, p.Create(event.CreateEvent{Object: cluster(maintenance)}))
+	assert.False(t, p.Create(event.CreateEvent{Object: cluster(other)}))
+
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: cluster(other), ObjectNew: cluster(maintenance)}))
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: cluster(maintenance), ObjectNew: cluster(nil)}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: cluster(maintenance), ObjectNew: cluster(maintenance)}))
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: cluster(nil), ObjectNew: cluster(other)}))
+
+	assert.False(t, p.Delete(event.DeleteEvent{Object: cluster(maintenance)}))
+}
@@ -24,6 +24,10 @@ import (
 const (
 	HubActive   = "active"
 	HubInactive = "inactive"
+	// HubMaintenance is the planned maintenance of the hub, the hub isn't inactivated by the heartbeat timeout
+	HubMaintenance = "maintenance"
+	// HubResuming is the hub exited from the maintenance, but not resynced yet
+	HubResuming = "resuming"
 
 	// heartbeatInterval = 1 * time.Minute
 	ActiveTimeout = 5 * time.Minute // if heartbeat < (now - ActiveTimeout), then status = inactive, vice versa
@@ -32,6 +36,14 @@ const (
 
 var hubStatusManager HubStatusManager
 
+// hubResyncEventTypes are the event types resynced when the hub is reactivated or resumed from the maintenance
+var hubResyncEventTypes = []string{
+	string(enum.HubClusterInfoType),
+	string(enum.ManagedClusterType),
+	string(enum.LocalPolicySpecType),
+	string(enum.LocalComplianceType),
+}
+
 type HubStatusManager interface {
 	inactive(ctx context.Context, hubs []models.LeafHubHeartbeat) error
 	reactive(ctx context.Context, hubs []models.LeafHubHeartbeat) error
@@ -39,16 +51,18 @@ type HubStatusManager interface {
 
 // manage the leaf hub lifecycle based on the heartbeat
 type HubManagement struct {
-	producer      transport.Producer
-	probeDuration time.Duration
-	activeTimeout time.Duration
+	producer       transport.Producer
+	probeDuration  time.Duration
+	activeTimeout  time.Duration
+	resyncInterval time.Duration
 }
 
 func NewHubManagement(producer transport.Producer, probeDuration, activeTimeout time.Duration) *HubManagement {
 	return &HubManagement{
-		producer:      producer,
-		probeDuration: probeDuration,
-		activeTimeout: activeTimeout,
+		producer:       producer,
+		probeDuration:  probeDuration,
+		activeTimeout:  activeTimeout,
+		resyncInterval: ResyncInterval,
 	}
 }
 
@@ -64,6 +78,14 @@ func AddHubManagement(mgr ctrl.Manager, producer transport.Producer) error {
 	if err := AddManagedClusterAddonController(mgr); err != nil {
 		return fmt.Errorf("failed to add the addon controller for hub management: %w", err)
 	}
+	// add the controller to switch the hub maintenance by the annotation of the hub cluster
+	if err := AddHubMaintenanceController(mgr); err != nil {
+		return fmt.Errorf("failed to add the maintenance controller for hub management: %w", err)
+	}
+	// add the controller to request the resync by the annotation of the hub cluster
+	if err := AddHubResyncController(mgr); err != nil {
+		return fmt.Errorf("failed to add the resync controller for hub management: %w", err)
+	}
 	hubStatusManager = instance
 	return nil
 }
@@ -93,6 +115,21 @@ func (h *HubManagement) Start(ctx context.Context) error {
 			}
 		}
 	}()
+
+	go func() {
+		ticker := time.NewTicker(h.resyncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := h.sendResyncRequests(ctx); err != nil {
+					log.Error(err, "failed to send the resync requests")
+				}
+			}
+		}
+	}()
 	return nil
 }
 
@@ -116,6 +153,14 @@ func (h *HubManagement) update(ctx context.Context) error {
 	if err := h.reactive(ctx, reactiveHubs); err != nil {
 		return fmt.Errorf("failed to reactive hubs %v", err)
 	}
+
+	var resumingHubs []models.LeafHubHeartbeat
+	if err := db.Where("status = ?", HubResuming).Find(&resumingHubs).Error; err != nil {
+		return err
+	}
+	if err := h.resume(ctx, resumingHubs); err != nil {
+		return fmt.Errorf("failed to resume hubs %v", err)
+	}
 	return nil
 }
 
@@ -202,13 +247,12 @@ func (h *HubManagement) reactive(ctx context.Context, hubs []models.LeafHubHeart
 }
 
 func (h *HubManagement) resync(ctx context.Context, hubName string) error {
-	resyncResources := []string{
-		string(enum.HubClusterInfoType),
-		string(enum.ManagedClusterType),
-		string(enum.LocalPolicySpecType),
-		string(enum.LocalComplianceType),
-	}
-	payloadBytes, err := json.Marshal(resyncResources)
+	return h.resyncEventTypes(ctx, hubName, hubResyncEventTypes)
+}
+
+// resyncEventTypes requests the hub to resend the full state of the event types
+func (h *HubManagement) resyncEventTypes(ctx context.Context, hubName string, eventTypes []string) error {
+	payloadBytes, err := json.Marshal(eventTypes)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,212 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubmanagement
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// ResyncInterval is the interval to send the pending resync requests to the active hubs
+const ResyncInterval = 10 * time.Second
+
+// ResyncEventTypes are the event types which can be requested to resend the full state from the hubs
+var ResyncEventTypes = []enum.EventType{
+	enum.HubClusterInfoType,
+	enum.ManagedClusterType,
+	enum.ManagedClusterInfoType,
+	enum.LocalPolicySpecType,
+	enum.LocalComplianceType,
+	enum.ComplianceType,
+	enum.PlacementDecisionType,
+	enum.LocalPlacementRuleSpecType,
+	enum.PlacementRuleSpecType,
+	enum.PlacementSpecType,
+	enum.SubscriptionReportType,
+	enum.SubscriptionStatusType,
+	enum.SecurityAlertCountsType,
+	enum.SecurityViolationsType,
+}
+
+// resyncTracker keeps the sent resync requests in memory, so the committed bundles are matched without querying the
+// database for each of them.
+type resyncTracker struct {
+	mutex sync.RWMutex
+	// hub name -> event type -> the earliest sent time of the requests which are sent but not committed
+	pending map[string]map[string]time.Time
+}
+
+var resyncRequests = &resyncTracker{pending: map[string]map[string]time.Time{}}
+
+// isPending returns whether the bundle of the event type created by the hub at the given time is resent for the
+// requests, the bundles created before the requests are sent, e.g. the ones left in the transport, don't commit them.
+func (t *resyncTracker) isPending(hubName, eventType string, createdAt time.Time) bool {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+	sentAt, found := t.pending[hubName][eventType]
+	return found && !createdAt.Before(sentAt)
+}
+
+func (t *resyncTracker) remove(hubName, eventType string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	delete(t.pending[hubName], eventType)
+}
+
+func (t *resyncTracker) reset(requests []models.ResyncRequest) {
+	pending := map[string]map[string]time.Time{}
+	for _, request := range requests {
+		if request.SentAt == nil {
+			continue
+		}
+		if pending[request.LeafHubName] == nil {
+			pending[request.LeafHubName] = map[string]time.Time{}
+		}
+		sentAt, found := pending[request.LeafHubName][request.EventType]
+		if !found || request.SentAt.Before(sentAt) {
+			pending[request.LeafHubName][request.EventType] = *request.SentAt
+		}
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.pending = pending
+}
+
+// ParseResyncEventTypes returns the full event types of the given names, the name can be either the full event type
+// or the short one without the prefix, e.g. "managedcluster" or "policy.localspec".
+func ParseResyncEventTypes(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("the event types must not be empty")
+	}
+	eventTypes := []string{}
+	for _, name := range names {
+		eventType := strings.TrimSpace(name)
+		if !strings.HasPrefix(eventType, enum.EventTypePrefix) {
+			eventType = enum.EventTypePrefix + eventType
+		}
+		valid := false
+		for _, resyncEventType := range ResyncEventTypes {
+			if eventType == string(resyncEventType) {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("the event type %s can't be resynced", name)
+		}
+		eventTypes = append(eventTypes, eventType)
+	}
+	return eventTypes, nil
+}
+
+// RequestResync records the requests to resend the full state of the event types from the hubs, they are sent to the
+// hubs by the hub management of the leader manager once the hubs are active.
+func RequestResync(ctx context.Context, hubNames, eventTypes []string, requestedBy string,
+) ([]models.ResyncRequest, error) {
+	requests := []models.ResyncRequest{}
+	uniqueHubNames := []string{}
+	for _, hubName := range hubNames {
+		if !utils.ContainsString(uniqueHubNames, hubName) {
+			uniqueHubNames = append(uniqueHubNames, hubName)
+		}
+	}
+	hubNames = uniqueHubNames
+	if len(hubNames) == 0 {
+		return requests, nil
+	}
+	db := database.GetGorm().WithContext(ctx)
+	var count int64
+	if err := db.Model(&models.LeafHubHeartbeat{}).Where("leaf_hub_name IN ?", hubNames).
+		Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to count the hubs: %w", err)
+	}
+	if int(count) != len(hubNames) {
+		return nil, ErrHubNotFound
+	}
+
+	for _, hubName := range hubNames {
+		for _, eventType := range eventTypes {
+			requests = append(requests, models.ResyncRequest{
+				LeafHubName: hubName,
+				EventType:   eventType,
+				RequestedBy: requestedBy,
+			})
+		}
+	}
+	if err := db.Create(&requests).Error; err != nil {
+		return nil, fmt.Errorf("failed to create the resync requests: %w", err)
+	}
+	log.Infow("request to resync the hubs", "hubs", hubNames, "eventTypes", eventTypes, "requestedBy", requestedBy)
+	return requests, nil
+}
+
+// CommitResync is the committed handler of the status events, it marks the resync requests of the event type from the
+// hub as committed if they are sent before the event is created by the hub, so the bundles sent before receiving the
+// requests don't commit them. The time of the event is set by the producer of the agent, so the clocks of the hub and
+// the global hub are expected to be synchronized.
+func CommitResync(ctx context.Context, evt *cloudevents.Event) {
+	if !resyncRequests.isPending(evt.Source(), evt.Type(), evt.Time()) {
+		return
+	}
+	err := database.GetGorm().WithContext(ctx).Model(&models.ResyncRequest{}).
+		Where("leaf_hub_name = ? AND event_type = ? AND sent_at <= ? AND committed_at IS NULL",
+			evt.Source(), evt.Type(), evt.Time()).
+		Update("committed_at", time.Now()).Error
+	if err != nil {
+		log.Warnw("failed to commit the resync requests", "hub", evt.Source(), "eventType", evt.Type(), "error", err)
+		return
+	}
+	resyncRequests.remove(evt.Source(), evt.Type())
+	log.Infow("the resync is committed", "hub", evt.Source(), "eventType", enum.ShortenEventType(evt.Type()))
+}
+
+// sendResyncRequests sends the pending resync requests to the active hubs, the requests of the same hub are merged
+// into one resync event. Then the sent requests are reloaded into the tracker.
+func (h *HubManagement) sendResyncRequests(ctx context.Context) error {
+	db := database.GetGorm().WithContext(ctx)
+	var requests []models.ResyncRequest
+	if err := db.Where("sent_at IS NULL AND leaf_hub_name IN "+
+		"(SELECT leaf_hub_name FROM status.leaf_hub_heartbeats WHERE status = ?)", HubActive).
+		Order("id").Find(&requests).Error; err != nil {
+		return fmt.Errorf("failed to list the pending resync requests: %w", err)
+	}
+
+	hubEventTypes := map[string][]string{}
+	hubRequestIDs := map[string][]int64{}
+	for _, request := range requests {
+		if !utils.ContainsString(hubEventTypes[request.LeafHubName], request.EventType) {
+			hubEventTypes[request.LeafHubName] = append(hubEventTypes[request.LeafHubName], request.EventType)
+		}
+		hubRequestIDs[request.LeafHubName] = append(hubRequestIDs[request.LeafHubName], request.ID)
+	}
+	for hubName, eventTypes := range hubEventTypes {
+		if err := h.resyncEventTypes(ctx, hubName, eventTypes); err != nil {
+			log.Warnw("failed to send the resync requests", "hub", hubName, "error", err)
+			continue
+		}
+		if err := db.Model(&models.ResyncRequest{}).Where("id IN ?", hubRequestIDs[hubName]).
+			Update("sent_at", time.Now()).Error; err != nil {
+			return fmt.Errorf("failed to mark the resync requests of the hub %s as sent: %w", hubName, err)
+		}
+		log.Infow("sent the resync requests", "hub", hubName, "eventTypes", eventTypes)
+	}
+
+	var sentRequests []models.ResyncRequest
+	if err := db.Select("leaf_hub_name", "event_type", "sent_at").
+		Where("sent_at IS NOT NULL AND committed_at IS NULL").Find(&sentRequests).Error; err != nil {
+		return fmt.Errorf("failed to list the sent resync requests: %w", err)
+	}
+	resyncRequests.reset(sentRequests)
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package hubmanagement
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+// ResyncRequestedByAnnotation identifies the resync requests created by the annotation of the hub cluster
+const ResyncRequestedByAnnotation = "annotation"
+
+type hubResyncController struct {
+	client client.Client
+}
+
+// AddHubResyncController requests the resync of the hub by the resync annotation of the managed hub cluster, the
+// annotation is removed once the request is recorded, so it can be annotated again for the next resync.
+func AddHubResyncController(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("hub-resync-controller").
+		For(&clusterv1.ManagedCluster{}).
+		WithEventFilter(predicate.NewPredicateFuncs(func(object client.Object) bool {
+			_, found := object.GetAnnotations()[constants.HubResyncAnnotation]
+			return found
+		})).
+		Complete(&hubResyncController{client: mgr.GetClient()})
+}
+
+func (c *hubResyncController) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	cluster := &clusterv1.ManagedCluster{}
+	if err := c.client.Get(ctx, request.NamespacedName, cluster); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	value, found := cluster.GetAnnotations()[constants.HubResyncAnnotation]
+	if !found {
+		return ctrl.Result{}, nil
+	}
+
+	eventTypes, err := ParseResyncEventTypes(strings.Split(value, ","))
+	if err != nil {
+		log.Warnw("invalid resync annotation, remove it", "name", cluster.Name, "value", value, "error", err)
+	} else {
+		_, err = RequestResync(ctx, []string{cluster.Name}, eventTypes, ResyncRequestedByAnnotation)
+		if errors.Is(err, ErrHubNotFound) {
+			log.Infow("the hub hasn't sent the heartbeat, skip the resync", "name", cluster.Name)
+		} else if err != nil {
+			return ctrl.Result{Requeue: true, RequeueAfter: 5 * time.Second}, err
+		}
+	}
+
+	delete(cluster.Annotations, constants.HubResyncAnnotation)
+	if err := c.client.Update(ctx, cluster); err != nil {
+		return ctrl.Result{Requeue: true, RequeueAfter: 5 * time.Second}, err
+	}
+	return ctrl.Result{}, nil
+}
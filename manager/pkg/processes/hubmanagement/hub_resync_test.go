@@ -0,0 +1,56 @@
+package hubmanagement
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestParseResyncEventTypes(t *testing.T) {
+	eventTypes, err := ParseResyncEventTypes([]string{"managedcluster", " policy.localspec",
+		string(enum.LocalComplianceType)})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		string(enum.ManagedClusterType),
+		string(enum.LocalPolicySpecType),
+		string(enum.LocalComplianceType),
+	}, eventTypes)
+
+	_, err = ParseResyncEventTypes([]string{"managedhub.heartbeat"})
+	assert.ErrorContains(t, err, "managedhub.heartbeat can't be resynced")
+
+	_, err = ParseResyncEventTypes(nil)
+	assert.Error(t, err)
+}
+
+func TestResyncTracker(t *testing.T) {
+	sentAt := time.Now()
+	laterSentAt := sentAt.Add(time.Minute)
+	tracker := &resyncTracker{}
+	assert.False(t, tracker.isPending("hub1", string(enum.ManagedClusterType), sentAt))
+
+	tracker.reset([]models.ResyncRequest{
+		{LeafHubName: "hub1", EventType: string(enum.ManagedClusterType), SentAt: &laterSentAt},
+		{LeafHubName: "hub1", EventType: string(enum.ManagedClusterType), SentAt: &sentAt},
+		{LeafHubName: "hub1", EventType: string(enum.LocalPolicySpecType), SentAt: &sentAt},
+		{LeafHubName: "hub2", EventType: string(enum.ManagedClusterType), SentAt: &sentAt},
+		{LeafHubName: "hub2", EventType: string(enum.LocalPolicySpecType)},
+	})
+	assert.True(t, tracker.isPending("hub1", string(enum.ManagedClusterType), sentAt))
+	assert.True(t, tracker.isPending("hub2", string(enum.ManagedClusterType), laterSentAt))
+	assert.False(t, tracker.isPending("hub2", string(enum.LocalPolicySpecType), laterSentAt))
+
+	// the bundle created before the request is sent doesn't commit it
+	assert.False(t, tracker.isPending("hub1", string(enum.ManagedClusterType), sentAt.Add(-time.Second)))
+	assert.False(t, tracker.isPending("hub1", string(enum.ManagedClusterType), time.Time{}))
+
+	tracker.remove("hub1", string(enum.ManagedClusterType))
+	assert.False(t, tracker.isPending("hub1", string(enum.ManagedClusterType), laterSentAt))
+	assert.True(t, tracker.isPending("hub1", string(enum.LocalPolicySpecType), laterSentAt))
+
+	tracker.remove("hub3", string(enum.ManagedClusterType))
+}
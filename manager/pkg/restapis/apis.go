@@ -16,9 +16,15 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authentication"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/deadletters"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/graphql"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/leafhubs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/managedclusters"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/policies"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/simulation"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/subscriptions"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/violations"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 )
 
@@ -30,6 +36,11 @@ type RestApiServerConfig struct {
 	ClusterAPIURL          string
 	ClusterAPICABundlePath string
 	ServerBasePath         string
+	// AuthorizationConfigPath is the file mapping the user groups to the allowed leaf hubs and managed cluster sets,
+	// all the authenticated users are allowed to access everything if it's empty
+	AuthorizationConfigPath string
+	// EnableGlobalResource serves the apis depending on the tables of the global resources, e.g. the label conflicts
+	EnableGlobalResource bool
 }
 
 // NeedLeaderElection implements the LeaderElectionRunnable interface, which indicates
@@ -109,15 +120,43 @@ func SetupRouter(nonK8sAPIServerConfig *RestApiServerConfig) (*gin.Engine, error
 		}
 		router.Use(authentication.Authentication(nonK8sAPIServerConfig.ClusterAPIURL, clusterAPICABundle))
 	}
+	// scope the resources by the groups of the authenticated user
+	if nonK8sAPIServerConfig.AuthorizationConfigPath != "" {
+		authorizationHandler, err := authorization.Authorization(nonK8sAPIServerConfig.AuthorizationConfigPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize the authorization: %w", err)
+		}
+		router.Use(authorizationHandler)
+	}
 
 	routerGroup := router.Group(nonK8sAPIServerConfig.ServerBasePath)
 	routerGroup.GET("/managedclusters", managedclusters.ListManagedClusters())
 	routerGroup.PATCH("/managedcluster/:clusterID",
 		managedclusters.PatchManagedCluster())
+	routerGroup.GET("/managedcluster/:clusterID/compliance", managedclusters.GetManagedClusterCompliance())
+	if nonK8sAPIServerConfig.EnableGlobalResource {
+		routerGroup.GET("/labelconflicts", managedclusters.ListLabelConflicts())
+	}
 	routerGroup.GET("/policies", policies.ListPolicies())
 	routerGroup.GET("/policy/:policyID/status", policies.GetPolicyStatus())
 	routerGroup.GET("/subscriptions", subscriptions.ListSubscriptions())
 	routerGroup.GET("/subscriptionreport/:subscriptionID", subscriptions.GetSubscriptionReport())
+	routerGroup.GET("/deadletters", deadletters.ListDeadLetters())
+	routerGroup.POST("/deadletter/:deadLetterID/replay", deadletters.ReplayDeadLetter())
+	routerGroup.GET("/violations", violations.ListViolations())
+	routerGroup.PUT("/leafhub/:leafHubName/maintenance", leafhubs.EnterMaintenance())
+	routerGroup.DELETE("/leafhub/:leafHubName/maintenance", leafhubs.ExitMaintenance())
+	routerGroup.GET("/leafhubs", leafhubs.ListLeafHubs())
+	routerGroup.GET("/resyncrequests", leafhubs.ListResyncRequests())
+	routerGroup.POST("/resyncrequests", leafhubs.RequestResync())
+	routerGroup.POST("/simulation", simulation.Simulate(nonK8sAPIServerConfig.EnableGlobalResource))
+
+	graphqlHandler, err := graphql.GraphQL()
+	if err != nil {
+		return nil, err
+	}
+	routerGroup.GET("/graphql", graphqlHandler)
+	routerGroup.POST("/graphql", graphqlHandler)
 
 	return router, nil
 }
@@ -0,0 +1,270 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package authorization
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+	"sigs.k8s.io/yaml"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authentication"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const (
+	// ScopeKey - the key for the authorized scope of the user in context.
+	ScopeKey = "scope"
+	// Wildcard matches all the leaf hubs or managed cluster sets in the rule.
+	Wildcard = "*"
+)
+
+var auditLog = logger.ZapLogger("restapi-audit")
+
+// Rule grants the members of the groups to access the clusters managed by the leaf hubs, or the clusters belonging
+// to the managed cluster sets.
+type Rule struct {
+	Groups             []string `json:"groups"`
+	LeafHubs           []string `json:"leafHubs,omitempty"`
+	ManagedClusterSets []string `json:"managedClusterSets,omitempty"`
+}
+
+// Config is the authorization config of the rest api, e.g.
+//
+//	rules:
+//	- groups: ["platform-admins"]
+//	  leafHubs: ["*"]
+//	- groups: ["team-a"]
+//	  leafHubs: ["hub1"]
+//	  managedClusterSets: ["team-a"]
+type Config struct {
+	Rules []Rule `json:"rules"`
+}
+
+// LoadConfig reads the authorization config from the file, the names of the leaf hubs and managed cluster sets must
+// be valid kubernetes names, since they're used in the sql conditions.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path) // #nosec G304
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the authorization config: %w", err)
+	}
+	config := &Config{}
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("failed to parse the authorization config: %w", err)
+	}
+	for _, rule := range config.Rules {
+		for _, name := range append(append([]string{}, rule.LeafHubs...), rule.ManagedClusterSets...) {
+			if name == Wildcard {
+				continue
+			}
+			if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+				return nil, fmt.Errorf("invalid name %q in the authorization config: %s", name, strings.Join(errs, ", "))
+			}
+		}
+	}
+	return config, nil
+}
+
+// Scope returns the union of the scopes granted to the groups, or nil if none of the groups is granted.
+func (c *Config) Scope(groups []string) *Scope {
+	var scope *Scope
+	for _, rule := range c.Rules {
+		if !containsAny(rule.Groups, groups) {
+			continue
+		}
+		if scope == nil {
+			scope = &Scope{}
+		}
+		for _, leafHub := range rule.LeafHubs {
+			if leafHub == Wildcard {
+				scope.All = true
+			}
+			scope.LeafHubs = appendUnique(scope.LeafHubs, leafHub)
+		}
+		for _, clusterSet := range rule.ManagedClusterSets {
+			if clusterSet == Wildcard {
+				scope.All = true
+			}
+			scope.ManagedClusterSets = appendUnique(scope.ManagedClusterSets, clusterSet)
+		}
+	}
+	return scope
+}
+
+// Scope is the leaf hubs and managed cluster sets the user is allowed to access.
+type Scope struct {
+	All                bool
+	LeafHubs           []string
+	ManagedClusterSets []string
+}
+
+// GetScope returns the authorized scope of the request, it's unrestricted if the authorization isn't enabled.
+func GetScope(ginCtx *gin.Context) *Scope {
+	if val, found := ginCtx.Get(ScopeKey); found {
+		if scope, ok := val.(*Scope); ok {
+			return scope
+		}
+	}
+	return &Scope{All: true}
+}
+
+// AllowsLeafHub returns true if all the clusters of the leaf hub are allowed.
+func (s *Scope) AllowsLeafHub(leafHubName string) bool {
+	return s.All || contains(s.LeafHubs, leafHubName)
+}
+
+// AllowsCluster returns true if the cluster managed by the leaf hub, with the cluster set label, is allowed.
+func (s *Scope) AllowsCluster(leafHubName string, labels map[string]string) bool {
+	return s.AllowsLeafHub(leafHubName) || contains(s.ManagedClusterSets, labels[clusterv1beta2.ClusterSetLabel])
+}
+
+// LeafHubCondition returns the sql condition filtering the rows by the leaf hub column, it's empty if unrestricted.
+func (s *Scope) LeafHubCondition(leafHubColumn string) string {
+	if s.All {
+		return ""
+	}
+	return fmt.Sprintf(" AND %s IN %s", leafHubColumn, sqlList(s.LeafHubs))
+}
+
+// ClusterCondition returns the sql condition filtering the managed cluster rows by the leaf hub column and the
+// cluster set label of the payload column.
+func (s *Scope) ClusterCondition(leafHubColumn, payloadColumn string) string {
+	if s.All {
+		return ""
+	}
+	return fmt.Sprintf(" AND (%s IN %s OR %s -> 'metadata' -> 'labels' ->> '%s' IN %s)",
+		leafHubColumn, sqlList(s.LeafHubs), payloadColumn, clusterv1beta2.ClusterSetLabel,
+		sqlList(s.ManagedClusterSets))
+}
+
+// ClusterRefCondition returns the sql condition filtering the rows referring to the clusters by the leaf hub and
+// cluster name columns, e.g. the compliance of the clusters.
+func (s *Scope) ClusterRefCondition(leafHubColumn, clusterNameColumn string) string {
+	if s.All {
+		return ""
+	}
+	return fmt.Sprintf(" AND (%s IN %s OR (%s, %s) IN (SELECT leaf_hub_name, payload -> 'metadata' ->> 'name' "+
+		"FROM status.managed_clusters WHERE deleted_at IS NULL AND payload -> 'metadata' -> 'labels' ->> '%s' IN %s))",
+		leafHubColumn, sqlList(s.LeafHubs), leafHubColumn, clusterNameColumn, clusterv1beta2.ClusterSetLabel,
+		sqlList(s.ManagedClusterSets))
+}
+
+// ClusterIDCondition returns the sql condition filtering the rows referring to the clusters by the leaf hub and
+// cluster id columns, e.g. the compliance history of the clusters.
+func (s *Scope) ClusterIDCondition(leafHubColumn, clusterIDColumn string) string {
+	if s.All {
+		return ""
+	}
+	return fmt.Sprintf(" AND (%s IN %s OR %s IN (SELECT cluster_id FROM status.managed_clusters "+
+		"WHERE deleted_at IS NULL AND payload -> 'metadata' -> 'labels' ->> '%s' IN %s))",
+		leafHubColumn, sqlList(s.LeafHubs), clusterIDColumn, clusterv1beta2.ClusterSetLabel,
+		sqlList(s.ManagedClusterSets))
+}
+
+// Deny aborts the request with 403, and writes the audit log.
+func Deny(ginCtx *gin.Context, reason string) {
+	user, _ := ginCtx.Get(authentication.UserKey)
+	groups, _ := ginCtx.Get(authentication.GroupsKey)
+	auditLog.Infow("request denied", "user", user, "groups", groups, "method", ginCtx.Request.Method,
+		"path", ginCtx.Request.URL.Path, "reason", reason)
+	ginCtx.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": reason})
+}
+
+// Authorization middleware, it resolves the scope of the authenticated user by the groups, the request is denied
+// if none of the groups is granted. The config file is reloaded once it's changed, e.g. the configmap is updated.
+func Authorization(configPath string) (gin.HandlerFunc, error) {
+	authorizer := &authorizer{log: logger.ZapLogger("restapi-authorization"), configPath: configPath}
+	if _, err := authorizer.getConfig(); err != nil {
+		return nil, err
+	}
+
+	return func(ginCtx *gin.Context) {
+		config, err := authorizer.getConfig()
+		if err != nil {
+			authorizer.log.Warnw("failed to reload the authorization config, using the previous one", "error", err)
+		}
+
+		var groups []string
+		if val, found := ginCtx.Get(authentication.GroupsKey); found {
+			groups, _ = val.([]string)
+		}
+		scope := config.Scope(groups)
+		if scope == nil {
+			Deny(ginCtx, "none of the user groups is granted to access the global hub api")
+			return
+		}
+		ginCtx.Set(ScopeKey, scope)
+		ginCtx.Next()
+	}, nil
+}
+
+type authorizer struct {
+	log        *zap.SugaredLogger
+	configPath string
+	mu         sync.Mutex
+	config     *Config
+	modTime    time.Time
+}
+
+// getConfig returns the latest config, and the previous config with the error if it fails to reload
+func (a *authorizer) getConfig() (*Config, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	info, err := os.Stat(a.configPath)
+	if err != nil {
+		return a.config, fmt.Errorf("failed to stat the authorization config: %w", err)
+	}
+	if a.config != nil && info.ModTime().Equal(a.modTime) {
+		return a.config, nil
+	}
+	config, err := LoadConfig(a.configPath)
+	if err != nil {
+		return a.config, err
+	}
+	a.log.Infow("loaded the authorization config", "path", a.configPath, "rules", len(config.Rules))
+	a.config, a.modTime = config, info.ModTime()
+	return a.config, nil
+}
+
+// sqlList returns the sql list of the names, the names are validated, so they don't contain the quotes
+func sqlList(names []string) string {
+	if len(names) == 0 {
+		return "(NULL)"
+	}
+	return fmt.Sprintf("('%s')", strings.Join(names, "', '"))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(values, candidates []string) bool {
+	for _, candidate := range candidates {
+		if contains(values, candidate) {
+			return true
+		}
+	}
+	return false
+}
+
+func appendUnique(values []string, value string) []string {
+	if contains(values, value) {
+		return values
+	}
+	return append(values, value)
+}
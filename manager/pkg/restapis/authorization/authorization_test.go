@@ -0,0 +1,99 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package authorization
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authentication"
+)
+
+const testConfig = `
+rules:
+- groups: ["admins"]
+  leafHubs: ["*"]
+- groups: ["team-a"]
+  leafHubs: ["hub1"]
+  managedClusterSets: ["set-a"]
+- groups: ["team-b"]
+  leafHubs: ["hub2"]
+`
+
+func writeConfig(t *testing.T, content string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadConfig(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, testConfig))
+	require.NoError(t, err)
+	require.Len(t, config.Rules, 3)
+
+	_, err = LoadConfig(writeConfig(t, "rules:\n- groups: [\"a\"]\n  leafHubs: [\"hub1' OR '1'='1\"]\n"))
+	require.ErrorContains(t, err, "invalid name")
+}
+
+func TestScope(t *testing.T) {
+	config, err := LoadConfig(writeConfig(t, testConfig))
+	require.NoError(t, err)
+
+	require.Nil(t, config.Scope([]string{"others"}))
+	require.True(t, config.Scope([]string{"admins", "team-a"}).All)
+
+	scope := config.Scope([]string{"team-a", "team-b"})
+	require.False(t, scope.All)
+	require.Equal(t, []string{"hub1", "hub2"}, scope.LeafHubs)
+	require.True(t, scope.AllowsLeafHub("hub2"))
+	require.False(t, scope.AllowsLeafHub("hub3"))
+	require.True(t, scope.AllowsCluster("hub3", map[string]string{
+		"cluster.open-cluster-management.io/clusterset": "set-a",
+	}))
+	require.False(t, scope.AllowsCluster("hub3", nil))
+
+	require.Equal(t, " AND leaf_hub_name IN ('hub1', 'hub2')", scope.LeafHubCondition("leaf_hub_name"))
+	require.Equal(t, "", (&Scope{All: true}).LeafHubCondition("leaf_hub_name"))
+	require.Equal(t, " AND leaf_hub_name IN (NULL)", (&Scope{}).LeafHubCondition("leaf_hub_name"))
+	require.Equal(t, " AND (leaf_hub_name IN ('hub1', 'hub2') OR payload -> 'metadata' -> 'labels' ->> "+
+		"'cluster.open-cluster-management.io/clusterset' IN ('set-a'))",
+		scope.ClusterCondition("leaf_hub_name", "payload"))
+}
+
+func TestAuthorization(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	middleware, err := Authorization(writeConfig(t, testConfig))
+	require.NoError(t, err)
+
+	request := func(groups []string) (int, *Scope) {
+		var scope *Scope
+		router := gin.New()
+		router.Use(func(ginCtx *gin.Context) {
+			ginCtx.Set(authentication.GroupsKey, groups)
+		}, middleware)
+		router.GET("/test", func(ginCtx *gin.Context) {
+			scope = GetScope(ginCtx)
+			ginCtx.Status(http.StatusOK)
+		})
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+		return w.Code, scope
+	}
+
+	code, _ := request([]string{"others"})
+	require.Equal(t, http.StatusForbidden, code)
+
+	code, scope := request([]string{"team-b"})
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, []string{"hub2"}, scope.LeafHubs)
+
+	_, err = Authorization(filepath.Join(t.TempDir(), "missing.yaml"))
+	require.Error(t, err)
+}
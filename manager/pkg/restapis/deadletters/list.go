@@ -0,0 +1,68 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package deadletters
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+	defaultListLimit       = 100
+)
+
+// ListDeadLetters godoc
+// @summary list dead letters
+// @description list the status events which are failed to be persisted by the manager, the latest first
+// @accept json
+// @produce json
+// @param        leafHubName    query     string  false  "list the dead letters from the leaf hub"
+// @param        eventType      query     string  false  "list the dead letters of the event type"
+// @param        limit          query     int     false  "maximum dead letter number to receive, default is 100"
+// @success      200  {array}     models.DeadLetter
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /deadletters [get]
+func ListDeadLetters() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		limit := defaultListLimit
+		if limitStr := ginCtx.Query("limit"); limitStr != "" {
+			var err error
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limitStr)
+				return
+			}
+		}
+
+		query := database.GetReadGorm().WithContext(ginCtx.Request.Context()).Order("id DESC").Limit(limit)
+		if leafHubName := ginCtx.Query("leafHubName"); leafHubName != "" {
+			query = query.Where("leaf_hub_name = ?", leafHubName)
+		}
+		if scope := authorization.GetScope(ginCtx); !scope.All {
+			query = query.Where("leaf_hub_name IN ?", scope.LeafHubs)
+		}
+		if eventType := ginCtx.Query("eventType"); eventType != "" {
+			query = query.Where("event_type = ?", eventType)
+		}
+
+		deadLetters := []models.DeadLetter{}
+		if err := query.Find(&deadLetters).Error; err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in querying dead letters: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, deadLetters)
+	}
+}
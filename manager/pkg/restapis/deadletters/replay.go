@@ -0,0 +1,77 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package deadletters
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// ReplayDeadLetter godoc
+// @summary replay dead letter
+// @description request to replay the dead letter through the conflation manager, it's replayed asynchronously by
+// @description the leader manager, and the expired event is still dropped by the version check
+// @accept json
+// @produce json
+// @param        deadLetterID    path    int    true    "Dead Letter ID"
+// @success      202
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @security     ApiKeyAuth
+// @router /deadletter/{deadLetterID}/replay [post]
+func ReplayDeadLetter() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		deadLetterID, err := strconv.ParseInt(ginCtx.Param("deadLetterID"), 10, 64)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, "invalid dead letter ID: %s", ginCtx.Param("deadLetterID"))
+			return
+		}
+
+		db := database.GetGorm().WithContext(ginCtx.Request.Context())
+		deadLetter := &models.DeadLetter{}
+		if err := db.Select("id", "leaf_hub_name").Where("id = ?", deadLetterID).Take(deadLetter).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				ginCtx.String(http.StatusNotFound, "dead letter(%d) not found", deadLetterID)
+				return
+			}
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in querying dead letter(%d): %v\n", deadLetterID, err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		if !authorization.GetScope(ginCtx).AllowsLeafHub(deadLetter.LeafHubName) {
+			authorization.Deny(ginCtx, fmt.Sprintf("dead letter(%d) of leaf hub %s is out of the authorized scope",
+				deadLetterID, deadLetter.LeafHubName))
+			return
+		}
+
+		// reset the replayed_at, so the dead letter can be replayed again
+		result := db.Model(&models.DeadLetter{}).
+			Where("id = ?", deadLetterID).
+			Updates(map[string]interface{}{"replay_requested_at": time.Now(), "replayed_at": nil})
+		if result.Error != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in requesting to replay dead letter(%d): %v\n",
+				deadLetterID, result.Error)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		if result.RowsAffected == 0 {
+			ginCtx.String(http.StatusNotFound, "dead letter(%d) not found", deadLetterID)
+			return
+		}
+		ginCtx.Status(http.StatusAccepted)
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	gql "github.com/graphql-go/graphql"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+)
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// GraphQL godoc
+// @summary query the global hub database with graphql
+// @description query the managed clusters, leaf hubs, policies, compliance, events and security alert counts
+// @description with graphql, the query can be sent by the POST body or the GET query parameters
+// @accept json
+// @produce json
+// @param        query          query     string  false  "the graphql query of the GET request"
+// @param        operationName  query     string  false  "the operation name of the GET request"
+// @param        variables      query     string  false  "the json encoded variables of the GET request"
+// @success      200
+// @failure      400
+// @failure      401
+// @failure      403
+// @security     ApiKeyAuth
+// @router /graphql [post]
+func GraphQL() (gin.HandlerFunc, error) {
+	schema, err := NewSchema()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the graphql schema: %w", err)
+	}
+
+	return func(ginCtx *gin.Context) {
+		req := request{}
+		if ginCtx.Request.Method == http.MethodGet {
+			req.Query = ginCtx.Query("query")
+			req.OperationName = ginCtx.Query("operationName")
+			if variables := ginCtx.Query("variables"); variables != "" {
+				if err := json.Unmarshal([]byte(variables), &req.Variables); err != nil {
+					ginCtx.String(http.StatusBadRequest, "invalid variables: %v", err)
+					return
+				}
+			}
+		} else if err := ginCtx.ShouldBindJSON(&req); err != nil {
+			ginCtx.String(http.StatusBadRequest, "invalid graphql request: %v", err)
+			return
+		}
+		if req.Query == "" {
+			ginCtx.String(http.StatusBadRequest, "the graphql query is required")
+			return
+		}
+
+		result := gql.Do(gql.Params{
+			Schema:         schema,
+			RequestString:  req.Query,
+			VariableValues: req.Variables,
+			OperationName:  req.OperationName,
+			Context:        withScope(ginCtx.Request.Context(), authorization.GetScope(ginCtx)),
+		})
+		ginCtx.JSON(http.StatusOK, result)
+	}, nil
+}
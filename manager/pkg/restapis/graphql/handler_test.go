@@ -0,0 +1,64 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package graphql
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQLHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	handler, err := GraphQL()
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/graphql", handler)
+	router.POST("/graphql", handler)
+
+	serve := func(req *http.Request) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		result := map[string]interface{}{}
+		_ = json.Unmarshal(w.Body.Bytes(), &result)
+		return w.Code, result
+	}
+
+	t.Run("introspect the query type", func(t *testing.T) {
+		body := `{"query": "{ __type(name: \"ManagedCluster\") { fields { name } } }"}`
+		code, result := serve(httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)))
+		require.Equal(t, http.StatusOK, code)
+		require.Nil(t, result["errors"])
+		fields := result["data"].(map[string]interface{})["__type"].(map[string]interface{})["fields"]
+		require.Contains(t, fields, map[string]interface{}{"name": "compliance"})
+	})
+
+	t.Run("query by the get request", func(t *testing.T) {
+		query := url.QueryEscape(`query Q($name: String!) { __type(name: $name) { name } }`)
+		variables := url.QueryEscape(`{"name": "LeafHub"}`)
+		code, result := serve(httptest.NewRequest(http.MethodGet,
+			"/graphql?query="+query+"&variables="+variables, nil))
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, map[string]interface{}{"__type": map[string]interface{}{"name": "LeafHub"}}, result["data"])
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		body := `{"query": "{ managedClusters { unknown } }"}`
+		code, result := serve(httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)))
+		require.Equal(t, http.StatusOK, code)
+		require.NotEmpty(t, result["errors"])
+	})
+
+	t.Run("missing query", func(t *testing.T) {
+		code, _ := serve(httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{}`)))
+		require.Equal(t, http.StatusBadRequest, code)
+	})
+}
@@ -0,0 +1,420 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	gql "github.com/graphql-go/graphql"
+	"gorm.io/gorm"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// the order of the managed clusters, it's the same as the rest api
+	clusterOrder = "payload -> 'metadata' ->> 'name', cluster_id"
+	clusterLabel = "payload -> 'metadata' -> 'labels' ->> ?"
+)
+
+var errInternal = errors.New("internal error")
+
+type scopeKey struct{}
+
+// withScope returns the context carrying the authorized scope of the request
+func withScope(ctx context.Context, scope *authorization.Scope) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+func scopeOf(ctx context.Context) *authorization.Scope {
+	if scope, ok := ctx.Value(scopeKey{}).(*authorization.Scope); ok {
+		return scope
+	}
+	return &authorization.Scope{All: true}
+}
+
+// managedCluster is the managed cluster row with the metadata decoded from the payload
+type managedCluster struct {
+	models.ManagedCluster
+	metadata metav1.ObjectMeta
+}
+
+func toManagedClusters(rows []models.ManagedCluster) []managedCluster {
+	clusters := make([]managedCluster, 0, len(rows))
+	for _, row := range rows {
+		object := &metav1.PartialObjectMetadata{}
+		if err := json.Unmarshal(row.Payload, object); err != nil {
+			logger.ZapLogger("restapi-graphql").Warnw("failed to decode the managed cluster",
+				"clusterId", row.ClusterID, "error", err)
+		}
+		clusters = append(clusters, managedCluster{ManagedCluster: row, metadata: object.ObjectMeta})
+	}
+	return clusters
+}
+
+// newQuery returns the query of the request, the rows are filtered by the scope condition of the request
+func newQuery(ctx context.Context, scopeCondition func(*authorization.Scope) string) *gorm.DB {
+	query := database.GetReadGorm().WithContext(ctx)
+	if condition := scopeCondition(scopeOf(ctx)); condition != "" {
+		query = query.Where("TRUE" + condition)
+	}
+	return query
+}
+
+func unscoped(*authorization.Scope) string {
+	return ""
+}
+
+func leafHubScope(column string) func(*authorization.Scope) string {
+	return func(scope *authorization.Scope) string {
+		return scope.LeafHubCondition(column)
+	}
+}
+
+func clusterScope(scope *authorization.Scope) string {
+	return scope.ClusterCondition("leaf_hub_name", "payload")
+}
+
+func clusterRefScope(scope *authorization.Scope) string {
+	return scope.ClusterRefCondition("leaf_hub_name", "cluster_name")
+}
+
+// policyScope allows the policies of the leaf hubs, and the policies applied to the allowed clusters
+func policyScope(scope *authorization.Scope) string {
+	if scope.All {
+		return ""
+	}
+	return fmt.Sprintf(" AND (TRUE%s OR policy_id IN (SELECT policy_id FROM local_status.compliance WHERE TRUE%s))",
+		scope.LeafHubCondition("leaf_hub_name"), clusterRefScope(scope))
+}
+
+// find runs the paginated query, the database error is logged and hidden from the client
+func find[T any](query *gorm.DB, args map[string]interface{}, order string) ([]T, error) {
+	limit, _ := args["limit"].(int)
+	offset, _ := args["offset"].(int)
+	if limit <= 0 || limit > maxLimit {
+		return nil, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+	}
+	if offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+
+	rows := []T{}
+	if err := query.Order(order).Limit(limit).Offset(offset).Find(&rows).Error; err != nil {
+		logger.ZapLogger("restapi-graphql").Errorw("failed to query the database", "error", err)
+		return nil, errInternal
+	}
+	return rows, nil
+}
+
+// first returns the first row of the query, or nil if not found
+func first[T any](query *gorm.DB) (interface{}, error) {
+	rows := []T{}
+	if err := query.Limit(1).Find(&rows).Error; err != nil {
+		logger.ZapLogger("restapi-graphql").Errorw("failed to query the database", "error", err)
+		return nil, errInternal
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	return rows[0], nil
+}
+
+// whereArgs adds the equality conditions of the string arguments, the key is the argument and the value is the column
+func whereArgs(query *gorm.DB, args map[string]interface{}, columns map[string]string) *gorm.DB {
+	for arg, column := range columns {
+		if value, ok := args[arg].(string); ok && value != "" {
+			query = query.Where(column+" = ?", value)
+		}
+	}
+	return query
+}
+
+func whereSince(query *gorm.DB, args map[string]interface{}) *gorm.DB {
+	if since, ok := args["since"].(time.Time); ok {
+		query = query.Where("created_at >= ?", since)
+	}
+	return query
+}
+
+// whereLabelSelector translates the label selector of the managed clusters into the parameterized conditions
+func whereLabelSelector(query *gorm.DB, selector string) (*gorm.DB, error) {
+	parsed, err := labels.Parse(selector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid label selector: %w", err)
+	}
+	requirements, _ := parsed.Requirements()
+	for _, requirement := range requirements {
+		key, values := requirement.Key(), requirement.Values().List()
+		switch requirement.Operator() {
+		case selection.Equals, selection.DoubleEquals:
+			query = query.Where(clusterLabel+" = ?", key, values[0])
+		case selection.NotEquals:
+			query = query.Where("("+clusterLabel+" IS NULL OR "+clusterLabel+" <> ?)", key, key, values[0])
+		case selection.In:
+			query = query.Where(clusterLabel+" IN ?", key, values)
+		case selection.NotIn:
+			query = query.Where("("+clusterLabel+" IS NULL OR "+clusterLabel+" NOT IN ?)", key, key, values)
+		case selection.Exists:
+			query = query.Where(clusterLabel+" IS NOT NULL", key)
+		case selection.DoesNotExist:
+			query = query.Where(clusterLabel+" IS NULL", key)
+		default:
+			return nil, fmt.Errorf("unsupported operator %q of the label selector", requirement.Operator())
+		}
+	}
+	return query, nil
+}
+
+func listManagedClusters(p gql.ResolveParams) (interface{}, error) {
+	query := whereArgs(newQuery(p.Context, clusterScope), p.Args, map[string]string{
+		"leafHubName": "leaf_hub_name",
+		"name":        "payload -> 'metadata' ->> 'name'",
+	})
+	if selector, ok := p.Args["labelSelector"].(string); ok && selector != "" {
+		var err error
+		if query, err = whereLabelSelector(query, selector); err != nil {
+			return nil, err
+		}
+	}
+	rows, err := find[models.ManagedCluster](query, p.Args, clusterOrder)
+	if err != nil {
+		return nil, err
+	}
+	return toManagedClusters(rows), nil
+}
+
+func listLeafHubs(p gql.ResolveParams) (interface{}, error) {
+	query := whereArgs(newQuery(p.Context, leafHubScope("leaf_hub_name")), p.Args, map[string]string{
+		"name": "leaf_hub_name",
+	})
+	return find[models.LeafHub](query, p.Args, "leaf_hub_name")
+}
+
+func listPolicies(p gql.ResolveParams) (interface{}, error) {
+	query := whereArgs(newQuery(p.Context, policyScope), p.Args, map[string]string{
+		"leafHubName": "leaf_hub_name",
+		"name":        "policy_name",
+	})
+	return find[models.LocalSpecPolicy](query, p.Args, "policy_name, policy_id")
+}
+
+func listCompliance(p gql.ResolveParams) (interface{}, error) {
+	query := whereArgs(newQuery(p.Context, clusterRefScope), p.Args, map[string]string{
+		"leafHubName": "leaf_hub_name",
+		"clusterName": "cluster_name",
+		"policyId":    "policy_id",
+		"state":       "compliance",
+	})
+	return find[models.LocalStatusCompliance](query, p.Args, "leaf_hub_name, cluster_name, policy_id")
+}
+
+func listComplianceHistory(p gql.ResolveParams) (interface{}, error) {
+	query := whereArgs(newQuery(p.Context, func(scope *authorization.Scope) string {
+		return scope.ClusterIDCondition("leaf_hub_name", "cluster_id")
+	}), p.Args, map[string]string{
+		"leafHubName": "leaf_hub_name",
+		"policyId":    "policy_id",
+		"clusterId":   "cluster_id",
+		"state":       "compliance",
+	})
+	for arg, condition := range map[string]string{"from": "compliance_date >= ?", "to": "compliance_date <= ?"} {
+		value, ok := p.Args[arg].(string)
+		if !ok || value == "" {
+			continue
+		}
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q of %s, the format should be %s", value, arg, dateLayout)
+		}
+		query = query.Where(condition, date)
+	}
+	return find[models.LocalComplianceHistory](query, p.Args,
+		"compliance_date DESC, leaf_hub_name, policy_id, cluster_id")
+}
+
+func listManagedClusterEvents(p gql.ResolveParams) (interface{}, error) {
+	query := whereArgs(newQuery(p.Context, clusterRefScope), p.Args, map[string]string{
+		"leafHubName": "leaf_hub_name",
+		"clusterName": "cluster_name",
+	})
+	return find[models.ManagedClusterEvent](whereSince(query, p.Args), p.Args, "created_at DESC")
+}
+
+func listPolicyEvents(p gql.ResolveParams) (interface{}, error) {
+	query := whereArgs(newQuery(p.Context, clusterRefScope), p.Args, map[string]string{
+		"leafHubName": "leaf_hub_name",
+		"policyId":    "policy_id",
+		"clusterName": "cluster_name",
+	})
+	return find[models.LocalReplicatedPolicyEvent](whereSince(query, p.Args), p.Args, "created_at DESC")
+}
+
+func listRootPolicyEvents(p gql.ResolveParams) (interface{}, error) {
+	query := whereArgs(newQuery(p.Context, leafHubScope("leaf_hub_name")), p.Args, map[string]string{
+		"leafHubName": "leaf_hub_name",
+		"policyId":    "policy_id",
+	})
+	rows, err := find[models.LocalRootPolicyEvent](whereSince(query, p.Args), p.Args, "created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	events := make([]models.LocalReplicatedPolicyEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, models.LocalReplicatedPolicyEvent{BaseLocalPolicyEvent: row.BaseLocalPolicyEvent})
+	}
+	return events, nil
+}
+
+func listClusterGroupUpgradeEvents(p gql.ResolveParams) (interface{}, error) {
+	query := whereArgs(newQuery(p.Context, leafHubScope("leaf_hub_name")), p.Args, map[string]string{
+		"leafHubName": "leaf_hub_name",
+		"cguName":     "cgu_name",
+	})
+	return find[models.ClusterGroupUpgradeEvent](whereSince(query, p.Args), p.Args, "created_at DESC")
+}
+
+func listAlertCounts(p gql.ResolveParams) (interface{}, error) {
+	query := whereArgs(newQuery(p.Context, leafHubScope("hub_name")), p.Args, map[string]string{
+		"hubName": "hub_name",
+	})
+	return find[models.SecurityAlertCounts](query, p.Args, "hub_name")
+}
+
+func resolveLeafHubOfCluster(p gql.ResolveParams) (interface{}, error) {
+	cluster, ok := p.Source.(managedCluster)
+	if !ok {
+		return nil, nil
+	}
+	return first[models.LeafHub](newQuery(p.Context, leafHubScope("leaf_hub_name")).
+		Where("leaf_hub_name = ?", cluster.LeafHubName))
+}
+
+func resolveComplianceOfCluster(p gql.ResolveParams) (interface{}, error) {
+	cluster, ok := p.Source.(managedCluster)
+	if !ok {
+		return nil, nil
+	}
+	query := newQuery(p.Context, unscoped).Where("leaf_hub_name = ? AND cluster_name = ?",
+		cluster.LeafHubName, cluster.metadata.Name)
+	return find[models.LocalStatusCompliance](whereArgs(query, p.Args, map[string]string{"state": "compliance"}),
+		p.Args, "policy_id")
+}
+
+func resolveEventsOfCluster(p gql.ResolveParams) (interface{}, error) {
+	cluster, ok := p.Source.(managedCluster)
+	if !ok {
+		return nil, nil
+	}
+	return find[models.ManagedClusterEvent](newQuery(p.Context, unscoped).Where(
+		"leaf_hub_name = ? AND cluster_name = ?", cluster.LeafHubName, cluster.metadata.Name),
+		p.Args, "created_at DESC")
+}
+
+func resolveHeartbeatOfLeafHub(p gql.ResolveParams) (interface{}, error) {
+	hub, ok := p.Source.(models.LeafHub)
+	if !ok {
+		return nil, nil
+	}
+	return first[models.LeafHubHeartbeat](newQuery(p.Context, unscoped).
+		Where("leaf_hub_name = ?", hub.LeafHubName))
+}
+
+func resolveAlertCountsOfLeafHub(p gql.ResolveParams) (interface{}, error) {
+	hub, ok := p.Source.(models.LeafHub)
+	if !ok {
+		return nil, nil
+	}
+	return first[models.SecurityAlertCounts](newQuery(p.Context, unscoped).Where("hub_name = ?", hub.LeafHubName))
+}
+
+func resolveClustersOfLeafHub(p gql.ResolveParams) (interface{}, error) {
+	hub, ok := p.Source.(models.LeafHub)
+	if !ok {
+		return nil, nil
+	}
+	rows, err := find[models.ManagedCluster](newQuery(p.Context, clusterScope).
+		Where("leaf_hub_name = ?", hub.LeafHubName), p.Args, clusterOrder)
+	if err != nil {
+		return nil, err
+	}
+	return toManagedClusters(rows), nil
+}
+
+func resolveComplianceOfPolicy(p gql.ResolveParams) (interface{}, error) {
+	policy, ok := p.Source.(models.LocalSpecPolicy)
+	if !ok {
+		return nil, nil
+	}
+	query := newQuery(p.Context, clusterRefScope).Where("policy_id = ?", policy.PolicyID)
+	return find[models.LocalStatusCompliance](whereArgs(query, p.Args, map[string]string{"state": "compliance"}),
+		p.Args, "leaf_hub_name, cluster_name")
+}
+
+func resolveEventsOfPolicy(p gql.ResolveParams) (interface{}, error) {
+	policy, ok := p.Source.(models.LocalSpecPolicy)
+	if !ok {
+		return nil, nil
+	}
+	rows, err := find[models.LocalRootPolicyEvent](newQuery(p.Context, leafHubScope("leaf_hub_name")).
+		Where("policy_id = ?", policy.PolicyID), p.Args, "created_at DESC")
+	if err != nil {
+		return nil, err
+	}
+	events := make([]models.LocalReplicatedPolicyEvent, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, models.LocalReplicatedPolicyEvent{BaseLocalPolicyEvent: row.BaseLocalPolicyEvent})
+	}
+	return events, nil
+}
+
+func resolveClusterEventsOfPolicy(p gql.ResolveParams) (interface{}, error) {
+	policy, ok := p.Source.(models.LocalSpecPolicy)
+	if !ok {
+		return nil, nil
+	}
+	query := newQuery(p.Context, clusterRefScope).Where("policy_id = ?", policy.PolicyID)
+	return find[models.LocalReplicatedPolicyEvent](whereArgs(query, p.Args, map[string]string{
+		"clusterName": "cluster_name",
+	}), p.Args, "created_at DESC")
+}
+
+func resolvePolicyOfCompliance(p gql.ResolveParams) (interface{}, error) {
+	compliance, ok := p.Source.(models.LocalStatusCompliance)
+	if !ok {
+		return nil, nil
+	}
+	return first[models.LocalSpecPolicy](newQuery(p.Context, unscoped).Where("policy_id = ?", compliance.PolicyID))
+}
+
+func resolveClusterOfCompliance(p gql.ResolveParams) (interface{}, error) {
+	compliance, ok := p.Source.(models.LocalStatusCompliance)
+	if !ok {
+		return nil, nil
+	}
+	cluster, err := first[models.ManagedCluster](newQuery(p.Context, unscoped).Where(
+		"leaf_hub_name = ? AND payload -> 'metadata' ->> 'name' = ?", compliance.LeafHubName, compliance.ClusterName))
+	if err != nil || cluster == nil {
+		return nil, err
+	}
+	return toManagedClusters([]models.ManagedCluster{cluster.(models.ManagedCluster)})[0], nil
+}
+
+func nilIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
@@ -0,0 +1,436 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package graphql
+
+import (
+	"encoding/json"
+
+	gql "github.com/graphql-go/graphql"
+	"gorm.io/datatypes"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 1000
+)
+
+// jsonScalar is the output only scalar of the jsonb columns, e.g. the payload of the kubernetes resources
+var jsonScalar = gql.NewScalar(gql.ScalarConfig{
+	Name:        "JSON",
+	Description: "The JSON value, e.g. the payload of the kubernetes resource.",
+	Serialize: func(value interface{}) interface{} {
+		raw, ok := value.(datatypes.JSON)
+		if !ok {
+			return value
+		}
+		if len(raw) == 0 {
+			return nil
+		}
+		var obj interface{}
+		if err := json.Unmarshal(raw, &obj); err != nil {
+			return nil
+		}
+		return obj
+	},
+})
+
+var complianceStateEnum = gql.NewEnum(gql.EnumConfig{
+	Name: "ComplianceState",
+	Values: gql.EnumValueConfigMap{
+		"COMPLIANT":     {Value: string(database.Compliant)},
+		"NON_COMPLIANT": {Value: string(database.NonCompliant)},
+		"PENDING":       {Value: string(database.Pending)},
+		"UNKNOWN":       {Value: string(database.Unknown)},
+	},
+})
+
+// pageArgs returns the pagination arguments with the additional arguments of the field
+func pageArgs(args gql.FieldConfigArgument) gql.FieldConfigArgument {
+	pagedArgs := gql.FieldConfigArgument{
+		"limit": {
+			Type:         gql.Int,
+			DefaultValue: defaultLimit,
+			Description:  "The max number of the items to return, it's between 1 and 1000, default is 100.",
+		},
+		"offset": {
+			Type:         gql.Int,
+			DefaultValue: 0,
+			Description:  "The number of the items to skip.",
+		},
+	}
+	for name, arg := range args {
+		pagedArgs[name] = arg
+	}
+	return pagedArgs
+}
+
+func stringArg(description string) *gql.ArgumentConfig {
+	return &gql.ArgumentConfig{Type: gql.String, Description: description}
+}
+
+// resolve returns the field resolver reading the value from the source object
+func resolve[T any](fn func(T) interface{}) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		source, ok := p.Source.(T)
+		if !ok {
+			return nil, nil
+		}
+		return fn(source), nil
+	}
+}
+
+func field[T any](typ gql.Output, fn func(T) interface{}) *gql.Field {
+	return &gql.Field{Type: typ, Resolve: resolve(fn)}
+}
+
+// NewSchema builds the graphql schema over the tables of the global hub database.
+func NewSchema() (gql.Schema, error) {
+	var managedClusterType, leafHubType, policyType, complianceType *gql.Object
+
+	heartbeatType := gql.NewObject(gql.ObjectConfig{
+		Name: "LeafHubHeartbeat",
+		Fields: gql.Fields{
+			"status": field(gql.String, func(h models.LeafHubHeartbeat) interface{} { return h.Status }),
+			"lastTimestamp": field(gql.DateTime,
+				func(h models.LeafHubHeartbeat) interface{} { return h.LastUpdateAt }),
+		},
+	})
+
+	alertCountsType := gql.NewObject(gql.ObjectConfig{
+		Name:        "SecurityAlertCounts",
+		Description: "The summary of the security alerts of the leaf hub.",
+		Fields: gql.Fields{
+			"hubName":   field(gql.String, func(a models.SecurityAlertCounts) interface{} { return a.HubName }),
+			"low":       field(gql.Int, func(a models.SecurityAlertCounts) interface{} { return a.Low }),
+			"medium":    field(gql.Int, func(a models.SecurityAlertCounts) interface{} { return a.Medium }),
+			"high":      field(gql.Int, func(a models.SecurityAlertCounts) interface{} { return a.High }),
+			"critical":  field(gql.Int, func(a models.SecurityAlertCounts) interface{} { return a.Critical }),
+			"detailUrl": field(gql.String, func(a models.SecurityAlertCounts) interface{} { return a.DetailURL }),
+			"source":    field(gql.String, func(a models.SecurityAlertCounts) interface{} { return a.Source }),
+			"createdAt": field(gql.DateTime, func(a models.SecurityAlertCounts) interface{} { return a.CreatedAt }),
+			"updatedAt": field(gql.DateTime, func(a models.SecurityAlertCounts) interface{} { return a.UpdatedAt }),
+		},
+	})
+
+	managedClusterEventType := gql.NewObject(gql.ObjectConfig{
+		Name: "ManagedClusterEvent",
+		Fields: gql.Fields{
+			"eventName": field(gql.String, func(e models.ManagedClusterEvent) interface{} { return e.EventName }),
+			"eventNamespace": field(gql.String,
+				func(e models.ManagedClusterEvent) interface{} { return e.EventNamespace }),
+			"clusterName": field(gql.String, func(e models.ManagedClusterEvent) interface{} { return e.ClusterName }),
+			"clusterId":   field(gql.String, func(e models.ManagedClusterEvent) interface{} { return e.ClusterID }),
+			"leafHubName": field(gql.String, func(e models.ManagedClusterEvent) interface{} { return e.LeafHubName }),
+			"message":     field(gql.String, func(e models.ManagedClusterEvent) interface{} { return e.Message }),
+			"reason":      field(gql.String, func(e models.ManagedClusterEvent) interface{} { return e.Reason }),
+			"reportingController": field(gql.String,
+				func(e models.ManagedClusterEvent) interface{} { return e.ReportingController }),
+			"reportingInstance": field(gql.String,
+				func(e models.ManagedClusterEvent) interface{} { return e.ReportingInstance }),
+			"type":      field(gql.String, func(e models.ManagedClusterEvent) interface{} { return e.EventType }),
+			"createdAt": field(gql.DateTime, func(e models.ManagedClusterEvent) interface{} { return e.CreatedAt }),
+		},
+	})
+
+	// the root policy events are converted to the policy events without the cluster
+	policyEventType := gql.NewObject(gql.ObjectConfig{
+		Name: "PolicyEvent",
+		Fields: gql.Fields{
+			"eventName": field(gql.String,
+				func(e models.LocalReplicatedPolicyEvent) interface{} { return e.EventName }),
+			"eventNamespace": field(gql.String,
+				func(e models.LocalReplicatedPolicyEvent) interface{} { return e.EventNamespace }),
+			"policyId": field(gql.String,
+				func(e models.LocalReplicatedPolicyEvent) interface{} { return e.PolicyID }),
+			"clusterId": field(gql.String,
+				func(e models.LocalReplicatedPolicyEvent) interface{} { return nilIfEmpty(e.ClusterID) }),
+			"clusterName": field(gql.String,
+				func(e models.LocalReplicatedPolicyEvent) interface{} { return nilIfEmpty(e.ClusterName) }),
+			"leafHubName": field(gql.String,
+				func(e models.LocalReplicatedPolicyEvent) interface{} { return e.LeafHubName }),
+			"message": field(gql.String,
+				func(e models.LocalReplicatedPolicyEvent) interface{} { return e.Message }),
+			"reason": field(gql.String,
+				func(e models.LocalReplicatedPolicyEvent) interface{} { return e.Reason }),
+			"count": field(gql.Int,
+				func(e models.LocalReplicatedPolicyEvent) interface{} { return e.Count }),
+			"source": field(jsonScalar,
+				func(e models.LocalReplicatedPolicyEvent) interface{} { return e.Source }),
+			"compliance": field(gql.String,
+				func(e models.LocalReplicatedPolicyEvent) interface{} { return e.Compliance }),
+			"createdAt": field(gql.DateTime,
+				func(e models.LocalReplicatedPolicyEvent) interface{} { return e.CreatedAt }),
+		},
+	})
+
+	clusterGroupUpgradeEventType := gql.NewObject(gql.ObjectConfig{
+		Name: "ClusterGroupUpgradeEvent",
+		Fields: gql.Fields{
+			"eventName": field(gql.String,
+				func(e models.ClusterGroupUpgradeEvent) interface{} { return e.EventName }),
+			"eventNamespace": field(gql.String,
+				func(e models.ClusterGroupUpgradeEvent) interface{} { return e.EventNamespace }),
+			"eventAnnotations": field(jsonScalar,
+				func(e models.ClusterGroupUpgradeEvent) interface{} { return e.EventAnns }),
+			"cguName": field(gql.String,
+				func(e models.ClusterGroupUpgradeEvent) interface{} { return e.CGUName }),
+			"leafHubName": field(gql.String,
+				func(e models.ClusterGroupUpgradeEvent) interface{} { return e.LeafHubName }),
+			"message": field(gql.String,
+				func(e models.ClusterGroupUpgradeEvent) interface{} { return e.Message }),
+			"reason": field(gql.String,
+				func(e models.ClusterGroupUpgradeEvent) interface{} { return e.Reason }),
+			"reportingController": field(gql.String,
+				func(e models.ClusterGroupUpgradeEvent) interface{} { return e.ReportingController }),
+			"reportingInstance": field(gql.String,
+				func(e models.ClusterGroupUpgradeEvent) interface{} { return e.ReportingInstance }),
+			"type": field(gql.String,
+				func(e models.ClusterGroupUpgradeEvent) interface{} { return e.EventType }),
+			"createdAt": field(gql.DateTime,
+				func(e models.ClusterGroupUpgradeEvent) interface{} { return e.CreatedAt }),
+		},
+	})
+
+	complianceHistoryType := gql.NewObject(gql.ObjectConfig{
+		Name:        "ComplianceHistory",
+		Description: "The daily compliance of the cluster to the policy.",
+		Fields: gql.Fields{
+			"policyId": field(gql.String,
+				func(h models.LocalComplianceHistory) interface{} { return h.PolicyID }),
+			"clusterId": field(gql.String,
+				func(h models.LocalComplianceHistory) interface{} { return h.ClusterID }),
+			"leafHubName": field(gql.String,
+				func(h models.LocalComplianceHistory) interface{} { return h.LeafHubName }),
+			"date": field(gql.String,
+				func(h models.LocalComplianceHistory) interface{} { return h.ComplianceDate.Format(dateLayout) }),
+			"state": field(complianceStateEnum,
+				func(h models.LocalComplianceHistory) interface{} { return h.Compliance }),
+			"changedFrequency": field(gql.Int,
+				func(h models.LocalComplianceHistory) interface{} { return h.ComplianceChangedFrequency }),
+		},
+	})
+
+	complianceStateArg := &gql.ArgumentConfig{Type: complianceStateEnum, Description: "filter by the compliance state"}
+
+	managedClusterType = gql.NewObject(gql.ObjectConfig{
+		Name: "ManagedCluster",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"id":          field(gql.String, func(c managedCluster) interface{} { return c.ClusterID }),
+				"name":        field(gql.String, func(c managedCluster) interface{} { return c.metadata.Name }),
+				"leafHubName": field(gql.String, func(c managedCluster) interface{} { return c.LeafHubName }),
+				"labels":      field(jsonScalar, func(c managedCluster) interface{} { return c.metadata.Labels }),
+				"payload":     field(jsonScalar, func(c managedCluster) interface{} { return c.Payload }),
+				"error":       field(gql.String, func(c managedCluster) interface{} { return c.Error }),
+				"createdAt":   field(gql.DateTime, func(c managedCluster) interface{} { return c.CreatedAt }),
+				"updatedAt":   field(gql.DateTime, func(c managedCluster) interface{} { return c.UpdatedAt }),
+				"leafHub": {
+					Type:    leafHubType,
+					Resolve: resolveLeafHubOfCluster,
+				},
+				"compliance": {
+					Type:    gql.NewList(complianceType),
+					Args:    pageArgs(gql.FieldConfigArgument{"state": complianceStateArg}),
+					Resolve: resolveComplianceOfCluster,
+				},
+				"events": {
+					Type:    gql.NewList(managedClusterEventType),
+					Args:    pageArgs(nil),
+					Resolve: resolveEventsOfCluster,
+				},
+			}
+		}),
+	})
+
+	leafHubType = gql.NewObject(gql.ObjectConfig{
+		Name: "LeafHub",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"name":      field(gql.String, func(h models.LeafHub) interface{} { return h.LeafHubName }),
+				"clusterId": field(gql.String, func(h models.LeafHub) interface{} { return h.ClusterID }),
+				"payload":   field(jsonScalar, func(h models.LeafHub) interface{} { return h.Payload }),
+				"createdAt": field(gql.DateTime, func(h models.LeafHub) interface{} { return h.CreatedAt }),
+				"updatedAt": field(gql.DateTime, func(h models.LeafHub) interface{} { return h.UpdatedAt }),
+				"heartbeat": {
+					Type:    heartbeatType,
+					Resolve: resolveHeartbeatOfLeafHub,
+				},
+				"alertCounts": {
+					Type:    alertCountsType,
+					Resolve: resolveAlertCountsOfLeafHub,
+				},
+				"managedClusters": {
+					Type:    gql.NewList(managedClusterType),
+					Args:    pageArgs(nil),
+					Resolve: resolveClustersOfLeafHub,
+				},
+			}
+		}),
+	})
+
+	policyType = gql.NewObject(gql.ObjectConfig{
+		Name:        "Policy",
+		Description: "The policy created on the leaf hub.",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"id":   field(gql.String, func(p models.LocalSpecPolicy) interface{} { return p.PolicyID }),
+				"name": field(gql.String, func(p models.LocalSpecPolicy) interface{} { return p.PolicyName }),
+				"leafHubName": field(gql.String,
+					func(p models.LocalSpecPolicy) interface{} { return p.LeafHubName }),
+				"standard": field(gql.String, func(p models.LocalSpecPolicy) interface{} { return p.PolicyStandard }),
+				"category": field(gql.String, func(p models.LocalSpecPolicy) interface{} { return p.PolicyCategory }),
+				"control":  field(gql.String, func(p models.LocalSpecPolicy) interface{} { return p.PolicyControl }),
+				"payload":  field(jsonScalar, func(p models.LocalSpecPolicy) interface{} { return p.Payload }),
+				"createdAt": field(gql.DateTime,
+					func(p models.LocalSpecPolicy) interface{} { return p.CreatedAt }),
+				"updatedAt": field(gql.DateTime,
+					func(p models.LocalSpecPolicy) interface{} { return p.UpdatedAt }),
+				"compliance": {
+					Type:    gql.NewList(complianceType),
+					Args:    pageArgs(gql.FieldConfigArgument{"state": complianceStateArg}),
+					Resolve: resolveComplianceOfPolicy,
+				},
+				"events": {
+					Type:        gql.NewList(policyEventType),
+					Description: "The events of the root policy.",
+					Args:        pageArgs(nil),
+					Resolve:     resolveEventsOfPolicy,
+				},
+				"clusterEvents": {
+					Type:        gql.NewList(policyEventType),
+					Description: "The events of the policy replicated to the clusters.",
+					Args:        pageArgs(gql.FieldConfigArgument{"clusterName": stringArg("filter by the cluster")}),
+					Resolve:     resolveClusterEventsOfPolicy,
+				},
+			}
+		}),
+	})
+
+	complianceType = gql.NewObject(gql.ObjectConfig{
+		Name:        "Compliance",
+		Description: "The current compliance of the cluster to the policy.",
+		Fields: gql.FieldsThunk(func() gql.Fields {
+			return gql.Fields{
+				"policyId": field(gql.String,
+					func(c models.LocalStatusCompliance) interface{} { return c.PolicyID }),
+				"clusterName": field(gql.String,
+					func(c models.LocalStatusCompliance) interface{} { return c.ClusterName }),
+				"leafHubName": field(gql.String,
+					func(c models.LocalStatusCompliance) interface{} { return c.LeafHubName }),
+				"state": field(complianceStateEnum,
+					func(c models.LocalStatusCompliance) interface{} { return string(c.Compliance) }),
+				"error": field(gql.String,
+					func(c models.LocalStatusCompliance) interface{} { return c.Error }),
+				"policy": {
+					Type:    policyType,
+					Resolve: resolvePolicyOfCompliance,
+				},
+				"cluster": {
+					Type:    managedClusterType,
+					Resolve: resolveClusterOfCompliance,
+				},
+			}
+		}),
+	})
+
+	leafHubNameArg := stringArg("filter by the leaf hub")
+	sinceArg := &gql.ArgumentConfig{Type: gql.DateTime, Description: "the events created since the time"}
+
+	query := gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"managedClusters": {
+				Type: gql.NewList(managedClusterType),
+				Args: pageArgs(gql.FieldConfigArgument{
+					"leafHubName":   leafHubNameArg,
+					"name":          stringArg("filter by the cluster name"),
+					"labelSelector": stringArg("filter by the label selector, e.g. env=prod,vendor in (OpenShift)"),
+				}),
+				Resolve: listManagedClusters,
+			},
+			"leafHubs": {
+				Type:    gql.NewList(leafHubType),
+				Args:    pageArgs(gql.FieldConfigArgument{"name": stringArg("filter by the leaf hub name")}),
+				Resolve: listLeafHubs,
+			},
+			"policies": {
+				Type: gql.NewList(policyType),
+				Args: pageArgs(gql.FieldConfigArgument{
+					"leafHubName": leafHubNameArg,
+					"name":        stringArg("filter by the policy name"),
+				}),
+				Resolve: listPolicies,
+			},
+			"compliance": {
+				Type: gql.NewList(complianceType),
+				Args: pageArgs(gql.FieldConfigArgument{
+					"leafHubName": leafHubNameArg,
+					"clusterName": stringArg("filter by the cluster name"),
+					"policyId":    stringArg("filter by the policy id"),
+					"state":       complianceStateArg,
+				}),
+				Resolve: listCompliance,
+			},
+			"complianceHistory": {
+				Type: gql.NewList(complianceHistoryType),
+				Args: pageArgs(gql.FieldConfigArgument{
+					"leafHubName": leafHubNameArg,
+					"policyId":    stringArg("filter by the policy id"),
+					"clusterId":   stringArg("filter by the cluster id"),
+					"state":       complianceStateArg,
+					"from":        stringArg("the first date of the history, e.g. 2025-01-01"),
+					"to":          stringArg("the last date of the history, e.g. 2025-01-31"),
+				}),
+				Resolve: listComplianceHistory,
+			},
+			"managedClusterEvents": {
+				Type: gql.NewList(managedClusterEventType),
+				Args: pageArgs(gql.FieldConfigArgument{
+					"leafHubName": leafHubNameArg,
+					"clusterName": stringArg("filter by the cluster name"),
+					"since":       sinceArg,
+				}),
+				Resolve: listManagedClusterEvents,
+			},
+			"policyEvents": {
+				Type: gql.NewList(policyEventType),
+				Args: pageArgs(gql.FieldConfigArgument{
+					"leafHubName": leafHubNameArg,
+					"policyId":    stringArg("filter by the policy id"),
+					"clusterName": stringArg("filter by the cluster name"),
+					"since":       sinceArg,
+				}),
+				Resolve: listPolicyEvents,
+			},
+			"rootPolicyEvents": {
+				Type: gql.NewList(policyEventType),
+				Args: pageArgs(gql.FieldConfigArgument{
+					"leafHubName": leafHubNameArg,
+					"policyId":    stringArg("filter by the policy id"),
+					"since":       sinceArg,
+				}),
+				Resolve: listRootPolicyEvents,
+			},
+			"clusterGroupUpgradeEvents": {
+				Type: gql.NewList(clusterGroupUpgradeEventType),
+				Args: pageArgs(gql.FieldConfigArgument{
+					"leafHubName": leafHubNameArg,
+					"cguName":     stringArg("filter by the cluster group upgrade name"),
+					"since":       sinceArg,
+				}),
+				Resolve: listClusterGroupUpgradeEvents,
+			},
+			"alertCounts": {
+				Type:    gql.NewList(alertCountsType),
+				Args:    pageArgs(gql.FieldConfigArgument{"hubName": stringArg("filter by the leaf hub")}),
+				Resolve: listAlertCounts,
+			},
+		},
+	})
+
+	return gql.NewSchema(gql.SchemaConfig{Query: query})
+}
@@ -0,0 +1,95 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package leafhubs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// leafHubInventory is the inventory reported by the leaf hub, the status is empty if no heartbeat is received
+type leafHubInventory struct {
+	LeafHubName string                 `json:"leafHubName"`
+	ClusterID   string                 `json:"clusterId"`
+	Status      string                 `json:"status"`
+	UpdatedAt   time.Time              `json:"updatedAt"`
+	Info        cluster.HubClusterInfo `json:"info"`
+}
+
+type leafHubRow struct {
+	LeafHubName string
+	ClusterID   string
+	Status      string
+	UpdatedAt   time.Time
+	Payload     datatypes.JSON
+}
+
+// ListLeafHubs godoc
+// @summary list leaf hubs
+// @description list the inventory of the leaf hubs, e.g. the OpenShift, Kubernetes, MCH and MCE versions, the enabled
+// @description MCH components, the allocatable resources of the nodes, the managed cluster counts and the addon health
+// @accept json
+// @produce json
+// @param        leafHubName         query     string  false  "get the inventory of the leaf hub"
+// @param        mchVersion          query     string  false  "list the leaf hubs with the MCH version"
+// @param        openshiftVersion    query     string  false  "list the leaf hubs with the OpenShift version"
+// @success      200  {array}     leafhubs.leafHubInventory
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /leafhubs [get]
+func ListLeafHubs() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		scope := authorization.GetScope(ginCtx)
+		query := database.GetReadGorm().WithContext(ginCtx.Request.Context()).Table("status.leaf_hubs AS h").
+			Select("h.leaf_hub_name, h.cluster_id, COALESCE(hb.status, '') AS status, h.updated_at, h.payload").
+			Joins("LEFT JOIN status.leaf_hub_heartbeats hb ON hb.leaf_hub_name = h.leaf_hub_name").
+			Where("h.deleted_at IS NULL" + scope.LeafHubCondition("h.leaf_hub_name")).
+			Order("h.leaf_hub_name")
+		if leafHubName := ginCtx.Query("leafHubName"); leafHubName != "" {
+			query = query.Where("h.leaf_hub_name = ?", leafHubName)
+		}
+		if mchVersion := ginCtx.Query("mchVersion"); mchVersion != "" {
+			query = query.Where("h.mch_version = ?", mchVersion)
+		}
+		if openshiftVersion := ginCtx.Query("openshiftVersion"); openshiftVersion != "" {
+			query = query.Where("h.openshift_version = ?", openshiftVersion)
+		}
+
+		rows := []leafHubRow{}
+		if err := query.Scan(&rows).Error; err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in querying leaf hubs: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		inventories := make([]leafHubInventory, 0, len(rows))
+		for _, row := range rows {
+			inventory := leafHubInventory{
+				LeafHubName: row.LeafHubName,
+				ClusterID:   row.ClusterID,
+				Status:      row.Status,
+				UpdatedAt:   row.UpdatedAt,
+			}
+			if err := json.Unmarshal(row.Payload, &inventory.Info); err != nil {
+				_, _ = fmt.Fprintf(gin.DefaultWriter, "error in parsing the inventory of leaf hub %s: %v\n",
+					row.LeafHubName, err)
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				return
+			}
+			inventories = append(inventories, inventory)
+		}
+		ginCtx.JSON(http.StatusOK, inventories)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package leafhubs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// EnterMaintenance godoc
+// @summary enter maintenance
+// @description put the leaf hub into the planned maintenance, the inactivity handling and the spec pushes of the
+// @description leaf hub are paused, and the managed clusters of the leaf hub are marked as stale
+// @accept json
+// @produce json
+// @param        leafHubName    path    string    true    "Leaf Hub Name"
+// @success      200
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @security     ApiKeyAuth
+// @router /leafhub/{leafHubName}/maintenance [put]
+func EnterMaintenance() gin.HandlerFunc {
+	return switchMaintenance(http.StatusOK, hubmanagement.EnterMaintenance)
+}
+
+// ExitMaintenance godoc
+// @summary exit maintenance
+// @description request to exit the planned maintenance of the leaf hub, the leaf hub is resynced and activated
+// @description asynchronously by the leader manager
+// @accept json
+// @produce json
+// @param        leafHubName    path    string    true    "Leaf Hub Name"
+// @success      202
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @security     ApiKeyAuth
+// @router /leafhub/{leafHubName}/maintenance [delete]
+func ExitMaintenance() gin.HandlerFunc {
+	return switchMaintenance(http.StatusAccepted, hubmanagement.ExitMaintenance)
+}
+
+func switchMaintenance(successStatus int, switchFunc func(context.Context, string) error) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		leafHubName := ginCtx.Param("leafHubName")
+		if !authorization.GetScope(ginCtx).AllowsLeafHub(leafHubName) {
+			authorization.Deny(ginCtx, fmt.Sprintf("leaf hub %s is out of the authorized scope", leafHubName))
+			return
+		}
+
+		if err := switchFunc(ginCtx.Request.Context(), leafHubName); err != nil {
+			if errors.Is(err, hubmanagement.ErrHubNotFound) {
+				ginCtx.String(http.StatusNotFound, "leaf hub %s not found", leafHubName)
+				return
+			}
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in switching the maintenance of leaf hub %s: %v\n",
+				leafHubName, err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.Status(successStatus)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package leafhubs
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authentication"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const defaultListLimit = 100
+
+// resyncRequest is the body to request the resync, all the active leaf hubs in the scope are resynced if the leaf hub
+// name is empty
+type resyncRequest struct {
+	LeafHubName string   `json:"leafHubName"`
+	EventTypes  []string `json:"eventTypes"`
+}
+
+// RequestResync godoc
+// @summary request resync
+// @description request the leaf hub, or all the active leaf hubs, to resend the full state of the event types, e.g.
+// @description managedcluster and policy.localspec. The requests are sent asynchronously by the leader manager, and
+// @description they're committed once the manager has handled the resent bundles
+// @accept json
+// @produce json
+// @param        body    body     leafhubs.resyncRequest  true  "the leaf hub and the event types to resync"
+// @success      202  {array}     models.ResyncRequest
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      404
+// @failure      500
+// @security     ApiKeyAuth
+// @router /resyncrequests [post]
+func RequestResync() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		body := &resyncRequest{}
+		if err := ginCtx.BindJSON(body); err != nil {
+			ginCtx.String(http.StatusBadRequest, "invalid resync request: %s", err.Error())
+			return
+		}
+		eventTypes, err := hubmanagement.ParseResyncEventTypes(body.EventTypes)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		scope := authorization.GetScope(ginCtx)
+		hubNames := []string{}
+		switch {
+		case body.LeafHubName == "":
+			err := database.GetGorm().WithContext(ginCtx.Request.Context()).Model(&models.LeafHubHeartbeat{}).
+				Where("status = ?"+scope.LeafHubCondition("leaf_hub_name"), hubmanagement.HubActive).
+				Pluck("leaf_hub_name", &hubNames).Error
+			if err != nil {
+				_, _ = fmt.Fprintf(gin.DefaultWriter, "error in querying the active leaf hubs: %v\n", err)
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				return
+			}
+		case !scope.AllowsLeafHub(body.LeafHubName):
+			authorization.Deny(ginCtx, fmt.Sprintf("leaf hub %s is out of the authorized scope", body.LeafHubName))
+			return
+		default:
+			hubNames = append(hubNames, body.LeafHubName)
+		}
+
+		requestedBy := ginCtx.GetString(authentication.UserKey)
+		requests, err := hubmanagement.RequestResync(ginCtx.Request.Context(), hubNames, eventTypes, requestedBy)
+		if err != nil {
+			if errors.Is(err, hubmanagement.ErrHubNotFound) {
+				ginCtx.String(http.StatusNotFound, "leaf hub %s not found", body.LeafHubName)
+				return
+			}
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in requesting the resync: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusAccepted, requests)
+	}
+}
+
+// ListResyncRequests godoc
+// @summary list resync requests
+// @description list the resync requests with the progress, the request is sent once the sentAt is set, and the
+// @description resent bundle is committed once the committedAt is set, the latest first
+// @accept json
+// @produce json
+// @param        leafHubName    query     string  false  "list the resync requests of the leaf hub"
+// @param        pending        query     bool    false  "list the resync requests which are not committed"
+// @param        limit          query     int     false  "maximum resync request number to receive, default is 100"
+// @success      200  {array}     models.ResyncRequest
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /resyncrequests [get]
+func ListResyncRequests() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		limit := defaultListLimit
+		if limitStr := ginCtx.Query("limit"); limitStr != "" {
+			var err error
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limitStr)
+				return
+			}
+		}
+
+		query := database.GetReadGorm().WithContext(ginCtx.Request.Context()).Order("id DESC").Limit(limit)
+		if leafHubName := ginCtx.Query("leafHubName"); leafHubName != "" {
+			query = query.Where("leaf_hub_name = ?", leafHubName)
+		}
+		if scope := authorization.GetScope(ginCtx); !scope.All {
+			query = query.Where("leaf_hub_name IN ?", scope.LeafHubs)
+		}
+		if pending, _ := strconv.ParseBool(ginCtx.Query("pending")); pending {
+			query = query.Where("committed_at IS NULL")
+		}
+
+		requests := []models.ResyncRequest{}
+		if err := query.Find(&requests).Error; err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in querying resync requests: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, requests)
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedclusters
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// managedClusterComplianceAt is the compliance of the local policies on the managed cluster at a past time.
+type managedClusterComplianceAt struct {
+	ClusterID string                     `json:"clusterId"`
+	At        time.Time                  `json:"at"`
+	Policies  []models.LocalComplianceAt `json:"policies"`
+}
+
+// GetManagedClusterCompliance godoc
+// @summary get managed cluster compliance
+// @description rebuild the compliance of the local policies on a given managed cluster at a past time
+// @accept json
+// @produce json
+// @param        clusterID    path     string    true     "Managed Cluster ID"
+// @param        at           query    string    false    "RFC3339 time to rebuild the compliance at, default is now"
+// @success      200  {object}    managedClusterComplianceAt
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /managedcluster/{clusterID}/compliance [get]
+func GetManagedClusterCompliance() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		clusterID := ginCtx.Param("clusterID")
+		at, ok := util.ParseTimeQuery(ginCtx, "at", time.Now())
+		if !ok {
+			return
+		}
+
+		scopeInSql := authorization.GetScope(ginCtx).ClusterIDCondition("leaf_hub_name", "cluster_id")
+		policies := []models.LocalComplianceAt{}
+		err := database.GetReadGorm().WithContext(ginCtx.Request.Context()).Raw(util.LocalComplianceAtQuery+
+			" AND cluster_id = ?"+scopeInSql+" ORDER BY leaf_hub_name, policy_id", at.Format(time.RFC3339Nano),
+			clusterID).Scan(&policies).Error
+		if err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in rebuilding the managed cluster compliance: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		ginCtx.JSON(http.StatusOK, &managedClusterComplianceAt{ClusterID: clusterID, At: at, Policies: policies})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package managedclusters
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const defaultLabelConflictsLimit = 100
+
+// ListLabelConflicts godoc
+// @summary list managed cluster label conflicts
+// @description list the labels changed on the leaf hubs that conflict with the global desired labels, the latest first
+// @accept json
+// @produce json
+// @param        leafHubName    query     string  false  "list the label conflicts from the leaf hub"
+// @param        clusterName    query     string  false  "list the label conflicts of the managed cluster"
+// @param        limit          query     int     false  "maximum label conflict number to receive, default is 100"
+// @success      200  {array}     models.LabelConflict
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /labelconflicts [get]
+func ListLabelConflicts() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		limit := defaultLabelConflictsLimit
+		if limitStr := ginCtx.Query("limit"); limitStr != "" {
+			var err error
+			if limit, err = strconv.Atoi(limitStr); err != nil || limit <= 0 {
+				ginCtx.String(http.StatusBadRequest, "invalid limit: %s", limitStr)
+				return
+			}
+		}
+
+		query := database.GetReadGorm().WithContext(ginCtx.Request.Context()).
+			Order("updated_at DESC").Order("label_key").Limit(limit)
+		// filter the conflicts by the authorized leaf hubs and managed cluster sets
+		if scopeInSql := authorization.GetScope(ginCtx).ClusterRefCondition("leaf_hub_name",
+			"managed_cluster_name"); scopeInSql != "" {
+			query = query.Where(strings.TrimPrefix(scopeInSql, " AND "))
+		}
+		if leafHubName := ginCtx.Query("leafHubName"); leafHubName != "" {
+			query = query.Where("leaf_hub_name = ?", leafHubName)
+		}
+		if clusterName := ginCtx.Query("clusterName"); clusterName != "" {
+			query = query.Where("managed_cluster_name = ?", clusterName)
+		}
+
+		conflicts := []models.LabelConflict{}
+		if err := query.Find(&conflicts).Error; err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in querying label conflicts: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, conflicts)
+	}
+}
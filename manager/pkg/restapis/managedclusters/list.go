@@ -24,7 +24,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	clusterv1 "open-cluster-management.io/api/cluster/v1"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 )
 
@@ -33,11 +36,20 @@ const (
 	syncIntervalInSeconds                       = 4
 	onlyPatchOfLabelsIsImplemented              = "only patch of labels is currently implemented"
 	onlyAddOrRemoveAreImplemented               = "only add or remove operations are currently implemented"
+	invalidLabelPolicy                          = "the label policy must be either hub-wins or global-wins"
 	noRowsAffectedByOptimisticConcurrencyUpdate = "no rows were affected by an optimistic-concurrency update query"
 	optimisticConcurrencyRetryAttempts          = 5
 	crdName                                     = "managedclusters.cluster.open-cluster-management.io"
 )
 
+// stalePayloadColumn marks the managed clusters of the hubs in maintenance with the stale annotation, the status of
+// the clusters is the last known status before the maintenance
+var stalePayloadColumn = fmt.Sprintf(`CASE WHEN leaf_hub_name IN (SELECT leaf_hub_name FROM status.leaf_hub_heartbeats
+	WHERE status IN ('%s', '%s')) THEN jsonb_set(payload, '{metadata,annotations}',
+	COALESCE(payload -> 'metadata' -> 'annotations', '{}'::jsonb) || '{"%s": "true"}'::jsonb)
+	ELSE payload END AS payload`, hubmanagement.HubMaintenance, hubmanagement.HubResuming,
+	constants.StaleStatusAnnotation)
+
 // ListManagedClusters godoc
 // @summary list managed clusters
 // @description list managed clusters
@@ -107,10 +119,15 @@ func ListManagedClusters() gin.HandlerFunc {
 			lastManagedClusterName,
 			lastManagedClusterUID)
 
+		// filter the managed clusters by the authorized leaf hubs and managed cluster sets
+		scopeInSql := authorization.GetScope(ginCtx).ClusterCondition("leaf_hub_name", "payload")
+
 		// managed cluster list query order by name and uid with limit if set
-		managedClusterListQuery := "SELECT payload FROM status.managed_clusters WHERE deleted_at is NULL AND " +
+		managedClusterListQuery := "SELECT " + stalePayloadColumn + " FROM status.managed_clusters WHERE " +
+			"deleted_at is NULL AND " +
 			LastResourceCompareCondition +
 			selectorInSql +
+			scopeInSql +
 			" ORDER BY (payload -> 'metadata' ->> 'name', cluster_id)"
 
 		// add limit
@@ -126,8 +143,8 @@ func ListManagedClusters() gin.HandlerFunc {
 		}
 
 		// last managed cluster query order by name and cluster id
-		lastManagedClusterQuery := "SELECT payload FROM status.managed_clusters WHERE deleted_at is NULL " +
-			"ORDER BY (payload -> 'metadata' ->> 'name', cluster_id) DESC LIMIT 1"
+		lastManagedClusterQuery := "SELECT payload FROM status.managed_clusters WHERE deleted_at is NULL" +
+			scopeInSql + " ORDER BY (payload -> 'metadata' ->> 'name', cluster_id) DESC LIMIT 1"
 
 		handleRows(ginCtx, managedClusterListQuery, lastManagedClusterQuery,
 			customResourceColumnDefinitions)
@@ -173,7 +190,7 @@ func handleRowsForWatch(ginCtx *gin.Context, managedClusterListQuery string) {
 func doHandleRowsForWatch(ctx context.Context, writer io.Writer, managedClusterListQuery string,
 	preAddedManagedClusterNames set.Set,
 ) {
-	db := database.GetGorm()
+	db := database.GetReadGorm()
 	rows, err := db.WithContext(ctx).Raw(managedClusterListQuery).Rows()
 	if err != nil {
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "error in quering managed cluster list: %v\n", err)
@@ -241,7 +258,7 @@ func doHandleRowsForWatch(ctx context.Context, writer io.Writer, managedClusterL
 func handleRows(ginCtx *gin.Context, managedClusterListQuery, lastManagedClusterQuery string,
 	customResourceColumnDefinitions []apiextensionsv1.CustomResourceColumnDefinition,
 ) {
-	db := database.GetGorm()
+	db := database.GetReadGorm()
 	ctx := ginCtx.Request.Context()
 
 	// load the lastManaged cluster
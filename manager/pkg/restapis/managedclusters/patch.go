@@ -4,6 +4,7 @@
 package managedclusters
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,7 +13,9 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
@@ -21,12 +24,15 @@ var (
 	errOnlyPatchOfLabelsIsImplemented   = errors.New(onlyPatchOfLabelsIsImplemented)
 	errOnlyAddOrRemoveAreImplemented    = errors.New(onlyAddOrRemoveAreImplemented)
 	errOptimisticConcurrencyWriteFailed = errors.New(noRowsAffectedByOptimisticConcurrencyUpdate)
+	errInvalidLabelPolicy               = errors.New(invalidLabelPolicy)
 )
 
 type patch struct {
 	Op    string `json:"op" binding:"required"`
 	Path  string `json:"path" binding:"required"`
 	Value string `json:"value"`
+	// Policy resolves the conflict when the label is changed on the hub, either hub-wins or global-wins
+	Policy string `json:"policy,omitempty"`
 }
 
 // PatchManagedCluster godoc
@@ -53,12 +59,21 @@ func PatchManagedCluster() gin.HandlerFunc {
 
 		db := database.GetGorm()
 		var leafHubName, managedClusterName string
-		if err := db.Raw(`SELECT leaf_hub_name, payload->'metadata'->>'name' FROM status.managed_clusters 
-			WHERE cluster_id = ?`, clusterID).Row().Scan(&leafHubName, &managedClusterName); err != nil {
+		var clusterSetName sql.NullString
+		if err := db.Raw(`SELECT leaf_hub_name, payload->'metadata'->>'name', payload->'metadata'->'labels'->>? 
+			FROM status.managed_clusters WHERE cluster_id = ?`, clusterv1beta2.ClusterSetLabel, clusterID).Row().Scan(
+			&leafHubName, &managedClusterName, &clusterSetName); err != nil {
 			_, _ = fmt.Fprintf(gin.DefaultWriter, "failed to get leaf hub and manged cluster name: %s\n", err.Error())
 			return
 		}
 
+		scope := authorization.GetScope(ginCtx)
+		if !scope.AllowsCluster(leafHubName, map[string]string{clusterv1beta2.ClusterSetLabel: clusterSetName.String}) {
+			authorization.Deny(ginCtx, fmt.Sprintf("managed cluster %s of leaf hub %s is out of the authorized scope",
+				managedClusterName, leafHubName))
+			return
+		}
+
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "patch for managed cluster: %s -leaf hub: %s\n",
 			managedClusterName, leafHubName)
 
@@ -70,12 +85,23 @@ func PatchManagedCluster() gin.HandlerFunc {
 			return
 		}
 
-		labelsToAdd, labelsToRemove, err := getLabels(ginCtx, patches)
+		labelsToAdd, labelsToRemove, labelPolicies, err := getLabels(ginCtx, patches)
 		if err != nil {
 			_, _ = fmt.Fprintf(gin.DefaultWriter, "failed to get labels: %s\n", err.Error())
 			return
 		}
 
+		// the user granted by the cluster sets isn't allowed to move the cluster across the cluster sets
+		if !scope.AllowsLeafHub(leafHubName) {
+			_, add := labelsToAdd[clusterv1beta2.ClusterSetLabel]
+			_, remove := labelsToRemove[clusterv1beta2.ClusterSetLabel]
+			if add || remove {
+				authorization.Deny(ginCtx, fmt.Sprintf("the label %s of managed cluster %s can't be patched",
+					clusterv1beta2.ClusterSetLabel, managedClusterName))
+				return
+			}
+		}
+
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "labels to add: %v\n", labelsToAdd)
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "labels to remove: %v\n", labelsToRemove)
 
@@ -83,7 +109,7 @@ func PatchManagedCluster() gin.HandlerFunc {
 
 		for retryAttempts > 0 {
 			err = updateLabels(clusterID, leafHubName, managedClusterName, labelsToAdd,
-				labelsToRemove)
+				labelsToRemove, labelPolicies)
 			if err == nil {
 				break
 			}
@@ -101,7 +127,7 @@ func PatchManagedCluster() gin.HandlerFunc {
 }
 
 func updateLabels(clusterID, leafHubName, managedClusterName string, labelsToAdd map[string]string,
-	labelsToRemove map[string]struct{},
+	labelsToRemove map[string]struct{}, labelPolicies map[string]string,
 ) error {
 	if len(labelsToAdd) == 0 && len(labelsToRemove) == 0 {
 		return nil
@@ -130,12 +156,18 @@ func updateLabels(clusterID, leafHubName, managedClusterName string, labelsToAdd
 		if err != nil {
 			return err
 		}
+		labelPoliciesPayload, err := json.Marshal(labelPolicies)
+		if err != nil {
+			return err
+		}
 		return db.Create(&models.ManagedClusterLabel{
 			ID:                 clusterID,
 			LeafHubName:        leafHubName,
 			ManagedClusterName: managedClusterName,
 			Labels:             labelToLoadPayload,
 			DeletedLabelKeys:   keysToRemovePayload,
+			LastAppliedLabels:  []byte("{}"),
+			LabelPolicies:      labelPoliciesPayload,
 			Version:            0,
 		}).Error
 	}
@@ -143,6 +175,7 @@ func updateLabels(clusterID, leafHubName, managedClusterName string, labelsToAdd
 	var (
 		existLabels              map[string]string
 		existLabelsToRemoveSlice []string
+		existLabelPolicies       map[string]string
 	)
 
 	// update the labels and version
@@ -152,10 +185,15 @@ func updateLabels(clusterID, leafHubName, managedClusterName string, labelsToAdd
 	if err := json.Unmarshal(managedClusterLabels[0].DeletedLabelKeys, &existLabelsToRemoveSlice); err != nil {
 		return fmt.Errorf("failed to unmarshal currentLabelToRemoveSlice: %w", err)
 	}
+	if len(managedClusterLabels[0].LabelPolicies) > 0 {
+		if err := json.Unmarshal(managedClusterLabels[0].LabelPolicies, &existLabelPolicies); err != nil {
+			return fmt.Errorf("failed to unmarshal currentLabelPolicies: %w", err)
+		}
+	}
 	existVersion := managedClusterLabels[0].Version
 
 	err = updateRow(clusterID, labelsToAdd, existLabels, labelsToRemove,
-		getMap(existLabelsToRemoveSlice), existVersion)
+		getMap(existLabelsToRemoveSlice), labelPolicies, existLabelPolicies, existVersion)
 	if err != nil {
 		return fmt.Errorf("failed to update managed_clusters_labels table: %w", err)
 	}
@@ -169,10 +207,20 @@ func updateLabels(clusterID, leafHubName, managedClusterName string, labelsToAdd
 }
 
 func updateRow(clusterID string, labelsToAdd, existLabelsToAdd map[string]string,
-	labelsToRemove, existLabelsToRemove map[string]struct{}, existVersion int,
+	labelsToRemove, existLabelsToRemove map[string]struct{}, labelPolicies, existLabelPolicies map[string]string,
+	existVersion int,
 ) error {
 	newLabelsToAdd := make(map[string]string)
 	newLabelsToRemove := make(map[string]struct{})
+	newLabelPolicies := make(map[string]string)
+
+	for key, policy := range existLabelPolicies {
+		newLabelPolicies[key] = policy
+	}
+
+	for key, policy := range labelPolicies {
+		newLabelPolicies[key] = policy
+	}
 
 	for key := range existLabelsToRemove {
 		if _, keyToBeAdded := labelsToAdd[key]; !keyToBeAdded {
@@ -203,6 +251,10 @@ func updateRow(clusterID string, labelsToAdd, existLabelsToAdd map[string]string
 	if err != nil {
 		return err
 	}
+	newLabelPoliciesPayload, err := json.Marshal(newLabelPolicies)
+	if err != nil {
+		return err
+	}
 	ret := db.Model(&models.ManagedClusterLabel{}).Where(&models.ManagedClusterLabel{
 		ID:      clusterID,
 		Version: int(existVersion),
@@ -210,6 +262,7 @@ func updateRow(clusterID string, labelsToAdd, existLabelsToAdd map[string]string
 		ID:               clusterID,
 		Labels:           newLabelsToAddPayload,
 		DeletedLabelKeys: newKeysToRemovePayload,
+		LabelPolicies:    newLabelPoliciesPayload,
 		Version:          int(existVersion) + 1,
 	})
 
@@ -245,9 +298,12 @@ func getKeys(aMap map[string]struct{}) []string {
 	return keys
 }
 
-func getLabels(ginCtx *gin.Context, patches []patch) (map[string]string, map[string]struct{}, error) {
+func getLabels(ginCtx *gin.Context, patches []patch) (map[string]string, map[string]struct{},
+	map[string]string, error,
+) {
 	labelsToAdd := make(map[string]string)
 	labelsToRemove := make(map[string]struct{})
+	labelPolicies := make(map[string]string)
 
 	// from https://datatracker.ietf.org/doc/html/rfc6902:
 	// Evaluation of a JSON Patch document begins against a target JSON
@@ -265,7 +321,16 @@ func getLabels(ginCtx *gin.Context, patches []patch) (map[string]string, map[str
 				"status": onlyPatchOfLabelsIsImplemented,
 			})
 
-			return nil, nil, errOnlyPatchOfLabelsIsImplemented
+			return nil, nil, nil, errOnlyPatchOfLabelsIsImplemented
+		}
+
+		if aPatch.Policy != "" && aPatch.Policy != models.LabelPolicyHubWins &&
+			aPatch.Policy != models.LabelPolicyGlobalWins {
+			ginCtx.JSON(http.StatusBadRequest, gin.H{
+				"status": invalidLabelPolicy,
+			})
+
+			return nil, nil, nil, errInvalidLabelPolicy
 		}
 
 		label := strings.Replace(rawLabel, "~1", "/", 1)
@@ -274,6 +339,10 @@ func getLabels(ginCtx *gin.Context, patches []patch) (map[string]string, map[str
 
 			labelsToAdd[label] = aPatch.Value
 
+			if aPatch.Policy != "" {
+				labelPolicies[label] = aPatch.Policy
+			}
+
 			continue
 		}
 
@@ -289,8 +358,8 @@ func getLabels(ginCtx *gin.Context, patches []patch) (map[string]string, map[str
 			"status": onlyAddOrRemoveAreImplemented,
 		})
 
-		return nil, nil, errOnlyAddOrRemoveAreImplemented
+		return nil, nil, nil, errOnlyAddOrRemoveAreImplemented
 	}
 
-	return labelsToAdd, labelsToRemove, nil
+	return labelsToAdd, labelsToRemove, labelPolicies, nil
 }
@@ -3,6 +3,12 @@
 
 package policies
 
+import (
+	"fmt"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+)
+
 const (
 	ServerInternalErrorMsg                = "internal error"
 	QueryPolicyFailureFormatMsg           = "error in querying policy: %v\n"
@@ -14,7 +20,7 @@ const (
 const (
 	policyQuery           = `SELECT payload FROM spec.policies WHERE deleted = FALSE AND id = ?`
 	policyComplianceQuery = `SELECT cluster_name,leaf_hub_name,compliance FROM status.compliance
-		WHERE policy_id = ? ORDER BY leaf_hub_name, cluster_name`
+		WHERE policy_id = ?%s ORDER BY leaf_hub_name, cluster_name`
 	policyMappingQuery = `SELECT p.payload -> 'metadata' ->> 'name' AS policy,
 								 pb.payload -> 'metadata' ->> 'name' AS binding,
 								 pr.payload -> 'metadata' ->> 'name' AS placementrule
@@ -46,3 +52,17 @@ const (
 	dbEnumCompliant    = "compliant"
 	dbEnumNonCompliant = "non_compliant"
 )
+
+// scopedPolicyComplianceQuery returns the compliance query of the clusters in the authorized scope
+func scopedPolicyComplianceQuery(scope *authorization.Scope) string {
+	return fmt.Sprintf(policyComplianceQuery, scope.ClusterRefCondition("leaf_hub_name", "cluster_name"))
+}
+
+// scopedPolicyCondition returns the condition of the policies having compliance in the authorized scope
+func scopedPolicyCondition(scope *authorization.Scope) string {
+	if scope.All {
+		return ""
+	}
+	return " AND id IN (SELECT policy_id FROM status.compliance WHERE TRUE" +
+		scope.ClusterRefCondition("leaf_hub_name", "cluster_name") + ")"
+}
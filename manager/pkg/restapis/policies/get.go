@@ -19,8 +19,10 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
 
 // GetPolicyStatus godoc
@@ -29,6 +31,7 @@ import (
 // @accept json
 // @produce json
 // @param        policyID    path    string    true    "Policy ID"
+// @param        at          query   string    false   "RFC3339 time to rebuild the compliance of the local policy at"
 // @success      200  {object}  policyv1.Policy
 // @failure      400
 // @failure      401
@@ -42,6 +45,28 @@ func GetPolicyStatus() gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
 		policyID := ginCtx.Param("policyID")
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "getting status for policy: %s\n", policyID)
+
+		scope := authorization.GetScope(ginCtx)
+		if _, found := ginCtx.GetQuery("at"); found {
+			handlePolicyStatusAt(ginCtx, policyID, scope)
+			return
+		}
+		if !scope.All {
+			allowed := false
+			err := database.GetReadGorm().WithContext(ginCtx.Request.Context()).Raw(
+				"SELECT EXISTS (SELECT 1 FROM spec.policies WHERE id = ?"+scopedPolicyCondition(scope)+")",
+				policyID).Row().Scan(&allowed)
+			if err != nil {
+				ginCtx.String(http.StatusInternalServerError, ServerInternalErrorMsg)
+				_, _ = fmt.Fprintf(gin.DefaultWriter, QueryPolicyFailureFormatMsg, err)
+				return
+			}
+			if !allowed {
+				authorization.Deny(ginCtx, fmt.Sprintf("policy %s is out of the authorized scope", policyID))
+				return
+			}
+		}
+		policyComplianceQuery := scopedPolicyComplianceQuery(scope)
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "policy query with policy ID: %s\n", policyQuery)
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "policy compliance query with policy ID: %v\n", policyComplianceQuery)
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "policy&placementbinding&placementrule mapping query: %v\n", policyMappingQuery)
@@ -57,6 +82,34 @@ func GetPolicyStatus() gin.HandlerFunc {
 	}
 }
 
+// policyStatusAt is the compliance of the local policy on the clusters at a past time.
+type policyStatusAt struct {
+	PolicyID string                     `json:"policyId"`
+	At       time.Time                  `json:"at"`
+	Clusters []models.LocalComplianceAt `json:"clusters"`
+}
+
+// handlePolicyStatusAt rebuilds the compliance of the local policy at the given time, the clusters are filtered by the
+// authorized scope.
+func handlePolicyStatusAt(ginCtx *gin.Context, policyID string, scope *authorization.Scope) {
+	at, ok := util.ParseTimeQuery(ginCtx, "at", time.Now())
+	if !ok {
+		return
+	}
+
+	clusters := []models.LocalComplianceAt{}
+	err := database.GetReadGorm().WithContext(ginCtx.Request.Context()).Raw(util.LocalComplianceAtQuery+
+		" AND policy_id = ?"+scope.ClusterIDCondition("leaf_hub_name", "cluster_id")+
+		" ORDER BY leaf_hub_name, cluster_name", at.Format(time.RFC3339Nano), policyID).Scan(&clusters).Error
+	if err != nil {
+		ginCtx.String(http.StatusInternalServerError, ServerInternalErrorMsg)
+		_, _ = fmt.Fprintf(gin.DefaultWriter, QueryPolicyFailureFormatMsg, err)
+		return
+	}
+
+	ginCtx.JSON(http.StatusOK, &policyStatusAt{PolicyID: policyID, At: at, Clusters: clusters})
+}
+
 func handlePolicyForWatch(ginCtx *gin.Context, policyID, policyQuery, policyMappingQuery, policyComplianceQuery string,
 ) {
 	writer := ginCtx.Writer
@@ -185,7 +238,7 @@ func queryPolicyStatus(ctx context.Context, policyID, policyQuery, policyMapping
 		return &unstructured.Unstructured{}, err
 	}
 
-	db := database.GetGorm()
+	db := database.GetReadGorm()
 	var payload []byte
 	err = db.WithContext(ctx).Raw(policyQuery, policyID).Row().Scan(&payload)
 	if err != nil {
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
@@ -111,10 +112,16 @@ func ListPolicies() gin.HandlerFunc {
 			lastPolicyName,
 			lastPolicyUID)
 
+		// the policies and compliance of the clusters in the authorized scope
+		scope := authorization.GetScope(ginCtx)
+		scopeInSql := scopedPolicyCondition(scope)
+		policyComplianceQuery := scopedPolicyComplianceQuery(scope)
+
 		// policy list query order by name and uid
 		policyListQuery := "SELECT id, payload FROM spec.policies WHERE deleted = FALSE AND " +
 			LastResourceCompareCondition +
 			selectorInSql +
+			scopeInSql +
 			" ORDER BY (payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid')"
 
 		// add limit
@@ -123,8 +130,8 @@ func ListPolicies() gin.HandlerFunc {
 		}
 
 		// last policy order by name and uid query
-		lastPolicyQuery := "SELECT id, payload FROM spec.policies WHERE deleted = FALSE " +
-			"ORDER BY (payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid') DESC LIMIT 1"
+		lastPolicyQuery := "SELECT id, payload FROM spec.policies WHERE deleted = FALSE" + scopeInSql +
+			" ORDER BY (payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid') DESC LIMIT 1"
 
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "last policy query: %v\n", lastPolicyQuery)
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "policy list query: %v\n", policyListQuery)
@@ -186,7 +193,7 @@ func doHandlePoliciesForWatch(ctx context.Context, writer gin.ResponseWriter,
 	if err != nil {
 		_, _ = fmt.Fprintf(gin.DefaultWriter, QueryPolicyMappingFailureFormatMsg, err)
 	}
-	db := database.GetGorm()
+	db := database.GetReadGorm()
 	policyRows, err := db.WithContext(ctx).Raw(policyListQuery).Rows()
 	if err != nil {
 		_, _ = fmt.Fprintf(gin.DefaultWriter, QueryPoliciesFailureFormatMsg, err)
@@ -296,7 +303,7 @@ func handlePolicies(ginCtx *gin.Context, policyListQuery, lastPolicyQuery,
 	policyMappingQuery, policyComplianceQuery string,
 	customResourceColumnDefinitions []apiextensionsv1.CustomResourceColumnDefinition,
 ) {
-	db := database.GetGorm()
+	db := database.GetReadGorm()
 	ctx := ginCtx.Request.Context()
 	lastPolicy := &policyv1.Policy{}
 	lastPolicyID := ""
@@ -416,7 +423,7 @@ func handlePolicies(ginCtx *gin.Context, policyListQuery, lastPolicyQuery,
 func getPolicyMatches(ctx context.Context, policyMappingQuery string) ([]*policyMatch, error) {
 	policyMatches := []*policyMatch{}
 
-	db := database.GetGorm()
+	db := database.GetReadGorm()
 	policyMatchRows, err := db.WithContext(ctx).Raw(policyMappingQuery).Rows()
 	if err != nil {
 		return policyMatches,
@@ -446,7 +453,7 @@ func getComplianceStatus(ctx context.Context, policyComplianceQuery, policyID st
 	compliancePerClusterStatuses := []*policyv1.CompliancePerClusterStatus{}
 	hasNonCompliantClusters := false
 
-	db := database.GetGorm()
+	db := database.GetReadGorm()
 	var statusCompliances []models.StatusCompliance
 	err := db.WithContext(ctx).Where(&models.StatusCompliance{
 		PolicyID: policyID,
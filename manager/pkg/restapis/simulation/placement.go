@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+var errCelSelectorNotSupported = errors.New("the cel selector of the placement can't be simulated")
+
+// clusterSelector is the compiled label and claim selectors of a placement predicate
+type clusterSelector struct {
+	labelSelector labels.Selector
+	claimSelector labels.Selector
+}
+
+// placementEvaluator selects the managed clusters of a hub like the placement controller of the hub does, except
+// the prioritizers, so the first numberOfClusters clusters are selected by the cluster name.
+type placementEvaluator struct {
+	placement *clusterv1beta1.Placement
+	selectors []clusterSelector
+}
+
+func newPlacementEvaluator(placement *clusterv1beta1.Placement) (*placementEvaluator, error) {
+	if limit := placement.Spec.NumberOfClusters; limit != nil && *limit < 0 {
+		return nil, fmt.Errorf("invalid number of clusters: %d", *limit)
+	}
+	evaluator := &placementEvaluator{placement: placement}
+	for _, predicate := range placement.Spec.Predicates {
+		requiredSelector := predicate.RequiredClusterSelector
+		if len(requiredSelector.CelSelector.CelExpressions) > 0 {
+			return nil, errCelSelectorNotSupported
+		}
+		labelSelector, err := metav1.LabelSelectorAsSelector(&requiredSelector.LabelSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid label selector: %w", err)
+		}
+		claimSelector, err := metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+			MatchExpressions: requiredSelector.ClaimSelector.MatchExpressions,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid claim selector: %w", err)
+		}
+		evaluator.selectors = append(evaluator.selectors, clusterSelector{
+			labelSelector: labelSelector,
+			claimSelector: claimSelector,
+		})
+	}
+	return evaluator, nil
+}
+
+// matches returns true if the cluster is in the cluster sets, matches any of the predicates, and all the NoSelect
+// taints of it are tolerated. All the cluster sets are bound to the placement if the cluster sets are empty.
+func (e *placementEvaluator) matches(cluster *clusterv1.ManagedCluster) bool {
+	if len(e.placement.Spec.ClusterSets) > 0 &&
+		!utils.ContainsString(e.placement.Spec.ClusterSets, cluster.Labels[clusterv1beta2.ClusterSetLabel]) {
+		return false
+	}
+
+	for _, taint := range cluster.Spec.Taints {
+		// the simulated placement is a new placement, so the NoSelectIfNew taints prevent the selection too
+		if taint.Effect == clusterv1.TaintEffectPreferNoSelect {
+			continue
+		}
+		if !tolerated(taint, e.placement.Spec.Tolerations) {
+			return false
+		}
+	}
+
+	if len(e.selectors) == 0 {
+		return true
+	}
+	clusterLabels := labels.Set(cluster.Labels)
+	claims := labels.Set{}
+	for _, claim := range cluster.Status.ClusterClaims {
+		claims[claim.Name] = claim.Value
+	}
+	for _, selector := range e.selectors {
+		if selector.labelSelector.Matches(clusterLabels) && selector.claimSelector.Matches(claims) {
+			return true
+		}
+	}
+	return false
+}
+
+// selectClusters returns the clusters of a hub selected by the placement, and the number of the matched clusters
+// before limiting them by the numberOfClusters.
+func (e *placementEvaluator) selectClusters(clusters []*clusterv1.ManagedCluster) ([]*clusterv1.ManagedCluster, int) {
+	selected := []*clusterv1.ManagedCluster{}
+	for _, cluster := range clusters {
+		if e.matches(cluster) {
+			selected = append(selected, cluster)
+		}
+	}
+	sort.Slice(selected, func(i, j int) bool { return selected[i].Name < selected[j].Name })
+
+	matched := len(selected)
+	if limit := e.placement.Spec.NumberOfClusters; limit != nil && int(*limit) < matched {
+		selected = selected[:*limit]
+	}
+	return selected, matched
+}
+
+func tolerated(taint clusterv1.Taint, tolerations []clusterv1beta1.Toleration) bool {
+	for _, toleration := range tolerations {
+		if toleration.Effect != "" && toleration.Effect != taint.Effect {
+			continue
+		}
+		if toleration.Key == "" && toleration.Operator == clusterv1beta1.TolerationOpExists {
+			return true
+		}
+		if toleration.Key != taint.Key {
+			continue
+		}
+		switch toleration.Operator {
+		case clusterv1beta1.TolerationOpExists:
+			return true
+		case clusterv1beta1.TolerationOpEqual, "":
+			if toleration.Value == taint.Value {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,135 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	clusterv1beta2 "open-cluster-management.io/api/cluster/v1beta2"
+)
+
+func newCluster(name string, labels map[string]string, claims map[string]string,
+	taints ...clusterv1.Taint,
+) *clusterv1.ManagedCluster {
+	cluster := &clusterv1.ManagedCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Spec:       clusterv1.ManagedClusterSpec{Taints: taints},
+	}
+	for claimName, value := range claims {
+		cluster.Status.ClusterClaims = append(cluster.Status.ClusterClaims,
+			clusterv1.ManagedClusterClaim{Name: claimName, Value: value})
+	}
+	return cluster
+}
+
+func names(clusters []*clusterv1.ManagedCluster) []string {
+	clusterNames := []string{}
+	for _, cluster := range clusters {
+		clusterNames = append(clusterNames, cluster.Name)
+	}
+	return clusterNames
+}
+
+func TestPlacementEvaluator(t *testing.T) {
+	unreachable := clusterv1.Taint{
+		Key:    clusterv1.ManagedClusterTaintUnreachable,
+		Effect: clusterv1.TaintEffectNoSelect,
+	}
+	clusters := []*clusterv1.ManagedCluster{
+		newCluster("prod-2", map[string]string{"env": "prod", clusterv1beta2.ClusterSetLabel: "set1"},
+			map[string]string{"region": "us-east-1"}),
+		newCluster("prod-1", map[string]string{"env": "prod", clusterv1beta2.ClusterSetLabel: "set1"},
+			map[string]string{"region": "eu-west-1"}),
+		newCluster("prod-3", map[string]string{"env": "prod", clusterv1beta2.ClusterSetLabel: "set1"},
+			map[string]string{"region": "us-east-1"}, unreachable),
+		newCluster("dev-1", map[string]string{"env": "dev", clusterv1beta2.ClusterSetLabel: "set2"},
+			map[string]string{"region": "us-east-1"}),
+	}
+
+	cases := []struct {
+		name     string
+		spec     clusterv1beta1.PlacementSpec
+		selected []string
+		matched  int
+	}{
+		{
+			name:     "select all the clusters without the taints",
+			spec:     clusterv1beta1.PlacementSpec{},
+			selected: []string{"dev-1", "prod-1", "prod-2"},
+			matched:  3,
+		},
+		{
+			name: "select the clusters by the label or the claim",
+			spec: clusterv1beta1.PlacementSpec{
+				Predicates: []clusterv1beta1.ClusterPredicate{
+					{RequiredClusterSelector: clusterv1beta1.ClusterSelector{
+						LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "prod"}},
+						ClaimSelector: clusterv1beta1.ClusterClaimSelector{
+							MatchExpressions: []metav1.LabelSelectorRequirement{
+								{Key: "region", Operator: metav1.LabelSelectorOpIn, Values: []string{"eu-west-1"}},
+							},
+						},
+					}},
+					{RequiredClusterSelector: clusterv1beta1.ClusterSelector{
+						LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"env": "dev"}},
+					}},
+				},
+			},
+			selected: []string{"dev-1", "prod-1"},
+			matched:  2,
+		},
+		{
+			name: "select the clusters of the cluster sets and tolerate the taint",
+			spec: clusterv1beta1.PlacementSpec{
+				ClusterSets: []string{"set1"},
+				Tolerations: []clusterv1beta1.Toleration{
+					{Key: clusterv1.ManagedClusterTaintUnreachable, Operator: clusterv1beta1.TolerationOpExists},
+				},
+			},
+			selected: []string{"prod-1", "prod-2", "prod-3"},
+			matched:  3,
+		},
+		{
+			name: "limit the number of clusters",
+			spec: clusterv1beta1.PlacementSpec{
+				ClusterSets:      []string{"set1"},
+				NumberOfClusters: func() *int32 { n := int32(1); return &n }(),
+			},
+			selected: []string{"prod-1"},
+			matched:  2,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			evaluator, err := newPlacementEvaluator(&clusterv1beta1.Placement{Spec: c.spec})
+			require.NoError(t, err)
+			selected, matched := evaluator.selectClusters(clusters)
+			assert.Equal(t, c.selected, names(selected))
+			assert.Equal(t, c.matched, matched)
+		})
+	}
+
+	_, err := newPlacementEvaluator(&clusterv1beta1.Placement{Spec: clusterv1beta1.PlacementSpec{
+		Predicates: []clusterv1beta1.ClusterPredicate{
+			{RequiredClusterSelector: clusterv1beta1.ClusterSelector{
+				CelSelector: clusterv1beta1.ClusterCelSelector{CelExpressions: []string{"true"}},
+			}},
+		},
+	}})
+	assert.ErrorIs(t, err, errCelSelectorNotSupported)
+}
+
+func TestTolerated(t *testing.T) {
+	taint := clusterv1.Taint{Key: "gpu", Value: "true", Effect: clusterv1.TaintEffectNoSelect}
+	assert.False(t, tolerated(taint, nil))
+	assert.True(t, tolerated(taint, []clusterv1beta1.Toleration{{Operator: clusterv1beta1.TolerationOpExists}}))
+	assert.True(t, tolerated(taint, []clusterv1beta1.Toleration{{Key: "gpu", Value: "true"}}))
+	assert.False(t, tolerated(taint, []clusterv1beta1.Toleration{{Key: "gpu", Value: "false"}}))
+	assert.False(t, tolerated(taint, []clusterv1beta1.Toleration{
+		{Key: "gpu", Operator: clusterv1beta1.TolerationOpExists, Effect: clusterv1.TaintEffectNoSelectIfNew},
+	}))
+}
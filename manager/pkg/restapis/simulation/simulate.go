@@ -0,0 +1,260 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	clusterv1beta1 "open-cluster-management.io/api/cluster/v1beta1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+const serverInternalErrorMsg = "internal error"
+
+// the sources of the predicted compliance, from the most to the least reliable one
+const (
+	// ComplianceSourceGlobal is the current compliance of the existing global policy with the same name
+	ComplianceSourceGlobal = "global"
+	// ComplianceSourceLocal is the current compliance of the local policy with the same name
+	ComplianceSourceLocal = "local"
+	// ComplianceSourceHistory is the latest compliance history of the local policy with the same name
+	ComplianceSourceHistory = "history"
+	// complianceUnknown is predicted if the policy has never been evaluated on the cluster
+	complianceUnknown = "unknown"
+)
+
+const (
+	clustersQuery = `SELECT leaf_hub_name, cluster_id, payload FROM status.managed_clusters
+		WHERE deleted_at IS NULL%s ORDER BY leaf_hub_name`
+	globalComplianceQuery = `SELECT c.leaf_hub_name, c.cluster_name, c.compliance FROM status.compliance c
+		INNER JOIN spec.policies p ON p.id = c.policy_id
+		WHERE p.deleted = FALSE AND p.payload -> 'metadata' ->> 'name' = ?
+		AND p.payload -> 'metadata' ->> 'namespace' = ?`
+	localComplianceQuery = `SELECT c.leaf_hub_name, c.cluster_name, c.compliance FROM local_status.compliance c
+		INNER JOIN local_spec.policies p ON p.policy_id = c.policy_id
+		WHERE p.deleted_at IS NULL AND p.policy_name = ? AND p.payload -> 'metadata' ->> 'namespace' = ?`
+	historyComplianceQuery = `SELECT DISTINCT ON (h.cluster_id) h.cluster_id, h.compliance
+		FROM history.local_compliance h INNER JOIN local_spec.policies p ON p.policy_id = h.policy_id
+		WHERE h.cluster_id IS NOT NULL AND p.policy_name = ? AND p.payload -> 'metadata' ->> 'namespace' = ?
+		ORDER BY h.cluster_id, h.compliance_date DESC`
+)
+
+// simulationRequest is the placement, and optionally the policy bound to it, to simulate
+type simulationRequest struct {
+	Placement *clusterv1beta1.Placement `json:"placement"`
+	Policy    *policyv1.Policy          `json:"policy,omitempty"`
+}
+
+type simulationResult struct {
+	Hubs []*hubSimulation `json:"hubs"`
+}
+
+type hubSimulation struct {
+	LeafHubName string `json:"leafHubName"`
+	// MatchedClusters is the number of the matched clusters before they're limited by the numberOfClusters
+	MatchedClusters int                 `json:"matchedClusters"`
+	Clusters        []*simulatedCluster `json:"clusters"`
+	// Compliance counts the selected clusters by the predicted compliance
+	Compliance map[string]int `json:"compliance,omitempty"`
+}
+
+type simulatedCluster struct {
+	Name             string `json:"name"`
+	ClusterID        string `json:"clusterId"`
+	Compliance       string `json:"compliance,omitempty"`
+	ComplianceSource string `json:"complianceSource,omitempty"`
+}
+
+type clusterCompliance struct {
+	LeafHubName string
+	ClusterName string
+	ClusterID   string
+	Compliance  string
+}
+
+// Simulate godoc
+// @summary simulate placement and policy
+// @description evaluate the placement against the managed clusters of all the leaf hubs, and return the clusters it
+// @description would select per hub. If the policy is given, the compliance of the selected clusters is predicted by
+// @description the current compliance and history of the existing global or local policy with the same namespace
+// @description and name, the global policy is looked up only if the global resources are enabled. Nothing is
+// @description written to the spec tables. The prioritizers aren't simulated, so the first numberOfClusters clusters
+// @description are selected by the name, and all the cluster sets are bound if the clusterSets is empty
+// @accept json
+// @produce json
+// @param        body    body     simulation.simulationRequest  true  "the placement and the policy to simulate"
+// @success      200  {object}    simulation.simulationResult
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /simulation [post]
+func Simulate(enableGlobalResource bool) gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		body := &simulationRequest{}
+		if err := ginCtx.BindJSON(body); err != nil {
+			ginCtx.String(http.StatusBadRequest, "invalid simulation request: %s", err.Error())
+			return
+		}
+		if body.Placement == nil {
+			ginCtx.String(http.StatusBadRequest, "the placement is required")
+			return
+		}
+		evaluator, err := newPlacementEvaluator(body.Placement)
+		if err != nil {
+			ginCtx.String(http.StatusBadRequest, err.Error())
+			return
+		}
+
+		ctx := ginCtx.Request.Context()
+		scope := authorization.GetScope(ginCtx)
+		hubNames, hubClusters, err := listClusters(ctx, scope)
+		if err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in querying the managed clusters: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+
+		var predictor *compliancePredictor
+		if body.Policy != nil && !body.Policy.Spec.Disabled {
+			predictor, err = newCompliancePredictor(ctx, body.Policy.Namespace, body.Policy.Name,
+				enableGlobalResource)
+			if err != nil {
+				_, _ = fmt.Fprintf(gin.DefaultWriter, "error in querying the policy compliance: %v\n", err)
+				ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+				return
+			}
+		}
+
+		result := &simulationResult{Hubs: []*hubSimulation{}}
+		for _, hubName := range hubNames {
+			selected, matched := evaluator.selectClusters(hubClusters[hubName])
+			hub := &hubSimulation{
+				LeafHubName:     hubName,
+				MatchedClusters: matched,
+				Clusters:        []*simulatedCluster{},
+			}
+			for _, cluster := range selected {
+				simulated := &simulatedCluster{Name: cluster.Name, ClusterID: string(cluster.UID)}
+				if predictor != nil {
+					simulated.Compliance, simulated.ComplianceSource = predictor.predict(hubName, cluster)
+					if hub.Compliance == nil {
+						hub.Compliance = map[string]int{}
+					}
+					hub.Compliance[simulated.Compliance]++
+				}
+				hub.Clusters = append(hub.Clusters, simulated)
+			}
+			result.Hubs = append(result.Hubs, hub)
+		}
+		ginCtx.JSON(http.StatusOK, result)
+	}
+}
+
+// listClusters returns the names of the leaf hubs and the managed clusters of them in the authorized scope
+func listClusters(ctx context.Context, scope *authorization.Scope) (
+	[]string, map[string][]*clusterv1.ManagedCluster, error,
+) {
+	rows, err := database.GetReadGorm().WithContext(ctx).Raw(fmt.Sprintf(clustersQuery,
+		scope.ClusterCondition("leaf_hub_name", "payload"))).Rows()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer rows.Close()
+
+	hubNames := []string{}
+	hubClusters := map[string][]*clusterv1.ManagedCluster{}
+	for rows.Next() {
+		var hubName, clusterID string
+		var payload []byte
+		if err := rows.Scan(&hubName, &clusterID, &payload); err != nil {
+			return nil, nil, err
+		}
+		cluster := &clusterv1.ManagedCluster{}
+		if err := json.Unmarshal(payload, cluster); err != nil {
+			return nil, nil, fmt.Errorf("failed to unmarshal the managed cluster %s: %w", clusterID, err)
+		}
+		// the uid of the payload is the id of the cluster in the global hub
+		cluster.UID = types.UID(clusterID)
+		if _, found := hubClusters[hubName]; !found {
+			hubNames = append(hubNames, hubName)
+		}
+		hubClusters[hubName] = append(hubClusters[hubName], cluster)
+	}
+	return hubNames, hubClusters, rows.Err()
+}
+
+// compliancePredictor predicts the compliance of the policy on the clusters by the current compliance of the existing
+// global policy, then the current compliance and the latest history of the local policy with the same name. The
+// global policy is skipped if the global resources are disabled, since the spec tables aren't created then.
+type compliancePredictor struct {
+	global  map[string]string
+	local   map[string]string
+	history map[string]string
+}
+
+func newCompliancePredictor(ctx context.Context, namespace, name string, global bool) (*compliancePredictor, error) {
+	db := database.GetReadGorm().WithContext(ctx)
+	predictor := &compliancePredictor{}
+
+	var err error
+	if global {
+		if predictor.global, err = queryCompliance(db, globalComplianceQuery, namespace, name); err != nil {
+			return nil, err
+		}
+	}
+	if predictor.local, err = queryCompliance(db, localComplianceQuery, namespace, name); err != nil {
+		return nil, err
+	}
+	if predictor.history, err = queryCompliance(db, historyComplianceQuery, namespace, name); err != nil {
+		return nil, err
+	}
+	return predictor, nil
+}
+
+func (p *compliancePredictor) predict(hubName string, cluster *clusterv1.ManagedCluster) (string, string) {
+	if compliance, found := p.global[clusterKey(hubName, cluster.Name)]; found {
+		return compliance, ComplianceSourceGlobal
+	}
+	if compliance, found := p.local[clusterKey(hubName, cluster.Name)]; found {
+		return compliance, ComplianceSourceLocal
+	}
+	if compliance, found := p.history[string(cluster.UID)]; found {
+		return compliance, ComplianceSourceHistory
+	}
+	return complianceUnknown, ""
+}
+
+// queryCompliance returns the compliance keyed by the cluster id, or by the leaf hub and cluster name if the query
+// doesn't return the cluster id.
+func queryCompliance(db *gorm.DB, query, namespace, name string) (map[string]string, error) {
+	rows := []clusterCompliance{}
+	if err := db.Raw(query, name, namespace).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	compliance := map[string]string{}
+	for _, row := range rows {
+		key := row.ClusterID
+		if key == "" {
+			key = clusterKey(row.LeafHubName, row.ClusterName)
+		}
+		compliance[key] = row.Compliance
+	}
+	return compliance, nil
+}
+
+func clusterKey(hubName, clusterName string) string {
+	return hubName + "/" + clusterName
+}
@@ -17,6 +17,7 @@ import (
 	appsv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
 	appsv1alpha1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1alpha1"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 )
@@ -27,7 +28,7 @@ const (
 	subscriptionQuery         = `SELECT payload->'metadata'->>'name', payload->'metadata'->>'namespace' 
 		FROM spec.subscriptions WHERE deleted = FALSE AND id = ?`
 	subscriptionReportQuery = `SELECT payload FROM status.subscription_reports
-		WHERE payload->'metadata'->>'name'= ? AND payload->'metadata'->>'namespace' = ?`
+		WHERE payload->'metadata'->>'name'= ? AND payload->'metadata'->>'namespace' = ?%s`
 )
 
 var subReportCustomResourceColumnDefinitions = util.GetCustomResourceColumnDefinitions(subscriptionRepostCRDName,
@@ -52,6 +53,10 @@ func GetSubscriptionReport() gin.HandlerFunc {
 	return func(ginCtx *gin.Context) {
 		subscriptionID := ginCtx.Param("subscriptionID")
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "getting subscription report for subscription: %s\n", subscriptionID)
+
+		// only aggregate the reports of the authorized leaf hubs
+		scope := authorization.GetScope(ginCtx)
+		subscriptionReportQuery := fmt.Sprintf(subscriptionReportQuery, scope.LeafHubCondition("leaf_hub_name"))
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "subscription query with subscription ID: %s\n", subscriptionQuery)
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "subscription report query with subscription name and namespace: %v\n",
 			subscriptionReportQuery)
@@ -70,6 +75,10 @@ func handleSubscriptionReport(ginCtx *gin.Context, subscriptionID, subscriptionQ
 	if err != nil {
 		ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
 	}
+	if err == nil && subscriptionReport == nil && !authorization.GetScope(ginCtx).All {
+		authorization.Deny(ginCtx, fmt.Sprintf("subscription %s is out of the authorized scope", subscriptionID))
+		return
+	}
 
 	if util.ShouldReturnAsTable(ginCtx) {
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "returning subscription as table...\n")
@@ -102,7 +111,7 @@ func getAggregatedSubscriptionReport(subscriptionID, subscriptionQuery,
 ) (*appsv1alpha1.SubscriptionReport, error) {
 	var subscriptionReport *appsv1alpha1.SubscriptionReport
 	var subName, subNamespace string
-	db := database.GetGorm()
+	db := database.GetReadGorm()
 	err := db.Raw(subscriptionQuery, subscriptionID).Row().Scan(&subName, &subNamespace)
 	if err != nil {
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "error in querying subscription with subscription ID(%s): %v\n", subscriptionID, err)
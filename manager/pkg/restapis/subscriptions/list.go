@@ -23,6 +23,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	appsv1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/v1"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/util"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 )
@@ -36,6 +37,17 @@ const (
 var customResourceColumnDefinitions = util.GetCustomResourceColumnDefinitions(crdName,
 	appsv1.SchemeGroupVersion.Version)
 
+// scopedSubscriptionCondition returns the condition of the subscriptions reported by the authorized leaf hubs, the
+// subscription reports are aggregated by the leaf hubs, so the managed cluster sets don't grant the subscriptions.
+func scopedSubscriptionCondition(scope *authorization.Scope) string {
+	if scope.All {
+		return ""
+	}
+	return " AND (payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'namespace') IN (" +
+		"SELECT payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'namespace' " +
+		"FROM status.subscription_reports WHERE TRUE" + scope.LeafHubCondition("leaf_hub_name") + ")"
+}
+
 // ListSubscriptions godoc
 // @summary list application subscriptions
 // @description list application subscriptions
@@ -98,14 +110,18 @@ func ListSubscriptions() gin.HandlerFunc {
 			lastSubscriptionName,
 			lastSubscriptionUID)
 
+		// the subscriptions reported by the authorized leaf hubs
+		scopeInSql := scopedSubscriptionCondition(authorization.GetScope(ginCtx))
+
 		// the last subscription query order by subscription name and uid
-		lastSubscriptionQuery := "SELECT payload FROM spec.subscriptions WHERE deleted = FALSE " +
-			"ORDER BY (payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid') DESC LIMIT 1"
+		lastSubscriptionQuery := "SELECT payload FROM spec.subscriptions WHERE deleted = FALSE" + scopeInSql +
+			" ORDER BY (payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid') DESC LIMIT 1"
 
 		// subscrition list query
 		subscriptionListQuery := "SELECT payload FROM spec.subscriptions WHERE deleted = FALSE AND " +
 			LastResourceCompareCondition +
 			selectorInSql +
+			scopeInSql +
 			" ORDER BY (payload -> 'metadata' ->> 'name', payload -> 'metadata' ->> 'uid')"
 
 		// add limit
@@ -162,7 +178,7 @@ func handleSubscriptionListForWatch(ginCtx *gin.Context, subscriptionListQuery s
 func doHandleRowsForWatch(ctx context.Context, writer io.Writer, subscriptionListQuery string,
 	preAddedSubscriptions set.Set,
 ) {
-	db := database.GetGorm()
+	db := database.GetReadGorm()
 	rows, err := db.WithContext(ctx).Raw(subscriptionListQuery).Rows()
 	if err != nil {
 		_, _ = fmt.Fprintf(gin.DefaultWriter, "error in quering subscription list: %v\n", err)
@@ -229,7 +245,7 @@ func doHandleRowsForWatch(ctx context.Context, writer io.Writer, subscriptionLis
 func handleRows(ginCtx *gin.Context, subscriptionListQuery, lastSubscriptionQuery string,
 	customResourceColumnDefinitions []apiextensionsv1.CustomResourceColumnDefinition,
 ) {
-	db := database.GetGorm()
+	db := database.GetReadGorm()
 	ctx := ginCtx.Request.Context()
 	lastSubscription := &appsv1.Subscription{}
 	var payload []byte
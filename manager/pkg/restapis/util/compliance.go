@@ -0,0 +1,30 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package util
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LocalComplianceAtQuery rebuilds the local compliance at the RFC3339 time, the time is converted to the database
+// timezone which the compliance events are recorded in.
+const LocalComplianceAtQuery = `SELECT policy_id, cluster_id, cluster_name, leaf_hub_name, compliance, changed_at
+	FROM history.get_local_compliance_at((?::timestamptz)::timestamp) WHERE TRUE`
+
+// ParseTimeQuery parses the RFC3339 time of the query parameter, it responds with 400 if the time is invalid.
+func ParseTimeQuery(ginCtx *gin.Context, param string, defaultValue time.Time) (time.Time, bool) {
+	valueStr := ginCtx.Query(param)
+	if valueStr == "" {
+		return defaultValue, true
+	}
+	value, err := time.Parse(time.RFC3339, valueStr)
+	if err != nil {
+		ginCtx.String(http.StatusBadRequest, "invalid %s, it must be a RFC3339 time: %s", param, valueStr)
+		return time.Time{}, false
+	}
+	return value, true
+}
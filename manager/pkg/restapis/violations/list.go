@@ -0,0 +1,91 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package violations
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis/authorization"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	serverInternalErrorMsg = "internal error"
+	defaultListLimit       = 100
+)
+
+// ListViolations godoc
+// @summary list security violations
+// @description list the security violations reported by the StackRox central of the leaf hubs, the latest seen first
+// @accept json
+// @produce json
+// @param        leafHubName    query     string  false  "list the violations from the leaf hub"
+// @param        clusterName    query     string  false  "list the violations of the cluster"
+// @param        policyName     query     string  false  "list the violations of the policy"
+// @param        severity       query     string  false  "list the violations of the severity, e.g. HIGH_SEVERITY"
+// @param        state          query     string  false  "list the violations of the state, e.g. ACTIVE or RESOLVED"
+// @param        limit          query     int     false  "maximum violation number to receive, default is 100"
+// @param        offset         query     int     false  "number of violations to skip"
+// @success      200  {array}     models.SecurityViolation
+// @failure      400
+// @failure      401
+// @failure      403
+// @failure      500
+// @security     ApiKeyAuth
+// @router /violations [get]
+func ListViolations() gin.HandlerFunc {
+	return func(ginCtx *gin.Context) {
+		limit, ok := parseNumberQuery(ginCtx, "limit", defaultListLimit)
+		if !ok {
+			return
+		}
+		offset, ok := parseNumberQuery(ginCtx, "offset", 0)
+		if !ok {
+			return
+		}
+
+		query := database.GetReadGorm().WithContext(ginCtx.Request.Context()).
+			Order("last_seen_at DESC").Order("id").Limit(limit).Offset(offset)
+		if scope := authorization.GetScope(ginCtx); !scope.All {
+			query = query.Where("hub_name IN ?", scope.LeafHubs)
+		}
+		for param, column := range map[string]string{
+			"leafHubName": "hub_name",
+			"clusterName": "cluster_name",
+			"policyName":  "policy_name",
+			"severity":    "severity",
+			"state":       "state",
+		} {
+			if value := ginCtx.Query(param); value != "" {
+				query = query.Where(fmt.Sprintf("%s = ?", column), value)
+			}
+		}
+
+		violations := []models.SecurityViolation{}
+		if err := query.Find(&violations).Error; err != nil {
+			_, _ = fmt.Fprintf(gin.DefaultWriter, "error in querying security violations: %v\n", err)
+			ginCtx.String(http.StatusInternalServerError, serverInternalErrorMsg)
+			return
+		}
+		ginCtx.JSON(http.StatusOK, violations)
+	}
+}
+
+func parseNumberQuery(ginCtx *gin.Context, param string, defaultValue int) (int, bool) {
+	valueStr := ginCtx.Query(param)
+	if valueStr == "" {
+		return defaultValue, true
+	}
+	value, err := strconv.Atoi(valueStr)
+	if err != nil || value < 0 || (param == "limit" && value == 0) {
+		ginCtx.String(http.StatusBadRequest, "invalid %s: %s", param, valueStr)
+		return 0, false
+	}
+	return value, true
+}
@@ -61,12 +61,15 @@ func AddDBControllers(mgr ctrl.Manager) error {
 	return nil
 }
 
-// AddDatabaseSyncers adds the controllers that send info from DB to transport layer to the Manager.
+// AddDatabaseSyncers adds the controllers that send info from DB to transport layer to the Manager. The changes of
+// the spec object tables are published by the outbox relay in order, and the managed cluster labels are synced
+// periodically.
 func AddDatabaseSyncers(mgr ctrl.Manager, config *configs.ManagerConfig, producer transport.Producer) error {
 	specSyncInterval := config.SyncerConfig.SpecSyncInterval
 	specDB := gorm.NewGormSpecDB()
 
-	addDBSyncerFunctions := []func(ctrl.Manager, specdb.SpecDB, transport.Producer, time.Duration) error{
+	outboxRelay := syncers.NewSpecOutboxRelay(specDB, producer)
+	registerOutboxFunctions := []func(*syncers.SpecOutboxRelay){
 		// dbsyncer.AddHoHConfigDBToTransportSyncer,
 		syncers.AddPoliciesDBToTransportSyncer,
 		syncers.AddPlacementRulesDBToTransportSyncer,
@@ -74,15 +77,19 @@ func AddDatabaseSyncers(mgr ctrl.Manager, config *configs.ManagerConfig, produce
 		syncers.AddApplicationsDBToTransportSyncer,
 		syncers.AddSubscriptionsDBToTransportSyncer,
 		syncers.AddChannelsDBToTransportSyncer,
-		syncers.AddManagedClusterLabelsDBToTransportSyncer,
 		syncers.AddPlacementsDBToTransportSyncer,
 		syncers.AddManagedClusterSetsDBToTransportSyncer,
 		syncers.AddManagedClusterSetBindingsDBToTransportSyncer,
 	}
-	for _, addDBSyncerFunction := range addDBSyncerFunctions {
-		if err := addDBSyncerFunction(mgr, specDB, producer, specSyncInterval); err != nil {
-			return fmt.Errorf("failed to add DB Syncer: %w", err)
-		}
+	for _, registerOutboxFunction := range registerOutboxFunctions {
+		registerOutboxFunction(outboxRelay)
+	}
+	if err := outboxRelay.AddToManager(mgr, specSyncInterval); err != nil {
+		return fmt.Errorf("failed to add DB Syncer: %w", err)
+	}
+
+	if err := syncers.AddManagedClusterLabelsDBToTransportSyncer(mgr, specDB, producer, specSyncInterval); err != nil {
+		return fmt.Errorf("failed to add DB Syncer: %w", err)
 	}
 	return nil
 }
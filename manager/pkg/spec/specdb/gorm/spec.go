@@ -12,6 +12,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/controllers/bundle"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
 
 var errQueryTableFailedTemplate = "failed to query table spec.%s - %w"
@@ -158,3 +159,28 @@ func (p *gormSpecDB) GetObjectsBundle(ctx context.Context, tableName string, cre
 
 	return timestamp, nil
 }
+
+// GetPendingOutboxEntries returns at most limit undelivered entries of the outbox, ordered by the sequence.
+func (p *gormSpecDB) GetPendingOutboxEntries(ctx context.Context, limit int) ([]models.SpecOutbox, error) {
+	db := database.GetGorm()
+	entries := []models.SpecOutbox{}
+	err := db.WithContext(ctx).Where("delivered_at IS NULL").Order("seq").Limit(limit).Find(&entries).Error
+	if err != nil {
+		return nil, fmt.Errorf(errQueryTableFailedTemplate, "outbox", err)
+	}
+	return entries, nil
+}
+
+// MarkOutboxDelivered marks the undelivered entries of the table up to the sequence(inclusive) as delivered.
+func (p *gormSpecDB) MarkOutboxDelivered(ctx context.Context, tableName string, seq int64) error {
+	db := database.GetGorm()
+	return db.WithContext(ctx).Model(&models.SpecOutbox{}).
+		Where("table_name = ? AND seq <= ? AND delivered_at IS NULL", tableName, seq).
+		Update("delivered_at", time.Now()).Error
+}
+
+// DeleteDeliveredOutboxEntries deletes the entries delivered before the given time.
+func (p *gormSpecDB) DeleteDeliveredOutboxEntries(ctx context.Context, before time.Time) error {
+	db := database.GetGorm()
+	return db.WithContext(ctx).Where("delivered_at < ?", before).Delete(&models.SpecOutbox{}).Error
+}
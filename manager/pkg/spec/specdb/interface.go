@@ -10,6 +10,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/controllers/bundle"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 )
 
 // SpecDB is the needed interface for the spec syncer and spec transport
@@ -17,6 +18,7 @@ type SpecDB interface {
 	// GetLastUpdateTimestamp returns the last update timestamp of a specific table.
 	GetLastUpdateTimestamp(ctx context.Context, tableName string, filterLocalResources bool) (*time.Time, error)
 	ObjectsSpecDB
+	OutboxSpecDB
 }
 
 // ObjectsSpecDB is the interface needed by the spec syncer and spec transport bridge to and from sync objects tables.
@@ -31,3 +33,13 @@ type ObjectsSpecDB interface {
 	GetObjectsBundle(ctx context.Context, tableName string, createObjFunc bundle.CreateObjectFunction,
 		intoBundle bundle.ObjectsBundle) (*time.Time, error)
 }
+
+// OutboxSpecDB is the interface needed by the outbox relay to publish the changes of the spec tables in order.
+type OutboxSpecDB interface {
+	// GetPendingOutboxEntries returns at most limit undelivered entries of the outbox, ordered by the sequence.
+	GetPendingOutboxEntries(ctx context.Context, limit int) ([]models.SpecOutbox, error)
+	// MarkOutboxDelivered marks the undelivered entries of the table up to the sequence(inclusive) as delivered.
+	MarkOutboxDelivered(ctx context.Context, tableName string, seq int64) error
+	// DeleteDeliveredOutboxEntries deletes the entries delivered before the given time.
+	DeleteDeliveredOutboxEntries(ctx context.Context, before time.Time) error
+}
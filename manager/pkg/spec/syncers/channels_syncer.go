@@ -1,19 +1,10 @@
 package syncers
 
 import (
-	"context"
-	"fmt"
-	"time"
-
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	channelv1 "open-cluster-management.io/multicloud-operators-channel/pkg/apis/apps/v1"
-	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/controllers/bundle"
-	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/specdb"
-	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/syncers/interval"
-	"github.com/stolostron/multicluster-global-hub/pkg/logger"
-	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
 const (
@@ -21,23 +12,8 @@ const (
 	channelsMsgKey    = "Channels"
 )
 
-// AddChannelsDBToTransportSyncer adds channels db to transport syncer to the manager.
-func AddChannelsDBToTransportSyncer(mgr ctrl.Manager, specDB specdb.SpecDB, producer transport.Producer,
-	specSyncInterval time.Duration,
-) error {
+// AddChannelsDBToTransportSyncer registers channels db to the spec outbox relay.
+func AddChannelsDBToTransportSyncer(relay *SpecOutboxRelay) {
 	createObjFunc := func() metav1.Object { return &channelv1.Channel{} }
-	lastSyncTimestampPtr := &time.Time{}
-
-	if err := mgr.Add(&genericDBToTransportSyncer{
-		log:            logger.ZapLogger("db-to-transport-syncer-channels"),
-		intervalPolicy: interval.NewExponentialBackoffPolicy(specSyncInterval),
-		syncBundleFunc: func(ctx context.Context) (bool, error) {
-			return syncObjectsBundle(ctx, producer, channelsMsgKey, specDB, channelsTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
-		},
-	}); err != nil {
-		return fmt.Errorf("failed to add channels db to transport syncer - %w", err)
-	}
-
-	return nil
+	relay.Register(channelsTableName, channelsMsgKey, createObjFunc, bundle.NewBaseObjectsBundle)
 }
@@ -0,0 +1,151 @@
+package syncers
+
+import (
+	"sort"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+const (
+	labelConflictAdoptedHubValue      = "adopted the hub value"
+	labelConflictReappliedGlobalValue = "reapplied the global value"
+)
+
+// labelsMergeResult is the result of the three-way merge of the labels of a managed cluster.
+type labelsMergeResult struct {
+	labels            map[string]string
+	deletedLabelKeys  []string
+	lastAppliedLabels map[string]string
+	// changed means the desired labels or the last applied labels are changed
+	changed bool
+	// reapply means the desired labels should be sent to the hub again
+	reapply   bool
+	conflicts []models.LabelConflict
+}
+
+// mergeLabels merges the labels of a managed cluster by the global desired labels, the last applied labels and the
+// live labels reported by the hub. For each label:
+//   - the live value equals the desired value: the label is converged, and recorded as the last applied value.
+//   - the label is never applied, or the live value equals the last applied value: the desired value is pending.
+//   - otherwise the label is changed on the hub, it's a conflict and resolved by the policy of the label.
+func mergeLabels(leafHubName, clusterName string, labels map[string]string, deletedLabelKeys []string,
+	lastAppliedLabels, liveLabels map[string]string, policies map[string]string,
+) *labelsMergeResult {
+	result := &labelsMergeResult{
+		labels:            copyLabels(labels),
+		lastAppliedLabels: copyLabels(lastAppliedLabels),
+		conflicts:         []models.LabelConflict{},
+	}
+	deleted := getMap(deletedLabelKeys)
+
+	keys := map[string]struct{}{}
+	for key := range labels {
+		keys[key] = struct{}{}
+	}
+	for key := range deleted {
+		keys[key] = struct{}{}
+	}
+	for key := range lastAppliedLabels {
+		keys[key] = struct{}{}
+	}
+
+	for _, key := range sortedKeys(keys) {
+		desired, desiredFound := labels[key]
+		if _, found := deleted[key]; found {
+			desired, desiredFound = "", false
+		}
+		lastApplied, lastAppliedFound := lastAppliedLabels[key]
+		live, liveFound := liveLabels[key]
+
+		// converged
+		if desiredFound == liveFound && desired == live {
+			if liveFound && (!lastAppliedFound || lastApplied != live) {
+				result.lastAppliedLabels[key] = live
+				result.changed = true
+			}
+			if !liveFound && lastAppliedFound {
+				delete(result.lastAppliedLabels, key)
+				result.changed = true
+			}
+			continue
+		}
+
+		// pending
+		if !lastAppliedFound || (liveFound && live == lastApplied) {
+			continue
+		}
+
+		conflict := models.LabelConflict{
+			LeafHubName:        leafHubName,
+			ManagedClusterName: clusterName,
+			LabelKey:           key,
+			DesiredValue:       optionalValue(desired, desiredFound),
+			LastAppliedValue:   optionalValue(lastApplied, lastAppliedFound),
+			LiveValue:          optionalValue(live, liveFound),
+			Policy:             labelPolicy(policies, key),
+		}
+
+		if conflict.Policy == models.LabelPolicyHubWins {
+			if liveFound {
+				result.labels[key] = live
+				result.lastAppliedLabels[key] = live
+				delete(deleted, key)
+			} else {
+				delete(result.labels, key)
+				delete(result.lastAppliedLabels, key)
+			}
+			conflict.Resolution = labelConflictAdoptedHubValue
+		} else {
+			if !desiredFound {
+				deleted[key] = struct{}{}
+			}
+			result.reapply = true
+			conflict.Resolution = labelConflictReappliedGlobalValue
+		}
+		result.changed = true
+		result.conflicts = append(result.conflicts, conflict)
+	}
+
+	result.deletedLabelKeys = sortedKeys(deleted)
+	return result
+}
+
+// labelPolicy returns the policy of the label, the global desired value wins by default.
+func labelPolicy(policies map[string]string, key string) string {
+	if policies[key] == models.LabelPolicyHubWins {
+		return models.LabelPolicyHubWins
+	}
+	return models.LabelPolicyGlobalWins
+}
+
+func optionalValue(value string, found bool) *string {
+	if !found {
+		return nil
+	}
+	return &value
+}
+
+func copyLabels(labels map[string]string) map[string]string {
+	copied := make(map[string]string, len(labels))
+	for key, value := range labels {
+		copied[key] = value
+	}
+	return copied
+}
+
+func getMap(keys []string) map[string]struct{} {
+	keysMap := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		keysMap[key] = struct{}{}
+	}
+	return keysMap
+}
+
+func sortedKeys(keysMap map[string]struct{}) []string {
+	keys := make([]string, 0, len(keysMap))
+	for key := range keysMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
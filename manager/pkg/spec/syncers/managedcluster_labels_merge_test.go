@@ -0,0 +1,119 @@
+package syncers
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+func TestMergeLabels(t *testing.T) {
+	cases := []struct {
+		name                  string
+		labels                map[string]string
+		deletedLabelKeys      []string
+		lastAppliedLabels     map[string]string
+		liveLabels            map[string]string
+		policies              map[string]string
+		wantLabels            map[string]string
+		wantDeletedLabelKeys  []string
+		wantLastAppliedLabels map[string]string
+		wantChanged           bool
+		wantReapply           bool
+		wantConflicts         []string
+	}{
+		{
+			name:                  "record the applied labels",
+			labels:                map[string]string{"env": "prod"},
+			lastAppliedLabels:     map[string]string{},
+			liveLabels:            map[string]string{"env": "prod", "local": "true"},
+			wantLabels:            map[string]string{"env": "prod"},
+			wantDeletedLabelKeys:  []string{},
+			wantLastAppliedLabels: map[string]string{"env": "prod"},
+			wantChanged:           true,
+		},
+		{
+			name:                  "drop the deleted label from the applied labels",
+			labels:                map[string]string{},
+			deletedLabelKeys:      []string{"env"},
+			lastAppliedLabels:     map[string]string{"env": "prod"},
+			liveLabels:            map[string]string{},
+			wantLabels:            map[string]string{},
+			wantDeletedLabelKeys:  []string{"env"},
+			wantLastAppliedLabels: map[string]string{},
+			wantChanged:           true,
+		},
+		{
+			name:                  "the global change is pending",
+			labels:                map[string]string{"env": "dev"},
+			lastAppliedLabels:     map[string]string{"env": "prod"},
+			liveLabels:            map[string]string{"env": "prod"},
+			wantLabels:            map[string]string{"env": "dev"},
+			wantDeletedLabelKeys:  []string{},
+			wantLastAppliedLabels: map[string]string{"env": "prod"},
+		},
+		{
+			name:                  "the global value wins by default",
+			labels:                map[string]string{"env": "prod"},
+			lastAppliedLabels:     map[string]string{"env": "prod"},
+			liveLabels:            map[string]string{"env": "test"},
+			wantLabels:            map[string]string{"env": "prod"},
+			wantDeletedLabelKeys:  []string{},
+			wantLastAppliedLabels: map[string]string{"env": "prod"},
+			wantChanged:           true,
+			wantReapply:           true,
+			wantConflicts:         []string{"env"},
+		},
+		{
+			name:                  "the global deletion wins if the label is added back on the hub",
+			labels:                map[string]string{},
+			lastAppliedLabels:     map[string]string{"env": "prod"},
+			liveLabels:            map[string]string{"env": "test"},
+			policies:              map[string]string{"env": models.LabelPolicyGlobalWins},
+			wantLabels:            map[string]string{},
+			wantDeletedLabelKeys:  []string{"env"},
+			wantLastAppliedLabels: map[string]string{"env": "prod"},
+			wantChanged:           true,
+			wantReapply:           true,
+			wantConflicts:         []string{"env"},
+		},
+		{
+			name:                  "the hub value wins",
+			labels:                map[string]string{"env": "prod", "zone": "east"},
+			deletedLabelKeys:      []string{"tier"},
+			lastAppliedLabels:     map[string]string{"env": "prod", "zone": "east", "tier": "gold"},
+			liveLabels:            map[string]string{"env": "test", "tier": "silver"},
+			policies:              map[string]string{"env": "hub-wins", "zone": "hub-wins", "tier": "hub-wins"},
+			wantLabels:            map[string]string{"env": "test", "tier": "silver"},
+			wantDeletedLabelKeys:  []string{},
+			wantLastAppliedLabels: map[string]string{"env": "test", "tier": "silver"},
+			wantChanged:           true,
+			wantConflicts:         []string{"env", "tier", "zone"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			result := mergeLabels("hub1", "cluster1", tc.labels, tc.deletedLabelKeys, tc.lastAppliedLabels,
+				tc.liveLabels, tc.policies)
+			require.Equal(t, tc.wantLabels, result.labels)
+			require.Equal(t, tc.wantDeletedLabelKeys, result.deletedLabelKeys)
+			require.Equal(t, tc.wantLastAppliedLabels, result.lastAppliedLabels)
+			require.Equal(t, tc.wantChanged, result.changed)
+			require.Equal(t, tc.wantReapply, result.reapply)
+
+			conflicts := []string{}
+			for _, conflict := range result.conflicts {
+				require.Equal(t, "hub1", conflict.LeafHubName)
+				require.Equal(t, "cluster1", conflict.ManagedClusterName)
+				require.Equal(t, labelPolicy(tc.policies, conflict.LabelKey), conflict.Policy)
+				conflicts = append(conflicts, conflict.LabelKey)
+			}
+			if tc.wantConflicts == nil {
+				tc.wantConflicts = []string{}
+			}
+			require.Equal(t, tc.wantConflicts, conflicts)
+		})
+	}
+}
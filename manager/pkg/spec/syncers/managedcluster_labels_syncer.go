@@ -10,6 +10,7 @@ import (
 	"gorm.io/gorm"
 	ctrl "sigs.k8s.io/controller-runtime"
 
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/specdb"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/syncers/interval"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
@@ -90,10 +91,13 @@ func syncManagedClusterLabelsBundles(ctx context.Context, producer transport.Pro
 func getUpdatedManagedClusterLabelsBundles(timestamp *time.Time,
 ) (map[string]*spec.ManagedClusterLabelsSpecBundle, error) {
 	db := database.GetGorm()
-	// select ManagedClusterLabelsSpec entries information from DB
+	// select ManagedClusterLabelsSpec entries information from DB, the hubs in maintenance are skipped, and their
+	// labels are touched to be sent again once they are resumed
 	rows, err := db.Raw(fmt.Sprintf(`SELECT * FROM spec.%[1]s WHERE leaf_hub_name IN (SELECT DISTINCT(leaf_hub_name) 
-		from spec.%[1]s WHERE updated_at::timestamp > timestamp '%[2]s') AND leaf_hub_name <> ''`,
-		managedClusterLabelsDBTableName, timestamp.Format(time.RFC3339Nano))).Rows()
+		from spec.%[1]s WHERE updated_at::timestamp > timestamp '%[2]s') AND leaf_hub_name <> ''
+		AND leaf_hub_name NOT IN (SELECT leaf_hub_name FROM status.leaf_hub_heartbeats WHERE status IN (?, ?))`,
+		managedClusterLabelsDBTableName, timestamp.Format(time.RFC3339Nano)),
+		hubmanagement.HubMaintenance, hubmanagement.HubResuming).Rows()
 	if err != nil {
 		return nil, err
 	}
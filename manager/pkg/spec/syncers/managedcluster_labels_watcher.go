@@ -8,6 +8,9 @@ import (
 	"time"
 
 	"go.uber.org/zap"
+	"gorm.io/datatypes"
+	gormpkg "gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/specdb"
@@ -15,6 +18,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/syncers/interval"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/spec"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 )
 
@@ -72,6 +76,9 @@ func (watcher *managedClusterLabelsStatusWatcher) updateDeletedLabelsPeriodicall
 			// update the deleted label keys to label table by the managed cluster table
 			trimmed := watcher.updateDeletedLabelsByManagedCluster()
 
+			// merge the desired, last applied and live labels, and record the conflicts
+			watcher.reconcileLabelsByManagedCluster()
+
 			cancelFunc() // cancel child ctx and is used to cleanup resources once context expires or update is done.
 
 			// get current trimming interval
@@ -163,6 +170,140 @@ func (watcher *managedClusterLabelsStatusWatcher) updateDeletedLabelsByManagedCl
 	return result
 }
 
+// reconcileLabelsByManagedCluster merges the labels of the managed clusters three-way by the desired labels, the last
+// applied labels and the live labels in the managed cluster table. The conflicts are resolved by the label policies,
+// and recorded into the label conflicts table.
+func (watcher *managedClusterLabelsStatusWatcher) reconcileLabelsByManagedCluster() {
+	db := database.GetGorm()
+	conn := database.GetConn()
+	err := database.Lock(conn)
+	if err != nil {
+		watcher.log.Error(err, "failed to lock db")
+		return
+	}
+	defer database.Unlock(conn)
+
+	var managedClusterLabels []managedClusterLiveLabels
+	if err := db.Raw(fmt.Sprintf(`SELECT l.*, c.payload->'metadata'->'labels' AS live_labels FROM spec.%s l
+		JOIN status.%s c ON c.leaf_hub_name = l.leaf_hub_name AND c.payload->'metadata'->>'name' = l.managed_cluster_name
+		AND c.deleted_at IS NULL WHERE l.leaf_hub_name <> ''`, labelsTableName, clusterTableName)).
+		Scan(&managedClusterLabels).Error; err != nil {
+		watcher.log.Error(err, "reconciling cycle skipped")
+		return
+	}
+
+	for i := range managedClusterLabels {
+		managedClusterLabel := &managedClusterLabels[i]
+		result, err := managedClusterLabel.merge()
+		if err != nil {
+			watcher.log.Error(err, "failed to merge the managed cluster labels", "leafHub",
+				managedClusterLabel.LeafHubName, "managedCluster", managedClusterLabel.ManagedClusterName)
+			continue
+		}
+		if !result.changed {
+			continue
+		}
+
+		if err := updateMergedLabelsToLabelTable(&managedClusterLabel.ManagedClusterLabel, result); err != nil {
+			watcher.log.Error(err, "failed to update the merged labels to label table", "leafHub",
+				managedClusterLabel.LeafHubName, "managedCluster", managedClusterLabel.ManagedClusterName)
+			continue
+		}
+
+		for _, conflict := range result.conflicts {
+			watcher.log.Infow("resolved the label conflict", "leafHub", conflict.LeafHubName, "managedCluster",
+				conflict.ManagedClusterName, "label", conflict.LabelKey, "policy", conflict.Policy,
+				"resolution", conflict.Resolution)
+		}
+	}
+}
+
+// managedClusterLiveLabels is the managed cluster labels row with the live labels reported by the hub.
+type managedClusterLiveLabels struct {
+	models.ManagedClusterLabel
+	LiveLabels datatypes.JSON `gorm:"column:live_labels"`
+}
+
+func (l *managedClusterLiveLabels) merge() (*labelsMergeResult, error) {
+	labels := map[string]string{}
+	if err := unmarshalJSONColumn(l.Labels, &labels); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal labels - %w", err)
+	}
+	deletedLabelKeys := []string{}
+	if err := unmarshalJSONColumn(l.DeletedLabelKeys, &deletedLabelKeys); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal deleted label keys - %w", err)
+	}
+	lastAppliedLabels := map[string]string{}
+	if err := unmarshalJSONColumn(l.LastAppliedLabels, &lastAppliedLabels); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal last applied labels - %w", err)
+	}
+	policies := map[string]string{}
+	if err := unmarshalJSONColumn(l.LabelPolicies, &policies); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal label policies - %w", err)
+	}
+	liveLabels := map[string]string{}
+	if err := unmarshalJSONColumn(l.LiveLabels, &liveLabels); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal live labels - %w", err)
+	}
+
+	return mergeLabels(l.LeafHubName, l.ManagedClusterName, labels, deletedLabelKeys, lastAppliedLabels,
+		liveLabels, policies), nil
+}
+
+func unmarshalJSONColumn(payload []byte, target any) error {
+	if len(payload) == 0 || string(payload) == "null" {
+		return nil
+	}
+	return json.Unmarshal(payload, target)
+}
+
+// updateMergedLabelsToLabelTable updates the merged labels for a managed cluster entry under optimistic concurrency
+// approach, and records the conflicts. The updated_at is only bumped if the desired labels should be sent to the hub.
+func updateMergedLabelsToLabelTable(managedClusterLabel *models.ManagedClusterLabel,
+	result *labelsMergeResult,
+) error {
+	labelsJSON, err := json.Marshal(result.labels)
+	if err != nil {
+		return fmt.Errorf("failed to marshal labels - %w", err)
+	}
+	deletedLabelsJSON, err := json.Marshal(result.deletedLabelKeys)
+	if err != nil {
+		return fmt.Errorf("failed to marshal deleted labels - %w", err)
+	}
+	lastAppliedLabelsJSON, err := json.Marshal(result.lastAppliedLabels)
+	if err != nil {
+		return fmt.Errorf("failed to marshal last applied labels - %w", err)
+	}
+
+	updatedAt := "updated_at"
+	if result.reapply || len(result.conflicts) > 0 {
+		updatedAt = "now()"
+	}
+
+	return database.GetGorm().Transaction(func(tx *gormpkg.DB) error {
+		ret := tx.Exec(fmt.Sprintf(`UPDATE spec.%s SET updated_at=%s,labels=?,deleted_label_keys=?,
+			last_applied_labels=?,version=? WHERE leaf_hub_name=? AND managed_cluster_name=? AND version=?`,
+			labelsTableName, updatedAt), labelsJSON, deletedLabelsJSON, lastAppliedLabelsJSON,
+			managedClusterLabel.Version+1, managedClusterLabel.LeafHubName, managedClusterLabel.ManagedClusterName,
+			managedClusterLabel.Version)
+		if ret.Error != nil {
+			return fmt.Errorf("failed to update managed cluster labels row in spec.%s - %w", labelsTableName, ret.Error)
+		} else if ret.RowsAffected == 0 {
+			return fmt.Errorf("failed to update managed cluster labels row in spec.%s", labelsTableName)
+		}
+
+		if len(result.conflicts) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "leaf_hub_name"}, {Name: "managed_cluster_name"}, {Name: "label_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"desired_value", "last_applied_value", "live_value", "policy", "resolution", "updated_at",
+			}),
+		}).Create(&result.conflicts).Error
+	})
+}
+
 func (watcher *managedClusterLabelsStatusWatcher) fillMissingLeafHubNames() {
 	entities, err := getLabelsWithoutLeafHubName()
 	if err != nil {
@@ -0,0 +1,170 @@
+package syncers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/controllers/bundle"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/specdb"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/syncers/interval"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+const (
+	// outboxBatchSize is the max number of the outbox entries handled in one relay round
+	outboxBatchSize = 500
+	// outboxRetention is how long the delivered entries are kept in the outbox
+	outboxRetention = 24 * time.Hour
+)
+
+// outboxSource is the bundle of a spec table published by the relay
+type outboxSource struct {
+	eventType        string
+	createObjFunc    bundle.CreateObjectFunction
+	createBundleFunc bundle.CreateBundleFunction
+}
+
+// SpecOutboxRelay publishes the changes recorded in the spec.outbox table to the transport. The entries are handled
+// in the sequence order, once an entry is pending, the whole bundle of its table is sent, then all the pending
+// entries of the table up to that point are marked as delivered. If the sending fails, the relay stops at the
+// failed entry, so the remaining entries are retried in the same order in the next round or after a restart.
+// Like the db syncers, the bundles of all the tables are sent once on start, so the hubs get the spec even if the
+// outbox is empty, e.g. after upgrading from the db syncers.
+type SpecOutboxRelay struct {
+	log      *zap.SugaredLogger
+	specDB   specdb.SpecDB
+	producer transport.Producer
+	sources  map[string]*outboxSource
+	// snapshotted is whether the bundles of all the tables have been sent since the relay started
+	snapshotted bool
+}
+
+func NewSpecOutboxRelay(specDB specdb.SpecDB, producer transport.Producer) *SpecOutboxRelay {
+	return &SpecOutboxRelay{
+		log:      logger.ZapLogger("spec-outbox-relay"),
+		specDB:   specDB,
+		producer: producer,
+		sources:  map[string]*outboxSource{},
+	}
+}
+
+// Register adds the table to be published by the relay with the given event type.
+func (r *SpecOutboxRelay) Register(tableName, eventType string, createObjFunc bundle.CreateObjectFunction,
+	createBundleFunc bundle.CreateBundleFunction,
+) {
+	r.sources[tableName] = &outboxSource{
+		eventType:        eventType,
+		createObjFunc:    createObjFunc,
+		createBundleFunc: createBundleFunc,
+	}
+}
+
+// AddToManager adds the relay to the manager, it runs with the interval policy like the other db syncers.
+func (r *SpecOutboxRelay) AddToManager(mgr ctrl.Manager, specSyncInterval time.Duration) error {
+	if err := mgr.Add(&genericDBToTransportSyncer{
+		log:            r.log,
+		intervalPolicy: interval.NewExponentialBackoffPolicy(specSyncInterval),
+		syncBundleFunc: r.relay,
+	}); err != nil {
+		return fmt.Errorf("failed to add spec outbox relay - %w", err)
+	}
+	return nil
+}
+
+// relay publishes the pending entries of the outbox and returns true if any bundle was sent to the transport.
+func (r *SpecOutboxRelay) relay(ctx context.Context) (bool, error) {
+	if !r.snapshotted {
+		if err := r.snapshot(ctx); err != nil {
+			return false, err
+		}
+		r.snapshotted = true
+		_, err := r.relayPending(ctx)
+		return true, err
+	}
+	return r.relayPending(ctx)
+}
+
+// snapshot sends the bundles of all the registered tables, it's retried in the next round if any of them fails
+func (r *SpecOutboxRelay) snapshot(ctx context.Context) error {
+	tableNames := make([]string, 0, len(r.sources))
+	for tableName := range r.sources {
+		tableNames = append(tableNames, tableName)
+	}
+	sort.Strings(tableNames)
+	for _, tableName := range tableNames {
+		if err := r.sendBundle(ctx, tableName, r.sources[tableName]); err != nil {
+			return fmt.Errorf("failed to send the snapshot of table(%s) - %w", tableName, err)
+		}
+	}
+	return nil
+}
+
+func (r *SpecOutboxRelay) relayPending(ctx context.Context) (bool, error) {
+	entries, err := r.specDB.GetPendingOutboxEntries(ctx, outboxBatchSize)
+	if err != nil {
+		return false, fmt.Errorf("unable to relay the outbox - %w", err)
+	}
+	if len(entries) == 0 {
+		if err := r.specDB.DeleteDeliveredOutboxEntries(ctx, time.Now().Add(-outboxRetention)); err != nil {
+			r.log.Warnw("failed to delete the delivered outbox entries", "error", err)
+		}
+		return false, nil
+	}
+
+	// the last pending sequence of each table in this round
+	lastSeqs := map[string]int64{}
+	for _, entry := range entries {
+		lastSeqs[entry.Table] = entry.Seq
+	}
+
+	sent := false
+	for _, entry := range entries {
+		lastSeq, pending := lastSeqs[entry.Table]
+		if !pending {
+			continue // delivered by the bundle of a previous entry
+		}
+		source, found := r.sources[entry.Table]
+		if !found {
+			r.log.Warnw("skip the outbox entries of the unregistered table", "table", entry.Table, "seq", lastSeq)
+		} else {
+			if err := r.sendBundle(ctx, entry.Table, source); err != nil {
+				return sent, fmt.Errorf("failed to relay the outbox entry(seq=%d) - %w", entry.Seq, err)
+			}
+			sent = true
+		}
+		if err := r.specDB.MarkOutboxDelivered(ctx, entry.Table, lastSeq); err != nil {
+			return sent, fmt.Errorf("failed to mark the outbox entries of table(%s) delivered - %w", entry.Table, err)
+		}
+		delete(lastSeqs, entry.Table)
+	}
+	return sent, nil
+}
+
+func (r *SpecOutboxRelay) sendBundle(ctx context.Context, tableName string, source *outboxSource) error {
+	bundleResult := source.createBundleFunc()
+	if _, err := r.specDB.GetObjectsBundle(ctx, tableName, source.createObjFunc, bundleResult); err != nil {
+		return fmt.Errorf("unable to get the bundle of table(%s) - %w", tableName, err)
+	}
+
+	payloadBytes, err := json.Marshal(bundleResult)
+	if err != nil {
+		return fmt.Errorf("failed to marshal bundle(%s) - %w", source.eventType, err)
+	}
+
+	evt := utils.ToCloudEvent(source.eventType, constants.CloudEventGlobalHubClusterName, transport.Broadcast,
+		payloadBytes)
+	if err := r.producer.SendEvent(ctx, evt); err != nil {
+		return fmt.Errorf("failed to sync message(%s) from table(%s) to destination(%s) - %w",
+			source.eventType, tableName, transport.Broadcast, err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package syncers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	policyv1 "open-cluster-management.io/governance-policy-propagator/api/v1"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/controllers/bundle"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/specdb"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+type fakeOutboxDB struct {
+	specdb.SpecDB
+	entries []models.SpecOutbox
+}
+
+func (db *fakeOutboxDB) GetPendingOutboxEntries(ctx context.Context, limit int) ([]models.SpecOutbox, error) {
+	pending := []models.SpecOutbox{}
+	for _, entry := range db.entries {
+		if entry.DeliveredAt == nil && len(pending) < limit {
+			pending = append(pending, entry)
+		}
+	}
+	return pending, nil
+}
+
+func (db *fakeOutboxDB) MarkOutboxDelivered(ctx context.Context, tableName string, seq int64) error {
+	now := time.Now()
+	for i := range db.entries {
+		if db.entries[i].Table == tableName && db.entries[i].Seq <= seq && db.entries[i].DeliveredAt == nil {
+			db.entries[i].DeliveredAt = &now
+		}
+	}
+	return nil
+}
+
+func (db *fakeOutboxDB) DeleteDeliveredOutboxEntries(ctx context.Context, before time.Time) error {
+	return nil
+}
+
+func (db *fakeOutboxDB) GetObjectsBundle(ctx context.Context, tableName string,
+	createObjFunc bundle.CreateObjectFunction, intoBundle bundle.ObjectsBundle,
+) (*time.Time, error) {
+	now := time.Now()
+	return &now, nil
+}
+
+type fakeProducer struct {
+	transport.Producer
+	sentTypes []string
+	failType  string
+}
+
+func (p *fakeProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	if evt.Type() == p.failType {
+		return errors.New("transport is unavailable")
+	}
+	p.sentTypes = append(p.sentTypes, evt.Type())
+	return nil
+}
+
+func TestSpecOutboxRelay(t *testing.T) {
+	db := &fakeOutboxDB{entries: []models.SpecOutbox{
+		{Seq: 1, Table: "policies"},
+		{Seq: 2, Table: "placements"},
+		{Seq: 3, Table: "policies"},
+		{Seq: 4, Table: "unknown"},
+		{Seq: 5, Table: "placementbindings"},
+	}}
+	producer := &fakeProducer{failType: "PlacementBindings"}
+
+	relay := NewSpecOutboxRelay(db, producer)
+	createObjFunc := func() metav1.Object { return &policyv1.Policy{} }
+	relay.Register("policies", "Policies", createObjFunc, bundle.NewBaseObjectsBundle)
+	relay.Register("placements", "Placements", createObjFunc, bundle.NewBaseObjectsBundle)
+	relay.Register("placementbindings", "PlacementBindings", createObjFunc, bundle.NewBaseObjectsBundle)
+	relay.snapshotted = true
+
+	// the relay stops at the failed entry, and the entries before it are delivered in order
+	sent, err := relay.relay(context.Background())
+	require.Error(t, err)
+	require.True(t, sent)
+	require.Equal(t, []string{"Policies", "Placements"}, producer.sentTypes)
+	pending, err := db.GetPendingOutboxEntries(context.Background(), outboxBatchSize)
+	require.NoError(t, err)
+	require.Equal(t, []models.SpecOutbox{{Seq: 5, Table: "placementbindings"}}, pending)
+
+	// the failed entry is retried in the next round
+	producer.failType = ""
+	sent, err = relay.relay(context.Background())
+	require.NoError(t, err)
+	require.True(t, sent)
+	require.Equal(t, []string{"Policies", "Placements", "PlacementBindings"}, producer.sentTypes)
+
+	sent, err = relay.relay(context.Background())
+	require.NoError(t, err)
+	require.False(t, sent)
+}
+
+func TestSpecOutboxRelaySnapshot(t *testing.T) {
+	db := &fakeOutboxDB{}
+	producer := &fakeProducer{failType: "Placements"}
+
+	relay := NewSpecOutboxRelay(db, producer)
+	createObjFunc := func() metav1.Object { return &policyv1.Policy{} }
+	relay.Register("policies", "Policies", createObjFunc, bundle.NewBaseObjectsBundle)
+	relay.Register("placements", "Placements", createObjFunc, bundle.NewBaseObjectsBundle)
+
+	// the snapshot is retried until all the tables are sent
+	_, err := relay.relay(context.Background())
+	require.Error(t, err)
+
+	// the bundles of all the tables are sent on start even if the outbox is empty
+	producer.failType = ""
+	producer.sentTypes = nil
+	sent, err := relay.relay(context.Background())
+	require.NoError(t, err)
+	require.True(t, sent)
+	require.Equal(t, []string{"Placements", "Policies"}, producer.sentTypes)
+
+	sent, err = relay.relay(context.Background())
+	require.NoError(t, err)
+	require.False(t, sent)
+}
@@ -1,19 +1,10 @@
 package syncers
 
 import (
-	"context"
-	"fmt"
-	"time"
-
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	placementrulev1 "open-cluster-management.io/multicloud-operators-subscription/pkg/apis/apps/placementrule/v1"
-	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/controllers/bundle"
-	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/specdb"
-	"github.com/stolostron/multicluster-global-hub/manager/pkg/spec/syncers/interval"
-	"github.com/stolostron/multicluster-global-hub/pkg/logger"
-	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
 
 const (
@@ -21,23 +12,8 @@ const (
 	placementRulesMsgKey    = "PlacementRules"
 )
 
-// AddPlacementRulesDBToTransportSyncer adds placement rules db to transport syncer to the manager.
-func AddPlacementRulesDBToTransportSyncer(mgr ctrl.Manager, specDB specdb.SpecDB, producer transport.Producer,
-	specSyncInterval time.Duration,
-) error {
+// AddPlacementRulesDBToTransportSyncer registers placement rules db to the spec outbox relay.
+func AddPlacementRulesDBToTransportSyncer(relay *SpecOutboxRelay) {
 	createObjFunc := func() metav1.Object { return &placementrulev1.PlacementRule{} }
-	lastSyncTimestampPtr := &time.Time{}
-
-	if err := mgr.Add(&genericDBToTransportSyncer{
-		log:            logger.ZapLogger("db-to-transport-syncer-placementrule"),
-		intervalPolicy: interval.NewExponentialBackoffPolicy(specSyncInterval),
-		syncBundleFunc: func(ctx context.Context) (bool, error) {
-			return syncObjectsBundle(ctx, producer, placementRulesMsgKey, specDB, placementRulesTableName,
-				createObjFunc, bundle.NewBaseObjectsBundle, lastSyncTimestampPtr)
-		},
-	}); err != nil {
-		return fmt.Errorf("failed to add placement rules db to transport syncer - %w", err)
-	}
-
-	return nil
+	relay.Register(placementRulesTableName, placementRulesMsgKey, createObjFunc, bundle.NewBaseObjectsBundle)
 }
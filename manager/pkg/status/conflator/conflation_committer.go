@@ -198,7 +198,8 @@ func (k *ConflationCommitter) getPositionsToCommit() []*transport.EventPosition
 			continue
 		}
 		position := metadata.TransportPosition()
-		if position == nil {
+		// the replayed event doesn't have the transport position
+		if position == nil || position.Topic == "" {
 			continue
 		}
 		key := positionKey(position.Topic, position.Partition)
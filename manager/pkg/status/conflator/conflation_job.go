@@ -24,4 +24,14 @@ type ConflationJob struct {
 	Reporter ResultReporter
 
 	ElementState *ElementState
+
+	// finish releases the in-flight slot of the hub in the ready queue
+	finish func()
+}
+
+// Finish is called once the job is handled by the worker.
+func (job *ConflationJob) Finish() {
+	if job.finish != nil {
+		job.finish()
+	}
 }
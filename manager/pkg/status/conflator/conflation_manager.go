@@ -1,6 +1,7 @@
 package conflator
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 
@@ -15,6 +16,22 @@ import (
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
 )
 
+// ExtSpilled marks the event spilled into the overflow handler, so that the conflation unit knows the spilled event
+// is replayed
+const ExtSpilled = "extspilled"
+
+var (
+	// ErrHubQueueFull is returned if the replayed event isn't added since the conflation queue of the hub is full, the
+	// event is expected to be replayed again later
+	ErrHubQueueFull = errors.New("the conflation queue of the hub is full")
+	// ErrExpiredEvent is returned if the replayed event is dropped by the version check of the conflation element
+	ErrExpiredEvent = errors.New("the event is older than the processed one")
+)
+
+// OverflowHandler stores the delta event overflowing the conflation queue of its hub, e.g. as the dead letter. The
+// stored event is expected to be replayed in order once the queue has room.
+type OverflowHandler func(evt *cloudevents.Event) error
+
 // ConflationManager implements conflation units management.
 type ConflationManager struct {
 	log             *zap.SugaredLogger
@@ -25,14 +42,16 @@ type ConflationManager struct {
 	lock          sync.Mutex
 	statistics    *statistics.Statistics
 	Requster      transport.Requester
+
+	overflowHandler OverflowHandler
 }
 
 // NewConflationManager creates a new instance of ConflationManager.
 func NewConflationManager(statistics *statistics.Statistics,
-	requster transport.Requester,
+	requster transport.Requester, readyQueueConfig *ReadyQueueConfig,
 ) *ConflationManager {
 	// conflationReadyQueue is shared between conflation manager and dispatcher
-	conflationUnitsReadyQueue := NewConflationReadyQueue(statistics)
+	conflationUnitsReadyQueue := NewConflationReadyQueue(statistics, readyQueueConfig)
 
 	return &ConflationManager{
 		log:             logger.ZapLogger("conflation-manager"),
@@ -53,21 +72,64 @@ func (cm *ConflationManager) Register(registration *ConflationRegistration) {
 	cm.statistics.Register(registration.eventType)
 }
 
-// Insert function inserts the bundle to the appropriate conflation unit.
+// SetOverflowHandler sets the handler of the delta events overflowing the conflation queue of their hub, it must be
+// set before inserting the events. The events are dropped if it isn't set.
+func (cm *ConflationManager) SetOverflowHandler(handler OverflowHandler) {
+	cm.overflowHandler = handler
+}
+
+// SetSpilled restores the number of the spilled events of the hub which aren't replayed yet, e.g. after the restart,
+// so the delta events of the hub are still spilled until they're replayed.
+func (cm *ConflationManager) SetSpilled(leafHubName string, count int) {
+	cu := cm.getConflationUnit(leafHubName)
+	cu.lock.Lock()
+	defer cu.lock.Unlock()
+	cu.spilled = count
+}
+
+// ReleaseSpilled counts the spilled event of the hub as replayed without replaying it, e.g. it's broken.
+func (cm *ConflationManager) ReleaseSpilled(leafHubName string) {
+	cu := cm.getConflationUnit(leafHubName)
+	cu.lock.Lock()
+	defer cu.lock.Unlock()
+	if cu.spilled > 0 {
+		cu.spilled--
+	}
+}
+
+// Insert adds the event received from the transport into the conflation unit of its hub.
 func (cm *ConflationManager) Insert(evt *cloudevents.Event) {
+	conflationMetadata := cm.newMetadata(evt)
+	if conflationMetadata == nil {
+		return
+	}
+	cm.getConflationUnit(evt.Source()).insert(evt, conflationMetadata)
+}
+
+// Replay adds the replayed event, e.g. a dead letter, into the conflation unit of its hub. The replayed event still
+// goes through the version check of the conflation element, so an expired complete state bundle doesn't override the
+// newer state, and ErrExpiredEvent is returned. If the queue of the hub is full, ErrHubQueueFull is returned.
+func (cm *ConflationManager) Replay(evt *cloudevents.Event) error {
+	conflationMetadata := cm.newMetadata(evt)
+	if conflationMetadata == nil {
+		return fmt.Errorf("failed to build the metadata of the event: %s", enum.ShortenEventType(evt.Type()))
+	}
+	return cm.getConflationUnit(evt.Source()).replay(evt, conflationMetadata)
+}
+
+func (cm *ConflationManager) newMetadata(evt *cloudevents.Event) ConflationMetadata {
 	// validate the event
 	if _, ok := cm.registrations[evt.Type()]; !ok {
 		cm.log.Infow("unregistered event type", "type", enum.ShortenEventType(evt.Type()))
 		fmt.Print(evt)
-		return
+		return nil
 	}
 	// metadata
 	conflationMetadata := metadata.NewThresholdMetadata(config.GetKafkaOwnerIdentity(), 3, evt)
 	if conflationMetadata == nil {
-		return
+		return nil
 	}
-
-	cm.getConflationUnit(evt.Source()).insert(evt, conflationMetadata)
+	return conflationMetadata
 }
 
 // GetTransportMetadatas provides collections of the CU's bundle transport-metadata.
@@ -89,6 +151,7 @@ func (cm *ConflationManager) getConflationUnit(leafHubName string) *ConflationUn
 	}
 	// otherwise, need to create conflation unit
 	conflationUnit := newConflationUnit(leafHubName, cm.readyQueue, cm.registrations, cm.statistics)
+	conflationUnit.overflowHandler = cm.overflowHandler
 	cm.conflationUnits[leafHubName] = conflationUnit
 	cm.statistics.IncrementNumberOfConflations()
 	return conflationUnit
@@ -0,0 +1,112 @@
+package conflator
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+func TestConflationManagerReplay(t *testing.T) {
+	eventType := string(enum.ManagedClusterType)
+	cm := NewConflationManager(statistics.NewStatistics(&statistics.StatisticsConfig{}), nil, nil)
+	cm.Register(NewConflationRegistration(0, enum.CompleteStateMode, eventType,
+		func(ctx context.Context, evt *cloudevents.Event) error { return nil }))
+
+	newEvent := func(eventVersion string) *cloudevents.Event {
+		evt := cloudevents.NewEvent()
+		evt.SetSource("hub1")
+		evt.SetType(eventType)
+		evt.SetExtension(version.ExtVersion, eventVersion)
+		return &evt
+	}
+	popVersion := func() string {
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		job, err := cm.GetReadyQueue().Pop(ctx)
+		if err != nil {
+			return ""
+		}
+		job.Metadata.MarkAsProcessed()
+		job.Reporter.ReportResult(job.Metadata, nil)
+		job.Finish()
+		return job.Metadata.Version().String()
+	}
+
+	cm.Insert(newEvent("1.5"))
+	require.Equal(t, "1.5", popVersion())
+
+	// the older bundle from the transport is dropped
+	cm.Insert(newEvent("1.3"))
+	require.Equal(t, "", popVersion())
+
+	// the replayed bundle older than the processed one is dropped too, so it doesn't override the newer state
+	require.ErrorIs(t, cm.Replay(newEvent("1.3")), ErrExpiredEvent)
+	require.Equal(t, "", popVersion())
+
+	// the replayed bundle is handled if it isn't older than the processed one
+	require.NoError(t, cm.Replay(newEvent("1.6")))
+	require.Equal(t, "1.6", popVersion())
+	require.Equal(t, "1.6", cm.GetMetadatas()[0].Version().String())
+}
+
+func TestConflationManagerSpill(t *testing.T) {
+	eventType := string(enum.ManagedClusterType)
+	cm := NewConflationManager(statistics.NewStatistics(&statistics.StatisticsConfig{}), nil, nil)
+	cm.Register(NewConflationRegistration(0, enum.DeltaStateMode, eventType,
+		func(ctx context.Context, evt *cloudevents.Event) error { return nil }))
+	spilled := []*cloudevents.Event{}
+	cm.SetOverflowHandler(func(evt *cloudevents.Event) error {
+		spilled = append(spilled, evt)
+		return nil
+	})
+
+	newEvent := func(hubName string, value int) *cloudevents.Event {
+		evt := cloudevents.NewEvent()
+		evt.SetSource(hubName)
+		evt.SetType(eventType)
+		evt.SetExtension(version.ExtVersion, fmt.Sprintf("1.%d", value))
+		return &evt
+	}
+	popJob := func() {
+		job, err := cm.GetReadyQueue().Pop(context.Background())
+		require.NoError(t, err)
+		job.Finish()
+	}
+
+	for i := 1; i <= hubQueueCapacity; i++ {
+		cm.Insert(newEvent("hub1", i))
+	}
+	// the event overflowing the queue of the hub is spilled
+	cm.Insert(newEvent("hub1", hubQueueCapacity+1))
+	require.Len(t, spilled, 1)
+	_, found := spilled[0].Extensions()[ExtSpilled]
+	require.True(t, found)
+
+	// the later event is spilled too even if the queue has room, so the events are handled in order
+	popJob()
+	cm.Insert(newEvent("hub1", hubQueueCapacity+2))
+	require.Len(t, spilled, 2)
+
+	// the spilled events are replayed once the queue has room
+	require.NoError(t, cm.Replay(spilled[0]))
+	require.ErrorIs(t, cm.Replay(spilled[1]), ErrHubQueueFull)
+	popJob()
+	require.NoError(t, cm.Replay(spilled[1]))
+
+	// the event isn't spilled once all the spilled events are replayed
+	popJob()
+	cm.Insert(newEvent("hub1", hubQueueCapacity+3))
+	require.Len(t, spilled, 2)
+
+	// the other hubs aren't affected
+	cm.Insert(newEvent("hub2", 1))
+	require.Len(t, spilled, 2)
+}
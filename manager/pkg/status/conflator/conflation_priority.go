@@ -16,6 +16,7 @@ const (
 	LocalReplicatedPolicyEventPriority ConflationPriority = iota
 	LocalPlacementRulesSpecPriority    ConflationPriority = iota
 	SecurityAlertCountsPriority        ConflationPriority = iota
+	SecurityViolationsPriority         ConflationPriority = iota
 	ManagedClusterMigrationPriority    ConflationPriority = iota
 
 	// enable global resource
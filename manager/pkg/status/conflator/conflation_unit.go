@@ -2,6 +2,7 @@ package conflator
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
@@ -21,6 +22,10 @@ type ConflationUnit struct {
 	isInReadyQueue bool
 	lock           sync.Mutex
 	statistics     *statistics.Statistics
+
+	// spilled is the number of the delta events of the hub spilled into the overflow handler and not replayed yet
+	spilled         int
+	overflowHandler OverflowHandler
 }
 
 func newConflationUnit(name string, readyQueue *ConflationReadyQueue,
@@ -57,7 +62,9 @@ func newConflationUnit(name string, readyQueue *ConflationReadyQueue,
 	return conflationUnit
 }
 
-// insert is an internal function, new bundles are inserted only via conflation manager.
+// insert is an internal function, new bundles are inserted only via conflation manager. The delta event overflowing
+// the queue of the hub is spilled, and the later delta events of the hub are spilled too until the spilled ones are
+// replayed, so the delta events are still handled in order.
 func (cu *ConflationUnit) insert(event *cloudevents.Event, eventMetadata ConflationMetadata) {
 	cu.lock.Lock()
 	defer cu.lock.Unlock()
@@ -76,13 +83,73 @@ func (cu *ConflationUnit) insert(event *cloudevents.Event, eventMetadata Conflat
 	}
 
 	// for the delta element, insert the ready queue directly and process one by one
+	if cu.spilled > 0 && conflationElement.SyncMode() != enum.CompleteStateMode {
+		cu.spill(event, eventMetadata)
+		return
+	}
 
 	// start conflation unit metric for specific bundle type - overwrite it each time new bundle arrives
 	// cu.statistics.StartConflationUnitMetrics(event)
 
 	// if we got here, we got bundle with newer version
 	// update the bundle in the priority queue.
-	conflationElement.AddToReadyQueue(event, eventMetadata, cu)
+	if !conflationElement.AddToReadyQueue(event, eventMetadata, cu) {
+		cu.spill(event, eventMetadata)
+	}
+}
+
+// replay inserts the replayed event, the spilled event is counted as replayed unless the queue of the hub is full.
+func (cu *ConflationUnit) replay(event *cloudevents.Event, eventMetadata ConflationMetadata) error {
+	cu.lock.Lock()
+	defer cu.lock.Unlock()
+
+	priority := cu.eventTypeToPriority[event.Type()]
+	conflationElement := cu.ElementPriorityQueue[priority]
+	if conflationElement == nil {
+		return fmt.Errorf("the event type %s isn't registered", enum.ShortenEventType(event.Type()))
+	}
+
+	_, spilled := event.Extensions()[ExtSpilled]
+	if conflationElement.Predicate(eventMetadata.Version()) {
+		if !conflationElement.AddToReadyQueue(event, eventMetadata, cu) {
+			return ErrHubQueueFull
+		}
+	} else {
+		log.Infow("drop the replayed event older than the processed one", "hub", cu.name,
+			"type", enum.ShortenEventType(event.Type()), "version", eventMetadata.Version())
+		if !spilled {
+			return ErrExpiredEvent
+		}
+	}
+
+	if spilled && cu.spilled > 0 {
+		cu.spilled--
+		if cu.spilled == 0 {
+			log.Infow("the spilled delta events of the hub are replayed", "hub", cu.name)
+		}
+	}
+	return nil
+}
+
+// spill hands over the delta event to the overflow handler, so that it's replayed once the queue of the hub has room
+func (cu *ConflationUnit) spill(event *cloudevents.Event, eventMetadata ConflationMetadata) {
+	if cu.overflowHandler == nil {
+		log.Errorw("drop the delta event: the queue of the hub is full", "hub", cu.name,
+			"type", enum.ShortenEventType(event.Type()), "version", eventMetadata.Version())
+		return
+	}
+	if cu.spilled == 0 {
+		log.Warnw("spill the delta events: the queue of the hub is full", "hub", cu.name)
+	}
+	spilledEvent := event.Clone()
+	spilledEvent.SetExtension(ExtSpilled, true)
+	if err := cu.overflowHandler(&spilledEvent); err != nil {
+		log.Errorw("drop the delta event: failed to spill it", "hub", cu.name,
+			"type", enum.ShortenEventType(event.Type()), "version", eventMetadata.Version(), "error", err)
+		return
+	}
+	cu.spilled++
+	cu.statistics.IncrementHubSpilledJobs(cu.name)
 }
 
 // GetNext returns the next ready to be processed bundle and its transport metadata.
@@ -129,7 +196,7 @@ func (cu *ConflationUnit) addCUToReadyQueueIfNeeded() {
 	// if we reached here, CU is not in RQ, then get next element(isn't processing)
 	element := cu.getNextReadyCompleteElement()
 	if element != nil { // there is a ready to be processed bundle
-		cu.readyQueue.ConflationUnitChan <- cu // let the dispatcher know this CU has a ready to be processed bundle
+		cu.readyQueue.PushUnit(cu) // let the dispatcher know this CU has a ready to be processed bundle
 		cu.isInReadyQueue = true
 	}
 }
@@ -84,11 +84,14 @@ func (e *completeElement) Predicate(eventVersion *version.Version) bool {
 	return true
 }
 
-func (e *completeElement) AddToReadyQueue(event *cloudevents.Event, metadata ConflationMetadata, cu *ConflationUnit) {
+func (e *completeElement) AddToReadyQueue(event *cloudevents.Event, metadata ConflationMetadata,
+	cu *ConflationUnit,
+) bool {
 	e.event = event
 	e.metadata = metadata
 
 	cu.addCUToReadyQueueIfNeeded()
+	return true
 }
 
 func (e *completeElement) IsReadyToProcess(cu *ConflationUnit) bool {
@@ -62,9 +62,14 @@ func (e *deltaElement) Predicate(eventVersion *version.Version) bool {
 	return true
 }
 
-func (e *deltaElement) AddToReadyQueue(event *cloudevents.Event, metadata ConflationMetadata, cu *ConflationUnit) {
-	cu.readyQueue.DeltaEventJobChan <- NewConflationJob(event, metadata, e.handlerFunction, cu, nil)
+func (e *deltaElement) AddToReadyQueue(event *cloudevents.Event, metadata ConflationMetadata,
+	cu *ConflationUnit,
+) bool {
+	if !cu.readyQueue.PushJob(cu.name, NewConflationJob(event, metadata, e.handlerFunction, cu, nil)) {
+		return false
+	}
 	e.metadata = metadata
+	return true
 }
 
 // Success is to update the conflation element state after processing the event
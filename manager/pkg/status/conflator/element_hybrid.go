@@ -56,8 +56,10 @@ func (e *hybridElement) Predicate(eventVersion *version.Version) bool {
 	return eventVersion.NewerGenerationThan(e.elementState.LastProcessedVersion)
 }
 
-func (e *hybridElement) AddToReadyQueue(event *cloudevents.Event, metadata ConflationMetadata, cu *ConflationUnit) {
-	cu.readyQueue.DeltaEventJobChan <- NewConflationJob(event, metadata, e.handlerFunction, cu, e.elementState)
+func (e *hybridElement) AddToReadyQueue(event *cloudevents.Event, metadata ConflationMetadata,
+	cu *ConflationUnit,
+) bool {
+	return cu.readyQueue.PushJob(cu.name, NewConflationJob(event, metadata, e.handlerFunction, cu, e.elementState))
 }
 
 // Success is to update the conflation element state after processing the event
@@ -46,8 +46,9 @@ type ConflationElement interface {
 	// Predicate assert the received eventMetdata should be processed based on the current state
 	Predicate(eventVersion *version.Version) bool
 
-	// Update is to update element payload
-	AddToReadyQueue(event *cloudevents.Event, metadata ConflationMetadata, cu *ConflationUnit)
+	// AddToReadyQueue is to update element payload, it returns false if the delta job isn't added since the queue of
+	// the hub is full
+	AddToReadyQueue(event *cloudevents.Event, metadata ConflationMetadata, cu *ConflationUnit) bool
 
 	// PostProcess is to update the conflation element state after processing the event
 	PostProcess(metadata ConflationMetadata, err error)
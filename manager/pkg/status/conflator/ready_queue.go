@@ -1,22 +1,255 @@
 package conflator
 
 import (
+	"context"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 )
 
-// NewConflationReadyQueue creates a new instance of ConflationReadyQueue.
-func NewConflationReadyQueue(statistics *statistics.Statistics) *ConflationReadyQueue {
-	return &ConflationReadyQueue{
-		statistics:         statistics,
-		DeltaEventJobChan:  make(chan *ConflationJob, 1000),
-		ConflationUnitChan: make(chan *ConflationUnit, 100),
-	}
+// hubQueueCapacity is the max number of the delta jobs buffered for a hub, the jobs beyond it are spilled by the
+// conflation unit into the overflow handler, so a slow hub never blocks the transport dispatching of the other hubs
+const hubQueueCapacity = 1000
+
+// ReadyQueueConfig configures the fair scheduling of the ready queue across the leaf hubs. The zero value means
+// there is no limitation, and all the hubs have the same weight.
+type ReadyQueueConfig struct {
+	// RateLimit is the max number of the jobs dispatched per second for each hub, 0 means unlimited
+	RateLimit float64
+	// RateBurst is the bucket size of the token bucket of each hub, it's at least 1 if the rate is limited
+	RateBurst int
+	// MaxInFlight is the max number of the jobs of each hub handled by the workers at the same time, 0 is unlimited
+	MaxInFlight int
+	// Weights is the number of the jobs dispatched for the hub in its turn, the default weight is 1
+	Weights map[string]int
+}
+
+// readyItem is either a delta job, or a conflation unit having a ready complete element
+type readyItem struct {
+	job        *ConflationJob
+	unit       *ConflationUnit
+	enqueuedAt time.Time
 }
 
-// ConflationReadyQueue is a queue of conflation units that have at least one bundle to process.
+type hubQueue struct {
+	name     string
+	items    []*readyItem
+	jobs     int // the number of the delta jobs in the items
+	inFlight int
+	limiter  *rate.Limiter
+	weight   int
+	credit   int  // the remaining jobs can be dispatched in the current turn
+	inRing   bool // whether the hub is in the round robin ring
+}
+
+// ConflationReadyQueue is a queue of the jobs and conflation units that have at least one bundle to process. The
+// hubs having ready items take turns to dispatch up to their weight of jobs, a hub is skipped if it runs out of the
+// tokens of its rate limiter, or it reaches the max in-flight jobs, so a noisy hub can't starve the others.
 type ConflationReadyQueue struct {
 	statistics *statistics.Statistics
-	// create a Job chan for the detal event
-	DeltaEventJobChan  chan *ConflationJob
-	ConflationUnitChan chan *ConflationUnit
+	config     ReadyQueueConfig
+
+	mu     sync.Mutex
+	hubs   map[string]*hubQueue
+	ring   []*hubQueue
+	cursor int
+	size   int
+	// notify wakes up the blocked Pop once an item is pushed or a job is finished
+	notify chan struct{}
+}
+
+// NewConflationReadyQueue creates a new instance of ConflationReadyQueue.
+func NewConflationReadyQueue(statistics *statistics.Statistics, config *ReadyQueueConfig) *ConflationReadyQueue {
+	q := &ConflationReadyQueue{
+		statistics: statistics,
+		hubs:       map[string]*hubQueue{},
+		notify:     make(chan struct{}, 1),
+	}
+	if config != nil {
+		q.config = *config
+	}
+	return q
+}
+
+// PushJob adds the delta job into the queue of its hub. It never blocks, since it's called by the conflation unit
+// holding its lock on the shared transport dispatching loop. The job isn't added and false is returned if the queue of
+// the hub is full.
+func (q *ConflationReadyQueue) PushJob(hubName string, job *ConflationJob) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	hub := q.getHubQueue(hubName)
+	if hub.jobs >= hubQueueCapacity {
+		return false
+	}
+	hub.jobs++
+	q.push(hub, &readyItem{job: job, enqueuedAt: time.Now()})
+	return true
+}
+
+// PushUnit adds the conflation unit into the queue of its hub, the unit appears at most once in the queue, so it
+// isn't limited by the capacity.
+func (q *ConflationReadyQueue) PushUnit(cu *ConflationUnit) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.push(q.getHubQueue(cu.name), &readyItem{unit: cu, enqueuedAt: time.Now()})
+}
+
+// Pop blocks until a job is ready to be dispatched, or the context is cancelled. The in-flight slot of the hub is
+// released once the job is finished.
+func (q *ConflationReadyQueue) Pop(ctx context.Context) (*ConflationJob, error) {
+	for {
+		q.mu.Lock()
+		item, hub, wait := q.next(time.Now())
+		q.mu.Unlock()
+
+		if item != nil {
+			job := item.job
+			if item.unit != nil {
+				var err error
+				if job, err = item.unit.GetNext(); err != nil {
+					log.Debugw("no job is ready in the conflation unit", "hub", hub.name, "reason", err.Error())
+					q.finish(hub)
+					continue
+				}
+			}
+			job.finish = func() { q.finish(hub) }
+			return job, nil
+		}
+
+		if err := q.wait(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// wait blocks until the queue is notified, or the duration elapses if it's positive
+func (q *ConflationReadyQueue) wait(ctx context.Context, duration time.Duration) error {
+	var timeout <-chan time.Time
+	if duration > 0 {
+		timer := time.NewTimer(duration)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-q.notify:
+	case <-timeout:
+	}
+	return nil
+}
+
+// next returns the item of the next eligible hub, or the duration to wait for the rate limited hubs if no hub is
+// eligible. A non-positive duration means waiting for the notification only.
+func (q *ConflationReadyQueue) next(now time.Time) (*readyItem, *hubQueue, time.Duration) {
+	wait := time.Duration(0)
+	for checked, total := 0, len(q.ring); checked < total && len(q.ring) > 0; checked++ {
+		hub := q.ring[q.cursor]
+		if len(hub.items) == 0 {
+			q.removeFromRing()
+			continue
+		}
+		if q.config.MaxInFlight > 0 && hub.inFlight >= q.config.MaxInFlight {
+			q.advance()
+			continue
+		}
+		if !hub.limiter.AllowN(now, 1) {
+			tokensLacked := 1 - hub.limiter.TokensAt(now)
+			delay := time.Duration(tokensLacked / float64(hub.limiter.Limit()) * float64(time.Second))
+			if wait <= 0 || delay < wait {
+				wait = delay
+			}
+			q.advance()
+			continue
+		}
+
+		item := hub.items[0]
+		hub.items[0] = nil
+		hub.items = hub.items[1:]
+		if item.job != nil {
+			hub.jobs--
+		}
+		q.size--
+		hub.inFlight++
+		hub.credit--
+		if hub.credit <= 0 || len(hub.items) == 0 {
+			q.advance()
+		}
+
+		q.statistics.SetHubQueueDepth(hub.name, len(hub.items))
+		q.statistics.SetHubInFlight(hub.name, hub.inFlight)
+		q.statistics.ObserveHubQueueWait(hub.name, now.Sub(item.enqueuedAt))
+		q.statistics.SetConflationReadyQueueSize(q.size)
+		return item, hub, 0
+	}
+	if wait > 0 && wait < time.Millisecond {
+		wait = time.Millisecond
+	}
+	return nil, nil, wait
+}
+
+func (q *ConflationReadyQueue) finish(hub *hubQueue) {
+	q.mu.Lock()
+	hub.inFlight--
+	q.statistics.SetHubInFlight(hub.name, hub.inFlight)
+	q.mu.Unlock()
+	q.wakeUp()
+}
+
+func (q *ConflationReadyQueue) push(hub *hubQueue, item *readyItem) {
+	hub.items = append(hub.items, item)
+	if !hub.inRing {
+		hub.inRing = true
+		hub.credit = hub.weight
+		q.ring = append(q.ring, hub)
+	}
+	q.size++
+	q.statistics.SetHubQueueDepth(hub.name, len(hub.items))
+	q.statistics.SetConflationReadyQueueSize(q.size)
+	q.wakeUp()
+}
+
+func (q *ConflationReadyQueue) wakeUp() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// advance moves the turn to the next hub in the ring, and refills the credit of the current hub
+func (q *ConflationReadyQueue) advance() {
+	q.ring[q.cursor].credit = q.ring[q.cursor].weight
+	q.cursor = (q.cursor + 1) % len(q.ring)
+}
+
+// removeFromRing removes the current hub from the ring, the turn moves to the next hub
+func (q *ConflationReadyQueue) removeFromRing() {
+	hub := q.ring[q.cursor]
+	hub.inRing = false
+	q.ring = append(q.ring[:q.cursor], q.ring[q.cursor+1:]...)
+	if q.cursor >= len(q.ring) {
+		q.cursor = 0
+	}
+}
+
+func (q *ConflationReadyQueue) getHubQueue(hubName string) *hubQueue {
+	if hub, found := q.hubs[hubName]; found {
+		return hub
+	}
+	limiter := rate.NewLimiter(rate.Inf, 0)
+	if q.config.RateLimit > 0 {
+		limiter = rate.NewLimiter(rate.Limit(q.config.RateLimit), max(q.config.RateBurst, 1))
+	}
+	hub := &hubQueue{
+		name:    hubName,
+		limiter: limiter,
+		weight:  max(q.config.Weights[hubName], 1),
+	}
+	q.hubs[hubName] = hub
+	return hub
 }
@@ -0,0 +1,99 @@
+package conflator
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+func pushJobs(q *ConflationReadyQueue, hubName string, count int) {
+	for i := 0; i < count; i++ {
+		evt := cloudevents.NewEvent()
+		evt.SetSource(hubName)
+		q.PushJob(hubName, NewConflationJob(&evt, nil, nil, nil, nil))
+	}
+}
+
+func popHubs(t *testing.T, q *ConflationReadyQueue, count int) []string {
+	hubs := []string{}
+	for i := 0; i < count; i++ {
+		job, err := q.Pop(context.Background())
+		require.NoError(t, err)
+		hubs = append(hubs, job.Event.Source())
+		job.Finish()
+	}
+	return hubs
+}
+
+func TestReadyQueueFairScheduling(t *testing.T) {
+	stats := statistics.NewStatistics(&statistics.StatisticsConfig{})
+
+	t.Run("round robin across hubs", func(t *testing.T) {
+		q := NewConflationReadyQueue(stats, nil)
+		pushJobs(q, "hub1", 5)
+		pushJobs(q, "hub2", 2)
+		require.Equal(t, []string{"hub1", "hub2", "hub1", "hub2", "hub1", "hub1", "hub1"}, popHubs(t, q, 7))
+	})
+
+	t.Run("weighted hubs", func(t *testing.T) {
+		q := NewConflationReadyQueue(stats, &ReadyQueueConfig{Weights: map[string]int{"hub1": 2}})
+		pushJobs(q, "hub1", 4)
+		pushJobs(q, "hub2", 3)
+		require.Equal(t, []string{"hub1", "hub1", "hub2", "hub1", "hub1", "hub2", "hub2"}, popHubs(t, q, 7))
+	})
+
+	t.Run("max in-flight jobs per hub", func(t *testing.T) {
+		q := NewConflationReadyQueue(stats, &ReadyQueueConfig{MaxInFlight: 1})
+		pushJobs(q, "hub1", 2)
+		pushJobs(q, "hub2", 1)
+
+		job1, err := q.Pop(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "hub1", job1.Event.Source())
+		// hub1 reaches the max in-flight jobs
+		require.Equal(t, []string{"hub2"}, popHubs(t, q, 1))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+		_, err = q.Pop(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+
+		job1.Finish()
+		require.Equal(t, []string{"hub1"}, popHubs(t, q, 1))
+	})
+
+	t.Run("rate limit per hub", func(t *testing.T) {
+		q := NewConflationReadyQueue(stats, &ReadyQueueConfig{RateLimit: 5, RateBurst: 1})
+		pushJobs(q, "hub1", 2)
+		pushJobs(q, "hub2", 1)
+
+		// hub1 runs out of the tokens, so it doesn't block the hub2
+		require.Equal(t, []string{"hub1", "hub2"}, popHubs(t, q, 2))
+
+		start := time.Now()
+		require.Equal(t, []string{"hub1"}, popHubs(t, q, 1))
+		require.GreaterOrEqual(t, time.Since(start), 100*time.Millisecond)
+	})
+	t.Run("full hub queue rejects the jobs without blocking", func(t *testing.T) {
+		q := NewConflationReadyQueue(stats, nil)
+		pushJobs(q, "hub1", hubQueueCapacity)
+
+		evt := cloudevents.NewEvent()
+		evt.SetSource("hub1")
+		require.False(t, q.PushJob("hub1", NewConflationJob(&evt, nil, nil, nil, nil)))
+
+		// the other hubs aren't affected
+		evt.SetSource("hub2")
+		require.True(t, q.PushJob("hub2", NewConflationJob(&evt, nil, nil, nil, nil)))
+		require.Equal(t, []string{"hub1", "hub2"}, popHubs(t, q, 2))
+
+		// the hub accepts the jobs again once the queue has room
+		evt.SetSource("hub1")
+		require.True(t, q.PushJob("hub1", NewConflationJob(&evt, nil, nil, nil, nil)))
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"k8s.io/apimachinery/pkg/util/wait"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
@@ -30,10 +31,12 @@ func NewWorker(workerID int32, dbWorkersPool chan *Worker,
 
 // Worker worker within the DB Worker pool. runs as a goroutine and invokes DBJobs.
 type Worker struct {
-	workerID   int32
-	workers    chan *Worker
-	jobsQueue  chan *conflator.ConflationJob
-	statistics *statistics.Statistics
+	workerID          int32
+	workers           chan *Worker
+	jobsQueue         chan *conflator.ConflationJob
+	statistics        *statistics.Statistics
+	deadLetterHandler DeadLetterHandler
+	committedHandler  CommittedHandler
 }
 
 // RunAsync runs DBJob and reports status to the given CU. once the job processing is finished worker returns to the
@@ -63,6 +66,8 @@ func (worker *Worker) start(ctx context.Context) {
 }
 
 func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJob) {
+	defer job.Finish()
+
 	conn := database.GetConn()
 
 	err := database.Lock(conn)
@@ -85,14 +90,17 @@ func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJo
 
 	// based on the handle result, update the element state
 	startTime := time.Now()
+	var handleErr error
 	err = wait.PollUntilContextTimeout(ctx, 5*time.Second, 1*time.Minute, true,
 		func(ctx context.Context) (bool, error) {
 			err := job.Handle(ctx, job.Event)
+			handleErr = err
 			if err != nil {
 				// TODO: This is to handle the expired array bundles from 1.5 to 1.6 upgrade.
 				// It will be removed after the upgrade.
 				if !strings.Contains(err.Error(), "cannot unmarshal array into Go value of") {
-					log.Warnf("received the expired event array bundle %, skipping the event", job.Event.Type())
+					log.Warnf("failed to handle the event (%s), skipping the event: %v", job.Event.Type(), err)
+					worker.deadLetter(ctx, job.Event, err)
 					return true, nil
 				}
 				log.Errorf("retrying to handle failed event (%s): %v", job.Event.Type(), err)
@@ -109,24 +117,30 @@ func (worker *Worker) handleJob(ctx context.Context, job *conflator.ConflationJo
 		},
 	)
 
-	worker.statistics.AddDatabaseMetrics(job.Event, time.Since(startTime), err)
+	worker.statistics.AddDatabaseMetrics(job.Event, time.Since(startTime), lastError(err, handleErr))
 
 	if err != nil {
 		log.Errorw("fails to process the DB job", "LF", job.Event.Source(), "WorkerID", worker.workerID,
 			"event", job.Event, "error", err)
+		worker.deadLetter(ctx, job.Event, lastError(err, handleErr))
 	} else {
 		log.Debugw("handle the DB job successfully", "LF", job.Event.Source(),
 			"WorkerID", worker.workerID,
 			"version", job.Event.Extensions()[version.ExtVersion])
+		if handleErr == nil {
+			worker.committed(ctx, job.Event)
+		}
 	}
 }
 
 func (worker *Worker) fullBundleHandle(ctx context.Context, job *conflator.ConflationJob) {
 	startTime := time.Now()
+	var handleErr error
 	// handle the event until it's metadata is marked as processed
 	err := wait.PollUntilContextTimeout(ctx, 5*time.Second, 5*time.Minute, true,
 		func(ctx context.Context) (bool, error) {
 			err := job.Handle(ctx, job.Event) // db connection released to pool when done
+			handleErr = err
 			if err != nil {
 				job.Metadata.MarkAsUnprocessed()
 				log.Warnf("failed to handle event (%s): %v", job.Event.Type(), err)
@@ -142,7 +156,7 @@ func (worker *Worker) fullBundleHandle(ctx context.Context, job *conflator.Confl
 			return job.Metadata.Processed(), nil
 		})
 
-	worker.statistics.AddDatabaseMetrics(job.Event, time.Since(startTime), err)
+	worker.statistics.AddDatabaseMetrics(job.Event, time.Since(startTime), lastError(err, handleErr))
 
 	job.Reporter.ReportResult(job.Metadata, err)
 
@@ -151,10 +165,37 @@ func (worker *Worker) fullBundleHandle(ctx context.Context, job *conflator.Confl
 			"WorkerID", worker.workerID,
 			"type", enum.ShortenEventType(job.Event.Type()),
 			"version", job.Metadata.Version())
+		worker.deadLetter(ctx, job.Event, lastError(err, handleErr))
 	} else {
 		log.Debugw("handle the DB job successfully", "LF", job.Event.Source(),
 			"WorkerID", worker.workerID,
 			"type", enum.ShortenEventType(job.Event.Type()),
 			"version", job.Metadata.Version())
+		worker.committed(ctx, job.Event)
+	}
+}
+
+// deadLetter hands over the event given up by the worker to the dead letter handler, the event isn't a dead letter
+// if the worker is stopped in the middle of handling it.
+func (worker *Worker) deadLetter(ctx context.Context, evt *cloudevents.Event, err error) {
+	if worker.deadLetterHandler == nil || ctx.Err() != nil {
+		return
+	}
+	worker.deadLetterHandler(ctx, evt, err)
+}
+
+// committed notifies the committed handler that the event is handled into the database.
+func (worker *Worker) committed(ctx context.Context, evt *cloudevents.Event) {
+	if worker.committedHandler == nil {
+		return
+	}
+	worker.committedHandler(ctx, evt)
+}
+
+// lastError returns the error of the last handling if it's present, otherwise the error of the polling, e.g. timeout
+func lastError(pollErr, handleErr error) error {
+	if handleErr != nil {
+		return handleErr
 	}
+	return pollErr
 }
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
@@ -12,10 +14,18 @@ import (
 
 var log = logger.DefaultZapLogger()
 
+// DeadLetterHandler receives the event which is given up by the worker, and the error of the last handling.
+type DeadLetterHandler func(ctx context.Context, evt *cloudevents.Event, err error)
+
+// CommittedHandler receives the event which is handled into the database successfully.
+type CommittedHandler func(ctx context.Context, evt *cloudevents.Event)
+
 // DBWorkerPool pool that registers all db workers and the assigns db jobs to available workers.
 type DBWorkerPool struct {
-	statistics *statistics.Statistics
-	workers    chan *Worker // A pool of workers that are registered within the workers pool
+	statistics        *statistics.Statistics
+	workers           chan *Worker // A pool of workers that are registered within the workers pool
+	deadLetterHandler DeadLetterHandler
+	committedHandler  CommittedHandler
 }
 
 // NewDBWorkerPool returns a new db workers pool dispatcher.
@@ -25,6 +35,16 @@ func NewDBWorkerPool(statistics *statistics.Statistics) (*DBWorkerPool, error) {
 	}, nil
 }
 
+// SetDeadLetterHandler sets the handler of the events failed to be handled, it must be set before starting the pool.
+func (pool *DBWorkerPool) SetDeadLetterHandler(handler DeadLetterHandler) {
+	pool.deadLetterHandler = handler
+}
+
+// SetCommittedHandler sets the handler of the events handled successfully, it must be set before starting the pool.
+func (pool *DBWorkerPool) SetCommittedHandler(handler CommittedHandler) {
+	pool.committedHandler = handler
+}
+
 // Start function starts the db workers pool.
 func (pool *DBWorkerPool) Start(ctx context.Context) error {
 	sqlDB, err := database.GetGorm().DB()
@@ -47,6 +67,8 @@ func (pool *DBWorkerPool) Start(ctx context.Context) error {
 	var i int32
 	for i = 1; i <= int32(workSize); i++ {
 		worker := NewWorker(i, pool.workers, pool.statistics)
+		worker.deadLetterHandler = pool.deadLetterHandler
+		worker.committedHandler = pool.committedHandler
 		go worker.start(ctx) // each worker adds itself to the pool inside start function
 	}
 
@@ -29,12 +29,15 @@ func NewConflationDispatcher(conflationReadyQueue *conflator.ConflationReadyQueu
 
 func AddConflationDispatcher(mgr ctrl.Manager, conflationManager *conflator.ConflationManager,
 	managerConfig *configs.ManagerConfig, stats *statistics.Statistics,
+	deadLetterHandler workerpool.DeadLetterHandler, committedHandler workerpool.CommittedHandler,
 ) error {
 	// add work pool: database layer initialization - worker pool + connection pool
 	dbWorkerPool, err := workerpool.NewDBWorkerPool(stats)
 	if err != nil {
 		return fmt.Errorf("failed to initialize DBWorkerPool: %w", err)
 	}
+	dbWorkerPool.SetDeadLetterHandler(deadLetterHandler)
+	dbWorkerPool.SetCommittedHandler(committedHandler)
 	if err := mgr.Add(dbWorkerPool); err != nil {
 		return fmt.Errorf("failed to add DB worker pool: %w", err)
 	}
@@ -73,22 +76,17 @@ func (dispatcher *ConflationDispatcher) Start(ctx context.Context) error {
 
 func (dispatcher *ConflationDispatcher) dispatch(ctx context.Context) {
 	for {
-		select {
-		case <-ctx.Done(): // if dispatcher was stopped do not process more bundles
+		// the ready queue schedules the jobs fairly across the hubs
+		job, err := dispatcher.conflationReadyQueue.Pop(ctx)
+		if err != nil { // if dispatcher was stopped do not process more bundles
+			return
+		}
+		worker := dispatcher.getBlockingWorker(ctx)
+		if worker == nil {
+			job.Finish()
 			return
-
-		case deltaEventJob := <-dispatcher.conflationReadyQueue.DeltaEventJobChan:
-			worker := dispatcher.getBlockingWorker(ctx)
-			worker.RunAsync(deltaEventJob)
-		case conflationUnit := <-dispatcher.conflationReadyQueue.ConflationUnitChan:
-			eventJob, err := conflationUnit.GetNext()
-			if err != nil {
-				dispatcher.log.Info(err.Error()) // don't need to throw the error when bundle is not ready
-				continue
-			}
-			worker := dispatcher.getBlockingWorker(ctx)
-			worker.RunAsync(eventJob)
 		}
+		worker.RunAsync(job)
 	}
 }
 
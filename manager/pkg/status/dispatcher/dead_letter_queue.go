@@ -0,0 +1,224 @@
+package dispatcher
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	kafka_confluent "github.com/cloudevents/sdk-go/protocol/kafka_confluent/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"go.uber.org/zap"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+const (
+	// ExtDeadLetterError is the extension carrying the handling error of the event published to the dead letter topic
+	ExtDeadLetterError = "extdeadlettererror"
+
+	deadLetterReplayInterval = 10 * time.Second
+)
+
+// DeadLetterQueue persists the events given up by the conflation handlers into the status.dead_letters table, and
+// publishes them to the dead letter topic if it's configured. The dead letters requested to be replayed(e.g. by the
+// rest api) are inserted into the conflation manager again, they still go through the version check of the
+// conflation unit, so an expired bundle doesn't override the newer state.
+type DeadLetterQueue struct {
+	log               *zap.SugaredLogger
+	producer          transport.Producer
+	topic             string
+	conflationManager *conflator.ConflationManager
+}
+
+func AddDeadLetterQueue(mgr ctrl.Manager, producer transport.Producer, topic string,
+	conflationManager *conflator.ConflationManager,
+) (*DeadLetterQueue, error) {
+	deadLetterQueue := NewDeadLetterQueue(producer, topic, conflationManager)
+	// the spilled events which aren't replayed before the restart are still replayed before the later delta events
+	if err := deadLetterQueue.restoreSpilled(); err != nil {
+		return nil, fmt.Errorf("failed to restore the spilled events: %w", err)
+	}
+	conflationManager.SetOverflowHandler(deadLetterQueue.Spill)
+	if err := mgr.Add(deadLetterQueue); err != nil {
+		return nil, fmt.Errorf("failed to add dead letter queue: %w", err)
+	}
+	return deadLetterQueue, nil
+}
+
+func NewDeadLetterQueue(producer transport.Producer, topic string,
+	conflationManager *conflator.ConflationManager,
+) *DeadLetterQueue {
+	return &DeadLetterQueue{
+		log:               logger.ZapLogger("dead-letter-queue"),
+		producer:          producer,
+		topic:             topic,
+		conflationManager: conflationManager,
+	}
+}
+
+// Start replays the requested dead letters periodically
+func (q *DeadLetterQueue) Start(ctx context.Context) error {
+	q.log.Infow("starting dead letter queue", "topic", q.topic)
+	ticker := time.NewTicker(deadLetterReplayInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			q.log.Info("stopped dead letter queue")
+			return nil
+		case <-ticker.C:
+			if err := q.replay(ctx); err != nil {
+				q.log.Warnw("failed to replay the dead letters", "error", err)
+			}
+		}
+	}
+}
+
+// Handle implements the workerpool.DeadLetterHandler, the failure of the dead letter is logged only, so that it
+// doesn't block the worker.
+func (q *DeadLetterQueue) Handle(ctx context.Context, evt *cloudevents.Event, handleErr error) {
+	q.log.Warnw("moving the event to the dead letters", "source", evt.Source(), "type", evt.Type(),
+		"error", handleErr)
+
+	deadLetter, err := NewDeadLetter(evt, handleErr)
+	if err != nil {
+		q.log.Errorw("failed to build the dead letter", "source", evt.Source(), "type", evt.Type(), "error", err)
+		return
+	}
+	if err := database.GetGorm().WithContext(ctx).Create(deadLetter).Error; err != nil {
+		q.log.Errorw("failed to store the dead letter", "source", evt.Source(), "type", evt.Type(), "error", err)
+	}
+
+	if q.topic == "" || q.producer == nil {
+		return
+	}
+	deadLetterEvent := evt.Clone()
+	deadLetterEvent.SetExtension(ExtDeadLetterError, handleErr.Error())
+	if err := q.producer.SendEvent(cecontext.WithTopic(ctx, q.topic), deadLetterEvent); err != nil {
+		q.log.Errorw("failed to send the dead letter", "topic", q.topic, "source", evt.Source(), "type", evt.Type(),
+			"error", err)
+	}
+}
+
+// Spill implements the conflator.OverflowHandler, the event overflowing the conflation queue of its hub is stored as
+// the dead letter requested to be replayed, so it's inserted into the conflation manager again in order once the
+// queue has room. It isn't published to the dead letter topic since it isn't failed.
+func (q *DeadLetterQueue) Spill(evt *cloudevents.Event) error {
+	deadLetter, err := NewDeadLetter(evt, conflator.ErrHubQueueFull)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	deadLetter.ReplayRequestedAt = &now
+	return database.GetGorm().Create(deadLetter).Error
+}
+
+// restoreSpilled sets the number of the spilled events of the hubs which aren't replayed yet to the conflation manager
+func (q *DeadLetterQueue) restoreSpilled() error {
+	spilled := []struct {
+		LeafHubName string
+		Count       int
+	}{}
+	err := database.GetGorm().Model(&models.DeadLetter{}).Select("leaf_hub_name, count(*) AS count").
+		Where("error = ? AND replay_requested_at IS NOT NULL AND replayed_at IS NULL", conflator.ErrHubQueueFull.Error()).
+		Group("leaf_hub_name").Scan(&spilled).Error
+	if err != nil {
+		return err
+	}
+	for _, hub := range spilled {
+		q.log.Infow("restore the spilled events of the hub", "hub", hub.LeafHubName, "count", hub.Count)
+		q.conflationManager.SetSpilled(hub.LeafHubName, hub.Count)
+	}
+	return nil
+}
+
+// replay inserts the requested dead letters into the conflation manager in order, and marks them as replayed. The
+// remaining dead letters of the hub are replayed in the next round once its conflation queue is full.
+func (q *DeadLetterQueue) replay(ctx context.Context) error {
+	db := database.GetGorm().WithContext(ctx)
+	deadLetters := []models.DeadLetter{}
+	err := db.Where("replay_requested_at IS NOT NULL AND replayed_at IS NULL").Order("id").Find(&deadLetters).Error
+	if err != nil {
+		return err
+	}
+	fullHubs := map[string]bool{}
+	for _, deadLetter := range deadLetters {
+		if fullHubs[deadLetter.LeafHubName] {
+			continue
+		}
+		evt, err := DeadLetterToEvent(&deadLetter)
+		if err != nil {
+			q.log.Errorw("failed to restore the dead letter", "id", deadLetter.ID, "error", err)
+			if deadLetter.Error == conflator.ErrHubQueueFull.Error() {
+				q.conflationManager.ReleaseSpilled(deadLetter.LeafHubName)
+			}
+		} else {
+			q.log.Debugw("replaying the dead letter", "id", deadLetter.ID, "source", evt.Source(), "type", evt.Type())
+			err := q.conflationManager.Replay(evt)
+			if errors.Is(err, conflator.ErrHubQueueFull) {
+				fullHubs[deadLetter.LeafHubName] = true
+				continue
+			}
+			if err != nil {
+				q.log.Infow("skip the dead letter", "id", deadLetter.ID, "source", evt.Source(), "type", evt.Type(),
+					"reason", err.Error())
+			}
+		}
+		// mark the broken dead letter as replayed too, otherwise it's retried forever
+		if err := db.Model(&deadLetter).Update("replayed_at", time.Now()).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// NewDeadLetter builds the dead letter record from the event, the data is stored as it is since it might not be
+// a valid json, e.g. the event failed to be unmarshalled.
+func NewDeadLetter(evt *cloudevents.Event, handleErr error) (*models.DeadLetter, error) {
+	attributes := evt.Clone()
+	if err := attributes.SetData(evt.DataContentType(), nil); err != nil {
+		return nil, err
+	}
+	eventBytes, err := json.Marshal(attributes)
+	if err != nil {
+		return nil, err
+	}
+	eventVersion := ""
+	if val, found := evt.Extensions()[version.ExtVersion]; found {
+		eventVersion = fmt.Sprintf("%v", val)
+	}
+	return &models.DeadLetter{
+		LeafHubName:  evt.Source(),
+		EventType:    evt.Type(),
+		EventVersion: eventVersion,
+		Error:        handleErr.Error(),
+		Event:        eventBytes,
+		Data:         evt.Data(),
+	}, nil
+}
+
+// DeadLetterToEvent restores the event from the dead letter record. The transport position is removed, so that the
+// committer won't commit the replayed event as the latest position of the topic.
+func DeadLetterToEvent(deadLetter *models.DeadLetter) (*cloudevents.Event, error) {
+	evt := cloudevents.NewEvent()
+	if err := json.Unmarshal(deadLetter.Event, &evt); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal the event: %w", err)
+	}
+	if err := evt.SetData(evt.DataContentType(), deadLetter.Data); err != nil {
+		return nil, fmt.Errorf("failed to set the event data: %w", err)
+	}
+	evt.SetExtension(kafka_confluent.KafkaTopicKey, nil)
+	evt.SetExtension(kafka_confluent.KafkaPartitionKey, nil)
+	evt.SetExtension(kafka_confluent.KafkaOffsetKey, nil)
+	return &evt, nil
+}
@@ -0,0 +1,47 @@
+package dispatcher
+
+import (
+	"errors"
+	"testing"
+
+	kafka_confluent "github.com/cloudevents/sdk-go/protocol/kafka_confluent/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+)
+
+func TestDeadLetter(t *testing.T) {
+	evt := cloudevents.NewEvent()
+	evt.SetID("1")
+	evt.SetSource("hub1")
+	evt.SetType("io.open-cluster-management.operator.multiclusterglobalhubs.managedcluster")
+	evt.SetExtension(version.ExtVersion, "1.2")
+	evt.SetExtension(kafka_confluent.KafkaTopicKey, "gh-status.hub1")
+	evt.SetExtension(kafka_confluent.KafkaPartitionKey, 0)
+	evt.SetExtension(kafka_confluent.KafkaOffsetKey, "10")
+	// the data isn't a valid json
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, []byte(`{"metadata":`)))
+
+	deadLetter, err := NewDeadLetter(&evt, errors.New("unexpected end of JSON input"))
+	require.NoError(t, err)
+	require.Equal(t, "hub1", deadLetter.LeafHubName)
+	require.Equal(t, evt.Type(), deadLetter.EventType)
+	require.Equal(t, "1.2", deadLetter.EventVersion)
+	require.Equal(t, "unexpected end of JSON input", deadLetter.Error)
+	require.Equal(t, `{"metadata":`, string(deadLetter.Data))
+
+	replayed, err := DeadLetterToEvent(deadLetter)
+	require.NoError(t, err)
+	require.Equal(t, evt.ID(), replayed.ID())
+	require.Equal(t, evt.Source(), replayed.Source())
+	require.Equal(t, evt.Type(), replayed.Type())
+	require.Equal(t, evt.DataContentType(), replayed.DataContentType())
+	require.Equal(t, "1.2", replayed.Extensions()[version.ExtVersion])
+	require.Equal(t, evt.Data(), replayed.Data())
+
+	// the transport position is removed from the replayed event
+	require.NotContains(t, replayed.Extensions(), kafka_confluent.KafkaTopicKey)
+	require.NotContains(t, replayed.Extensions(), kafka_confluent.KafkaPartitionKey)
+	require.NotContains(t, replayed.Extensions(), kafka_confluent.KafkaOffsetKey)
+}
@@ -0,0 +1,145 @@
+package dispatcher
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/client-go/util/workqueue"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+const (
+	// ConditionTypeSchemaCompatible is the condition of the managed hub cluster indicating whether the events emitted
+	// by the hub are compatible with the schema versions supported by the manager
+	ConditionTypeSchemaCompatible = "GlobalHubSchemaCompatible"
+	ReasonSchemaCompatible        = "SchemaCompatible"
+	ReasonSchemaIncompatible      = "IncompatibleSchema"
+)
+
+// SchemaConditionReporter reports the schema compatibility of the managed hubs as the condition of the hub clusters.
+// It tracks the incompatible event types of each hub in memory, and only queues the hub once they're changed. The
+// conditions are updated by its own worker, so the transport dispatching never waits for the api server.
+type SchemaConditionReporter struct {
+	log    *zap.SugaredLogger
+	client client.Client
+	queue  workqueue.TypedRateLimitingInterface[string]
+	mu     sync.Mutex
+	// hub name -> event type -> the incompatible error, the hub isn't tracked until the first event is reported
+	incompatibles map[string]map[string]string
+}
+
+func NewSchemaConditionReporter(c client.Client) *SchemaConditionReporter {
+	return &SchemaConditionReporter{
+		log:    logger.ZapLogger("schema-condition-reporter"),
+		client: c,
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[string](),
+			workqueue.TypedRateLimitingQueueConfig[string]{Name: "schema-condition-reporter"}),
+		incompatibles: map[string]map[string]string{},
+	}
+}
+
+// Report records the compatibility of the event type received from the hub, a nil error means it's compatible.
+func (r *SchemaConditionReporter) Report(hubName, eventType string, incompatibleErr error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	eventTypes, tracked := r.incompatibles[hubName]
+	if !tracked {
+		eventTypes = map[string]string{}
+		r.incompatibles[hubName] = eventTypes
+	}
+	_, wasIncompatible := eventTypes[eventType]
+	if tracked && wasIncompatible == (incompatibleErr != nil) {
+		return
+	}
+
+	if incompatibleErr != nil {
+		eventTypes[eventType] = incompatibleErr.Error()
+	} else {
+		delete(eventTypes, eventType)
+	}
+	r.queue.Add(hubName)
+}
+
+// Start updates the conditions of the queued hubs until the context is cancelled
+func (r *SchemaConditionReporter) Start(ctx context.Context) error {
+	go func() {
+		<-ctx.Done()
+		r.queue.ShutDown()
+	}()
+	for r.processNext(ctx) {
+	}
+	return nil
+}
+
+// processNext updates the condition of the next queued hub, the failed one is requeued with the rate limit
+func (r *SchemaConditionReporter) processNext(ctx context.Context) bool {
+	hubName, shutdown := r.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer r.queue.Done(hubName)
+
+	r.mu.Lock()
+	messages := make([]string, 0, len(r.incompatibles[hubName]))
+	for _, msg := range r.incompatibles[hubName] {
+		messages = append(messages, msg)
+	}
+	r.mu.Unlock()
+
+	if err := r.updateCondition(ctx, hubName, messages); err != nil {
+		r.log.Warnw("failed to update the schema compatible condition", "hub", hubName, "error", err)
+		r.queue.AddRateLimited(hubName)
+		return true
+	}
+	r.queue.Forget(hubName)
+	return true
+}
+
+func (r *SchemaConditionReporter) updateCondition(ctx context.Context, hubName string, messages []string) error {
+	condition := metav1.Condition{
+		Type:    ConditionTypeSchemaCompatible,
+		Status:  metav1.ConditionTrue,
+		Reason:  ReasonSchemaCompatible,
+		Message: "The events of the hub are compatible with the global hub manager",
+	}
+	if len(messages) > 0 {
+		sort.Strings(messages)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonSchemaIncompatible
+		condition.Message = fmt.Sprintf("The events of the hub are dropped: %s", strings.Join(messages, "; "))
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster := &clusterv1.ManagedCluster{}
+		if err := r.client.Get(ctx, types.NamespacedName{Name: hubName}, cluster); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		existing := meta.FindStatusCondition(cluster.Status.Conditions, condition.Type)
+		if existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason &&
+			existing.Message == condition.Message {
+			return nil
+		}
+		condition.LastTransitionTime = metav1.NewTime(time.Now())
+		meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+		r.log.Infow("updating the schema compatible condition", "hub", hubName, "status", condition.Status,
+			"message", condition.Message)
+		return r.client.Status().Update(ctx, cluster)
+	})
+}
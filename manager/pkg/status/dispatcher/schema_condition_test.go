@@ -0,0 +1,65 @@
+package dispatcher
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/schema"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func TestSchemaConditionReporter(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clusterv1.AddToScheme(scheme))
+	hub := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "hub1"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(hub).WithStatusSubresource(hub).Build()
+
+	ctx := context.Background()
+	reporter := NewSchemaConditionReporter(fakeClient)
+	getCondition := func() *metav1.Condition {
+		cluster := &clusterv1.ManagedCluster{}
+		require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "hub1"}, cluster))
+		return meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeSchemaCompatible)
+	}
+
+	// report the hub and update its condition by the worker
+	report := func(hubName, eventType string, err error) {
+		reporter.Report(hubName, eventType, err)
+		for reporter.queue.Len() > 0 {
+			require.True(t, reporter.processNext(ctx))
+		}
+	}
+
+	report("hub1", string(enum.ManagedClusterType), nil)
+	condition := getCondition()
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+
+	incompatibleErr := &schema.IncompatibleError{
+		EventType: string(enum.HubClusterInfoType), Version: 2, SupportedVersion: 1, Reason: "newer agent",
+	}
+	report("hub1", string(enum.HubClusterInfoType), incompatibleErr)
+	condition = getCondition()
+	require.Equal(t, metav1.ConditionFalse, condition.Status)
+	require.Equal(t, ReasonSchemaIncompatible, condition.Reason)
+	require.Contains(t, condition.Message, incompatibleErr.Error())
+
+	// the other compatible event type doesn't recover the condition, and the unchanged hub isn't queued
+	reporter.Report("hub1", string(enum.ManagedClusterType), nil)
+	require.Zero(t, reporter.queue.Len())
+	require.Equal(t, metav1.ConditionFalse, getCondition().Status)
+
+	report("hub1", string(enum.HubClusterInfoType), nil)
+	require.Equal(t, metav1.ConditionTrue, getCondition().Status)
+
+	// the hub cluster doesn't exist
+	report("hub2", string(enum.HubClusterInfoType), incompatibleErr)
+}
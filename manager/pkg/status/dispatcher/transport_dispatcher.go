@@ -2,13 +2,16 @@ package dispatcher
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"go.uber.org/zap"
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/schema"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
@@ -20,16 +23,24 @@ type TransportDispatcher struct {
 	consumer          transport.Consumer
 	conflationManager *conflator.ConflationManager
 	statistic         *statistics.Statistics
+	schemaRegistry    *schema.Registry
+	schemaReporter    *SchemaConditionReporter
 }
 
 func AddTransportDispatcher(mgr ctrl.Manager, consumer transport.Consumer, managerConfig *configs.ManagerConfig,
 	conflationManager *conflator.ConflationManager, stats *statistics.Statistics,
 ) error {
+	schemaReporter := NewSchemaConditionReporter(mgr.GetClient())
+	if err := mgr.Add(schemaReporter); err != nil {
+		return fmt.Errorf("failed to add schema condition reporter to runtime manager: %w", err)
+	}
 	transportDispatcher := &TransportDispatcher{
 		log:               logger.DefaultZapLogger(),
 		consumer:          consumer,
 		conflationManager: conflationManager,
 		statistic:         stats,
+		schemaRegistry:    schema.DefaultRegistry,
+		schemaReporter:    schemaReporter,
 	}
 	if err := mgr.Add(transportDispatcher); err != nil {
 		return fmt.Errorf("failed to add transport dispatcher to runtime manager: %w", err)
@@ -57,7 +68,27 @@ func (d *TransportDispatcher) dispatch(ctx context.Context) {
 		case evt := <-d.consumer.EventChan():
 			d.statistic.ReceivedEvent(evt)
 			d.log.Debugf("received event: %s", evt)
+			if !d.checkSchema(evt) {
+				continue
+			}
 			d.conflationManager.Insert(evt)
 		}
 	}
 }
+
+// checkSchema upcasts the event into the schema version supported by the manager, the incompatible event is dropped
+// and reported as the condition of the hub, so that the hubs with mixed versions don't break the status silently.
+func (d *TransportDispatcher) checkSchema(evt *cloudevents.Event) bool {
+	err := d.schemaRegistry.Upcast(evt)
+	incompatibleErr := &schema.IncompatibleError{}
+	if err != nil && !errors.As(err, &incompatibleErr) {
+		d.log.Warnw("failed to check the schema of the event", "source", evt.Source(), "type", evt.Type(),
+			"error", err)
+		return false
+	}
+	if err != nil {
+		d.log.Warnw("dropping the incompatible event", "source", evt.Source(), "type", evt.Type(), "error", err)
+	}
+	d.schemaReporter.Report(evt.Source(), evt.Type(), err)
+	return err == nil
+}
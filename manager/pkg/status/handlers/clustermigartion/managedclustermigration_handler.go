@@ -98,6 +98,9 @@ func (k *managedClusterMigrationHandler) handle(ctx context.Context, evt *cloude
 			log.Infof("status: cluster errors, id: %s, errors: %v", bundle.MigrationId, bundle.ClusterErrors)
 		}
 	} else {
+		if bundle.DryRunReport != nil {
+			migration.SetDryRunReport(bundle.MigrationId, hubClusterName, bundle.Stage, bundle.DryRunReport)
+		}
 		migration.SetFinished(bundle.MigrationId, hubClusterName, bundle.Stage)
 		log.Infof("status: migration stage completed, id: %s, hub: %s, stage: %s",
 			bundle.MigrationId, hubClusterName, bundle.Stage)
@@ -66,3 +66,27 @@ func TestHandleMigrationEvent(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleDryRunReport(t *testing.T) {
+	migrationId := "dry-run"
+	migration.AddMigrationStatus(migrationId)
+	defer migration.RemoveMigrationStatus(migrationId)
+	handler := &managedClusterMigrationHandler{}
+
+	report := &migrationv1alpha1.DryRunReport{
+		RBAC: []string{"create ClusterRoleBinding/global-hub-migration-test-registration"},
+	}
+	event := cloudevents.NewEvent()
+	event.SetSource("hub2")
+	event.SetType("com.example.migration")
+	event.SetExtension(constants.CloudEventExtensionKeyClusterName, constants.CloudEventGlobalHubClusterName)
+	require.NoError(t, event.SetData(cloudevents.ApplicationJSON, migrationbundle.MigrationStatusBundle{
+		Stage:        migrationv1alpha1.PhaseInitializing,
+		MigrationId:  migrationId,
+		DryRunReport: report,
+	}))
+
+	require.NoError(t, handler.handle(context.Background(), &event))
+	assert.True(t, migration.GetFinished(migrationId, "hub2", migrationv1alpha1.PhaseInitializing))
+	assert.Equal(t, report, migration.GetDryRunReport(migrationId, "hub2", migrationv1alpha1.PhaseInitializing))
+}
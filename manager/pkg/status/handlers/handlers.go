@@ -22,7 +22,7 @@ import (
 func RegisterHandlers(mgr ctrl.Manager, cmr *conflator.ConflationManager, enableGlobalResource bool) {
 	// managed hub
 	managedhub.RegisterHubClusterHeartbeatHandler(cmr)
-	managedhub.RegsiterHubClusterInfoHandler(cmr)
+	managedhub.RegsiterHubClusterInfoHandler(mgr.GetClient(), cmr)
 
 	// managed cluster
 	managedcluster.RegisterManagedClusterHandler(mgr.GetClient(), cmr)
@@ -48,6 +48,7 @@ func RegisterHandlers(mgr ctrl.Manager, cmr *conflator.ConflationManager, enable
 
 	// security
 	security.RegisterSecurityAlertCountsHandler(cmr)
+	security.RegisterSecurityViolationsHandler(cmr)
 
 	if enableGlobalResource {
 		// global policy
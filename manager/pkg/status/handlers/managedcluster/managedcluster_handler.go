@@ -14,6 +14,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/managedhub"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
@@ -59,6 +60,13 @@ func (h *managedClusterHandler) handleEvent(ctx context.Context, evt *cloudevent
 	leafHubName := evt.Source()
 	log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
 
+	if paused, err := hubmanagement.IsStatusPaused(ctx, leafHubName); err != nil {
+		return err
+	} else if paused {
+		log.Debugw("skip the bundle of the hub in maintenance", "type", enum.ShortenEventType(evt.Type()), "LH", leafHubName)
+		return nil
+	}
+
 	var bundle generic.GenericBundle[clusterv1.ManagedCluster]
 	err := evt.DataAs(&bundle)
 	if err != nil {
@@ -8,10 +8,16 @@ import (
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
 	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
@@ -21,14 +27,16 @@ import (
 var log = logger.DefaultZapLogger()
 
 type hubClusterInfoHandler struct {
+	client        client.Client
 	eventType     string
 	eventSyncMode enum.EventSyncMode
 	eventPriority conflator.ConflationPriority
 }
 
-func RegsiterHubClusterInfoHandler(conflationManager *conflator.ConflationManager) {
+func RegsiterHubClusterInfoHandler(c client.Client, conflationManager *conflator.ConflationManager) {
 	eventType := string(enum.HubClusterInfoType)
 	hubClusterInfo := &hubClusterInfoHandler{
+		client:        c,
 		eventType:     eventType,
 		eventSyncMode: enum.CompleteStateMode,
 		eventPriority: conflator.HubClusterInfoPriority,
@@ -81,10 +89,41 @@ func (h *hubClusterInfoHandler) handleEvent(ctx context.Context,
 		return err
 	}
 
+	// the annotation is recorded again by the periodic resync of the hub info if it's failed
+	if err := h.annotateClientCertIssuer(ctx, leafHubName, hubInfoData.TransportCertIssuer); err != nil {
+		log.Warnw("failed to annotate the client certificate issuer", "name", leafHubName, "error", err)
+	}
+
 	log.Debugw("handler finished", "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
 	return nil
 }
 
+// annotateClientCertIssuer records the issuer of the transport client certificate the agent is connected with on the
+// managed hub cluster, the operator confirms the agents are reconnected with the rotated certificates by it
+func (h *hubClusterInfoHandler) annotateClientCertIssuer(ctx context.Context, hubName, issuer string) error {
+	if issuer == "" || h.client == nil {
+		return nil
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		hub := &clusterv1.ManagedCluster{}
+		if err := h.client.Get(ctx, types.NamespacedName{Name: hubName}, hub); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if hub.Annotations[constants.ClientCertIssuerAnnotation] == issuer {
+			return nil
+		}
+		if hub.Annotations == nil {
+			hub.Annotations = map[string]string{}
+		}
+		hub.Annotations[constants.ClientCertIssuerAnnotation] = issuer
+		log.Infow("the agent is connected with the client certificate", "name", hubName, "issuer", issuer)
+		return h.client.Update(ctx, hub)
+	})
+}
+
 // TODO: Should get the cluster info by leafhub name and cluster id!
 func GetClusterInfo(db *gorm.DB, clusterName string) (models.ClusterInfo, error) {
 	var clusterInfo []models.ClusterInfo
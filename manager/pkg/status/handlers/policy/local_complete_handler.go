@@ -10,6 +10,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator/dependency"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
@@ -63,6 +64,13 @@ func (h *localPolicyCompleteHandler) handleCompleteCompliance(log *zap.SugaredLo
 	leafHub := evt.Source()
 	log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
 
+	if paused, err := hubmanagement.IsStatusPaused(ctx, leafHub); err != nil {
+		return err
+	} else if paused {
+		log.Debugw("skip the bundle of the hub in maintenance", "type", enum.ShortenEventType(evt.Type()), "LH", leafHub)
+		return nil
+	}
+
 	db := database.GetGorm()
 
 	// policyID: {  nonCompliance: (cluster3, cluster4), unknowns: (cluster5) }
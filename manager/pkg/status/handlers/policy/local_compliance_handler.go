@@ -12,6 +12,7 @@ import (
 	"gorm.io/gorm/clause"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers/managedhub"
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/grc"
@@ -54,6 +55,13 @@ func (h *localPolicyComplianceHandler) handleCompliance(ctx context.Context, evt
 	leafHub := evt.Source()
 	log.Debugw("handler start ", "type ", enum.ShortenEventType(evt.Type()), "LH ", evt.Source(), "version ", version)
 
+	if paused, err := hubmanagement.IsStatusPaused(ctx, leafHub); err != nil {
+		return err
+	} else if paused {
+		log.Debugw("skip the bundle of the hub in maintenance", "type", enum.ShortenEventType(evt.Type()), "LH", leafHub)
+		return nil
+	}
+
 	data := grc.ComplianceBundle{}
 	if err := evt.DataAs(&data); err != nil {
 		return err
@@ -0,0 +1,205 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"go.uber.org/zap"
+	"gorm.io/gorm/clause"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	dbmodels "github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+const (
+	violationsBatchSize = 50
+
+	ViolationStateActive   = "ACTIVE"
+	ViolationStateResolved = "RESOLVED"
+)
+
+type securityViolationsHandler struct {
+	log           *zap.SugaredLogger
+	eventType     string
+	eventSyncMode enum.EventSyncMode
+	eventPriority conflator.ConflationPriority
+}
+
+func RegisterSecurityViolationsHandler(conflationManager *conflator.ConflationManager) {
+	eventType := string(enum.SecurityViolationsType)
+	logName := strings.ReplaceAll(eventType, enum.EventTypePrefix, "")
+	h := &securityViolationsHandler{
+		log:           logger.ZapLogger(logName),
+		eventType:     eventType,
+		eventSyncMode: enum.DeltaStateMode,
+		eventPriority: conflator.SecurityViolationsPriority,
+	}
+	conflationManager.Register(conflator.NewConflationRegistration(
+		h.eventPriority,
+		h.eventSyncMode,
+		h.eventType,
+		h.handleEvent,
+	))
+}
+
+func (h *securityViolationsHandler) handleEvent(ctx context.Context, evt *cloudevents.Event) error {
+	version := evt.Extensions()[eventversion.ExtVersion]
+	leafHubName := evt.Source()
+	h.log.Debugw("handler start", "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
+
+	var bundle generic.GenericBundle[wiremodels.SecurityViolation]
+	if err := evt.DataAs(&bundle); err != nil {
+		h.log.Warnw("failed to unmarshal security violations bundle", "type", enum.ShortenEventType(evt.Type()),
+			"LH", evt.Source(), "version", version, "error", err)
+		return nil
+	}
+
+	for _, violations := range [][]wiremodels.SecurityViolation{bundle.Resync, bundle.Create, bundle.Update} {
+		if err := h.upsertViolations(leafHubName, violations); err != nil {
+			return fmt.Errorf("failed to process security violations - %w", err)
+		}
+	}
+
+	if len(bundle.ResyncMetadata) > 0 {
+		if err := h.resolveViolations(leafHubName, bundle.ResyncMetadata); err != nil {
+			return fmt.Errorf("failed to resolve security violations - %w", err)
+		}
+	}
+
+	h.log.Debugw("handler finished", "type", enum.ShortenEventType(evt.Type()), "LH", evt.Source(), "version", version)
+	return nil
+}
+
+func (h *securityViolationsHandler) upsertViolations(leafHubName string,
+	violations []wiremodels.SecurityViolation,
+) error {
+	if len(violations) == 0 {
+		return nil
+	}
+
+	db := database.GetGorm()
+	firstSeen, err := h.getFirstSeen(leafHubName, violations)
+	if err != nil {
+		return fmt.Errorf("failed to get the stored violations - %w", err)
+	}
+
+	now := time.Now().UTC()
+	dbModels := make([]dbmodels.SecurityViolation, 0, len(violations))
+	for _, violation := range violations {
+		categories, err := json.Marshal(violation.Categories)
+		if err != nil {
+			return err
+		}
+		// the table is partitioned by the time first seen by the global hub, so the partitions created by the data
+		// retention always cover the new violations, and the stored violation keeps its partition once the central
+		// updates its time
+		createdAt, found := firstSeen[violationKey{violation.Source, violation.ID}]
+		if !found {
+			createdAt = now
+		}
+		lastSeenAt := violation.Time.UTC()
+		if lastSeenAt.IsZero() {
+			lastSeenAt = now
+		}
+		dbModels = append(dbModels, dbmodels.SecurityViolation{
+			HubName:           leafHubName,
+			Source:            violation.Source,
+			ID:                violation.ID,
+			PolicyID:          violation.PolicyID,
+			PolicyName:        violation.PolicyName,
+			Severity:          violation.Severity,
+			Categories:        categories,
+			LifecycleStage:    violation.LifecycleStage,
+			State:             violation.State,
+			ClusterName:       violation.ClusterName,
+			ClusterID:         violation.ClusterID,
+			Namespace:         violation.Namespace,
+			DeploymentName:    violation.DeploymentName,
+			DeploymentID:      violation.DeploymentID,
+			ResourceType:      violation.ResourceType,
+			EnforcementAction: violation.EnforcementAction,
+			EnforcementCount:  violation.EnforcementCount,
+			DetailURL:         violation.DetailURL,
+			CreatedAt:         createdAt,
+			LastSeenAt:        lastSeenAt,
+		})
+	}
+
+	return db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "hub_name"}, {Name: "source"}, {Name: "id"}, {Name: "created_at"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"policy_id", "policy_name", "severity", "categories", "lifecycle_stage", "state", "cluster_name",
+			"cluster_id", "namespace", "deployment_name", "deployment_id", "resource_type", "enforcement_action",
+			"enforcement_count", "detail_url", "last_seen_at", "updated_at",
+		}),
+	}).CreateInBatches(dbModels, violationsBatchSize).Error
+}
+
+// violationKey identifies the violation of the hub
+type violationKey struct {
+	source string
+	id     string
+}
+
+// getFirstSeen returns the time the stored violations are first seen, it's the partition key of the violations
+func (h *securityViolationsHandler) getFirstSeen(leafHubName string, violations []wiremodels.SecurityViolation,
+) (map[violationKey]time.Time, error) {
+	ids := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		ids = append(ids, violation.ID)
+	}
+	stored := []dbmodels.SecurityViolation{}
+	err := database.GetGorm().Select("source", "id", "created_at").
+		Where("hub_name = ? AND id IN ?", leafHubName, ids).Find(&stored).Error
+	if err != nil {
+		return nil, err
+	}
+	firstSeen := map[violationKey]time.Time{}
+	for _, violation := range stored {
+		key := violationKey{violation.Source, violation.ID}
+		if existing, found := firstSeen[key]; !found || violation.CreatedAt.Before(existing) {
+			firstSeen[key] = violation.CreatedAt
+		}
+	}
+	return firstSeen, nil
+}
+
+// resolveViolations marks the active violations of the sources that aren't in the metadata as resolved, the metadata
+// contains all the active violations of the sources.
+func (h *securityViolationsHandler) resolveViolations(leafHubName string, metadata []generic.ObjectMetadata) error {
+	activeIds := map[string][]string{}
+	for _, meta := range metadata {
+		if _, ok := activeIds[meta.Namespace]; !ok {
+			activeIds[meta.Namespace] = []string{}
+		}
+		if meta.ID != "" {
+			activeIds[meta.Namespace] = append(activeIds[meta.Namespace], meta.ID)
+		}
+	}
+
+	db := database.GetGorm()
+	for source, ids := range activeIds {
+		tx := db.Model(&dbmodels.SecurityViolation{}).
+			Where("hub_name = ? AND source = ? AND state = ?", leafHubName, source, ViolationStateActive)
+		if len(ids) > 0 {
+			tx = tx.Where("id NOT IN ?", ids)
+		}
+		result := tx.Updates(map[string]any{"state": ViolationStateResolved, "updated_at": time.Now()})
+		if result.Error != nil {
+			return result.Error
+		}
+		h.log.Debugw("resolved security violations", "LH", leafHubName, "source", source,
+			"count", result.RowsAffected)
+	}
+	return nil
+}
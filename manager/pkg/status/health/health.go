@@ -0,0 +1,94 @@
+package health
+
+import (
+	"math"
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+const (
+	// HealthyScore is the min score of a healthy sample, the SLO is the ratio of the healthy samples
+	HealthyScore = 80
+	// SLOTarget is the target ratio of the healthy samples of each hub
+	SLOTarget = 0.99
+)
+
+// signal is a health signal of the hub, it's scored 1 if the value is within the good threshold, 0 if it exceeds the
+// bad threshold, and linearly in between.
+type signal struct {
+	weight float64
+	good   float64
+	bad    float64
+}
+
+var (
+	heartbeatLatencySignal = signal{weight: 0.4, good: 2 * 60, bad: 5 * 60}
+	eventLagSignal         = signal{weight: 0.3, good: 60, bad: 10 * 60}
+	queueBacklogSignal     = signal{weight: 0.15, good: 100, bad: 1000}
+	failureRatioSignal     = signal{weight: 0.15, good: 0, bad: 0.1}
+)
+
+func (s signal) score(value float64) float64 {
+	switch {
+	case value <= s.good:
+		return 1
+	case value >= s.bad:
+		return 0
+	default:
+		return (s.bad - value) / (s.bad - s.good)
+	}
+}
+
+// burnRateWindow is the window to calculate the burn rate of the error budget, the alert fires once the burn rate
+// in the window exceeds the threshold. The thresholds follow the multiwindow burn rate alerts of the SRE workbook.
+type burnRateWindow struct {
+	name      string
+	duration  time.Duration
+	threshold float64
+	severity  string
+}
+
+var burnRateWindows = []burnRateWindow{
+	{name: "1h", duration: time.Hour, threshold: 14.4, severity: "critical"},
+	{name: "6h", duration: 6 * time.Hour, threshold: 6, severity: "warning"},
+}
+
+// hubHealth is the health sample of the hub scored by the signals.
+type hubHealth struct {
+	name             string
+	heartbeatLatency time.Duration
+	sample           statistics.HubHealthSample
+	score            int
+}
+
+func (h *hubHealth) healthy() bool {
+	return h.score >= HealthyScore
+}
+
+// scoreHubHealth scores the hub by the weighted scores of the signals, from 0 to 100.
+func scoreHubHealth(name string, heartbeatLatency time.Duration, sample statistics.HubHealthSample) *hubHealth {
+	failureRatio := float64(0)
+	if sample.HandledEvents > 0 {
+		failureRatio = float64(sample.FailedEvents) / float64(sample.HandledEvents)
+	}
+	score := heartbeatLatencySignal.weight*heartbeatLatencySignal.score(heartbeatLatency.Seconds()) +
+		eventLagSignal.weight*eventLagSignal.score(sample.MaxEventLag.Seconds()) +
+		queueBacklogSignal.weight*queueBacklogSignal.score(float64(sample.QueueBacklog)) +
+		failureRatioSignal.weight*failureRatioSignal.score(failureRatio)
+	return &hubHealth{
+		name:             name,
+		heartbeatLatency: heartbeatLatency,
+		sample:           sample,
+		score:            int(math.Round(score * 100)),
+	}
+}
+
+// burnRate is how fast the error budget is consumed in the window, 1 means the budget is exactly consumed at the end
+// of the SLO period.
+func burnRate(unhealthy, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(unhealthy) / float64(total) / (1 - SLOTarget)
+}
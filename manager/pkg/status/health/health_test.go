@@ -0,0 +1,103 @@
+package health
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+func TestScoreHubHealth(t *testing.T) {
+	cases := []struct {
+		name             string
+		heartbeatLatency time.Duration
+		sample           statistics.HubHealthSample
+		wantScore        int
+		wantHealthy      bool
+	}{
+		{
+			name:             "all the signals are good",
+			heartbeatLatency: 30 * time.Second,
+			sample:           statistics.HubHealthSample{MaxEventLag: time.Second, HandledEvents: 10},
+			wantScore:        100,
+			wantHealthy:      true,
+		},
+		{
+			name:             "the heartbeat is missing",
+			heartbeatLatency: 10 * time.Minute,
+			sample:           statistics.HubHealthSample{},
+			wantScore:        60,
+			wantHealthy:      false,
+		},
+		{
+			name:             "the events are lagging and the queue is backlogged",
+			heartbeatLatency: time.Minute,
+			sample: statistics.HubHealthSample{
+				MaxEventLag: (60 + 540/2) * time.Second, QueueBacklog: 1000, HandledEvents: 10,
+			},
+			wantScore:   70,
+			wantHealthy: false,
+		},
+		{
+			name:             "a few events are failed",
+			heartbeatLatency: time.Minute,
+			sample:           statistics.HubHealthSample{HandledEvents: 100, FailedEvents: 5},
+			wantScore:        93,
+			wantHealthy:      true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			health := scoreHubHealth("hub1", tc.heartbeatLatency, tc.sample)
+			require.Equal(t, tc.wantScore, health.score)
+			require.Equal(t, tc.wantHealthy, health.healthy())
+		})
+	}
+}
+
+func TestBurnRate(t *testing.T) {
+	require.Equal(t, float64(0), burnRate(0, 0))
+	require.InDelta(t, 1, burnRate(1, 100), 0.0001)
+	require.InDelta(t, 15, burnRate(9, 60), 0.0001)
+}
+
+func TestUpdateCondition(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, clusterv1.AddToScheme(scheme))
+	hub := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "hub1"}}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(hub).WithStatusSubresource(hub).Build()
+
+	ctx := context.Background()
+	scorer := NewHubHealthScorer(fakeClient, &statistics.Statistics{}, time.Minute)
+	getCondition := func() *metav1.Condition {
+		cluster := &clusterv1.ManagedCluster{}
+		require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "hub1"}, cluster))
+		return meta.FindStatusCondition(cluster.Status.Conditions, ConditionTypeHubHealthy)
+	}
+
+	health := scoreHubHealth("hub1", time.Minute, statistics.HubHealthSample{})
+	require.NoError(t, scorer.updateCondition(ctx, health, nil))
+	condition := getCondition()
+	require.NotNil(t, condition)
+	require.Equal(t, metav1.ConditionTrue, condition.Status)
+
+	health = scoreHubHealth("hub1", 10*time.Minute, statistics.HubHealthSample{})
+	require.NoError(t, scorer.updateCondition(ctx, health, []string{"the error budget is burning 20.0x in the last 1h"}))
+	condition = getCondition()
+	require.Equal(t, metav1.ConditionFalse, condition.Status)
+	require.Equal(t, ReasonSLOBurnRateHigh, condition.Reason)
+	require.Contains(t, condition.Message, "burning 20.0x in the last 1h")
+
+	// the hub cluster doesn't exist
+	health = scoreHubHealth("hub2", 10*time.Minute, statistics.HubHealthSample{})
+	require.NoError(t, scorer.updateCondition(ctx, health, nil))
+}
@@ -0,0 +1,223 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
+)
+
+const (
+	// ScoreInterval is the interval to sample the health of the hubs
+	ScoreInterval = 1 * time.Minute
+
+	// ConditionTypeHubHealthy is the condition of the managed hub cluster indicating whether the health SLO of the
+	// hub is met, it's false once the error budget is burning too fast in any of the windows
+	ConditionTypeHubHealthy = "GlobalHubHealthy"
+	ReasonHubHealthy        = "HubHealthy"
+	ReasonSLOBurnRateHigh   = "SLOBurnRateHigh"
+
+	hubActive = "active"
+)
+
+var (
+	hubHealthScoreGaugeVec = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_leaf_hub_health_score",
+			Help: "The health score of the leaf hub, from 0 to 100.",
+		},
+		[]string{"hub"},
+	)
+	hubSLOBurnRateGaugeVec = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_leaf_hub_slo_burn_rate",
+			Help: "The burn rate of the error budget of the leaf hub health SLO in the window.",
+		},
+		[]string{"hub", "window"},
+	)
+	hubSLOAlertGaugeVec = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_leaf_hub_slo_alert",
+			Help: "Whether the burn rate alert of the leaf hub health SLO is firing. 1 == firing, 0 == resolved.",
+		},
+		[]string{"hub", "severity"},
+	)
+	registerHealthMetricsOnce sync.Once
+)
+
+// HubHealthScorer samples the health of the active hubs periodically. The samples are stored into the
+// status.leaf_hub_health table, and the burn rates of the health SLO are calculated by the samples in the windows,
+// then exported as metrics and the condition of the hub clusters.
+type HubHealthScorer struct {
+	log        *zap.SugaredLogger
+	client     client.Client
+	statistics *statistics.Statistics
+	interval   time.Duration
+	// hub name -> the reason of the healthy condition, the condition is updated only when the reason is changed
+	reasons map[string]string
+}
+
+func AddHubHealthScorer(mgr ctrl.Manager, stats *statistics.Statistics) error {
+	registerHealthMetricsOnce.Do(func() {
+		metrics.Registry.MustRegister(hubHealthScoreGaugeVec, hubSLOBurnRateGaugeVec, hubSLOAlertGaugeVec)
+	})
+	return mgr.Add(NewHubHealthScorer(mgr.GetClient(), stats, ScoreInterval))
+}
+
+func NewHubHealthScorer(c client.Client, stats *statistics.Statistics, interval time.Duration) *HubHealthScorer {
+	return &HubHealthScorer{
+		log:        logger.ZapLogger("hub-health-scorer"),
+		client:     c,
+		statistics: stats,
+		interval:   interval,
+		reasons:    map[string]string{},
+	}
+}
+
+func (s *HubHealthScorer) Start(ctx context.Context) error {
+	s.log.Infow("start scoring the hub health", "interval", s.interval)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.score(ctx); err != nil {
+				s.log.Warnw("failed to score the hub health", "error", err)
+			}
+		}
+	}
+}
+
+// NeedLeaderElection makes sure only the leader updates the health samples and conditions
+func (s *HubHealthScorer) NeedLeaderElection() bool {
+	return true
+}
+
+func (s *HubHealthScorer) score(ctx context.Context) error {
+	db := database.GetGorm().WithContext(ctx)
+	var heartbeats []models.LeafHubHeartbeat
+	if err := db.Where("status = ?", hubActive).Find(&heartbeats).Error; err != nil {
+		return fmt.Errorf("failed to list the heartbeats of the active hubs: %w", err)
+	}
+
+	samples := s.statistics.CollectHubHealthSamples()
+	now := time.Now()
+	for _, heartbeat := range heartbeats {
+		health := scoreHubHealth(heartbeat.Name, now.Sub(heartbeat.LastUpdateAt), samples[heartbeat.Name])
+		hubHealthScoreGaugeVec.WithLabelValues(health.name).Set(float64(health.score))
+
+		if err := db.Create(&models.LeafHubHealth{
+			LeafHubName:             health.name,
+			Score:                   health.score,
+			Healthy:                 health.healthy(),
+			HeartbeatLatencySeconds: health.heartbeatLatency.Seconds(),
+			EventLagSeconds:         health.sample.MaxEventLag.Seconds(),
+			QueueBacklog:            health.sample.QueueBacklog,
+			HandledEvents:           health.sample.HandledEvents,
+			FailedEvents:            health.sample.FailedEvents,
+			CreatedAt:               now,
+		}).Error; err != nil {
+			return fmt.Errorf("failed to store the health sample of the hub %s: %w", health.name, err)
+		}
+
+		alerts, err := s.evaluateBurnRates(ctx, health.name, now)
+		if err != nil {
+			return err
+		}
+		if err := s.updateCondition(ctx, health, alerts); err != nil {
+			s.log.Warnw("failed to update the healthy condition", "hub", health.name, "error", err)
+		}
+	}
+	return nil
+}
+
+// evaluateBurnRates exports the burn rates of the windows, and returns the messages of the firing alerts.
+func (s *HubHealthScorer) evaluateBurnRates(ctx context.Context, hubName string, now time.Time) ([]string, error) {
+	alerts := []string{}
+	for _, window := range burnRateWindows {
+		var result struct {
+			Unhealthy int64
+			Total     int64
+		}
+		err := database.GetGorm().WithContext(ctx).Model(&models.LeafHubHealth{}).
+			Select("COUNT(*) FILTER (WHERE NOT healthy) AS unhealthy, COUNT(*) AS total").
+			Where("leaf_hub_name = ? AND created_at > ?", hubName, now.Add(-window.duration)).
+			Scan(&result).Error
+		if err != nil {
+			return nil, fmt.Errorf("failed to count the health samples of the hub %s: %w", hubName, err)
+		}
+
+		rate := burnRate(result.Unhealthy, result.Total)
+		hubSLOBurnRateGaugeVec.WithLabelValues(hubName, window.name).Set(rate)
+		firing := rate >= window.threshold
+		if firing {
+			hubSLOAlertGaugeVec.WithLabelValues(hubName, window.severity).Set(1)
+			alerts = append(alerts, fmt.Sprintf("the error budget is burning %.1fx in the last %s", rate, window.name))
+		} else {
+			hubSLOAlertGaugeVec.WithLabelValues(hubName, window.severity).Set(0)
+		}
+	}
+	return alerts, nil
+}
+
+func (s *HubHealthScorer) updateCondition(ctx context.Context, health *hubHealth, alerts []string) error {
+	condition := metav1.Condition{
+		Type:   ConditionTypeHubHealthy,
+		Status: metav1.ConditionTrue,
+		Reason: ReasonHubHealthy,
+		Message: fmt.Sprintf("The health SLO of the hub is met, the health score is %d when it's evaluated",
+			health.score),
+	}
+	if len(alerts) > 0 {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = ReasonSLOBurnRateHigh
+		condition.Message = fmt.Sprintf("The health SLO of the hub is at risk: %s, the health score is %d when "+
+			"it's evaluated", alerts[0], health.score)
+	}
+	if s.reasons[health.name] == condition.Reason {
+		return nil
+	}
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cluster := &clusterv1.ManagedCluster{}
+		if err := s.client.Get(ctx, types.NamespacedName{Name: health.name}, cluster); err != nil {
+			if errors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		existing := meta.FindStatusCondition(cluster.Status.Conditions, condition.Type)
+		if existing != nil && existing.Status == condition.Status && existing.Reason == condition.Reason {
+			return nil
+		}
+		condition.LastTransitionTime = metav1.NewTime(time.Now())
+		meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+		s.log.Infow("updating the healthy condition", "hub", health.name, "status", condition.Status,
+			"message", condition.Message)
+		return s.client.Status().Update(ctx, cluster)
+	})
+	if err != nil {
+		return err
+	}
+	s.reasons[health.name] = condition.Reason
+	return nil
+}
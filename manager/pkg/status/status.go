@@ -6,9 +6,11 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/conflator"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/dispatcher"
 	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/handlers"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/status/health"
 	"github.com/stolostron/multicluster-global-hub/pkg/statistics"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 )
@@ -20,6 +22,7 @@ var statusCtrlStarted = false
 func AddStatusSyncers(
 	mgr ctrl.Manager,
 	consumer transport.Consumer,
+	producer transport.Producer,
 	requester transport.Requester,
 	managerConfig *configs.ManagerConfig,
 ) error {
@@ -33,7 +36,8 @@ func AddStatusSyncers(
 	}
 
 	// manage all Conflation Units and handlers
-	conflationManager := conflator.NewConflationManager(stats, requester)
+	statistics.RegisterHubMetrics()
+	conflationManager := conflator.NewConflationManager(stats, requester, managerConfig.ReadyQueueConfig)
 	handlers.RegisterHandlers(mgr, conflationManager, managerConfig.EnableGlobalResource)
 
 	// start consume message from transport to conflation manager
@@ -41,8 +45,16 @@ func AddStatusSyncers(
 		return err
 	}
 
+	// keep the events failed to be persisted, and replay them on request
+	deadLetterQueue, err := dispatcher.AddDeadLetterQueue(mgr, producer, managerConfig.DeadLetterTopic,
+		conflationManager)
+	if err != nil {
+		return err
+	}
+
 	// start persist event from conflation manager to database with registered handlers
-	if err := dispatcher.AddConflationDispatcher(mgr, conflationManager, managerConfig, stats); err != nil {
+	if err := dispatcher.AddConflationDispatcher(mgr, conflationManager, managerConfig, stats,
+		deadLetterQueue.Handle, hubmanagement.CommitResync); err != nil {
 		return err
 	}
 
@@ -51,6 +63,10 @@ func AddStatusSyncers(
 	if err := mgr.Add(committer); err != nil {
 		return fmt.Errorf("failed to start the offset committer: %w", err)
 	}
+	// score the health of the hubs by the signals of the status path, and track the SLO burn rates
+	if err := health.AddHubHealthScorer(mgr, stats); err != nil {
+		return fmt.Errorf("failed to start the hub health scorer: %w", err)
+	}
 	statusCtrlStarted = true
 	return nil
 }
@@ -99,6 +99,38 @@ type ManagedClusterMigrationSpec struct {
 	// +optional
 	// +operator-sdk:csv:customresourcedefinitions:type=spec
 	SupportedConfigs *ConfigMeta `json:"supportedConfigs,omitempty"`
+
+	// DryRun previews the migration without any side effects. The clusters are validated, and the initializing and
+	// deploying are simulated against the target hub, then the migration is completed with the dry run report.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	DryRun bool `json:"dryRun,omitempty"`
+
+	// Strategy splits the managed clusters into waves, the waves are migrated one by one, and a failed wave is rolled
+	// back without affecting the completed waves. All the clusters are migrated at once if it isn't specified.
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	Strategy *MigrationStrategy `json:"strategy,omitempty"`
+}
+
+// MigrationStrategy defines how the managed clusters are migrated in waves
+type MigrationStrategy struct {
+	// BatchSize is the number of the managed clusters migrated in each wave
+	// +kubebuilder:validation:Minimum=1
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	BatchSize int `json:"batchSize"`
+
+	// MaxConcurrency is the maximum number of the managed clusters registering into the target hub at the same time
+	// in a wave, it defaults to the batch size
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	MaxConcurrency int `json:"maxConcurrency,omitempty"`
+
+	// PauseBetweenWaves is the duration to wait after a wave is completed before starting the next wave
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=spec
+	PauseBetweenWaves *metav1.Duration `json:"pauseBetweenWaves,omitempty"`
 }
 
 // ManagedClusterMigrationStatus defines the observed state of managedclustermigration
@@ -111,6 +143,70 @@ type ManagedClusterMigrationStatus struct {
 	// Conditions represents the latest available observations of the current state
 	// +operator-sdk:csv:customresourcedefinitions:type=status
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// DryRunReport is the preview of the migration, it's only reported when the dryRun is enabled
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	DryRunReport *DryRunReport `json:"dryRunReport,omitempty"`
+
+	// CurrentWave is the index of the wave in progress
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	CurrentWave int `json:"currentWave,omitempty"`
+
+	// Waves are the status of the waves, they're only reported when the strategy is specified
+	// +optional
+	// +operator-sdk:csv:customresourcedefinitions:type=status
+	Waves []MigrationWaveStatus `json:"waves,omitempty"`
+}
+
+// MigrationWaveStatus is the observed state of a wave
+type MigrationWaveStatus struct {
+	// Phase is Pending before the wave is started, then it follows the phase of the migration until the wave is
+	// Completed or Failed
+	Phase string `json:"phase"`
+
+	// Clusters are the managed clusters migrated in the wave
+	Clusters []string `json:"clusters"`
+
+	// RegisteredClusters are the managed clusters of the wave which have been registered into the target hub
+	// +optional
+	RegisteredClusters []string `json:"registeredClusters,omitempty"`
+
+	// StartTime is the time when the wave is started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time when the wave is completed or failed
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// DryRunReport describes the changes the migration would make in the target hub
+type DryRunReport struct {
+	// Resources are the resources that would be copied from the source hub to the target hub, grouped by the
+	// managed clusters
+	// +optional
+	Resources []DryRunClusterResources `json:"resources,omitempty"`
+
+	// RBAC is the rbac resources that would be created or updated in the target hub for the cluster registration,
+	// in the format of "<create|update> <kind>/<name>"
+	// +optional
+	RBAC []string `json:"rbac,omitempty"`
+
+	// Conflicts are the problems found in the target hub, e.g. the resources already exist in the target hub
+	// +optional
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// DryRunClusterResources is the resources of the managed cluster that would be copied to the target hub
+type DryRunClusterResources struct {
+	// ClusterName is the name of the managed cluster
+	ClusterName string `json:"clusterName"`
+
+	// Resources are in the format of "<kind>/<namespace>/<name>"
+	// +optional
+	Resources []string `json:"resources,omitempty"`
 }
 
 // +kubebuilder:object:root=true
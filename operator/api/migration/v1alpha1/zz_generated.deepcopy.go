@@ -45,6 +45,58 @@ func (in *ConfigMeta) DeepCopy() *ConfigMeta {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunClusterResources) DeepCopyInto(out *DryRunClusterResources) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunClusterResources.
+func (in *DryRunClusterResources) DeepCopy() *DryRunClusterResources {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunClusterResources)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunReport) DeepCopyInto(out *DryRunReport) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = make([]DryRunClusterResources, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.RBAC != nil {
+		in, out := &in.RBAC, &out.RBAC
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conflicts != nil {
+		in, out := &in.Conflicts, &out.Conflicts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunReport.
+func (in *DryRunReport) DeepCopy() *DryRunReport {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunReport)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagedClusterMigration) DeepCopyInto(out *ManagedClusterMigration) {
 	*out = *in
@@ -117,6 +169,11 @@ func (in *ManagedClusterMigrationSpec) DeepCopyInto(out *ManagedClusterMigration
 		*out = new(ConfigMeta)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(MigrationStrategy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterMigrationSpec.
@@ -139,6 +196,18 @@ func (in *ManagedClusterMigrationStatus) DeepCopyInto(out *ManagedClusterMigrati
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.DryRunReport != nil {
+		in, out := &in.DryRunReport, &out.DryRunReport
+		*out = new(DryRunReport)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]MigrationWaveStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedClusterMigrationStatus.
@@ -150,3 +219,56 @@ func (in *ManagedClusterMigrationStatus) DeepCopy() *ManagedClusterMigrationStat
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationStrategy) DeepCopyInto(out *MigrationStrategy) {
+	*out = *in
+	if in.PauseBetweenWaves != nil {
+		in, out := &in.PauseBetweenWaves, &out.PauseBetweenWaves
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationStrategy.
+func (in *MigrationStrategy) DeepCopy() *MigrationStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MigrationWaveStatus) DeepCopyInto(out *MigrationWaveStatus) {
+	*out = *in
+	if in.Clusters != nil {
+		in, out := &in.Clusters, &out.Clusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RegisteredClusters != nil {
+		in, out := &in.RegisteredClusters, &out.RegisteredClusters
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MigrationWaveStatus.
+func (in *MigrationWaveStatus) DeepCopy() *MigrationWaveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MigrationWaveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -200,11 +200,40 @@ type PostgresSpec struct {
 	// +kubebuilder:default:="18m"
 	Retention string `json:"retention,omitempty"`
 
+	// RetentionPolicies override the retention of the tables, the event types or the hubs, e.g. keep the
+	// history.local_compliance for 24 months and the event.managed_clusters for 1 month.
+	// The data in the scope of multiple policies follows the policy of the hub, then the event type.
+	// +optional
+	RetentionPolicies []RetentionPolicy `json:"retentionPolicies,omitempty"`
+
 	// StorageSize specifies the size for storage
 	// +optional
 	StorageSize string `json:"storageSize,omitempty"`
 }
 
+// RetentionPolicy specifies how long to keep the data of a table, the events of a type, or the data of a hub.
+// At least one of the table, the event type and the hub is required.
+type RetentionPolicy struct {
+	// Table is the partitioned table, e.g. event.managed_clusters, or the table of the soft deleted records,
+	// e.g. status.managed_clusters. The policy applies to all the tables if it's empty.
+	// +optional
+	Table string `json:"table,omitempty"`
+	// EventType is the reason of the events in the event tables, e.g. PolicyStatusSync
+	// +optional
+	EventType string `json:"eventType,omitempty"`
+	// Hub is the name of the managed hub, it overrides the retention of the data of the hub
+	// +optional
+	Hub string `json:"hub,omitempty"`
+	// Retention is a duration string like "1y6m", "4w" or "30d", the valid time units are "y", "m", "w" and "d"
+	// +kubebuilder:validation:Pattern=`^([0-9]+y)?([0-9]+m)?([0-9]+w)?([0-9]+d)?$`
+	Retention string `json:"retention"`
+	// PartitionGranularity is the time range of the partitions of the table, it only applies to the policy of a
+	// partitioned table without the event type and the hub. The default value is monthly.
+	// +kubebuilder:validation:Enum=daily;weekly;monthly
+	// +optional
+	PartitionGranularity string `json:"partitionGranularity,omitempty"`
+}
+
 // KafkaSpec defines the desired state of kafka
 type KafkaSpec struct {
 	// KafkaTopics specify the desired topics
@@ -253,6 +282,14 @@ type MulticlusterGlobalHubStatus struct {
 	// +kubebuilder:default:="Progressing"
 	// +optional
 	Phase GlobalHubPhaseType `json:"phase"`
+
+	// CertificateRotation reports the rotation of the transport client CA and the client certificate of each hub
+	// +optional
+	CertificateRotation *CertificateRotationStatus `json:"certificateRotation,omitempty"`
+
+	// DatabaseReplicas reports the health of the read replicas of the database
+	// +optional
+	DatabaseReplicas []DatabaseReplicaStatus `json:"databaseReplicas,omitempty"`
 }
 type GlobalHubPhaseType string
 
@@ -263,6 +300,82 @@ const (
 	GlobalHubError        GlobalHubPhaseType = "Error"
 )
 
+// CertificateRotationPhase is the phase of the transport client CA rotation
+type CertificateRotationPhase string
+
+const (
+	// CertificateRotationIdle means only the current CA is trusted
+	CertificateRotationIdle CertificateRotationPhase = "Idle"
+	// CertificateRotationRotating means both the current and the retiring CAs are trusted, until all the clients are
+	// reconnected with the certificates issued by the current CA
+	CertificateRotationRotating CertificateRotationPhase = "Rotating"
+)
+
+// CertificateRotationStatus contains the rotation status of the transport client CA
+type CertificateRotationStatus struct {
+	// Phase is the rotation phase of the transport client CA
+	// +optional
+	Phase CertificateRotationPhase `json:"phase,omitempty"`
+
+	// CurrentCA is the key id of the CA issuing the client certificates
+	// +optional
+	CurrentCA string `json:"currentCA,omitempty"`
+
+	// CurrentCAExpiration is the expiration time of the current CA
+	// +optional
+	CurrentCAExpiration *metav1.Time `json:"currentCAExpiration,omitempty"`
+
+	// NextRotationTime is the time when the current CA will be rotated
+	// +optional
+	NextRotationTime *metav1.Time `json:"nextRotationTime,omitempty"`
+
+	// LastRotationTime is the time when the CA was rotated last time
+	// +optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+
+	// RetiringCAs are the key ids of the old CAs, which are still trusted during the rotation
+	// +optional
+	RetiringCAs []string `json:"retiringCAs,omitempty"`
+
+	// Clients list the client certificate status of the global hub manager and the managed hubs
+	// +optional
+	Clients []ClientCertificateStatus `json:"clients,omitempty"`
+}
+
+// ClientCertificateStatus contains the certificate status of a transport client
+type ClientCertificateStatus struct {
+	// Name is the managed hub name, or the global hub manager
+	Name string `json:"name"`
+
+	// Issuer is the key id of the CA issued the certificate which the client is connected with
+	// +optional
+	Issuer string `json:"issuer,omitempty"`
+
+	// Rotated is true if the client is connected with the certificate issued by the current CA
+	Rotated bool `json:"rotated"`
+}
+
+// DatabaseReplicaStatus contains the health of a read replica of the database
+type DatabaseReplicaStatus struct {
+	// Host is the address of the replica
+	Host string `json:"host"`
+
+	// Healthy is true if the replica is reachable and its replication lag is within the max lag
+	Healthy bool `json:"healthy"`
+
+	// Lag is the replication lag of the replica
+	// +optional
+	Lag *metav1.Duration `json:"lag,omitempty"`
+
+	// Message is the reason why the replica is unhealthy
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// LastProbeTime is the time when the replica was checked last time
+	// +optional
+	LastProbeTime *metav1.Time `json:"lastProbeTime,omitempty"`
+}
+
 // StatusCondition contains condition information.
 type StatusCondition struct {
 	// The component name
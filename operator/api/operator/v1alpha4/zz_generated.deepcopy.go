@@ -68,6 +68,58 @@ func (in *AdvancedSpec) DeepCopy() *AdvancedSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateRotationStatus) DeepCopyInto(out *CertificateRotationStatus) {
+	*out = *in
+	if in.CurrentCAExpiration != nil {
+		in, out := &in.CurrentCAExpiration, &out.CurrentCAExpiration
+		*out = (*in).DeepCopy()
+	}
+	if in.NextRotationTime != nil {
+		in, out := &in.NextRotationTime, &out.NextRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	if in.RetiringCAs != nil {
+		in, out := &in.RetiringCAs, &out.RetiringCAs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Clients != nil {
+		in, out := &in.Clients, &out.Clients
+		*out = make([]ClientCertificateStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateRotationStatus.
+func (in *CertificateRotationStatus) DeepCopy() *CertificateRotationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateRotationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClientCertificateStatus) DeepCopyInto(out *ClientCertificateStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClientCertificateStatus.
+func (in *ClientCertificateStatus) DeepCopy() *ClientCertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClientCertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommonSpec) DeepCopyInto(out *CommonSpec) {
 	*out = *in
@@ -92,7 +144,7 @@ func (in *CommonSpec) DeepCopy() *CommonSpec {
 func (in *DataLayerSpec) DeepCopyInto(out *DataLayerSpec) {
 	*out = *in
 	out.Kafka = in.Kafka
-	out.Postgres = in.Postgres
+	in.Postgres.DeepCopyInto(&out.Postgres)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DataLayerSpec.
@@ -105,6 +157,30 @@ func (in *DataLayerSpec) DeepCopy() *DataLayerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseReplicaStatus) DeepCopyInto(out *DatabaseReplicaStatus) {
+	*out = *in
+	if in.Lag != nil {
+		in, out := &in.Lag, &out.Lag
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.LastProbeTime != nil {
+		in, out := &in.LastProbeTime, &out.LastProbeTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseReplicaStatus.
+func (in *DatabaseReplicaStatus) DeepCopy() *DatabaseReplicaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseReplicaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FeatureGate) DeepCopyInto(out *FeatureGate) {
 	*out = *in
@@ -227,7 +303,7 @@ func (in *MulticlusterGlobalHubSpec) DeepCopyInto(out *MulticlusterGlobalHubSpec
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.DataLayerSpec = in.DataLayerSpec
+	in.DataLayerSpec.DeepCopyInto(&out.DataLayerSpec)
 	if in.AdvancedSpec != nil {
 		in, out := &in.AdvancedSpec, &out.AdvancedSpec
 		*out = new(AdvancedSpec)
@@ -262,6 +338,18 @@ func (in *MulticlusterGlobalHubStatus) DeepCopyInto(out *MulticlusterGlobalHubSt
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.CertificateRotation != nil {
+		in, out := &in.CertificateRotation, &out.CertificateRotation
+		*out = new(CertificateRotationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DatabaseReplicas != nil {
+		in, out := &in.DatabaseReplicas, &out.DatabaseReplicas
+		*out = make([]DatabaseReplicaStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MulticlusterGlobalHubStatus.
@@ -277,6 +365,11 @@ func (in *MulticlusterGlobalHubStatus) DeepCopy() *MulticlusterGlobalHubStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PostgresSpec) DeepCopyInto(out *PostgresSpec) {
 	*out = *in
+	if in.RetentionPolicies != nil {
+		in, out := &in.RetentionPolicies, &out.RetentionPolicies
+		*out = make([]RetentionPolicy, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresSpec.
@@ -289,6 +382,21 @@ func (in *PostgresSpec) DeepCopy() *PostgresSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetentionPolicy) DeepCopyInto(out *RetentionPolicy) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetentionPolicy.
+func (in *RetentionPolicy) DeepCopy() *RetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StatusCondition) DeepCopyInto(out *StatusCondition) {
 	*out = *in
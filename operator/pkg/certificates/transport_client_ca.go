@@ -0,0 +1,391 @@
+// Copyright (c) Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+// Licensed under the Apache License 2.0
+
+package certificates
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// The transport client CA is the strimzi custom clients CA, which issues the certificates of the kafka users and the
+// agents. The old CA certificates are kept as "ca-<keyID>.crt" in the cert secret, so that the kafka brokers trust
+// both the old and new CA until the old one is retired.
+const (
+	transportClientCACertificateCN = "global-hub-transport-clients-ca"
+	clientCAKeyName                = "ca.key"
+	retiringCACertPrefix           = "ca-"
+	retiringCACertSuffix           = ".crt"
+
+	strimziKindLabel                  = "strimzi.io/kind"
+	strimziClusterLabel               = "strimzi.io/cluster"
+	strimziCACertGenerationAnnotation = "strimzi.io/ca-cert-generation"
+	strimziCAKeyGenerationAnnotation  = "strimzi.io/ca-key-generation"
+
+	// pendingCAKeyName keeps the key of the new CA in the key secret until the cert secret is switched to it
+	pendingCAKeyName = "ca-pending.key"
+)
+
+// TransportClientCA describes the current and the retiring transport client CAs
+type TransportClientCA struct {
+	KeyID    string
+	NotAfter time.Time
+	// RetiringKeyIDs are the key ids of the old CAs, which are still trusted by the kafka brokers
+	RetiringKeyIDs []string
+	// RetiringNotAfter is the latest expiration of the retiring CAs
+	RetiringNotAfter time.Time
+}
+
+// TransportClientCASecretNames returns the names of the secrets holding the key and the certificate of the clients CA
+func TransportClientCASecretNames(kafkaClusterName string) (string, string) {
+	return fmt.Sprintf("%s-clients-ca", kafkaClusterName), fmt.Sprintf("%s-clients-ca-cert", kafkaClusterName)
+}
+
+// EnsureTransportClientCA creates the clients CA of the kafka cluster if it doesn't exist. The CA generated by the
+// strimzi operator before is adopted, so that the issued certificates are still valid.
+func EnsureTransportClientCA(ctx context.Context, c client.Client, scheme *runtime.Scheme,
+	mgh *v1alpha4.MulticlusterGlobalHub, kafkaClusterName string, lifetime time.Duration,
+) error {
+	keySecretName, certSecretName := TransportClientCASecretNames(kafkaClusterName)
+	keySecret := &corev1.Secret{}
+	keyErr := c.Get(ctx, types.NamespacedName{Namespace: mgh.Namespace, Name: keySecretName}, keySecret)
+	if keyErr != nil && !errors.IsNotFound(keyErr) {
+		return keyErr
+	}
+	certSecret := &corev1.Secret{}
+	certErr := c.Get(ctx, types.NamespacedName{Namespace: mgh.Namespace, Name: certSecretName}, certSecret)
+	if certErr != nil && !errors.IsNotFound(certErr) {
+		return certErr
+	}
+
+	if keyErr == nil && certErr == nil {
+		if err := ensureClientCALabels(ctx, c, keySecret, kafkaClusterName); err != nil {
+			return err
+		}
+		return ensureClientCALabels(ctx, c, certSecret, kafkaClusterName)
+	}
+	if keyErr == nil || certErr == nil {
+		return fmt.Errorf("the clients CA is incomplete, both the secrets %s and %s are required",
+			keySecretName, certSecretName)
+	}
+
+	keyPEM, certPEM, err := newTransportClientCA(lifetime)
+	if err != nil {
+		return err
+	}
+	keySecret = newClientCASecret(mgh.Namespace, keySecretName, kafkaClusterName, strimziCAKeyGenerationAnnotation,
+		map[string][]byte{clientCAKeyName: keyPEM})
+	certSecret = newClientCASecret(mgh.Namespace, certSecretName, kafkaClusterName, strimziCACertGenerationAnnotation,
+		map[string][]byte{caCertName: certPEM})
+	for _, secret := range []*corev1.Secret{certSecret, keySecret} {
+		if err := controllerutil.SetControllerReference(mgh, secret, scheme); err != nil {
+			return err
+		}
+		if err := c.Create(ctx, secret); err != nil {
+			return err
+		}
+	}
+	log.Info("transport clients CA created", "name", certSecretName)
+	return nil
+}
+
+func newClientCASecret(namespace, name, kafkaClusterName, generationAnnotation string,
+	data map[string][]byte,
+) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				strimziKindLabel:    "Kafka",
+				strimziClusterLabel: kafkaClusterName,
+				constants.BackupKey: constants.BackupGlobalHubValue,
+			},
+			Annotations: map[string]string{
+				generationAnnotation: "0",
+			},
+		},
+		Data: data,
+	}
+}
+
+func ensureClientCALabels(ctx context.Context, c client.Client, secret *corev1.Secret,
+	kafkaClusterName string,
+) error {
+	labels := secret.GetLabels()
+	if labels[strimziKindLabel] == "Kafka" && labels[strimziClusterLabel] == kafkaClusterName &&
+		labels[constants.BackupKey] == constants.BackupGlobalHubValue {
+		return nil
+	}
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[strimziKindLabel] = "Kafka"
+	labels[strimziClusterLabel] = kafkaClusterName
+	labels[constants.BackupKey] = constants.BackupGlobalHubValue
+	secret.SetLabels(labels)
+	return c.Update(ctx, secret)
+}
+
+// newTransportClientCA returns the PKCS#8 encoded key and the certificate of a new clients CA
+func newTransportClientCA(lifetime time.Duration) ([]byte, []byte, error) {
+	sn, err := rand.Int(rand.Reader, serialNumberLimit)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now()
+	ca := &x509.Certificate{
+		SerialNumber: sn,
+		Subject: pkix.Name{
+			Organization: []string{"Red Hat, Inc."},
+			Country:      []string{"US"},
+			CommonName:   transportClientCACertificateCN,
+		},
+		NotBefore:             now,
+		NotAfter:              now.Add(lifetime),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, nil, err
+	}
+	caBytes, err := x509.CreateCertificate(rand.Reader, ca, ca, &caKey.PublicKey, caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyBytes, err := x509.MarshalPKCS8PrivateKey(caKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyBytes}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caBytes}), nil
+}
+
+// GetTransportClientCA returns the current and the retiring clients CAs
+func GetTransportClientCA(ctx context.Context, c client.Client, namespace, kafkaClusterName string,
+) (*TransportClientCA, error) {
+	_, certSecretName := TransportClientCASecretNames(kafkaClusterName)
+	certSecret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: certSecretName}, certSecret); err != nil {
+		return nil, err
+	}
+	return parseTransportClientCA(certSecret)
+}
+
+func parseTransportClientCA(certSecret *corev1.Secret) (*TransportClientCA, error) {
+	caCert, err := utils.ParseCertificate(certSecret.Data[caCertName])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the clients CA %s: %w", certSecret.Name, err)
+	}
+	keyID, err := utils.CertificateKeyID(certSecret.Data[caCertName])
+	if err != nil {
+		return nil, err
+	}
+	clientCA := &TransportClientCA{KeyID: keyID, NotAfter: caCert.NotAfter}
+
+	for _, key := range retiringCACertKeys(certSecret) {
+		retiringCert, err := utils.ParseCertificate(certSecret.Data[key])
+		if err != nil {
+			log.Error(err, "failed to parse the retiring clients CA", "key", key)
+			continue
+		}
+		clientCA.RetiringKeyIDs = append(clientCA.RetiringKeyIDs,
+			strings.TrimSuffix(strings.TrimPrefix(key, retiringCACertPrefix), retiringCACertSuffix))
+		if retiringCert.NotAfter.After(clientCA.RetiringNotAfter) {
+			clientCA.RetiringNotAfter = retiringCert.NotAfter
+		}
+	}
+	return clientCA, nil
+}
+
+// retiringCACertKeys returns the sorted keys of the old CA certificates in the cert secret
+func retiringCACertKeys(certSecret *corev1.Secret) []string {
+	keys := []string{}
+	for key := range certSecret.Data {
+		if key != caCertName && strings.HasPrefix(key, retiringCACertPrefix) &&
+			strings.HasSuffix(key, retiringCACertSuffix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// RotateTransportClientCA replaces the clients CA with a new one, and keeps the old certificate trusted until it's
+// retired. Following the strimzi custom CA renewal, the cert secret is updated before the key secret. The new key is
+// kept in the key secret as pending before the cert secret is updated, so the rotation is completed by the
+// CompleteTransportClientCARotation if it's interrupted before the key is switched.
+func RotateTransportClientCA(ctx context.Context, c client.Client, namespace, kafkaClusterName string,
+	lifetime time.Duration,
+) (*TransportClientCA, error) {
+	keySecretName, certSecretName := TransportClientCASecretNames(kafkaClusterName)
+	keySecret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: keySecretName}, keySecret); err != nil {
+		return nil, err
+	}
+	certSecret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: certSecretName}, certSecret); err != nil {
+		return nil, err
+	}
+	oldKeyID, err := utils.CertificateKeyID(certSecret.Data[caCertName])
+	if err != nil {
+		return nil, err
+	}
+
+	keyPEM, certPEM, err := newTransportClientCA(lifetime)
+	if err != nil {
+		return nil, err
+	}
+
+	keySecret.Data[pendingCAKeyName] = keyPEM
+	if err := c.Update(ctx, keySecret); err != nil {
+		return nil, err
+	}
+
+	certSecret.Data[retiringCACertPrefix+oldKeyID+retiringCACertSuffix] = certSecret.Data[caCertName]
+	certSecret.Data[caCertName] = certPEM
+	if err := increaseGeneration(certSecret, strimziCACertGenerationAnnotation); err != nil {
+		return nil, err
+	}
+	if err := c.Update(ctx, certSecret); err != nil {
+		return nil, err
+	}
+
+	if _, err := completeRotation(ctx, c, keySecret, certSecret); err != nil {
+		return nil, err
+	}
+	log.Info("transport clients CA rotated", "name", certSecretName, "retiring", oldKeyID)
+	return parseTransportClientCA(certSecret)
+}
+
+// CompleteTransportClientCARotation switches the key secret to the pending key if the cert secret is switched to it,
+// e.g. the rotation is interrupted by the failure of updating the key secret, it returns true if the rotation is
+// completed by it. Then it checks the key matches the certificate of the clients CA, so the mismatched key isn't used
+// to sign the client certificates, and the old CA isn't retired.
+func CompleteTransportClientCARotation(ctx context.Context, c client.Client, namespace, kafkaClusterName string,
+) (bool, error) {
+	keySecretName, certSecretName := TransportClientCASecretNames(kafkaClusterName)
+	keySecret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: keySecretName}, keySecret); err != nil {
+		return false, err
+	}
+	certSecret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: certSecretName}, certSecret); err != nil {
+		return false, err
+	}
+	completed, err := completeRotation(ctx, c, keySecret, certSecret)
+	if err != nil {
+		return false, err
+	}
+	matched, err := utils.KeyMatchesCertificate(keySecret.Data[clientCAKeyName], certSecret.Data[caCertName])
+	if err != nil {
+		return false, fmt.Errorf("failed to check the key of the clients CA %s: %w", certSecretName, err)
+	}
+	if !matched {
+		return false, fmt.Errorf("the key of the clients CA %s doesn't match the certificate", certSecretName)
+	}
+	return completed, nil
+}
+
+// completeRotation moves the pending key to the current key if the certificate is issued by it, otherwise the cert
+// secret isn't updated by the rotation, so the pending key is dropped, and the CA is rotated again later.
+func completeRotation(ctx context.Context, c client.Client, keySecret, certSecret *corev1.Secret) (bool, error) {
+	pendingKey, found := keySecret.Data[pendingCAKeyName]
+	if !found {
+		return false, nil
+	}
+	matched, err := utils.KeyMatchesCertificate(pendingKey, certSecret.Data[caCertName])
+	if err != nil {
+		return false, fmt.Errorf("failed to check the pending key of the clients CA %s: %w", keySecret.Name, err)
+	}
+	if matched {
+		keySecret.Data[clientCAKeyName] = pendingKey
+		if err := increaseGeneration(keySecret, strimziCAKeyGenerationAnnotation); err != nil {
+			return false, err
+		}
+	} else {
+		log.Info("drop the pending key of the clients CA, the certificate isn't rotated", "name", keySecret.Name)
+	}
+	delete(keySecret.Data, pendingCAKeyName)
+	if err := c.Update(ctx, keySecret); err != nil {
+		return false, err
+	}
+	return matched, nil
+}
+
+// RetireTransportClientCA removes the old CA certificates, the clients with the certificates issued by them can't
+// connect to the kafka cluster any more
+func RetireTransportClientCA(ctx context.Context, c client.Client, namespace, kafkaClusterName string) error {
+	keySecretName, certSecretName := TransportClientCASecretNames(kafkaClusterName)
+	certSecret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: certSecretName}, certSecret); err != nil {
+		return err
+	}
+	keys := retiringCACertKeys(certSecret)
+	if len(keys) == 0 {
+		return nil
+	}
+	// the old CA might be the only one matching the key if the rotation isn't completed
+	keySecret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: keySecretName}, keySecret); err != nil {
+		return err
+	}
+	matched, err := utils.KeyMatchesCertificate(keySecret.Data[clientCAKeyName], certSecret.Data[caCertName])
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return fmt.Errorf("the key of the clients CA %s doesn't match the certificate, skip retiring the old CA",
+			certSecretName)
+	}
+	for _, key := range keys {
+		delete(certSecret.Data, key)
+	}
+	if err := increaseGeneration(certSecret, strimziCACertGenerationAnnotation); err != nil {
+		return err
+	}
+	if err := c.Update(ctx, certSecret); err != nil {
+		return err
+	}
+	log.Info("transport clients CA retired", "name", certSecretName, "retired", keys)
+	return nil
+}
+
+func increaseGeneration(secret *corev1.Secret, annotation string) error {
+	generation := 0
+	if val, ok := secret.Annotations[annotation]; ok {
+		var err error
+		if generation, err = strconv.Atoi(val); err != nil {
+			return fmt.Errorf("invalid %s of the secret %s: %w", annotation, secret.Name, err)
+		}
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[annotation] = strconv.Itoa(generation + 1)
+	return nil
+}
@@ -0,0 +1,142 @@
+// Copyright (c) Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+// Licensed under the Apache License 2.0
+
+package certificates
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+func TestTransportClientCARotation(t *testing.T) {
+	ctx := context.Background()
+	mgh := getMGH()
+	s := scheme.Scheme
+	_ = v1alpha4.SchemeBuilder.AddToScheme(s)
+	c := fake.NewClientBuilder().WithScheme(s).Build()
+
+	keySecretName, certSecretName := TransportClientCASecretNames("kafka")
+	require.NoError(t, EnsureTransportClientCA(ctx, c, s, mgh, "kafka", time.Hour))
+	// rerun won't recreate the CA
+	caBefore, err := GetTransportClientCA(ctx, c, mgh.Namespace, "kafka")
+	require.NoError(t, err)
+	require.NoError(t, EnsureTransportClientCA(ctx, c, s, mgh, "kafka", time.Hour))
+	ca, err := GetTransportClientCA(ctx, c, mgh.Namespace, "kafka")
+	require.NoError(t, err)
+	assert.Equal(t, caBefore.KeyID, ca.KeyID)
+	assert.Empty(t, ca.RetiringKeyIDs)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), ca.NotAfter, time.Minute)
+
+	keySecret := &corev1.Secret{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: mgh.Namespace, Name: keySecretName}, keySecret))
+	assert.Equal(t, "kafka", keySecret.Labels[strimziClusterLabel])
+	assert.Equal(t, "0", keySecret.Annotations[strimziCAKeyGenerationAnnotation])
+
+	// the old CA is still trusted after the rotation
+	rotated, err := RotateTransportClientCA(ctx, c, mgh.Namespace, "kafka", 2*time.Hour)
+	require.NoError(t, err)
+	assert.NotEqual(t, ca.KeyID, rotated.KeyID)
+	assert.Equal(t, []string{ca.KeyID}, rotated.RetiringKeyIDs)
+	assert.WithinDuration(t, ca.NotAfter, rotated.RetiringNotAfter, time.Second)
+
+	certSecret := &corev1.Secret{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: mgh.Namespace, Name: certSecretName}, certSecret))
+	assert.Equal(t, "1", certSecret.Annotations[strimziCACertGenerationAnnotation])
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: mgh.Namespace, Name: keySecretName}, keySecret))
+	assert.Equal(t, "1", keySecret.Annotations[strimziCAKeyGenerationAnnotation])
+	keyID, err := utils.CertificateKeyID(certSecret.Data[caCertName])
+	require.NoError(t, err)
+	assert.Equal(t, rotated.KeyID, keyID)
+
+	// the new key matches the new CA certificate
+	block, _ := pem.Decode(keySecret.Data[clientCAKeyName])
+	require.NotNil(t, block)
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	require.NoError(t, err)
+	caCert, err := utils.ParseCertificate(certSecret.Data[caCertName])
+	require.NoError(t, err)
+	assert.True(t, key.(*rsa.PrivateKey).PublicKey.Equal(caCert.PublicKey))
+
+	require.NoError(t, RetireTransportClientCA(ctx, c, mgh.Namespace, "kafka"))
+	retired, err := GetTransportClientCA(ctx, c, mgh.Namespace, "kafka")
+	require.NoError(t, err)
+	assert.Equal(t, rotated.KeyID, retired.KeyID)
+	assert.Empty(t, retired.RetiringKeyIDs)
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: mgh.Namespace, Name: certSecretName}, certSecret))
+	assert.Equal(t, "2", certSecret.Annotations[strimziCACertGenerationAnnotation])
+
+	// nothing to retire
+	require.NoError(t, RetireTransportClientCA(ctx, c, mgh.Namespace, "kafka"))
+}
+
+func TestInterruptedTransportClientCARotation(t *testing.T) {
+	ctx := context.Background()
+	mgh := getMGH()
+	s := scheme.Scheme
+	_ = v1alpha4.SchemeBuilder.AddToScheme(s)
+
+	keySecretName, _ := TransportClientCASecretNames("kafka")
+	// fail the update of the key secret after the cert secret is switched to the new CA
+	certUpdated, failKeyUpdate := false, true
+	c := fake.NewClientBuilder().WithScheme(s).WithInterceptorFuncs(interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			if obj.GetName() != keySecretName {
+				certUpdated = true
+			} else if certUpdated && failKeyUpdate {
+				return errors.New("failed to update the key secret")
+			}
+			return c.Update(ctx, obj, opts...)
+		},
+	}).Build()
+
+	require.NoError(t, EnsureTransportClientCA(ctx, c, s, mgh, "kafka", time.Hour))
+	ca, err := GetTransportClientCA(ctx, c, mgh.Namespace, "kafka")
+	require.NoError(t, err)
+
+	_, err = RotateTransportClientCA(ctx, c, mgh.Namespace, "kafka", 2*time.Hour)
+	require.Error(t, err)
+
+	// the key doesn't match the new CA, so the old CA isn't retired
+	_, err = CompleteTransportClientCARotation(ctx, c, mgh.Namespace, "kafka")
+	require.Error(t, err)
+	require.Error(t, RetireTransportClientCA(ctx, c, mgh.Namespace, "kafka"))
+
+	// the rotation is completed by the pending key on the next reconcile
+	failKeyUpdate = false
+	completed, err := CompleteTransportClientCARotation(ctx, c, mgh.Namespace, "kafka")
+	require.NoError(t, err)
+	assert.True(t, completed)
+	rotated, err := GetTransportClientCA(ctx, c, mgh.Namespace, "kafka")
+	require.NoError(t, err)
+	assert.NotEqual(t, ca.KeyID, rotated.KeyID)
+	assert.Equal(t, []string{ca.KeyID}, rotated.RetiringKeyIDs)
+
+	keySecret := &corev1.Secret{}
+	require.NoError(t, c.Get(ctx, types.NamespacedName{Namespace: mgh.Namespace, Name: keySecretName}, keySecret))
+	assert.NotContains(t, keySecret.Data, pendingCAKeyName)
+	assert.Equal(t, "1", keySecret.Annotations[strimziCAKeyGenerationAnnotation])
+
+	// nothing to complete
+	completed, err = CompleteTransportClientCARotation(ctx, c, mgh.Namespace, "kafka")
+	require.NoError(t, err)
+	assert.False(t, completed)
+	require.NoError(t, RetireTransportClientCA(ctx, c, mgh.Namespace, "kafka"))
+}
@@ -127,6 +127,7 @@ type ManifestsConfig struct {
 	AggregationLevel        string
 	EnableLocalPolicies     string
 	EventSendMode           string
+	EventRules              string
 }
 
 type Resources struct {
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+)
+
+const (
+	DefaultCALifetime   = 5 * 365 * 24 * time.Hour
+	DefaultCertLifetime = 365 * 24 * time.Hour
+	// the default renew-before is 1/5 of the lifetime
+	defaultRenewBeforeRatio = 5
+)
+
+var (
+	certRotationPolicy     = defaultCertRotationPolicy()
+	certRotationPolicyLock sync.RWMutex
+)
+
+// CertRotationPolicy specifies the lifetimes and the renew-before thresholds of the transport client CA and the
+// client certificates issued by it
+type CertRotationPolicy struct {
+	CALifetime      time.Duration
+	CARenewBefore   time.Duration
+	CertLifetime    time.Duration
+	CertRenewBefore time.Duration
+}
+
+func defaultCertRotationPolicy() *CertRotationPolicy {
+	return &CertRotationPolicy{
+		CALifetime:      DefaultCALifetime,
+		CARenewBefore:   DefaultCALifetime / defaultRenewBeforeRatio,
+		CertLifetime:    DefaultCertLifetime,
+		CertRenewBefore: DefaultCertLifetime / defaultRenewBeforeRatio,
+	}
+}
+
+// GetCertRotationPolicy parses the rotation policy from the annotations of the mgh, the unset values are defaulted
+func GetCertRotationPolicy(mgh *v1alpha4.MulticlusterGlobalHub) (*CertRotationPolicy, error) {
+	policy := defaultCertRotationPolicy()
+
+	var err error
+	if policy.CALifetime, err = parseDurationAnnotation(mgh, operatorconstants.AnnotationCertCALifetime,
+		DefaultCALifetime); err != nil {
+		return nil, err
+	}
+	if policy.CARenewBefore, err = parseDurationAnnotation(mgh, operatorconstants.AnnotationCertCARenewBefore,
+		policy.CALifetime/defaultRenewBeforeRatio); err != nil {
+		return nil, err
+	}
+	if policy.CertLifetime, err = parseDurationAnnotation(mgh, operatorconstants.AnnotationCertLifetime,
+		DefaultCertLifetime); err != nil {
+		return nil, err
+	}
+	if policy.CertRenewBefore, err = parseDurationAnnotation(mgh, operatorconstants.AnnotationCertRenewBefore,
+		policy.CertLifetime/defaultRenewBeforeRatio); err != nil {
+		return nil, err
+	}
+
+	if policy.CARenewBefore >= policy.CALifetime {
+		return nil, fmt.Errorf("the CA renew-before %s must be less than the CA lifetime %s",
+			policy.CARenewBefore, policy.CALifetime)
+	}
+	if policy.CertRenewBefore >= policy.CertLifetime {
+		return nil, fmt.Errorf("the certificate renew-before %s must be less than the certificate lifetime %s",
+			policy.CertRenewBefore, policy.CertLifetime)
+	}
+	// the client certificates can't outlive the CA issuing them
+	if policy.CertLifetime > policy.CALifetime {
+		return nil, fmt.Errorf("the certificate lifetime %s must not be greater than the CA lifetime %s",
+			policy.CertLifetime, policy.CALifetime)
+	}
+	return policy, nil
+}
+
+func parseDurationAnnotation(mgh *v1alpha4.MulticlusterGlobalHub, key string, defaultVal time.Duration,
+) (time.Duration, error) {
+	val := getAnnotation(mgh, key)
+	if val == "" {
+		return defaultVal, nil
+	}
+	duration, err := time.ParseDuration(val)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the annotation %s: %w", key, err)
+	}
+	if duration <= 0 {
+		return 0, fmt.Errorf("the annotation %s must be positive, got %s", key, val)
+	}
+	return duration, nil
+}
+
+// SetCertRotationPolicy caches the rotation policy, which is used when signing the transport client certificates
+func SetCertRotationPolicy(policy *CertRotationPolicy) {
+	certRotationPolicyLock.Lock()
+	defer certRotationPolicyLock.Unlock()
+	certRotationPolicy = policy
+}
+
+func GetCachedCertRotationPolicy() *CertRotationPolicy {
+	certRotationPolicyLock.RLock()
+	defer certRotationPolicyLock.RUnlock()
+	return certRotationPolicy
+}
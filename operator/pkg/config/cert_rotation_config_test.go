@@ -0,0 +1,76 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+)
+
+func TestGetCertRotationPolicy(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        *CertRotationPolicy
+		wantErr     bool
+	}{
+		{
+			name: "default",
+			want: defaultCertRotationPolicy(),
+		},
+		{
+			name: "renew before defaults to 1/5 of the lifetime",
+			annotations: map[string]string{
+				operatorconstants.AnnotationCertCALifetime: "100h",
+				operatorconstants.AnnotationCertLifetime:   "10h",
+			},
+			want: &CertRotationPolicy{
+				CALifetime:      100 * time.Hour,
+				CARenewBefore:   20 * time.Hour,
+				CertLifetime:    10 * time.Hour,
+				CertRenewBefore: 2 * time.Hour,
+			},
+		},
+		{
+			name: "invalid duration",
+			annotations: map[string]string{
+				operatorconstants.AnnotationCertCALifetime: "5y",
+			},
+			wantErr: true,
+		},
+		{
+			name: "renew before the lifetime",
+			annotations: map[string]string{
+				operatorconstants.AnnotationCertLifetime:    "10h",
+				operatorconstants.AnnotationCertRenewBefore: "10h",
+			},
+			wantErr: true,
+		},
+		{
+			name: "certificate outlives the CA",
+			annotations: map[string]string{
+				operatorconstants.AnnotationCertCALifetime: "10h",
+				operatorconstants.AnnotationCertLifetime:   "20h",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &v1alpha4.MulticlusterGlobalHub{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+			}
+			got, err := GetCertRotationPolicy(mgh)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -20,9 +20,11 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/base64"
+	"encoding/json"
 	"fmt"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -240,11 +242,53 @@ func GetEventSendMode(obj client.Object) string {
 	return eventSendMode
 }
 
+// GetAgentEventRules returns the event rules of the agents from the annotations, it's quoted as a json string, which is
+// also a valid yaml scalar, so the multi-line rules can be rendered into the agent configmap. It's empty if the
+// annotation isn't specified, the rules are validated by the agent.
+func GetAgentEventRules(obj client.Object) string {
+	eventRules := getAnnotation(obj, operatorconstants.AnnotationAgentEventRules)
+	if eventRules == "" {
+		return ""
+	}
+	quoted, err := json.Marshal(eventRules)
+	if err != nil {
+		log.Errorw("failed to quote the event rules", "error", err)
+		return ""
+	}
+	return string(quoted)
+}
+
 // GetSchedulerInterval returns the scheduler interval for moving policy compliance history
 func GetSchedulerInterval(mgh *v1alpha4.MulticlusterGlobalHub) string {
 	return getAnnotation(mgh, operatorconstants.AnnotationMGHSchedulerInterval)
 }
 
+// GetComplianceSnapshotInterval returns the interval of the local compliance snapshots, it's empty if the annotation
+// isn't specified or invalid, then the manager uses the default interval.
+func GetComplianceSnapshotInterval(mgh *v1alpha4.MulticlusterGlobalHub) string {
+	text := getAnnotation(mgh, operatorconstants.AnnotationComplianceSnapshotInterval)
+	if text == "" {
+		return ""
+	}
+	if value, err := time.ParseDuration(text); err != nil || value <= 0 {
+		log.Errorf("Failed to parse value '%s' of annotation '%s', will ignore it: %v",
+			text, operatorconstants.AnnotationComplianceSnapshotInterval, err)
+		return ""
+	}
+	return text
+}
+
+// GetFailoverRole returns the failover role of the global hub, "active" or "standby", or empty if the failover is
+// disabled
+func GetFailoverRole(mgh *v1alpha4.MulticlusterGlobalHub) string {
+	return getAnnotation(mgh, constants.GHFailoverRoleAnnotation)
+}
+
+// IsStandby returns true if the global hub is the standby one, which doesn't deploy the agents until it's promoted
+func IsStandby(mgh *v1alpha4.MulticlusterGlobalHub) bool {
+	return GetFailoverRole(mgh) == constants.FailoverRoleStandby
+}
+
 // SkipAuth returns true to skip authenticate for non-k8s api
 func SkipAuth(mgh *v1alpha4.MulticlusterGlobalHub) bool {
 	toSkipAuth := getAnnotation(mgh, operatorconstants.AnnotationMGHSkipAuth)
@@ -344,6 +388,20 @@ func GetPostgresStorageSize(mgh *v1alpha4.MulticlusterGlobalHub) string {
 	return GHPostgresDefaultStorageSize
 }
 
+// GetPostgresReplicas returns the number of the read replicas of the built-in postgres
+func GetPostgresReplicas(mgh *v1alpha4.MulticlusterGlobalHub) (int32, error) {
+	val := getAnnotation(mgh, operatorconstants.AnnotationPostgresReplicas)
+	if val == "" {
+		return 0, nil
+	}
+	replicas, err := strconv.ParseInt(val, 10, 32)
+	if err != nil || replicas < 0 {
+		return 0, fmt.Errorf("the annotation %s must be a non-negative integer: %s",
+			operatorconstants.AnnotationPostgresReplicas, val)
+	}
+	return int32(replicas), nil
+}
+
 func SetImagePullSecretName(mgh *v1alpha4.MulticlusterGlobalHub) {
 	if mgh.Spec.ImagePullSecret != imagePullSecretName {
 		imagePullSecretName = mgh.Spec.ImagePullSecret
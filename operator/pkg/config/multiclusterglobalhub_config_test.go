@@ -27,6 +27,7 @@ import (
 	fakeimagev1client "github.com/openshift/client-go/image/clientset/versioned/typed/image/v1/fake"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
 
 	globalhubv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
 	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
@@ -249,6 +250,52 @@ func TestStackRoxPoolParsing(t *testing.T) {
 	}
 }
 
+func TestComplianceSnapshotIntervalParsing(t *testing.T) {
+	for annotationValue, expectedValue := range map[string]string{
+		"12h":    "12h",
+		"broken": "",
+		"-1h":    "",
+		"":       "",
+	} {
+		mghInstance := &globalhubv1alpha4.MulticlusterGlobalHub{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{
+					operatorconstants.AnnotationComplianceSnapshotInterval: annotationValue,
+				},
+			},
+		}
+		actualValue := GetComplianceSnapshotInterval(mghInstance)
+		if actualValue != expectedValue {
+			t.Fatalf("expected snapshot interval from annotation '%s' to be '%s', but it is '%s'",
+				annotationValue, expectedValue, actualValue)
+		}
+	}
+}
+
+func TestAgentEventRules(t *testing.T) {
+	eventRules := "- name: drop-probe-noise\n  reasons: [\"ProbeSucceeded\"]\n  action: drop\n"
+	mghInstance := &globalhubv1alpha4.MulticlusterGlobalHub{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{
+				operatorconstants.AnnotationAgentEventRules: eventRules,
+			},
+		},
+	}
+	// the quoted rules are rendered into the configmap, and they're unquoted as the yaml scalar
+	quoted := GetAgentEventRules(mghInstance)
+	data := map[string]string{}
+	if err := yaml.Unmarshal([]byte("eventRules: "+quoted), &data); err != nil {
+		t.Fatalf("failed to unmarshal the rendered event rules: %v", err)
+	}
+	if data["eventRules"] != eventRules {
+		t.Fatalf("expected the event rules '%s', but got '%s'", eventRules, data["eventRules"])
+	}
+
+	if actualValue := GetAgentEventRules(&globalhubv1alpha4.MulticlusterGlobalHub{}); actualValue != "" {
+		t.Fatalf("expected the event rules to be empty, but it is '%s'", actualValue)
+	}
+}
+
 func TestStackRoxPoolNotPresent(t *testing.T) {
 	mghInstance := &globalhubv1alpha4.MulticlusterGlobalHub{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,36 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/pkg/retention"
+)
+
+// GetRetentionPolicies validates the retention policies of the mgh and returns them in json for the manager, it
+// returns an empty string if there is no policy
+func GetRetentionPolicies(mgh *v1alpha4.MulticlusterGlobalHub) (string, error) {
+	specPolicies := mgh.Spec.DataLayerSpec.Postgres.RetentionPolicies
+	if len(specPolicies) == 0 {
+		return "", nil
+	}
+	policies := make([]retention.Policy, 0, len(specPolicies))
+	for _, p := range specPolicies {
+		policies = append(policies, retention.Policy{
+			Table:                p.Table,
+			EventType:            p.EventType,
+			Hub:                  p.Hub,
+			Retention:            p.Retention,
+			PartitionGranularity: retention.Granularity(p.PartitionGranularity),
+		})
+	}
+	if err := retention.Validate(policies); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(policies)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal the retention policies: %w", err)
+	}
+	return string(data), nil
+}
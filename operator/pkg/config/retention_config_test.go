@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+)
+
+func TestGetRetentionPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies []v1alpha4.RetentionPolicy
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "no policy",
+			want: "",
+		},
+		{
+			name: "table and hub policies",
+			policies: []v1alpha4.RetentionPolicy{
+				{Table: "event.managed_clusters", Retention: "1m", PartitionGranularity: "weekly"},
+				{Hub: "hub1", Retention: "6m"},
+			},
+			want: `[{"table":"event.managed_clusters","retention":"1m","partitionGranularity":"weekly"},` +
+				`{"hub":"hub1","retention":"6m"}]`,
+		},
+		{
+			name: "unknown table",
+			policies: []v1alpha4.RetentionPolicy{
+				{Table: "status.transport", Retention: "1m"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &v1alpha4.MulticlusterGlobalHub{}
+			mgh.Spec.DataLayerSpec.Postgres.RetentionPolicies = tt.policies
+			got, err := GetRetentionPolicies(mgh)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// SchemaMigrationOptions specifies how the database schema is migrated
+type SchemaMigrationOptions struct {
+	TargetVersion int
+	DryRun        bool
+}
+
+// GetSchemaMigrationOptions parses the migration options from the annotations of the mgh, the target version is
+// defaulted to the schema version expected by the manager
+func GetSchemaMigrationOptions(mgh *v1alpha4.MulticlusterGlobalHub) (SchemaMigrationOptions, error) {
+	options := SchemaMigrationOptions{
+		TargetVersion: database.SchemaVersion,
+		DryRun:        strings.EqualFold(getAnnotation(mgh, operatorconstants.AnnotationSchemaMigrationDryRun), "true"),
+	}
+
+	val := getAnnotation(mgh, operatorconstants.AnnotationSchemaVersion)
+	if val == "" {
+		return options, nil
+	}
+	target, err := strconv.Atoi(val)
+	if err != nil {
+		return options, fmt.Errorf("failed to parse the annotation %s: %w",
+			operatorconstants.AnnotationSchemaVersion, err)
+	}
+	if target < 0 || target > database.SchemaVersion {
+		return options, fmt.Errorf("the annotation %s must be in the range [0, %d]",
+			operatorconstants.AnnotationSchemaVersion, database.SchemaVersion)
+	}
+	options.TargetVersion = target
+	return options, nil
+}
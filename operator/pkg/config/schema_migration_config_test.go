@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+func TestGetSchemaMigrationOptions(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        SchemaMigrationOptions
+		wantErr     bool
+	}{
+		{
+			name: "default",
+			want: SchemaMigrationOptions{TargetVersion: database.SchemaVersion},
+		},
+		{
+			name: "dry run the rollback",
+			annotations: map[string]string{
+				operatorconstants.AnnotationSchemaVersion:         "1",
+				operatorconstants.AnnotationSchemaMigrationDryRun: "True",
+			},
+			want: SchemaMigrationOptions{TargetVersion: 1, DryRun: true},
+		},
+		{
+			name: "invalid version",
+			annotations: map[string]string{
+				operatorconstants.AnnotationSchemaVersion: "v1",
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown version",
+			annotations: map[string]string{
+				operatorconstants.AnnotationSchemaVersion: "100",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mgh := &v1alpha4.MulticlusterGlobalHub{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+			}
+			got, err := GetSchemaMigrationOptions(mgh)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
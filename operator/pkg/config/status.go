@@ -78,6 +78,14 @@ const (
 	CONDITION_REASON_RETENTION_PARSED_FAILED = "DataRetentionParsedFailed"
 )
 
+// NOTE: the status of the database schema migration
+const (
+	CONDITION_TYPE_SCHEMA_MIGRATED           = "SchemaMigrated"
+	CONDITION_REASON_SCHEMA_MIGRATED         = "SchemaMigrated"
+	CONDITION_REASON_SCHEMA_MIGRATION_FAILED = "SchemaMigrationFailed"
+	CONDITION_REASON_SCHEMA_MIGRATION_DRYRUN = "SchemaMigrationDryRun"
+)
+
 // NOTE: the status of ManagerDeployed can only be True; otherwise there is no condition
 const (
 	MINIMUM_REPLICAS_AVAILABLE          = "MinimumReplicasAvailable"
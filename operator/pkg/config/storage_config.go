@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -41,6 +42,9 @@ type PostgresConnection struct {
 	ReadonlyUserDatabaseURI string
 	// ca certificate
 	CACert []byte
+	// the read replicas connection with the super user
+	// they're used for the read-only queries of the manager
+	ReplicaDatabaseURIs []string
 }
 
 // SetPostgresType assert the current storage is BYO or built-in, and cache the state to memeory
@@ -93,9 +97,21 @@ func GetPGConnectionFromGHStorageSecret(ctx context.Context, client client.Clien
 		SuperuserDatabaseURI:    string(pgSecret.Data["database_uri"]),
 		ReadonlyUserDatabaseURI: string(pgSecret.Data["database_uri_with_readonlyuser"]),
 		CACert:                  pgSecret.Data["ca.crt"],
+		ReplicaDatabaseURIs:     splitDatabaseURIs(string(pgSecret.Data["database_replica_uris"])),
 	}, nil
 }
 
+// splitDatabaseURIs splits the comma separated database uris
+func splitDatabaseURIs(uris string) []string {
+	var result []string
+	for _, uri := range strings.Split(uris, ",") {
+		if uri = strings.TrimSpace(uri); uri != "" {
+			result = append(result, uri)
+		}
+	}
+	return result
+}
+
 func GetPGConnectionFromBuildInPostgres(ctx context.Context, client client.Client) (
 	*PostgresConnection, error,
 ) {
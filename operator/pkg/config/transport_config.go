@@ -16,6 +16,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
 
 const (
@@ -191,6 +192,20 @@ func GetKafkaClientCA() ([]byte, []byte) {
 	return kafkaClientCAKey, kafkaClientCACert
 }
 
+// GetKafkaClientCAKeyID returns the subject key id of the current kafka client CA, the client certificates issued by
+// the other CAs are re-issued during the rotation
+func GetKafkaClientCAKeyID() string {
+	if kafkaClientCACert == nil {
+		return ""
+	}
+	keyID, err := utils.CertificateKeyID(kafkaClientCACert)
+	if err != nil {
+		log.Warnf("failed to get the key id of the kafka client CA: %v", err)
+		return ""
+	}
+	return keyID
+}
+
 func GetInventoryClientCA() ([]byte, []byte) {
 	return inventoryClientCAKey, inventoryClientCACert
 }
@@ -229,11 +244,6 @@ func SetKafkaClientCA(ctx context.Context, namespace, name string, c client.Clie
 	if err != nil {
 		return err
 	}
-	if kafkaClientCAKey == nil || !bytes.Equal(clientCAKeySecret.Data["ca.key"], kafkaClientCAKey) {
-		log.Infof("set the ca - client key: %s", clientCAKeySecret.Name)
-		kafkaClientCAKey = clientCAKeySecret.Data["ca.key"]
-	}
-
 	clientCACertSecret := &corev1.Secret{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      fmt.Sprintf("%s-clients-ca-cert", name),
@@ -245,6 +255,21 @@ func SetKafkaClientCA(ctx context.Context, namespace, name string, c client.Clie
 		return err
 	}
 
+	// the key and the certificate are mismatched while the rotation of the clients CA isn't completed, keep signing
+	// by the previous ones until it's completed by the cert rotation controller
+	matched, err := utils.KeyMatchesCertificate(clientCAKeySecret.Data["ca.key"], clientCACertSecret.Data["ca.crt"])
+	if err != nil {
+		return fmt.Errorf("failed to check the clients CA %s: %w", clientCACertSecret.Name, err)
+	}
+	if !matched {
+		return fmt.Errorf("the key of the clients CA %s doesn't match the certificate", clientCACertSecret.Name)
+	}
+
+	if kafkaClientCAKey == nil || !bytes.Equal(clientCAKeySecret.Data["ca.key"], kafkaClientCAKey) {
+		log.Infof("set the ca - client key: %s", clientCAKeySecret.Name)
+		kafkaClientCAKey = clientCAKeySecret.Data["ca.key"]
+	}
+
 	if kafkaClientCACert == nil || !bytes.Equal(clientCACertSecret.Data["ca.crt"], kafkaClientCACert) {
 		log.Infof("set the ca - client cert: %s", clientCACertSecret.Name)
 		kafkaClientCACert = clientCACertSecret.Data["ca.crt"]
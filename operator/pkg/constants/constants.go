@@ -76,6 +76,32 @@ const (
 	// controller-runtime's For() method does not trigger reconciliation for status-only updates.
 	// The value is a timestamp in RFC3339 format indicating when the transport connection was last updated.
 	AnnotationMGHTransportUpdate = "global-hub.open-cluster-management.io/transport-update"
+	// AnnotationCertCALifetime specifies the lifetime of the CA issuing the transport client certificates of the
+	// built-in kafka, the value is parsed with the time.ParseDuration, e.g. "43800h". The default is 5 years.
+	AnnotationCertCALifetime = "global-hub.open-cluster-management.io/cert-ca-lifetime"
+	// AnnotationCertCARenewBefore specifies how long before the expiration the CA is rotated. The default is 1/5 of
+	// the CA lifetime.
+	AnnotationCertCARenewBefore = "global-hub.open-cluster-management.io/cert-ca-renew-before"
+	// AnnotationCertLifetime specifies the lifetime of the transport client certificates of the manager and agents.
+	// The default is 1 year.
+	AnnotationCertLifetime = "global-hub.open-cluster-management.io/cert-lifetime"
+	// AnnotationCertRenewBefore specifies how long before the expiration the client certificates of the kafka users
+	// are renewed. The default is 1/5 of the certificate lifetime.
+	AnnotationCertRenewBefore = "global-hub.open-cluster-management.io/cert-renew-before"
+	// AnnotationSchemaVersion specifies the target version of the database schema, the migrations above it are
+	// reverted. The default is the latest version expected by the manager.
+	AnnotationSchemaVersion = "global-hub.open-cluster-management.io/schema-version"
+	// AnnotationSchemaMigrationDryRun only reports the pending migrations of the database schema without running them
+	AnnotationSchemaMigrationDryRun = "global-hub.open-cluster-management.io/schema-migration-dry-run"
+	// AnnotationPostgresReplicas specifies the number of the read replicas of the built-in postgres, the replicas
+	// serve the read-only queries of the manager. The default is 0.
+	AnnotationPostgresReplicas = "global-hub.open-cluster-management.io/postgres-replicas"
+	// AnnotationComplianceSnapshotInterval specifies the interval of the snapshots of the local compliance taken by
+	// the manager, the value is parsed with the time.ParseDuration, e.g. "12h". The default is 24 hours.
+	AnnotationComplianceSnapshotInterval = "global-hub.open-cluster-management.io/compliance-snapshot-interval"
+	// AnnotationAgentEventRules specifies the filter and redaction rules of the kube events in yaml, they're rendered
+	// into the configmap of the agents, so they aren't reverted by the addon.
+	AnnotationAgentEventRules = "global-hub.open-cluster-management.io/agent-event-rules"
 )
 
 // hub installation constants
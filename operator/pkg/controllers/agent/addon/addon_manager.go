@@ -144,7 +144,7 @@ func newRegistrationOption() *addonframeworkagent.RegistrationOption {
 		},
 		CSRSign: func(csr *certificatesv1.CertificateSigningRequest) []byte {
 			key, cert := config.GetKafkaClientCA()
-			return agentcert.Sign(csr, key, cert)
+			return agentcert.Sign(csr, key, cert, config.GetCachedCertRotationPolicy().CertLifetime)
 		},
 	}
 }
@@ -128,6 +128,21 @@ func StartDefaultAgentController(initOption config.ControllerOption) (config.Con
 					return e.Object.GetName() == constants.GHManagedClusterAddonName
 				},
 			})).
+		// deploy the agents once the standby global hub is promoted, the transport of the agents is switched to it
+		Watches(&v1alpha4.MulticlusterGlobalHub{},
+			handler.EnqueueRequestsFromMapFunc(defaultAgentController.allClusterRequests),
+			builder.WithPredicates(predicate.Funcs{
+				CreateFunc: func(e event.CreateEvent) bool {
+					return false
+				},
+				UpdateFunc: func(e event.UpdateEvent) bool {
+					return e.ObjectNew.GetAnnotations()[constants.GHFailoverRoleAnnotation] !=
+						e.ObjectOld.GetAnnotations()[constants.GHFailoverRoleAnnotation]
+				},
+				DeleteFunc: func(e event.DeleteEvent) bool {
+					return false
+				},
+			})).
 		// secondary watch for managedclusteraddon
 		Watches(&addonv1alpha1.ClusterManagementAddOn{},
 			handler.EnqueueRequestsFromMapFunc(defaultAgentController.allClusterRequests),
@@ -216,6 +231,11 @@ func (r *DefaultAgentController) Reconcile(ctx context.Context, req ctrl.Request
 	}
 
 	config.SetGlobalhubAgentRemoved(false)
+	// the agents keep connecting to the active global hub until the standby one is promoted
+	if config.IsStandby(mgh) {
+		log.Debug("skip deploying the agents by the standby global hub")
+		return ctrl.Result{}, nil
+	}
 	if config.GetTransporter() == nil {
 		log.Debug("wait transporter ready")
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
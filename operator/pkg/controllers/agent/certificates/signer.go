@@ -18,7 +18,10 @@ import (
 )
 
 // default: https://github.com/open-cluster-management-io/addon-framework/blob/main/pkg/utils/csr_helpers.go#L65
-func Sign(csr *certificatesv1.CertificateSigningRequest, clientCaKey, clientCaCert []byte) []byte {
+// the certificate expires after the certExpiryDuration, but no later than the client CA
+func Sign(csr *certificatesv1.CertificateSigningRequest, clientCaKey, clientCaCert []byte,
+	certExpiryDuration time.Duration,
+) []byte {
 	caKey, caCert, err := parseClientCA(clientCaKey, clientCaCert)
 	if err != nil {
 		log.Infof("The singer checks CSR(%s), not get client CA: %v", csr.Name, err)
@@ -30,7 +33,6 @@ func Sign(csr *certificatesv1.CertificateSigningRequest, clientCaKey, clientCaCe
 		usages = append(usages, string(usage))
 	}
 
-	certExpiryDuration := 365 * 24 * time.Hour
 	durationUntilExpiry := time.Until(caCert.NotAfter)
 	if durationUntilExpiry <= 0 {
 		log.Infof("The signer has expired, expired time: %v", caCert.NotAfter)
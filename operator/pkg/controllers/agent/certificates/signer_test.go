@@ -21,7 +21,7 @@ func TestCSRSigner(t *testing.T) {
 
 	csr := newCSR("test", "cluster1")
 	// sign the cert
-	certBytes := Sign(csr, cakey, caCert)
+	certBytes := Sign(csr, cakey, caCert, 24*time.Hour)
 	assert.NotNil(t, certBytes, "expect cert not be nil")
 
 	// parse cert
@@ -31,6 +31,8 @@ func TestCSRSigner(t *testing.T) {
 
 	// validate the CN
 	assert.Equal(t, certs[0].Subject.CommonName, "test", "CN is not correct")
+	// validate the lifetime
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), certs[0].NotAfter, 10*time.Minute)
 }
 
 func generateKeyAndCert() ([]byte, []byte, error) {
@@ -118,6 +118,9 @@ func StartStandaloneAgentController(ctx context.Context, mgr ctrl.Manager) error
 // +kubebuilder:rbac:groups=operator.open-cluster-management.io,resources=multiclusterglobalhubagents/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=operator.open-cluster-management.io,resources=multiclusterglobalhubagents/finalizers,verbs=update
 // +kubebuilder:rbac:groups="config.openshift.io",resources=infrastructures;clusterversions,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
+// +kubebuilder:rbac:groups="addon.open-cluster-management.io",resources=managedclusteraddons,verbs=get;list;watch
+// +kubebuilder:rbac:groups="multicluster.openshift.io",resources=multiclusterengines,verbs=get;list;watch
 // +kubebuilder:rbac:groups="policy.open-cluster-management.io",resources=policyautomations;policysets;placementbindings;policies,verbs=get;list;watch;patch;update
 // +kubebuilder:rbac:groups="cluster.open-cluster-management.io",resources=placements;managedclustersets;managedclustersetbindings,verbs=get;list;watch;patch;update
 // +kubebuilder:rbac:groups="cluster.open-cluster-management.io",resources=managedclusters;managedclusters/finalizers;placementdecisions;placementdecisions/finalizers;placements;placements/finalizers,verbs=get;list;watch;patch;update
@@ -179,6 +182,7 @@ func renderAgentManifests(
 	var enableStackroxIntegration bool
 	var stackroxPollInterval time.Duration
 	var eventSendMode string
+	var eventRules string
 
 	if mgh != nil {
 		namespace = mgh.Namespace
@@ -195,6 +199,7 @@ func renderAgentManifests(
 		enableStackroxIntegration = config.WithStackroxIntegration(mgh)
 		stackroxPollInterval = config.GetStackroxPollInterval(mgh)
 		eventSendMode = config.GetEventSendMode(mgh)
+		eventRules = config.GetAgentEventRules(mgh)
 	}
 	if mgha != nil {
 		namespace = mgha.Namespace
@@ -205,6 +210,7 @@ func renderAgentManifests(
 		tolerations = mgha.Spec.Tolerations
 		owner = mgha
 		eventSendMode = config.GetEventSendMode(mgha)
+		eventRules = config.GetAgentEventRules(mgha)
 	}
 	// create new HoHRenderer and HoHDeployer
 	hohRenderer, hohDeployer := renderer.NewHoHRenderer(fs), deployer.NewHoHDeployer(mgr.GetClient())
@@ -266,6 +272,7 @@ func renderAgentManifests(
 			StackroxPollInterval      time.Duration
 			DeployMode                string
 			EventSendMode             string
+			EventRules                string
 		}{
 			Image:                     config.GetImage(config.GlobalHubAgentImageKey),
 			ImagePullSecret:           imagePullSecret,
@@ -286,6 +293,7 @@ func renderAgentManifests(
 			StackroxPollInterval:      stackroxPollInterval,
 			DeployMode:                deployMode,
 			EventSendMode:             eventSendMode,
+			EventRules:                eventRules,
 		}, nil
 	})
 	if err != nil {
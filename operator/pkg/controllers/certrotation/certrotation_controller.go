@@ -0,0 +1,361 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certrotation
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/certificates"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/transporter/protocol"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+// +kubebuilder:rbac:groups=operator.open-cluster-management.io,resources=multiclusterglobalhubs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch;create;update;delete
+// +kubebuilder:rbac:groups=cluster.open-cluster-management.io,resources=managedclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=addon.open-cluster-management.io,resources=managedclusteraddons,verbs=get;list;watch
+
+const (
+	// ManagerClientName is the client name of the global hub manager in the rotation status
+	ManagerClientName = constants.CloudEventGlobalHubClusterName
+	// rotatingInterval is the interval to check the reconnected clients during the rotation
+	rotatingInterval = time.Minute
+	// the interval to wait for the clients CA to be created by the transporter
+	waitingInterval = 10 * time.Second
+	// maxRequeueInterval makes sure the rotation is checked at least once a day
+	maxRequeueInterval = 24 * time.Hour
+)
+
+var (
+	log                    = logger.DefaultZapLogger()
+	certRotationController *CertRotationController
+)
+
+// CertRotationController rotates the transport client CA of the built-in kafka before it expires. During the rotation,
+// both the old and new CAs are trusted by the kafka cluster, and the old one is retired only if the manager and all the
+// agents are reconnected with the certificates issued by the new CA, or the old CA is expired.
+type CertRotationController struct {
+	c client.Client
+}
+
+func (r *CertRotationController) IsResourceRemoved() bool {
+	return true
+}
+
+func StartController(initOption config.ControllerOption) (config.ControllerInterface, error) {
+	if certRotationController != nil {
+		return certRotationController, nil
+	}
+	// the clients CA is provided by the built-in kafka, and the agents are registered by the ACM addon framework
+	if !config.IsACMResourceReady() || !config.GetKafkaResourceReady() {
+		return nil, nil
+	}
+	log.Info("start cert rotation controller")
+
+	certRotationController = &CertRotationController{c: initOption.Manager.GetClient()}
+	if err := certRotationController.SetupWithManager(initOption.Manager); err != nil {
+		certRotationController = nil
+		return nil, err
+	}
+	log.Info("inited cert rotation controller")
+	return certRotationController, nil
+}
+
+func (r *CertRotationController) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("certRotationController").
+		For(&v1alpha4.MulticlusterGlobalHub{}, builder.WithPredicates(mghPred)).
+		Watches(&clusterv1.ManagedCluster{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(issuerPred)).
+		Watches(&corev1.Secret{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(secretPred)).
+		Watches(&addonv1alpha1.ManagedClusterAddOn{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(addonPred)).
+		Complete(r)
+}
+
+// mghPred watches the rotation policy in the annotations besides the spec
+var mghPred = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return true
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration() ||
+			!equality.Semantic.DeepEqual(e.ObjectNew.GetAnnotations(), e.ObjectOld.GetAnnotations())
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return false
+	},
+}
+
+// issuerPred watches the issuer of the client certificate which the agent is reconnected with
+var issuerPred = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return e.ObjectNew.GetAnnotations()[constants.ClientCertIssuerAnnotation] !=
+			e.ObjectOld.GetAnnotations()[constants.ClientCertIssuerAnnotation]
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return true
+	},
+}
+
+// secretPred watches the transport secret of the manager and the clients CA
+var secretPred = predicate.NewPredicateFuncs(func(object client.Object) bool {
+	_, caCertSecretName := certificates.TransportClientCASecretNames(protocol.KafkaClusterName)
+	return object.GetNamespace() == config.GetMGHNamespacedName().Namespace &&
+		(object.GetName() == constants.GHTransportConfigSecret || object.GetName() == caCertSecretName)
+})
+
+var addonPred = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return e.Object.GetName() == constants.GHManagedClusterAddonName
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		return false
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return e.Object.GetName() == constants.GHManagedClusterAddonName
+	},
+}
+
+func (r *CertRotationController) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	mgh, err := config.GetMulticlusterGlobalHub(ctx, r.c)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if mgh == nil || config.IsPaused(mgh) || mgh.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+	// the standby doesn't serve the agents, and the BYO kafka manages the client certificates itself
+	if config.IsStandby(mgh) || config.IsBYOKafka() || config.GetTransporter() == nil {
+		return ctrl.Result{}, nil
+	}
+
+	policy, err := config.GetCertRotationPolicy(mgh)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	config.SetCertRotationPolicy(policy)
+
+	clientCA, err := certificates.GetTransportClientCA(ctx, r.c, mgh.Namespace, protocol.KafkaClusterName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			log.Debug("waiting the transport clients CA to be created")
+			return ctrl.Result{RequeueAfter: waitingInterval}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// complete the rotation interrupted before the key is switched, the old CA isn't retired until the key matches
+	completed, err := certificates.CompleteTransportClientCARotation(ctx, r.c, mgh.Namespace,
+		protocol.KafkaClusterName)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if completed {
+		log.Infow("completed the interrupted rotation of the transport clients CA", "current", clientCA.KeyID)
+		if err := r.distribute(ctx, mgh); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	var lastRotationTime *metav1.Time
+	if mgh.Status.CertificateRotation != nil {
+		lastRotationTime = mgh.Status.CertificateRotation.LastRotationTime
+	}
+
+	now := time.Now()
+	if len(clientCA.RetiringKeyIDs) == 0 && !now.Before(clientCA.NotAfter.Add(-policy.CARenewBefore)) {
+		if clientCA, err = r.rotate(ctx, mgh, policy); err != nil {
+			return ctrl.Result{}, err
+		}
+		// the status keeps the time in seconds
+		lastRotationTime = &metav1.Time{Time: now.Truncate(time.Second)}
+	}
+
+	clients, err := r.listClients(ctx, mgh, clientCA.KeyID)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(clientCA.RetiringKeyIDs) > 0 && (allRotated(clients) || now.After(clientCA.RetiringNotAfter)) {
+		if err := certificates.RetireTransportClientCA(ctx, r.c, mgh.Namespace, protocol.KafkaClusterName); err != nil {
+			return ctrl.Result{}, err
+		}
+		clientCA.RetiringKeyIDs = nil
+	}
+
+	if err := r.updateStatus(ctx, mgh, newRotationStatus(clientCA, policy, clients, lastRotationTime)); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if len(clientCA.RetiringKeyIDs) > 0 {
+		return ctrl.Result{RequeueAfter: rotatingInterval}, nil
+	}
+	requeueAfter := time.Until(clientCA.NotAfter.Add(-policy.CARenewBefore))
+	if requeueAfter > maxRequeueInterval {
+		requeueAfter = maxRequeueInterval
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// rotate replaces the clients CA, then the managed hub addons are re-rendered with the new CA so that the agents
+// re-issue their certificates. The kafka users are re-issued by the strimzi user operator.
+func (r *CertRotationController) rotate(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
+	policy *config.CertRotationPolicy,
+) (*certificates.TransportClientCA, error) {
+	clientCA, err := certificates.RotateTransportClientCA(ctx, r.c, mgh.Namespace, protocol.KafkaClusterName,
+		policy.CALifetime)
+	if err != nil {
+		return nil, err
+	}
+	log.Infow("rotated the transport clients CA", "current", clientCA.KeyID, "retiring", clientCA.RetiringKeyIDs)
+
+	if err := r.distribute(ctx, mgh); err != nil {
+		return nil, err
+	}
+	return clientCA, nil
+}
+
+// distribute loads the rotated clients CA to sign the agent certificates, and re-renders the managed hub addons
+func (r *CertRotationController) distribute(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub) error {
+	if err := config.SetKafkaClientCA(ctx, mgh.Namespace, protocol.KafkaClusterName, r.c); err != nil {
+		return err
+	}
+	if addonManager := config.GetAddonManager(); addonManager != nil {
+		return utils.TriggerManagedHubAddons(ctx, r.c, addonManager)
+	}
+	return nil
+}
+
+// listClients returns the certificate status of the manager, the local agent and the managed hub agents
+func (r *CertRotationController) listClients(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
+	currentCA string,
+) ([]v1alpha4.ClientCertificateStatus, error) {
+	clients := []v1alpha4.ClientCertificateStatus{}
+
+	managerSecret := &corev1.Secret{}
+	err := r.c.Get(ctx, types.NamespacedName{Namespace: mgh.Namespace, Name: constants.GHTransportConfigSecret},
+		managerSecret)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	clients = append(clients, newClientStatus(ManagerClientName,
+		managerSecret.Annotations[constants.ClientCertIssuerAnnotation], currentCA))
+
+	hubs := map[string]bool{}
+	addons := &addonv1alpha1.ManagedClusterAddOnList{}
+	if err := r.c.List(ctx, addons); err != nil {
+		return nil, err
+	}
+	for _, addon := range addons.Items {
+		if addon.Name == constants.GHManagedClusterAddonName {
+			hubs[addon.Namespace] = true
+		}
+	}
+	if mgh.Spec.InstallAgentOnLocal && config.GetLocalClusterName() != "" {
+		hubs[config.GetLocalClusterName()] = true
+	}
+
+	for hubName := range hubs {
+		cluster := &clusterv1.ManagedCluster{}
+		if err := r.c.Get(ctx, types.NamespacedName{Name: hubName}, cluster); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		clients = append(clients, newClientStatus(hubName,
+			cluster.Annotations[constants.ClientCertIssuerAnnotation], currentCA))
+	}
+
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i].Name < clients[j].Name
+	})
+	return clients, nil
+}
+
+func newClientStatus(name, issuer, currentCA string) v1alpha4.ClientCertificateStatus {
+	return v1alpha4.ClientCertificateStatus{Name: name, Issuer: issuer, Rotated: issuer == currentCA}
+}
+
+func allRotated(clients []v1alpha4.ClientCertificateStatus) bool {
+	for _, c := range clients {
+		if !c.Rotated {
+			return false
+		}
+	}
+	return true
+}
+
+func newRotationStatus(clientCA *certificates.TransportClientCA, policy *config.CertRotationPolicy,
+	clients []v1alpha4.ClientCertificateStatus, lastRotationTime *metav1.Time,
+) *v1alpha4.CertificateRotationStatus {
+	status := &v1alpha4.CertificateRotationStatus{
+		Phase:               v1alpha4.CertificateRotationIdle,
+		CurrentCA:           clientCA.KeyID,
+		CurrentCAExpiration: &metav1.Time{Time: clientCA.NotAfter},
+		NextRotationTime:    &metav1.Time{Time: clientCA.NotAfter.Add(-policy.CARenewBefore)},
+		LastRotationTime:    lastRotationTime,
+		RetiringCAs:         clientCA.RetiringKeyIDs,
+		Clients:             clients,
+	}
+	if len(clientCA.RetiringKeyIDs) > 0 {
+		status.Phase = v1alpha4.CertificateRotationRotating
+	}
+	return status
+}
+
+func (r *CertRotationController) updateStatus(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
+	desired *v1alpha4.CertificateRotationStatus,
+) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		curmgh := &v1alpha4.MulticlusterGlobalHub{}
+		if err := r.c.Get(ctx, client.ObjectKeyFromObject(mgh), curmgh); err != nil {
+			return err
+		}
+		if equality.Semantic.DeepEqual(curmgh.Status.CertificateRotation, desired) {
+			return nil
+		}
+		curmgh.Status.CertificateRotation = desired
+		return r.c.Status().Update(ctx, curmgh)
+	})
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package certrotation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	addonv1alpha1 "open-cluster-management.io/api/addon/v1alpha1"
+	clusterv1 "open-cluster-management.io/api/cluster/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/certificates"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/transporter/protocol"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+)
+
+func TestCertRotationController_Reconcile(t *testing.T) {
+	ctx := context.Background()
+	mgh := &v1alpha4.MulticlusterGlobalHub{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "multiclusterglobalhub",
+			Namespace: "multicluster-global-hub",
+			Annotations: map[string]string{
+				operatorconstants.AnnotationCertCALifetime:    "1h",
+				operatorconstants.AnnotationCertCARenewBefore: "40m",
+				operatorconstants.AnnotationCertLifetime:      "30m",
+			},
+		},
+	}
+	managerSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.GHTransportConfigSecret, Namespace: mgh.Namespace},
+	}
+	hub1 := &clusterv1.ManagedCluster{ObjectMeta: metav1.ObjectMeta{Name: "hub1"}}
+	hub1Addon := &addonv1alpha1.ManagedClusterAddOn{
+		ObjectMeta: metav1.ObjectMeta{Name: constants.GHManagedClusterAddonName, Namespace: "hub1"},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(config.GetRuntimeScheme()).
+		WithObjects(mgh, managerSecret, hub1, hub1Addon).
+		WithStatusSubresource(&v1alpha4.MulticlusterGlobalHub{}).Build()
+	config.SetBYOKafka(false)
+	config.SetTransporter(protocol.NewBYOTransporter(ctx, types.NamespacedName{
+		Namespace: mgh.Namespace,
+		Name:      constants.GHTransportSecretName,
+	}, fakeClient))
+	r := &CertRotationController{c: fakeClient}
+
+	// wait for the clients CA
+	result, err := r.Reconcile(ctx, ctrl.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, waitingInterval, result.RequeueAfter)
+
+	// the CA expires in 30m, which is within the renew-before 40m
+	require.NoError(t, certificates.EnsureTransportClientCA(ctx, fakeClient, config.GetRuntimeScheme(), mgh,
+		protocol.KafkaClusterName, 30*time.Minute))
+	oldCA, err := certificates.GetTransportClientCA(ctx, fakeClient, mgh.Namespace, protocol.KafkaClusterName)
+	require.NoError(t, err)
+	setIssuer(t, fakeClient, managerSecret, oldCA.KeyID)
+	setIssuer(t, fakeClient, hub1, oldCA.KeyID)
+
+	result, err = r.Reconcile(ctx, ctrl.Request{})
+	require.NoError(t, err)
+	assert.Equal(t, rotatingInterval, result.RequeueAfter)
+	assert.Equal(t, 30*time.Minute, config.GetCachedCertRotationPolicy().CertLifetime)
+
+	status := getRotationStatus(t, fakeClient, mgh)
+	assert.Equal(t, v1alpha4.CertificateRotationRotating, status.Phase)
+	assert.NotEqual(t, oldCA.KeyID, status.CurrentCA)
+	assert.Equal(t, []string{oldCA.KeyID}, status.RetiringCAs)
+	assert.NotNil(t, status.LastRotationTime)
+	assert.Equal(t, []v1alpha4.ClientCertificateStatus{
+		{Name: ManagerClientName, Issuer: oldCA.KeyID, Rotated: false},
+		{Name: "hub1", Issuer: oldCA.KeyID, Rotated: false},
+	}, status.Clients)
+	newCA := status.CurrentCA
+	assert.Equal(t, newCA, config.GetKafkaClientCAKeyID())
+
+	// keep the old CA until all the clients are reconnected with the new certificates
+	setIssuer(t, fakeClient, managerSecret, newCA)
+	_, err = r.Reconcile(ctx, ctrl.Request{})
+	require.NoError(t, err)
+	status = getRotationStatus(t, fakeClient, mgh)
+	assert.Equal(t, v1alpha4.CertificateRotationRotating, status.Phase)
+	assert.Equal(t, []string{oldCA.KeyID}, status.RetiringCAs)
+
+	setIssuer(t, fakeClient, hub1, newCA)
+	result, err = r.Reconcile(ctx, ctrl.Request{})
+	require.NoError(t, err)
+	status = getRotationStatus(t, fakeClient, mgh)
+	assert.Equal(t, v1alpha4.CertificateRotationIdle, status.Phase)
+	assert.Equal(t, newCA, status.CurrentCA)
+	assert.Empty(t, status.RetiringCAs)
+	assert.True(t, status.Clients[0].Rotated)
+	assert.True(t, status.Clients[1].Rotated)
+	// the next rotation is 20m later
+	assert.InDelta(t, (20 * time.Minute).Seconds(), result.RequeueAfter.Seconds(), 60)
+
+	clientCA, err := certificates.GetTransportClientCA(ctx, fakeClient, mgh.Namespace, protocol.KafkaClusterName)
+	require.NoError(t, err)
+	assert.Equal(t, newCA, clientCA.KeyID)
+	assert.Empty(t, clientCA.RetiringKeyIDs)
+}
+
+func setIssuer(t *testing.T, c client.Client, obj client.Object, issuer string) {
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(obj), obj))
+	obj.SetAnnotations(map[string]string{constants.ClientCertIssuerAnnotation: issuer})
+	require.NoError(t, c.Update(context.Background(), obj))
+}
+
+func getRotationStatus(t *testing.T, c client.Client,
+	mgh *v1alpha4.MulticlusterGlobalHub,
+) *v1alpha4.CertificateRotationStatus {
+	cur := &v1alpha4.MulticlusterGlobalHub{}
+	require.NoError(t, c.Get(context.Background(), client.ObjectKeyFromObject(mgh), cur))
+	require.NotNil(t, cur.Status.CertificateRotation)
+	return cur.Status.CertificateRotation
+}
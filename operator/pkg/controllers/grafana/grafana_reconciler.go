@@ -42,6 +42,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/renderer"
 	operatorutils "github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	commonutils "github.com/stolostron/multicluster-global-hub/pkg/utils"
 )
@@ -653,7 +654,7 @@ func (r *GrafanaReconciler) GenerateGrafanaDataSourceSecret(
 		saToken = string(saSecret.Data["token"])
 	}
 
-	datasourceVal, err := GrafanaDataSource(storageConn.ReadonlyUserDatabaseURI, storageConn.CACert, saToken)
+	datasourceVal, err := GrafanaDataSource(readDatabaseURI(mgh, storageConn), storageConn.CACert, saToken)
 	if err != nil {
 		datasourceVal, err = GrafanaDataSource(storageConn.SuperuserDatabaseURI, storageConn.CACert, saToken)
 		if err != nil {
@@ -703,6 +704,28 @@ func (r *GrafanaReconciler) GenerateGrafanaDataSourceSecret(
 	return false, nil
 }
 
+// readDatabaseURI returns the readonly database uri for the dashboards, it points to a replica reported as healthy in
+// the mgh status, otherwise to the primary database. The readonly user is replicated with the database.
+func readDatabaseURI(mgh *v1alpha4.MulticlusterGlobalHub, storageConn *config.PostgresConnection) string {
+	for _, replica := range mgh.Status.DatabaseReplicas {
+		if !replica.Healthy {
+			continue
+		}
+		for _, replicaURI := range storageConn.ReplicaDatabaseURIs {
+			if database.ReplicaHost(replicaURI) != replica.Host {
+				continue
+			}
+			objURI, err := url.Parse(storageConn.ReadonlyUserDatabaseURI)
+			if err != nil {
+				return storageConn.ReadonlyUserDatabaseURI
+			}
+			objURI.Host = replica.Host
+			return objURI.String()
+		}
+	}
+	return storageConn.ReadonlyUserDatabaseURI
+}
+
 func GrafanaDataSource(databaseURI string, cert []byte, serviceAccountToken string) ([]byte, error) {
 	postgresURI := string(databaseURI)
 	objURI, err := url.Parse(postgresURI)
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	routev1 "github.com/openshift/api/route/v1"
@@ -250,6 +251,11 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 	if months < 1 {
 		months = 1
 	}
+	retentionPolicies, err := config.GetRetentionPolicies(mgh)
+	if err != nil {
+		reconcileErr = fmt.Errorf("failed to parse the retention policies: %v", err)
+		return ctrl.Result{}, reconcileErr
+	}
 
 	replicas := int32(1)
 	if mgh.Spec.AvailabilityConfig == v1alpha4.HAHigh {
@@ -304,11 +310,13 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 			RenewDeadline:             strconv.Itoa(electionConfig.RenewDeadline),
 			RetryPeriod:               strconv.Itoa(electionConfig.RetryPeriod),
 			SchedulerInterval:         config.GetSchedulerInterval(mgh),
+			SnapshotInterval:          config.GetComplianceSnapshotInterval(mgh),
 			SkipAuth:                  config.SkipAuth(mgh),
 			LaunchJobNames:            config.GetLaunchJobNames(mgh),
 			NodeSelector:              mgh.Spec.NodeSelector,
 			Tolerations:               mgh.Spec.Tolerations,
 			RetentionMonth:            months,
+			RetentionPolicies:         retentionPolicies,
 			StatisticLogInterval:      config.GetStatisticLogInterval(),
 			EnableGlobalResource:      r.operatorConfig.GlobalResourceEnabled,
 			EnableInventoryAPI:        config.WithInventory(mgh),
@@ -316,6 +324,12 @@ func (r *ManagerReconciler) Reconcile(ctx context.Context,
 			Resources:                 utils.GetResources(operatorconstants.Manager, mgh.Spec.AdvancedSpec),
 			WithACM:                   config.IsACMResourceReady(),
 			TransportFailureThreshold: r.operatorConfig.TransportFailureThreshold,
+			FailoverRole:              config.GetFailoverRole(mgh),
+			FailoverName:              string(mgh.UID),
+			PrimaryDatabaseSecret:     constants.GHPrimaryDatabaseSecret,
+			FailoverWitnessSecret:     constants.GHFailoverWitnessSecret,
+			ReplicaDatabaseURLs: base64.StdEncoding.EncodeToString(
+				[]byte(strings.Join(storageConn.ReplicaDatabaseURIs, ","))),
 		}, nil
 	})
 	if err != nil {
@@ -458,11 +472,13 @@ type ManagerVariables struct {
 	RenewDeadline             string
 	RetryPeriod               string
 	SchedulerInterval         string
+	SnapshotInterval          string
 	SkipAuth                  bool
 	LaunchJobNames            string
 	NodeSelector              map[string]string
 	Tolerations               []corev1.Toleration
 	RetentionMonth            int
+	RetentionPolicies         string
 	StatisticLogInterval      string
 	EnableGlobalResource      bool
 	EnableInventoryAPI        bool
@@ -470,4 +486,9 @@ type ManagerVariables struct {
 	Resources                 *corev1.ResourceRequirements
 	WithACM                   bool
 	TransportFailureThreshold int
+	FailoverRole              string
+	FailoverName              string
+	PrimaryDatabaseSecret     string
+	FailoverWitnessSecret     string
+	ReplicaDatabaseURLs       string
 }
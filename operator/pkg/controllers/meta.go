@@ -43,6 +43,7 @@ import (
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/agent"
 	addonagent "github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/agent/addon"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/backup"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/certrotation"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/grafana"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/inventory"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/managedhub"
@@ -73,7 +74,9 @@ var controllerStartFuncMap = map[string]Func{
 	"managedhub":       managedhub.StartController,
 	"acm":              acm.StartController,
 	"backup":           backup.StartController,
+	"certRotation":     certrotation.StartController,
 	"postgresUser":     storage.StartPostgresConfigUserController,
+	"databaseReplica":  storage.StartDatabaseReplicaController,
 	"inventory":        inventory.StartInventoryController,
 	"spicedb":          inventory.StartSpiceDBReconciler,
 }
@@ -107,11 +107,30 @@ CREATE TABLE IF NOT EXISTS spec.managed_clusters_labels (
     managed_cluster_name character varying(254) NOT NULL,
     labels jsonb DEFAULT '{}'::jsonb NOT NULL,
     deleted_label_keys jsonb DEFAULT '[]'::jsonb NOT NULL,
+    -- the labels observed on the managed cluster once they were applied, it's the base of the three-way merge
+    last_applied_labels jsonb DEFAULT '{}'::jsonb NOT NULL,
+    -- the conflict policy of the labels: hub-wins or global-wins(default)
+    label_policies jsonb DEFAULT '{}'::jsonb NOT NULL,
     updated_at timestamp without time zone DEFAULT now() NOT NULL,
     version bigint DEFAULT 0 NOT NULL,
     CONSTRAINT managed_clusters_labels_version_check CHECK ((version >= 0))
 );
 
+CREATE TABLE IF NOT EXISTS spec.label_conflicts (
+    leaf_hub_name character varying(254) NOT NULL,
+    managed_cluster_name character varying(254) NOT NULL,
+    label_key text NOT NULL,
+    -- the null value means the label is absent
+    desired_value text,
+    last_applied_value text,
+    live_value text,
+    policy character varying(64) NOT NULL,
+    resolution text NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT label_conflicts_unique_constraint UNIQUE (leaf_hub_name, managed_cluster_name, label_key)
+);
+
 CREATE TABLE IF NOT EXISTS spec.managedclustersetbindings (
     id uuid PRIMARY KEY,
     payload jsonb NOT NULL,
@@ -168,6 +187,16 @@ CREATE TABLE IF NOT EXISTS spec.subscriptions (
     deleted boolean DEFAULT false NOT NULL
 );
 
+-- spec.outbox records every change of the spec tables with a sequence number, the relay of the manager publishes
+-- the changes in order and marks them delivered, so a change isn't lost if the manager restarts before sending it.
+CREATE TABLE IF NOT EXISTS spec.outbox (
+    seq bigserial PRIMARY KEY,
+    table_name character varying(254) NOT NULL,
+    object_id uuid NOT NULL,
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    delivered_at timestamp without time zone
+);
+
 CREATE TABLE IF NOT EXISTS status.aggregated_compliance (
     policy_id uuid NOT NULL,
     leaf_hub_name character varying(254) NOT NULL,
@@ -217,6 +246,8 @@ CREATE TABLE IF NOT EXISTS status.subscription_statuses (
 
 CREATE UNIQUE INDEX IF NOT EXISTS managed_cluster_sets_tracking_cluster_set_name_and_leaf_hub_name_idx ON spec.managed_cluster_sets_tracking (cluster_set_name, leaf_hub_name);
 
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON spec.outbox (seq) WHERE delivered_at IS NULL;
+
 CREATE INDEX IF NOT EXISTS compliance_leaf_hub_cluster_idx ON status.compliance (leaf_hub_name, cluster_name);
 
 CREATE INDEX IF NOT EXISTS compliance_leaf_hub_non_compliant_idx ON status.compliance (leaf_hub_name, compliance) WHERE (compliance <> 'compliant'::status.compliance_type);
@@ -277,3 +277,12 @@ BEGIN
   RETURN NEW;
 END;
 $$;
+
+CREATE OR REPLACE FUNCTION public.record_spec_outbox() RETURNS trigger
+    LANGUAGE plpgsql
+    AS $$
+BEGIN
+  INSERT INTO spec.outbox (table_name, object_id) VALUES (TG_TABLE_NAME, NEW.id);
+  RETURN NEW;
+END;
+$$;
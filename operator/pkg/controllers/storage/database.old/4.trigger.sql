@@ -63,6 +63,25 @@ CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.policies FOR EACH ROW EXECUTE
 DROP TRIGGER IF EXISTS set_timestamp ON spec.subscriptions;
 CREATE TRIGGER set_timestamp BEFORE UPDATE ON spec.subscriptions FOR EACH ROW EXECUTE FUNCTION public.trigger_set_timestamp();
 
+DROP TRIGGER IF EXISTS record_outbox ON spec.applications;
+CREATE TRIGGER record_outbox AFTER INSERT OR UPDATE ON spec.applications FOR EACH ROW WHEN (NEW.payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL) EXECUTE FUNCTION public.record_spec_outbox();
+DROP TRIGGER IF EXISTS record_outbox ON spec.channels;
+CREATE TRIGGER record_outbox AFTER INSERT OR UPDATE ON spec.channels FOR EACH ROW WHEN (NEW.payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL) EXECUTE FUNCTION public.record_spec_outbox();
+DROP TRIGGER IF EXISTS record_outbox ON spec.managedclustersetbindings;
+CREATE TRIGGER record_outbox AFTER INSERT OR UPDATE ON spec.managedclustersetbindings FOR EACH ROW WHEN (NEW.payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL) EXECUTE FUNCTION public.record_spec_outbox();
+DROP TRIGGER IF EXISTS record_outbox ON spec.managedclustersets;
+CREATE TRIGGER record_outbox AFTER INSERT OR UPDATE ON spec.managedclustersets FOR EACH ROW WHEN (NEW.payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL) EXECUTE FUNCTION public.record_spec_outbox();
+DROP TRIGGER IF EXISTS record_outbox ON spec.placementbindings;
+CREATE TRIGGER record_outbox AFTER INSERT OR UPDATE ON spec.placementbindings FOR EACH ROW WHEN (NEW.payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL) EXECUTE FUNCTION public.record_spec_outbox();
+DROP TRIGGER IF EXISTS record_outbox ON spec.placementrules;
+CREATE TRIGGER record_outbox AFTER INSERT OR UPDATE ON spec.placementrules FOR EACH ROW WHEN (NEW.payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL) EXECUTE FUNCTION public.record_spec_outbox();
+DROP TRIGGER IF EXISTS record_outbox ON spec.placements;
+CREATE TRIGGER record_outbox AFTER INSERT OR UPDATE ON spec.placements FOR EACH ROW WHEN (NEW.payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL) EXECUTE FUNCTION public.record_spec_outbox();
+DROP TRIGGER IF EXISTS record_outbox ON spec.policies;
+CREATE TRIGGER record_outbox AFTER INSERT OR UPDATE ON spec.policies FOR EACH ROW WHEN (NEW.payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL) EXECUTE FUNCTION public.record_spec_outbox();
+DROP TRIGGER IF EXISTS record_outbox ON spec.subscriptions;
+CREATE TRIGGER record_outbox AFTER INSERT OR UPDATE ON spec.subscriptions FOR EACH ROW WHEN (NEW.payload->'metadata'->'labels'->'global-hub.open-cluster-management.io/global-resource' IS NOT NULL) EXECUTE FUNCTION public.record_spec_outbox();
+
 DROP TRIGGER IF EXISTS update_compliance_table ON status.compliance;
 CREATE TRIGGER update_compliance_table AFTER INSERT OR UPDATE ON status.compliance FOR EACH ROW WHEN (pg_trigger_depth() < 1) EXECUTE FUNCTION public.set_cluster_id_to_compliance();
 
@@ -27,12 +27,28 @@ CREATE TABLE IF NOT EXISTS local_status.compliance (
 CREATE TABLE IF NOT EXISTS status.leaf_hub_heartbeats (
     leaf_hub_name character varying(254) NOT NULL,
     last_timestamp timestamp without time zone DEFAULT now() NOT NULL,
-    status VARCHAR(10) DEFAULT 'active'
+    -- active, inactive, maintenance or resuming
+    status VARCHAR(20) DEFAULT 'active'
 );
 CREATE UNIQUE INDEX IF NOT EXISTS leaf_hub_heartbeats_leaf_hub_idx ON status.leaf_hub_heartbeats (leaf_hub_name);
 CREATE INDEX IF NOT EXISTS leaf_hub_heartbeats_leaf_hub_timestamp_idx ON status.leaf_hub_heartbeats(last_timestamp);
 CREATE INDEX IF NOT EXISTS leaf_hub_heartbeats_leaf_hub_status_idx ON status.leaf_hub_heartbeats(status);
 
+-- the health samples of the leaf hubs, which are scored by the heartbeat latency, event lag, queue backlog and the
+-- failed events, the SLO burn rates are calculated by the samples in the windows
+CREATE TABLE IF NOT EXISTS status.leaf_hub_health (
+    leaf_hub_name character varying(254) NOT NULL,
+    score integer NOT NULL,
+    healthy boolean NOT NULL,
+    heartbeat_latency_seconds double precision NOT NULL DEFAULT 0,
+    event_lag_seconds double precision NOT NULL DEFAULT 0,
+    queue_backlog integer NOT NULL DEFAULT 0,
+    handled_events bigint NOT NULL DEFAULT 0,
+    failed_events bigint NOT NULL DEFAULT 0,
+    created_at timestamp without time zone DEFAULT now() NOT NULL
+) PARTITION BY RANGE (created_at);
+CREATE INDEX IF NOT EXISTS leaf_hub_health_leaf_hub_created_at_idx ON status.leaf_hub_health (leaf_hub_name, created_at);
+
 CREATE TABLE IF NOT EXISTS status.managed_clusters (
     leaf_hub_name character varying(254) NOT NULL,
     cluster_name character varying(254) generated always as (payload -> 'metadata' ->> 'name') stored,
@@ -52,6 +68,16 @@ CREATE TABLE IF NOT EXISTS status.leaf_hubs (
     payload jsonb NOT NULL,
     console_url text generated always as (payload ->> 'consoleURL') stored,
     grafana_url text generated always as (payload ->> 'grafanaURL') stored,
+    mch_version text generated always as (payload ->> 'mchVersion') stored,
+    mce_version text generated always as (payload ->> 'mceVersion') stored,
+    openshift_version text generated always as (payload ->> 'openshiftVersion') stored,
+    kubernetes_version text generated always as (payload ->> 'kubernetesVersion') stored,
+    node_count integer generated always as ((payload -> 'capacity' ->> 'nodeCount')::integer) stored,
+    allocatable_cpu_millicores bigint generated always as
+        ((payload -> 'capacity' ->> 'allocatableCPUMillicores')::bigint) stored,
+    allocatable_memory_bytes bigint generated always as
+        ((payload -> 'capacity' ->> 'allocatableMemoryBytes')::bigint) stored,
+    managed_cluster_count integer generated always as ((payload -> 'managedClusters' ->> 'total')::integer) stored,
     created_at timestamp without time zone DEFAULT now() NOT NULL,
     updated_at timestamp without time zone DEFAULT now() NOT NULL,
     deleted_at timestamp without time zone,
@@ -75,6 +101,34 @@ CREATE TABLE IF NOT EXISTS event.managed_clusters (
     CONSTRAINT managed_clusters_unique_constraint UNIQUE (leaf_hub_name, event_name, created_at)
 ) PARTITION BY RANGE (created_at);
 
+CREATE TABLE IF NOT EXISTS security.violations (
+    hub_name text NOT NULL,
+    source text NOT NULL,
+    id text NOT NULL,
+    policy_id text NOT NULL,
+    policy_name text NOT NULL,
+    severity text NOT NULL,
+    categories jsonb,
+    lifecycle_stage text,
+    state text NOT NULL,
+    cluster_name text,
+    cluster_id text,
+    namespace text,
+    deployment_name text,
+    deployment_id text,
+    resource_type text,
+    enforcement_action text,
+    enforcement_count integer NOT NULL DEFAULT 0,
+    detail_url text,
+    -- the time when the violation is first seen by the global hub, it's kept once the violation is stored
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    -- the time of the latest occurrence of the violation reported by the central
+    last_seen_at timestamp without time zone DEFAULT now() NOT NULL,
+    updated_at timestamp without time zone DEFAULT now() NOT NULL,
+    CONSTRAINT violations_unique_constraint UNIQUE (hub_name, source, id, created_at)
+) PARTITION BY RANGE (created_at);
+CREATE INDEX IF NOT EXISTS violations_id_idx ON security.violations (hub_name, source, id);
+
 CREATE TABLE IF NOT EXISTS event.local_policies (
     event_name text NOT NULL,
     event_namespace text,
@@ -136,6 +190,30 @@ CREATE TABLE IF NOT EXISTS history.local_compliance_job_log (
     error TEXT
 );
 
+-- the periodical snapshots of the local compliance, the compliance at any past time is rebuilt by the latest snapshot
+-- of the hub before that time and the event.local_policies after the snapshot. The snapshot_at is the creation time of
+-- the latest compliance event of the hub when the snapshot is taken
+CREATE TABLE IF NOT EXISTS history.local_compliance_snapshots (
+    snapshot_at timestamp without time zone NOT NULL,
+    policy_id uuid NOT NULL,
+    cluster_id uuid NOT NULL,
+    cluster_name character varying(254) NOT NULL,
+    leaf_hub_name character varying(254) NOT NULL,
+    compliance local_status.compliance_type NOT NULL,
+    CONSTRAINT local_compliance_snapshots_unique_constraint UNIQUE (snapshot_at, leaf_hub_name, policy_id, cluster_id)
+) PARTITION BY RANGE (snapshot_at);
+CREATE INDEX IF NOT EXISTS local_policies_event_compliance_idx ON event.local_policies (policy_id, cluster_id, created_at);
+
+-- the heartbeats of the active global hub managers, they're replicated to the standby global hub, which takes over
+-- once the heartbeat of the active one is expired
+CREATE TABLE IF NOT EXISTS status.global_hub_heartbeats (
+    name character varying(254) PRIMARY KEY,
+    -- active or standby
+    role character varying(20) NOT NULL,
+    heartbeat_at timestamp without time zone DEFAULT now() NOT NULL,
+    promoted_at timestamp without time zone
+);
+
 CREATE TABLE IF NOT EXISTS status.transport (
     -- transport name, it is the topic name for the kafka transport
     name character varying(254) PRIMARY KEY,
@@ -144,6 +222,35 @@ CREATE TABLE IF NOT EXISTS status.transport (
     updated_at timestamp without time zone DEFAULT now() NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS status.dead_letters (
+    id bigserial PRIMARY KEY,
+    leaf_hub_name character varying(254) NOT NULL,
+    event_type character varying(254) NOT NULL,
+    event_version character varying(254),
+    error text NOT NULL,
+    event jsonb NOT NULL, -- the cloudevent attributes and extensions
+    data bytea, -- the cloudevent data, it might not be a valid json
+    created_at timestamp without time zone DEFAULT now() NOT NULL,
+    replay_requested_at timestamp without time zone,
+    replayed_at timestamp without time zone
+);
+CREATE INDEX IF NOT EXISTS dead_letters_leaf_hub_idx ON status.dead_letters (leaf_hub_name, created_at);
+CREATE INDEX IF NOT EXISTS dead_letters_replay_idx ON status.dead_letters (id) WHERE replay_requested_at IS NOT NULL AND replayed_at IS NULL;
+
+-- the requests to resend the full state of the event types from the leaf hubs, the request is sent to the hub once
+-- it's active, and it's committed once the manager has handled a bundle of the event type after it's sent
+CREATE TABLE IF NOT EXISTS status.resync_requests (
+    id bigserial PRIMARY KEY,
+    leaf_hub_name character varying(254) NOT NULL,
+    event_type character varying(254) NOT NULL,
+    requested_by text NOT NULL,
+    requested_at timestamp without time zone DEFAULT now() NOT NULL,
+    sent_at timestamp without time zone,
+    committed_at timestamp without time zone
+);
+CREATE INDEX IF NOT EXISTS resync_requests_leaf_hub_idx ON status.resync_requests (leaf_hub_name, requested_at);
+CREATE INDEX IF NOT EXISTS resync_requests_pending_idx ON status.resync_requests (id) WHERE committed_at IS NULL;
+
 CREATE TABLE IF NOT EXISTS security.alert_counts (
     hub_name text NOT NULL,
     low integer NOT NULL,
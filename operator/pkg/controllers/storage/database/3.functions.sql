@@ -113,6 +113,47 @@ END;
 $$;
 
 
+-- rebuild the local compliance at the given time by the latest snapshot of each hub before that time, and the latest
+-- compliance event of each policy and cluster after the snapshot, the snapshot time is on the clock of the hub events
+-- get the compliance at '2023-07-06 14:05:00': SELECT * FROM history.get_local_compliance_at('2023-07-06 14:05:00');
+CREATE OR REPLACE FUNCTION history.get_local_compliance_at(at_time timestamp)
+RETURNS TABLE (
+    policy_id uuid,
+    cluster_id uuid,
+    cluster_name text,
+    leaf_hub_name text,
+    compliance local_status.compliance_type,
+    changed_at timestamp
+) AS $$
+    WITH latest_snapshot AS (
+        SELECT s.leaf_hub_name, max(s.snapshot_at) AS snapshot_at
+        FROM history.local_compliance_snapshots s
+        WHERE s.snapshot_at <= at_time
+        GROUP BY s.leaf_hub_name
+    ),
+    snapshot_compliance AS (
+        SELECT s.policy_id, s.cluster_id, s.cluster_name::text, s.leaf_hub_name::text, s.compliance,
+            s.snapshot_at AS changed_at
+        FROM history.local_compliance_snapshots s
+        JOIN latest_snapshot l ON s.leaf_hub_name = l.leaf_hub_name AND s.snapshot_at = l.snapshot_at
+    ),
+    event_compliance AS (
+        SELECT DISTINCT ON (e.leaf_hub_name, e.policy_id, e.cluster_id)
+            e.policy_id, e.cluster_id, e.cluster_name, e.leaf_hub_name::text, e.compliance, e.created_at AS changed_at
+        FROM event.local_policies e
+        LEFT JOIN latest_snapshot l ON l.leaf_hub_name = e.leaf_hub_name
+        WHERE e.created_at <= at_time
+            AND e.created_at > COALESCE(l.snapshot_at, '-infinity'::timestamp)
+        ORDER BY e.leaf_hub_name, e.policy_id, e.cluster_id, e.created_at DESC, e.count DESC
+    )
+    SELECT DISTINCT ON (c.leaf_hub_name, c.policy_id, c.cluster_id)
+        c.policy_id, c.cluster_id, COALESCE(c.cluster_name, s.cluster_name), c.leaf_hub_name, c.compliance, c.changed_at
+    FROM (SELECT * FROM event_compliance UNION ALL SELECT * FROM snapshot_compliance) c
+    LEFT JOIN snapshot_compliance s
+        ON s.leaf_hub_name = c.leaf_hub_name AND s.policy_id = c.policy_id AND s.cluster_id = c.cluster_id
+    ORDER BY c.leaf_hub_name, c.policy_id, c.cluster_id, c.changed_at DESC
+$$ LANGUAGE sql STABLE;
+
 --- create the monthly partitioned tables function by created_at/compliance_date column
 --- sample: SELECT create_monthly_range_partitioned_table('event.local_root_policies', '2023-08-01');
 CREATE OR REPLACE FUNCTION create_monthly_range_partitioned_table(full_table_name text, input_time text)
@@ -127,6 +168,30 @@ BEGIN
                   );
 END $$ LANGUAGE plpgsql;
 
+--- create the monthly partitioned table unless the month is covered by any existing partition, since the data
+--- retention job might partition the table daily or weekly by the configured granularity, and it fills the gaps
+--- sample: SELECT create_range_partitioned_table('event.local_root_policies', '2023-08-01');
+CREATE OR REPLACE FUNCTION create_range_partitioned_table(full_table_name text, input_time text)
+RETURNS VOID AS
+$$
+DECLARE
+    range_start timestamp := DATE_TRUNC('MONTH', input_time::date);
+    range_end timestamp := DATE_TRUNC('MONTH', (input_time::date + INTERVAL '1 MONTH'));
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM (
+            SELECT regexp_match(pg_get_expr(child.relpartbound, child.oid),
+                                'FROM \(''([^'']+)''\) TO \(''([^'']+)''\)') AS bound
+            FROM pg_inherits JOIN pg_class child ON pg_inherits.inhrelid = child.oid
+            WHERE pg_inherits.inhparent = full_table_name::regclass
+        ) partitions
+        WHERE bound IS NOT NULL AND bound[1]::timestamp < range_end AND bound[2]::timestamp > range_start
+    ) THEN
+        RETURN;
+    END IF;
+    PERFORM create_monthly_range_partitioned_table(full_table_name, input_time);
+END $$ LANGUAGE plpgsql;
+
 CREATE OR REPLACE FUNCTION public.set_cluster_id_to_local_compliance() RETURNS trigger
     LANGUAGE plpgsql
     AS $$
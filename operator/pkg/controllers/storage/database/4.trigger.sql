@@ -18,17 +18,24 @@ AFTER INSERT OR UPDATE ON status.managed_clusters
 FOR EACH ROW
 EXECUTE FUNCTION public.update_cluster_event_cluster_id();
 
---- create the current month partitioned tables for local_policies and local_root_policies
-SELECT create_monthly_range_partitioned_table('event.local_root_policies', to_char(current_date, 'YYYY-MM-DD'));
-SELECT create_monthly_range_partitioned_table('event.local_policies', to_char(current_date, 'YYYY-MM-DD'));
-SELECT create_monthly_range_partitioned_table('history.local_compliance', to_char(current_date, 'YYYY-MM-DD'));
-SELECT create_monthly_range_partitioned_table('event.managed_clusters', to_char(current_date, 'YYYY-MM-DD'));
+--- create the current month partitioned tables for local_policies and local_root_policies, the months covered by the
+--- partitions of the configured granularity are skipped
+SELECT create_range_partitioned_table('event.local_root_policies', to_char(current_date, 'YYYY-MM-DD'));
+SELECT create_range_partitioned_table('event.local_policies', to_char(current_date, 'YYYY-MM-DD'));
+SELECT create_range_partitioned_table('history.local_compliance', to_char(current_date, 'YYYY-MM-DD'));
+SELECT create_range_partitioned_table('event.managed_clusters', to_char(current_date, 'YYYY-MM-DD'));
+SELECT create_range_partitioned_table('security.violations', to_char(current_date, 'YYYY-MM-DD'));
+SELECT create_range_partitioned_table('history.local_compliance_snapshots', to_char(current_date, 'YYYY-MM-DD'));
+SELECT create_range_partitioned_table('status.leaf_hub_health', to_char(current_date, 'YYYY-MM-DD'));
 
 --- create the previous month partitioned tables for receiving the data from the previous month
-SELECT create_monthly_range_partitioned_table('event.local_root_policies', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
-SELECT create_monthly_range_partitioned_table('event.local_policies', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
-SELECT create_monthly_range_partitioned_table('history.local_compliance', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
-SELECT create_monthly_range_partitioned_table('event.managed_clusters', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
+SELECT create_range_partitioned_table('event.local_root_policies', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
+SELECT create_range_partitioned_table('event.local_policies', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
+SELECT create_range_partitioned_table('history.local_compliance', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
+SELECT create_range_partitioned_table('event.managed_clusters', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
+SELECT create_range_partitioned_table('security.violations', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
+SELECT create_range_partitioned_table('history.local_compliance_snapshots', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
+SELECT create_range_partitioned_table('status.leaf_hub_health', to_char(current_date - interval '1 month', 'YYYY-MM-DD'));
 
 -- Attach the function to the event table
 DROP TRIGGER IF EXISTS trg_update_history_compliance_by_event ON event.local_policies;
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/stdlib"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// the interval of reporting the health of the database replicas
+var replicaProbeInterval = 1 * time.Minute
+
+var databaseReplicaReconciler *DatabaseReplicaReconciler
+
+// replicaProber returns the replication lag of the replica
+type replicaProber func(ctx context.Context, uri string, caCert []byte) (time.Duration, error)
+
+func StartDatabaseReplicaController(initOption config.ControllerOption) (config.ControllerInterface, error) {
+	if databaseReplicaReconciler != nil {
+		return databaseReplicaReconciler, nil
+	}
+	databaseReplicaReconciler = NewDatabaseReplicaReconciler(initOption.Manager.GetClient(), probeReplica)
+	if err := databaseReplicaReconciler.SetupWithManager(initOption.Manager); err != nil {
+		databaseReplicaReconciler = nil
+		return nil, err
+	}
+	log.Info("start database replica controller")
+	return databaseReplicaReconciler, nil
+}
+
+// DatabaseReplicaReconciler reports the health and the replication lag of the database read replicas in the status
+// of the mgh, the manager routes the read-only queries to the healthy ones
+type DatabaseReplicaReconciler struct {
+	c     client.Client
+	probe replicaProber
+}
+
+func NewDatabaseReplicaReconciler(c client.Client, probe replicaProber) *DatabaseReplicaReconciler {
+	return &DatabaseReplicaReconciler{c: c, probe: probe}
+}
+
+func (r *DatabaseReplicaReconciler) IsResourceRemoved() bool {
+	return true
+}
+
+func (r *DatabaseReplicaReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).Named("databaseReplicaController").
+		For(&v1alpha4.MulticlusterGlobalHub{}, builder.WithPredicates(predicate.Funcs{
+			CreateFunc: func(e event.CreateEvent) bool {
+				return true
+			},
+			// skip the status updates, otherwise the controller is triggered by itself
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				return e.ObjectNew.GetGeneration() != e.ObjectOld.GetGeneration() ||
+					!equality.Semantic.DeepEqual(e.ObjectNew.GetAnnotations(), e.ObjectOld.GetAnnotations())
+			},
+			DeleteFunc: func(e event.DeleteEvent) bool {
+				return false
+			},
+		})).
+		Complete(r)
+}
+
+func (r *DatabaseReplicaReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	mgh, err := config.GetMulticlusterGlobalHub(ctx, r.c)
+	if err != nil || mgh == nil || config.IsPaused(mgh) || mgh.DeletionTimestamp != nil {
+		return ctrl.Result{}, err
+	}
+
+	storageConn := config.GetStorageConnection()
+	if storageConn == nil {
+		log.Debug("storage connection isn't ready")
+		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+
+	replicas := make([]v1alpha4.DatabaseReplicaStatus, 0, len(storageConn.ReplicaDatabaseURIs))
+	for _, uri := range storageConn.ReplicaDatabaseURIs {
+		replicas = append(replicas, r.probeStatus(ctx, uri, storageConn.CACert))
+	}
+	if len(replicas) == 0 {
+		replicas = nil
+	}
+
+	if err := r.updateStatus(ctx, mgh, replicas); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update the database replicas status: %w", err)
+	}
+	if replicas == nil {
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{RequeueAfter: replicaProbeInterval}, nil
+}
+
+func (r *DatabaseReplicaReconciler) probeStatus(ctx context.Context, uri string, caCert []byte,
+) v1alpha4.DatabaseReplicaStatus {
+	now := metav1.Now()
+	status := v1alpha4.DatabaseReplicaStatus{
+		Host:          database.ReplicaHost(uri),
+		LastProbeTime: &now,
+	}
+	lag, err := r.probe(ctx, uri, caCert)
+	if err != nil {
+		status.Message = err.Error()
+		return status
+	}
+	status.Lag = &metav1.Duration{Duration: lag.Round(time.Second)}
+	if lag > database.DefaultMaxReplicaLag {
+		status.Message = fmt.Sprintf("the replication lag exceeds %s, the queries fall back to the primary",
+			database.DefaultMaxReplicaLag)
+		return status
+	}
+	status.Healthy = true
+	return status
+}
+
+func (r *DatabaseReplicaReconciler) updateStatus(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub,
+	desired []v1alpha4.DatabaseReplicaStatus,
+) error {
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		curmgh := &v1alpha4.MulticlusterGlobalHub{}
+		if err := r.c.Get(ctx, client.ObjectKeyFromObject(mgh), curmgh); err != nil {
+			return err
+		}
+		if equality.Semantic.DeepEqual(curmgh.Status.DatabaseReplicas, desired) {
+			return nil
+		}
+		curmgh.Status.DatabaseReplicas = desired
+		return r.c.Status().Update(ctx, curmgh)
+	})
+}
+
+func probeReplica(ctx context.Context, uri string, caCert []byte) (time.Duration, error) {
+	pgConfig, err := database.GetPostgresConfig(uri, caCert)
+	if err != nil {
+		return 0, err
+	}
+	db := stdlib.OpenDB(*pgConfig)
+	defer func() {
+		if err := db.Close(); err != nil {
+			log.Warnw("failed to close the replica connection", "error", err)
+		}
+	}()
+
+	probeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	return database.ReplicationLag(probeCtx, db)
+}
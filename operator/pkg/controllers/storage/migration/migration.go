@@ -0,0 +1,253 @@
+package migration
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	iofs "io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+//go:embed migrations
+var migrationsFS embed.FS
+
+var (
+	log = logger.DefaultZapLogger()
+	// the migration files are named as <version>_<name>.up.sql and <version>_<name>.down.sql
+	fileNamePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)\.sql$`)
+)
+
+// Migration is a versioned and reversible change of the database schema
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+	// Checksum is the sha256 of the up and down sql, the applied migrations must not be changed
+	Checksum string
+}
+
+func (m Migration) String() string {
+	return fmt.Sprintf("%04d_%s", m.Version, m.Name)
+}
+
+// Plan is the migrations to apply, or to revert if the target version is lower than the current one
+type Plan struct {
+	CurrentVersion int
+	TargetVersion  int
+	Up             []Migration
+	Down           []Migration
+}
+
+func (p *Plan) Empty() bool {
+	return len(p.Up) == 0 && len(p.Down) == 0
+}
+
+// Migrations returns the migrations of the global hub database
+func Migrations() ([]Migration, error) {
+	return Load(migrationsFS, "migrations")
+}
+
+// Load reads the migrations from the dir, the versions must start from 1 without gaps and each of them must have
+// both the up and down files
+func Load(fsys iofs.FS, dir string) ([]Migration, error) {
+	entries, err := iofs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the migrations: %w", err)
+	}
+
+	migrations := map[int]*Migration{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		matches := fileNamePattern.FindStringSubmatch(entry.Name())
+		if matches == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+		version, err := strconv.Atoi(matches[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version %s: %w", entry.Name(), err)
+		}
+		sqlBytes, err := iofs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", entry.Name(), err)
+		}
+
+		m, found := migrations[version]
+		if !found {
+			m = &Migration{Version: version, Name: matches[2]}
+			migrations[version] = m
+		} else if m.Name != matches[2] {
+			return nil, fmt.Errorf("migration %d has different names: %s and %s", version, m.Name, matches[2])
+		}
+		if matches[3] == "up" {
+			m.Up = string(sqlBytes)
+		} else {
+			m.Down = string(sqlBytes)
+		}
+	}
+
+	result := make([]Migration, 0, len(migrations))
+	for _, m := range migrations {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %s must have both the up and down sql", m)
+		}
+		sum := sha256.Sum256([]byte(m.Up + m.Down))
+		m.Checksum = hex.EncodeToString(sum[:])
+		result = append(result, *m)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	for i, m := range result {
+		if m.Version != i+1 {
+			return nil, fmt.Errorf("migration %s is out of order, expected version %d", m, i+1)
+		}
+	}
+	return result, nil
+}
+
+// Migrator applies the migrations to the database, and records them in the schema_migrations table
+type Migrator struct {
+	conn       *sql.Conn
+	migrations []Migration
+}
+
+// NewMigrator creates the migrator with a dedicated connection, since the advisory lock is held by the session
+func NewMigrator(conn *sql.Conn, migrations []Migration) *Migrator {
+	return &Migrator{conn: conn, migrations: migrations}
+}
+
+// LatestVersion returns the version of the last migration
+func (m *Migrator) LatestVersion() int {
+	return len(m.migrations)
+}
+
+// Migrate moves the database schema to the target version. The applied migrations are verified by the checksums,
+// and each migration runs in a transaction with the record of it. The dry run only returns the plan without
+// changing the database.
+func (m *Migrator) Migrate(ctx context.Context, target int, dryRun bool) (*Plan, error) {
+	if target < 0 || target > m.LatestVersion() {
+		return nil, fmt.Errorf("the target schema version %d is out of the range [0, %d]", target, m.LatestVersion())
+	}
+
+	if !dryRun {
+		if _, err := m.conn.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			version integer PRIMARY KEY,
+			name text NOT NULL,
+			checksum text NOT NULL,
+			applied_at timestamp without time zone DEFAULT now() NOT NULL
+		)`, database.SchemaMigrationsTable)); err != nil {
+			return nil, fmt.Errorf("failed to create the table %s: %w", database.SchemaMigrationsTable, err)
+		}
+		// exclude the other operators, the manager and the backup while migrating
+		if err := database.AdvisoryLock(m.conn); err != nil {
+			return nil, fmt.Errorf("failed to lock the database: %w", err)
+		}
+		defer database.AdvisoryUnlock(m.conn)
+	}
+
+	current, err := m.verify(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &Plan{CurrentVersion: current, TargetVersion: target}
+	if target > current {
+		plan.Up = m.migrations[current:target]
+	}
+	for version := current; version > target; version-- {
+		plan.Down = append(plan.Down, m.migrations[version-1])
+	}
+	if dryRun {
+		return plan, nil
+	}
+
+	for _, migration := range plan.Up {
+		if err := m.apply(ctx, migration, migration.Up, fmt.Sprintf(
+			"INSERT INTO %s (version, name, checksum) VALUES ($1, $2, $3)", database.SchemaMigrationsTable),
+			migration.Version, migration.Name, migration.Checksum); err != nil {
+			return plan, err
+		}
+		log.Infof("applied the schema migration %s", migration)
+	}
+	for _, migration := range plan.Down {
+		if err := m.apply(ctx, migration, migration.Down, fmt.Sprintf(
+			"DELETE FROM %s WHERE version = $1", database.SchemaMigrationsTable), migration.Version); err != nil {
+			return plan, err
+		}
+		log.Infof("reverted the schema migration %s", migration)
+	}
+	return plan, nil
+}
+
+// verify compares the applied migrations with the known ones, and returns the current version
+func (m *Migrator) verify(ctx context.Context) (int, error) {
+	var table sql.NullString
+	if err := m.conn.QueryRowContext(ctx, "SELECT to_regclass($1)::text", database.SchemaMigrationsTable).
+		Scan(&table); err != nil {
+		return 0, fmt.Errorf("failed to check the table %s: %w", database.SchemaMigrationsTable, err)
+	}
+	// the dry run before the first migration
+	if !table.Valid {
+		return 0, nil
+	}
+
+	rows, err := m.conn.QueryContext(ctx, fmt.Sprintf("SELECT version, name, checksum FROM %s ORDER BY version",
+		database.SchemaMigrationsTable))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list the applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	current := 0
+	for rows.Next() {
+		var version int
+		var name, checksum string
+		if err := rows.Scan(&version, &name, &checksum); err != nil {
+			return 0, fmt.Errorf("failed to scan the applied migration: %w", err)
+		}
+		if version > m.LatestVersion() {
+			return 0, fmt.Errorf("the database schema version %d is newer than the latest known version %d",
+				version, m.LatestVersion())
+		}
+		if version != current+1 {
+			return 0, fmt.Errorf("the migration %d is missing in the database", current+1)
+		}
+		if known := m.migrations[version-1]; known.Checksum != checksum {
+			return 0, fmt.Errorf("the checksum of the applied migration %04d_%s doesn't match the migration %s",
+				version, name, known)
+		}
+		current = version
+	}
+	return current, rows.Err()
+}
+
+func (m *Migrator) apply(ctx context.Context, migration Migration, migrationSQL, recordSQL string,
+	recordArgs ...any,
+) error {
+	tx, err := m.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin the transaction of the migration %s: %w", migration, err)
+	}
+	if _, err = tx.ExecContext(ctx, migrationSQL); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to run the migration %s: %w", migration, err)
+	}
+	if _, err = tx.ExecContext(ctx, recordSQL, recordArgs...); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("failed to record the migration %s: %w", migration, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit the migration %s: %w", migration, err)
+	}
+	return nil
+}
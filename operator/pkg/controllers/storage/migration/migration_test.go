@@ -0,0 +1,116 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+func TestMigrations(t *testing.T) {
+	migrations, err := Migrations()
+	require.NoError(t, err)
+	// the binaries refuse to start until the database is migrated to the latest version
+	assert.Equal(t, database.SchemaVersion, len(migrations))
+	for _, m := range migrations {
+		assert.NotEmpty(t, m.Up, m.String())
+		assert.NotEmpty(t, m.Down, m.String())
+	}
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "ordered by the version",
+			files: fstest.MapFS{
+				"m/0002_b.up.sql":   {Data: []byte("up b")},
+				"m/0002_b.down.sql": {Data: []byte("down b")},
+				"m/0001_a.up.sql":   {Data: []byte("up a")},
+				"m/0001_a.down.sql": {Data: []byte("down a")},
+			},
+			want: []string{"0001_a", "0002_b"},
+		},
+		{
+			name: "missing the down sql",
+			files: fstest.MapFS{
+				"m/0001_a.up.sql": {Data: []byte("up a")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "gap in the versions",
+			files: fstest.MapFS{
+				"m/0001_a.up.sql":   {Data: []byte("up a")},
+				"m/0001_a.down.sql": {Data: []byte("down a")},
+				"m/0003_c.up.sql":   {Data: []byte("up c")},
+				"m/0003_c.down.sql": {Data: []byte("down c")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "different names of a version",
+			files: fstest.MapFS{
+				"m/0001_a.up.sql":   {Data: []byte("up a")},
+				"m/0001_b.down.sql": {Data: []byte("down b")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid file name",
+			files: fstest.MapFS{
+				"m/a.sql": {Data: []byte("up a")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := Load(tt.files, "m")
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			names := []string{}
+			for _, m := range migrations {
+				names = append(names, m.String())
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestChecksum(t *testing.T) {
+	files := fstest.MapFS{
+		"m/0001_a.up.sql":   {Data: []byte("up a")},
+		"m/0001_a.down.sql": {Data: []byte("down a")},
+	}
+	migrations, err := Load(files, "m")
+	require.NoError(t, err)
+
+	// the checksum covers both the up and down sql
+	files["m/0001_a.down.sql"] = &fstest.MapFile{Data: []byte("down a;")}
+	changed, err := Load(files, "m")
+	require.NoError(t, err)
+	assert.NotEqual(t, migrations[0].Checksum, changed[0].Checksum)
+}
+
+func TestMigrateTargetOutOfRange(t *testing.T) {
+	migrations, err := Migrations()
+	require.NoError(t, err)
+	m := NewMigrator(nil, migrations)
+
+	_, err = m.Migrate(context.Background(), m.LatestVersion()+1, true)
+	assert.Error(t, err)
+	_, err = m.Migrate(context.Background(), -1, true)
+	assert.Error(t, err)
+}
@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS spec.managed_clusters_labels
+    DROP COLUMN IF EXISTS last_applied_labels,
+    DROP COLUMN IF EXISTS label_policies;
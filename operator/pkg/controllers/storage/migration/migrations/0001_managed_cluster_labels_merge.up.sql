@@ -0,0 +1,4 @@
+-- the three-way merge of the managed cluster labels
+ALTER TABLE IF EXISTS spec.managed_clusters_labels
+    ADD COLUMN IF NOT EXISTS last_applied_labels jsonb DEFAULT '{}'::jsonb NOT NULL,
+    ADD COLUMN IF NOT EXISTS label_policies jsonb DEFAULT '{}'::jsonb NOT NULL;
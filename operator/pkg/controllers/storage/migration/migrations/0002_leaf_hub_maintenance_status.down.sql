@@ -0,0 +1,3 @@
+-- the maintenance and resuming status don't fit, the hubs are marked active again by the next heartbeat
+UPDATE status.leaf_hub_heartbeats SET status = 'inactive' WHERE length(status) > 10;
+ALTER TABLE IF EXISTS status.leaf_hub_heartbeats ALTER COLUMN status TYPE VARCHAR(10);
@@ -0,0 +1,2 @@
+-- the maintenance status of the leaf hubs
+ALTER TABLE IF EXISTS status.leaf_hub_heartbeats ALTER COLUMN status TYPE VARCHAR(20);
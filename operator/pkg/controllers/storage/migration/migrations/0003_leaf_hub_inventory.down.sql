@@ -0,0 +1,9 @@
+ALTER TABLE IF EXISTS status.leaf_hubs
+    DROP COLUMN IF EXISTS mch_version,
+    DROP COLUMN IF EXISTS mce_version,
+    DROP COLUMN IF EXISTS openshift_version,
+    DROP COLUMN IF EXISTS kubernetes_version,
+    DROP COLUMN IF EXISTS node_count,
+    DROP COLUMN IF EXISTS allocatable_cpu_millicores,
+    DROP COLUMN IF EXISTS allocatable_memory_bytes,
+    DROP COLUMN IF EXISTS managed_cluster_count;
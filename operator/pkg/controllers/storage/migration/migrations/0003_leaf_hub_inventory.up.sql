@@ -0,0 +1,14 @@
+-- the inventory of the leaf hubs
+ALTER TABLE IF EXISTS status.leaf_hubs
+    ADD COLUMN IF NOT EXISTS mch_version text generated always as (payload ->> 'mchVersion') stored,
+    ADD COLUMN IF NOT EXISTS mce_version text generated always as (payload ->> 'mceVersion') stored,
+    ADD COLUMN IF NOT EXISTS openshift_version text generated always as (payload ->> 'openshiftVersion') stored,
+    ADD COLUMN IF NOT EXISTS kubernetes_version text generated always as (payload ->> 'kubernetesVersion') stored,
+    ADD COLUMN IF NOT EXISTS node_count integer generated always as
+        ((payload -> 'capacity' ->> 'nodeCount')::integer) stored,
+    ADD COLUMN IF NOT EXISTS allocatable_cpu_millicores bigint generated always as
+        ((payload -> 'capacity' ->> 'allocatableCPUMillicores')::bigint) stored,
+    ADD COLUMN IF NOT EXISTS allocatable_memory_bytes bigint generated always as
+        ((payload -> 'capacity' ->> 'allocatableMemoryBytes')::bigint) stored,
+    ADD COLUMN IF NOT EXISTS managed_cluster_count integer generated always as
+        ((payload -> 'managedClusters' ->> 'total')::integer) stored;
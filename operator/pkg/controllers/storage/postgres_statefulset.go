@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"strings"
 
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/discovery/cached/memory"
@@ -31,6 +33,11 @@ var (
 	builtinPostgresInitName   = fmt.Sprintf("%s-init", BuiltinPostgresName)
 	builtinPartialPostgresURI = fmt.Sprintf("%s.%s.svc:5432/hoh?sslmode=verify-ca", BuiltinPostgresName,
 		utils.GetDefaultNamespace())
+	// the read replicas streaming from the builtin postgres: sts, service, cert
+	BuiltinPostgresReplicaName       = fmt.Sprintf("%s-replica", BuiltinPostgresName)
+	builtinPostgresReplicaCertName   = fmt.Sprintf("%s-cert", BuiltinPostgresReplicaName)
+	builtinPartialPostgresReplicaURI = fmt.Sprintf("%s.%s.svc:5432/hoh?sslmode=verify-ca", BuiltinPostgresReplicaName,
+		utils.GetDefaultNamespace())
 )
 
 const (
@@ -64,6 +71,11 @@ func InitPostgresByStatefulset(ctx context.Context, mgh *globalhubv1alpha4.Multi
 	}
 	log.Infof("the postgres customized config: %s", customizedConfig)
 
+	replicas, err := config.GetPostgresReplicas(mgh)
+	if err != nil {
+		return nil, err
+	}
+
 	// get the postgres objects
 	postgresRenderer, postgresDeployer := renderer.NewHoHRenderer(stsPostgresFS), deployer.NewHoHDeployer(mgr.GetClient())
 	postgresObjects, err := postgresRenderer.Render("manifests.sts", "",
@@ -93,6 +105,9 @@ func InitPostgresByStatefulset(ctx context.Context, mgh *globalhubv1alpha4.Multi
 				EnablePostgresMetrics        bool
 				EnableInventoryAPI           bool
 				PostgresCustomizedConfig     []byte
+				PostgresReplicas             int32
+				ReplicaName                  string
+				PostgresReplicaCertName      string
 			}{
 				Name:                         BuiltinPostgresName,
 				Namespace:                    mgh.GetNamespace(),
@@ -121,6 +136,9 @@ func InitPostgresByStatefulset(ctx context.Context, mgh *globalhubv1alpha4.Multi
 				EnablePostgresMetrics:    (!config.IsBYOPostgres()) && mgh.Spec.EnableMetrics,
 				EnableInventoryAPI:       config.WithInventory(mgh),
 				PostgresCustomizedConfig: []byte(customizedConfig),
+				PostgresReplicas:         replicas,
+				ReplicaName:              BuiltinPostgresReplicaName,
+				PostgresReplicaCertName:  builtinPostgresReplicaCertName,
 			}, nil
 		})
 	if err != nil {
@@ -139,18 +157,54 @@ func InitPostgresByStatefulset(ctx context.Context, mgh *globalhubv1alpha4.Multi
 		mapper, mgr.GetScheme()); err != nil {
 		return nil, fmt.Errorf("failed to create/update postgres objects: %w", err)
 	}
+	if replicas == 0 {
+		if err = deletePostgresReplicas(ctx, mgh, mgr.GetClient()); err != nil {
+			return nil, err
+		}
+	}
 
 	ca, err := getPostgresCA(ctx, mgh, mgr.GetClient())
 	if err != nil {
 		return nil, err
 	}
-	return &config.PostgresConnection{
+	conn := &config.PostgresConnection{
 		SuperuserDatabaseURI: "postgresql://" + credential.postgresAdminUsername + ":" +
 			credential.postgresAdminUserPassword + "@" + builtinPartialPostgresURI,
 		ReadonlyUserDatabaseURI: "postgresql://" + credential.postgresReadonlyUsername + ":" +
 			credential.postgresReadonlyUserPassword + "@" + builtinPartialPostgresURI,
 		CACert: []byte(ca),
-	}, nil
+	}
+	// the replica service balances the connections across the replica pods, and the service ca signs its cert
+	if replicas > 0 {
+		conn.ReplicaDatabaseURIs = []string{
+			"postgresql://" + credential.postgresAdminUsername + ":" +
+				credential.postgresAdminUserPassword + "@" + builtinPartialPostgresReplicaURI,
+		}
+	}
+	return conn, nil
+}
+
+// deletePostgresReplicas removes the replica statefulset and service once the replicas are disabled, the volumes
+// are kept like the ones of the builtin postgres
+func deletePostgresReplicas(ctx context.Context, mgh *globalhubv1alpha4.MulticlusterGlobalHub,
+	c client.Client,
+) error {
+	objs := []client.Object{
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{
+			Name:      BuiltinPostgresReplicaName,
+			Namespace: mgh.Namespace,
+		}},
+		&corev1.Service{ObjectMeta: metav1.ObjectMeta{
+			Name:      BuiltinPostgresReplicaName,
+			Namespace: mgh.Namespace,
+		}},
+	}
+	for _, obj := range objs {
+		if err := c.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete the postgres replica %T: %w", obj, err)
+		}
+	}
+	return nil
 }
 
 func getPostgresCredential(ctx context.Context, mgh *globalhubv1alpha4.MulticlusterGlobalHub,
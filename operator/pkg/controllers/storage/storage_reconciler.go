@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
 	promv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -28,6 +29,7 @@ import (
 
 	"github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/controllers/storage/migration"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/utils"
 	"github.com/stolostron/multicluster-global-hub/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
@@ -51,9 +53,6 @@ var databaseFS embed.FS
 //go:embed database.old
 var databaseOldFS embed.FS
 
-//go:embed upgrade
-var upgradeFS embed.FS
-
 //go:embed manifests.sts
 var stsPostgresFS embed.FS
 
@@ -65,6 +64,9 @@ type StorageReconciler struct {
 	databaseReconcileCount int
 	enableGlobalResource   bool
 	enableMetrics          bool
+	// the last applied migration options and the resulting schema version
+	schemaMigration *config.SchemaMigrationOptions
+	schemaVersion   int
 }
 
 var WatchedSecret = sets.NewString(
@@ -229,7 +231,8 @@ func (r *StorageReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	if needRequeue {
 		return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
 	}
-	config.SetDatabaseReady(true)
+	// the manager refuses to start until the schema is migrated to the expected version
+	config.SetDatabaseReady(r.schemaVersion >= database.SchemaVersion)
 
 	// Update retention condition
 	reconcileErr = config.UpdateCondition(ctx, r.GetClient(), types.NamespacedName{
@@ -255,7 +258,19 @@ func getRetentionConditions(mgh *v1alpha4.MulticlusterGlobalHub) metav1.Conditio
 	if months < 1 {
 		months = 1
 	}
+	if _, err := config.GetRetentionPolicies(mgh); err != nil {
+		return metav1.Condition{
+			Type:    config.CONDITION_TYPE_DATABASE,
+			Status:  config.CONDITION_STATUS_FALSE,
+			Reason:  config.CONDITION_REASON_RETENTION_PARSED_FAILED,
+			Message: fmt.Sprintf("failed to parse the retention policies, err:%v", err),
+		}
+	}
 	msg := fmt.Sprintf("The data will be kept in the database for %d months.", months)
+	if policies := len(mgh.Spec.DataLayerSpec.Postgres.RetentionPolicies); policies > 0 {
+		msg = fmt.Sprintf("The data will be kept in the database for %d months, except the data overridden by "+
+			"%d retention policies.", months, policies)
+	}
 	return metav1.Condition{
 		Type:    config.CONDITION_TYPE_DATABASE,
 		Status:  config.CONDITION_STATUS_TRUE,
@@ -297,8 +312,13 @@ func (r *StorageReconciler) ReconcileStorage(ctx context.Context,
 }
 
 func (r *StorageReconciler) ReconcileDatabase(ctx context.Context, mgh *v1alpha4.MulticlusterGlobalHub) (bool, error) {
-	// Don't reconcile, or create the connection, when database has been initialized
-	if r.databaseReconcileCount > 0 {
+	migrationOptions, err := config.GetSchemaMigrationOptions(mgh)
+	if err != nil {
+		return false, err
+	}
+	// Don't reconcile, or create the connection, when database has been initialized and the migration options
+	// aren't changed
+	if r.databaseReconcileCount > 0 && r.schemaMigration != nil && *r.schemaMigration == migrationOptions {
 		return false, nil
 	}
 	storageConn := config.GetStorageConnection()
@@ -327,9 +347,96 @@ func (r *StorageReconciler) ReconcileDatabase(ctx context.Context, mgh *v1alpha4
 		log.Debug("global hub database initialized")
 		r.databaseReconcileCount++
 	}
+
+	if err = r.migrateSchema(ctx, storageConn, mgh, migrationOptions); err != nil {
+		return false, err
+	}
+	r.schemaMigration = &migrationOptions
 	return false, nil
 }
 
+// migrateSchema moves the database schema to the target version, and reports the result in the mgh condition
+func (r *StorageReconciler) migrateSchema(ctx context.Context, storageConn *config.PostgresConnection,
+	mgh *v1alpha4.MulticlusterGlobalHub, options config.SchemaMigrationOptions,
+) error {
+	plan, err := runSchemaMigration(ctx, storageConn, options)
+	cond := metav1.Condition{
+		Type:   config.CONDITION_TYPE_SCHEMA_MIGRATED,
+		Status: config.CONDITION_STATUS_TRUE,
+		Reason: config.CONDITION_REASON_SCHEMA_MIGRATED,
+	}
+	switch {
+	case err != nil:
+		cond.Status = config.CONDITION_STATUS_FALSE
+		cond.Reason = config.CONDITION_REASON_SCHEMA_MIGRATION_FAILED
+		cond.Message = err.Error()
+	case options.DryRun && !plan.Empty():
+		cond.Status = config.CONDITION_STATUS_FALSE
+		cond.Reason = config.CONDITION_REASON_SCHEMA_MIGRATION_DRYRUN
+		cond.Message = fmt.Sprintf("The schema version is %d, the pending migrations to the version %d: %s",
+			plan.CurrentVersion, plan.TargetVersion, describeSchemaMigrationPlan(plan))
+	default:
+		cond.Message = fmt.Sprintf("The schema version is %d", plan.TargetVersion)
+	}
+	if e := config.UpdateCondition(ctx, r.GetClient(), types.NamespacedName{
+		Namespace: mgh.Namespace,
+		Name:      mgh.Name,
+	}, cond, ""); e != nil {
+		log.Errorf("failed to update the schema migration condition: %v", e)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to migrate the database schema: %w", err)
+	}
+
+	r.schemaVersion = plan.TargetVersion
+	if options.DryRun {
+		r.schemaVersion = plan.CurrentVersion
+		log.Infof("schema migration dry run: %s", cond.Message)
+	}
+	return nil
+}
+
+func runSchemaMigration(ctx context.Context, storageConn *config.PostgresConnection,
+	options config.SchemaMigrationOptions,
+) (*migration.Plan, error) {
+	migrations, err := migration.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	pgConfig, err := database.GetPostgresConfig(storageConn.SuperuserDatabaseURI, storageConn.CACert)
+	if err != nil {
+		return nil, err
+	}
+	db := stdlib.OpenDB(*pgConfig)
+	defer func() {
+		if e := db.Close(); e != nil {
+			log.Errorf("failed to close the migration connection: %v", e)
+		}
+	}()
+	// the advisory lock is held by the session, so the migration uses a single connection
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
+	}
+	defer func() {
+		if e := conn.Close(); e != nil {
+			log.Errorf("failed to close the migration connection: %v", e)
+		}
+	}()
+	return migration.NewMigrator(conn, migrations).Migrate(ctx, options.TargetVersion, options.DryRun)
+}
+
+func describeSchemaMigrationPlan(plan *migration.Plan) string {
+	steps := make([]string, 0, len(plan.Up)+len(plan.Down))
+	for _, m := range plan.Up {
+		steps = append(steps, "up "+m.String())
+	}
+	for _, m := range plan.Down {
+		steps = append(steps, "down "+m.String())
+	}
+	return strings.Join(steps, ", ")
+}
+
 func (r *StorageReconciler) applyGlobalHubInitSQL(ctx context.Context, conn *pgx.Conn, readonlyUserURI string) error {
 	// Check if backup is enabled
 	var backupEnabled bool
@@ -369,14 +476,7 @@ func (r *StorageReconciler) applyGlobalHubInitSQL(ctx context.Context, conn *pgx
 		}
 	}
 
-	if !r.upgrade {
-		err = applySQL(ctx, conn, upgradeFS, "upgrade", readonlyUsername)
-		if err != nil {
-			return fmt.Errorf("failed to apply the upgrade sql: %v", err)
-		}
-		r.upgrade = true
-	}
-
+	r.upgrade = true
 	return nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"context"
 	"embed"
 	"fmt"
+	"reflect"
 	"time"
 
 	kafkav1beta2 "github.com/RedHatInsights/strimzi-client-go/apis/kafka.strimzi.io/v1beta2"
@@ -169,6 +170,24 @@ var kafkaUserPred = predicate.Funcs{
 	},
 }
 
+// kafkaUserSecretPred refreshes the manager transport secret once the certificate of the global hub kafka user is
+// re-issued, e.g. the clients CA is rotated
+var kafkaUserSecretPred = predicate.Funcs{
+	CreateFunc: func(e event.CreateEvent) bool {
+		return false
+	},
+	UpdateFunc: func(e event.UpdateEvent) bool {
+		if e.ObjectNew.GetName() != DefaultGlobalHubKafkaUserName ||
+			e.ObjectNew.GetNamespace() != utils.GetDefaultNamespace() {
+			return false
+		}
+		return !reflect.DeepEqual(e.ObjectNew.(*corev1.Secret).Data, e.ObjectOld.(*corev1.Secret).Data)
+	},
+	DeleteFunc: func(e event.DeleteEvent) bool {
+		return false
+	},
+}
+
 func StartKafkaController(ctx context.Context, mgr ctrl.Manager, transporter transport.Transporter) error {
 	if startedKafkaController {
 		return nil
@@ -189,6 +208,8 @@ func StartKafkaController(ctx context.Context, mgr ctrl.Manager, transporter tra
 			&handler.EnqueueRequestForObject{}, builder.WithPredicates(kafkaUserPred)).
 		Watches(&kafkav1beta2.KafkaTopic{},
 			&handler.EnqueueRequestForObject{}, builder.WithPredicates(kafkaPred)).
+		Watches(&corev1.Secret{},
+			&handler.EnqueueRequestForObject{}, builder.WithPredicates(kafkaUserSecretPred)).
 		Complete(r)
 	if err != nil {
 		return err
@@ -8,6 +8,7 @@ import (
 	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	kafkav1beta2 "github.com/RedHatInsights/strimzi-client-go/apis/kafka.strimzi.io/v1beta2"
 	jsonpatch "github.com/evanphx/json-patch"
@@ -26,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	operatorv1alpha4 "github.com/stolostron/multicluster-global-hub/operator/api/operator/v1alpha4"
+	"github.com/stolostron/multicluster-global-hub/operator/pkg/certificates"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/config"
 	operatorconstants "github.com/stolostron/multicluster-global-hub/operator/pkg/constants"
 	"github.com/stolostron/multicluster-global-hub/operator/pkg/deployer"
@@ -232,6 +234,18 @@ func (k *strimziTransporter) EnsureKafka() (bool, error) {
 		return true, err
 	}
 
+	// the clients CA is provided by global hub instead of the strimzi operator, so that it's rotated with an overlap
+	policy, err := config.GetCertRotationPolicy(k.mgh)
+	if err != nil {
+		return true, err
+	}
+	config.SetCertRotationPolicy(policy)
+	err = certificates.EnsureTransportClientCA(k.ctx, k.manager.GetClient(), k.manager.GetScheme(), k.mgh,
+		k.kafkaClusterName, policy.CALifetime)
+	if err != nil {
+		return true, err
+	}
+
 	err, _ = k.CreateUpdateKafkaCluster(k.mgh)
 	if err != nil {
 		return true, err
@@ -541,6 +555,7 @@ func (k *strimziTransporter) GetConnCredential(clusterName string) (*transport.K
 	// certificates
 	credential.CASecretName = GetClusterCASecret(k.kafkaClusterName)
 	credential.ClientSecretName = config.AgentCertificateSecretName()
+	credential.ClientCAKeyID = config.GetKafkaClientCAKeyID()
 
 	// topics
 	credential.StatusTopic = config.GetStatusTopic(clusterName)
@@ -769,6 +784,7 @@ func (k *strimziTransporter) getKafkaResources(
 }
 
 func (k *strimziTransporter) newKafkaCluster(mgh *operatorv1alpha4.MulticlusterGlobalHub) *kafkav1beta2.Kafka {
+	clientsCa := newClientsCa(config.GetCachedCertRotationPolicy())
 	var nodePort int32 = 30093
 	listeners := []kafkav1beta2.KafkaSpecKafkaListenersElem{
 		{
@@ -844,6 +860,7 @@ func (k *strimziTransporter) newKafkaCluster(mgh *operatorv1alpha4.MulticlusterG
 				TopicOperator: &kafkav1beta2.KafkaSpecEntityOperatorTopicOperator{},
 				UserOperator:  &kafkav1beta2.KafkaSpecEntityOperatorUserOperator{},
 			},
+			ClientsCa: clientsCa,
 		},
 	}
 
@@ -855,6 +872,27 @@ func (k *strimziTransporter) newKafkaCluster(mgh *operatorv1alpha4.MulticlusterG
 	return kafkaCluster
 }
 
+// newClientsCa uses the clients CA rotated by global hub, the user certificates are issued and renewed by the user
+// operator based on the validity and renewal days
+func newClientsCa(policy *config.CertRotationPolicy) *kafkav1beta2.KafkaSpecClientsCa {
+	generateCA := false
+	validityDays := durationDays(policy.CertLifetime)
+	renewalDays := durationDays(policy.CertRenewBefore)
+	return &kafkav1beta2.KafkaSpecClientsCa{
+		GenerateCertificateAuthority: &generateCA,
+		ValidityDays:                 &validityDays,
+		RenewalDays:                  &renewalDays,
+	}
+}
+
+func durationDays(duration time.Duration) int32 {
+	days := int32(duration / (24 * time.Hour))
+	if days < 1 {
+		return 1
+	}
+	return days
+}
+
 // set metricsConfig for kafka cluster based on the mgh enableMetrics
 func (k *strimziTransporter) setMetricsConfig(mgh *operatorv1alpha4.MulticlusterGlobalHub,
 	kafkaCluster *kafkav1beta2.Kafka,
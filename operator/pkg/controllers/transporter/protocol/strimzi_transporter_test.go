@@ -103,6 +103,11 @@ func TestNewKafkaCluster(t *testing.T) {
         }
     },
     "spec": {
+        "clientsCa": {
+            "generateCertificateAuthority": false,
+            "renewalDays": 73,
+            "validityDays": 365
+        },
         "entityOperator": {
             "topicOperator": {},
             "userOperator": {}
@@ -173,6 +178,11 @@ func TestNewKafkaCluster(t *testing.T) {
         }
     },
     "spec": {
+        "clientsCa": {
+            "generateCertificateAuthority": false,
+            "renewalDays": 73,
+            "validityDays": 365
+        },
         "entityOperator": {
             "topicOperator": {},
             "userOperator": {}
@@ -246,6 +256,11 @@ func TestNewKafkaCluster(t *testing.T) {
         }
     },
     "spec": {
+        "clientsCa": {
+            "generateCertificateAuthority": false,
+            "renewalDays": 73,
+            "validityDays": 365
+        },
         "entityOperator": {
             "topicOperator": {},
             "userOperator": {}
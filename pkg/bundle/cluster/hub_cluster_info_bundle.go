@@ -1,10 +1,49 @@
 package cluster
 
+// HubClusterInfoSchemaVersion is the schema version of the HubClusterInfo, the version 2 adds the inventory of the hub,
+// e.g. the versions, capacity, managed cluster counts and addon health. The fields are optional, so the version 1
+// payload is valid in version 2 without conversion.
+const HubClusterInfoSchemaVersion = 2
+
 type HubClusterInfo struct {
 	ConsoleURL string `json:"consoleURL"`
 	GrafanaURL string `json:"grafanaURL"`
 	MchVersion string `json:"mchVersion"`
 	ClusterId  string `json:"clusterId"`
+
+	OpenShiftVersion  string                 `json:"openshiftVersion,omitempty"`
+	KubernetesVersion string                 `json:"kubernetesVersion,omitempty"`
+	MceVersion        string                 `json:"mceVersion,omitempty"`
+	MchComponents     []string               `json:"mchComponents,omitempty"`
+	Capacity          *HubCapacity           `json:"capacity,omitempty"`
+	ManagedClusters   *ManagedClusterCounts  `json:"managedClusters,omitempty"`
+	Addons            map[string]AddonHealth `json:"addons,omitempty"`
+
+	// TransportCertIssuer is the key id of the CA issued the client certificate the agent is connected with
+	TransportCertIssuer string `json:"transportCertIssuer,omitempty"`
+}
+
+// HubCapacity is the total allocatable resources of the nodes in the hub cluster
+type HubCapacity struct {
+	NodeCount                int   `json:"nodeCount"`
+	AllocatableCPUMillicores int64 `json:"allocatableCPUMillicores"`
+	AllocatableMemoryBytes   int64 `json:"allocatableMemoryBytes"`
+}
+
+// ManagedClusterCounts is the number of the managed clusters by the status of the available condition
+type ManagedClusterCounts struct {
+	Total       int `json:"total"`
+	Available   int `json:"available"`
+	Unavailable int `json:"unavailable"`
+	Unknown     int `json:"unknown"`
+}
+
+// AddonHealth is the number of the managed cluster addons of the same name by the health
+type AddonHealth struct {
+	Total       int `json:"total"`
+	Available   int `json:"available"`
+	Degraded    int `json:"degraded"`
+	Unavailable int `json:"unavailable"`
 }
 
 type HubClusterInfoBundle *HubClusterInfo
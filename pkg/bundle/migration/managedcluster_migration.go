@@ -6,6 +6,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
+	migrationv1alpha1 "github.com/stolostron/multicluster-global-hub/operator/api/migration/v1alpha1"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 )
 
@@ -29,6 +30,8 @@ type MigrationSourceBundle struct {
 	// Indicates which stage is being rolled back
 	RollbackStage             string `json:"rollbackStage,omitempty"`
 	RollbackingTimeoutMinutes int    `json:"rollbackingTimeoutMinutes,omitempty"`
+	// DryRun previews the stage without any side effects
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // MigrationTargetBundle defines the resources from migration controllers to the target cluster
@@ -40,6 +43,8 @@ type MigrationTargetBundle struct {
 	ManagedClusters                       []string `json:"managedClusters,omitempty"`
 	RollbackStage                         string   `json:"rollbackStage,omitempty"`
 	RegisteringTimeoutMinutes             int      `json:"registeringTimeoutMinutes,omitempty"`
+	// DryRun previews the stage without any side effects
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 // The bundle sent from the managed hubs to the global hub
@@ -50,11 +55,15 @@ type MigrationStatusBundle struct {
 	Resync          bool              `json:"resync,omitempty"`
 	ManagedClusters []string          `json:"managedClusters,omitempty"`
 	ClusterErrors   map[string]string `json:"clusterErrors,omitempty"`
+	// DryRunReport is the preview of the stage reported by the target hub in the dry run migration
+	DryRunReport *migrationv1alpha1.DryRunReport `json:"dryRunReport,omitempty"`
 }
 
 type MigrationResourceBundle struct {
 	MigrationId               string                     `json:"migrationId"`
 	MigrationClusterResources []MigrationClusterResource `json:"migrationClusterResources"`
+	// DryRun asks the target hub to preview the resources rather than applying them
+	DryRun bool `json:"dryRun,omitempty"`
 }
 
 type MigrationClusterResource struct {
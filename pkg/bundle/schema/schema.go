@@ -0,0 +1,153 @@
+package schema
+
+import (
+	"fmt"
+	"sync"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+const (
+	// ExtSchemaVersion is the extension carrying the schema version of the event data
+	ExtSchemaVersion = "extschemaversion"
+	// LegacyVersion is the schema version of the events without the ExtSchemaVersion, which are emitted by the
+	// agents before the schema versioning is introduced
+	LegacyVersion = 1
+)
+
+// UpcastFunc converts the event data from a schema version to the next version
+type UpcastFunc func(data []byte) ([]byte, error)
+
+// IncompatibleError indicates the event data can't be converted into the schema version supported by the receiver
+type IncompatibleError struct {
+	EventType        string
+	Version          int
+	SupportedVersion int
+	Reason           string
+}
+
+func (e *IncompatibleError) Error() string {
+	return fmt.Sprintf("the schema version %d of event %s is incompatible with the supported version %d: %s",
+		e.Version, enum.ShortenEventType(e.EventType), e.SupportedVersion, e.Reason)
+}
+
+type eventSchema struct {
+	currentVersion int
+	// upcasters[v] converts the data from version v to v+1
+	upcasters map[int]UpcastFunc
+}
+
+// Registry keeps the current schema version of each event type, and the upcasters to convert the event data of the
+// older versions into the current version.
+type Registry struct {
+	mu      sync.RWMutex
+	schemas map[enum.EventType]*eventSchema
+}
+
+func NewRegistry() *Registry {
+	return &Registry{schemas: map[enum.EventType]*eventSchema{}}
+}
+
+// Register sets the current schema version of the event type, the upcaster of each version prior to the current
+// one(from LegacyVersion) must be provided, otherwise the events of that version are incompatible.
+func (r *Registry) Register(eventType enum.EventType, currentVersion int, upcasters map[int]UpcastFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schemas[eventType] = &eventSchema{currentVersion: currentVersion, upcasters: upcasters}
+}
+
+// CurrentVersion returns the current schema version of the event type, it's LegacyVersion if not registered.
+func (r *Registry) CurrentVersion(eventType string) int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if s, found := r.schemas[enum.EventType(eventType)]; found {
+		return s.currentVersion
+	}
+	return LegacyVersion
+}
+
+// Stamp sets the current schema version into the event if it isn't set.
+func (r *Registry) Stamp(evt *cloudevents.Event) {
+	if _, found := evt.Extensions()[ExtSchemaVersion]; found {
+		return
+	}
+	evt.SetExtension(ExtSchemaVersion, r.CurrentVersion(evt.Type()))
+}
+
+// Upcast converts the event data into the current schema version of the event type. It returns IncompatibleError
+// if the event is emitted with a newer version, or there is no upcaster for the older version.
+func (r *Registry) Upcast(evt *cloudevents.Event) error {
+	eventVersion, err := VersionOf(evt)
+	if err != nil {
+		return err
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, found := r.schemas[enum.EventType(evt.Type())]
+	currentVersion := LegacyVersion
+	if found {
+		currentVersion = s.currentVersion
+	}
+
+	if eventVersion == currentVersion {
+		return nil
+	}
+	if eventVersion > currentVersion {
+		return &IncompatibleError{
+			EventType: evt.Type(), Version: eventVersion, SupportedVersion: currentVersion,
+			Reason: "the event is emitted by a newer agent, the manager needs to be upgraded",
+		}
+	}
+
+	data := evt.Data()
+	for v := eventVersion; v < currentVersion; v++ {
+		upcast, ok := s.upcasters[v]
+		if !ok {
+			return &IncompatibleError{
+				EventType: evt.Type(), Version: eventVersion, SupportedVersion: currentVersion,
+				Reason: fmt.Sprintf("no upcaster from version %d, the agent needs to be upgraded", v),
+			}
+		}
+		if data, err = upcast(data); err != nil {
+			return &IncompatibleError{
+				EventType: evt.Type(), Version: eventVersion, SupportedVersion: currentVersion,
+				Reason: fmt.Sprintf("failed to upcast from version %d: %v", v, err),
+			}
+		}
+	}
+	if err := evt.SetData(evt.DataContentType(), data); err != nil {
+		return fmt.Errorf("failed to set the upcasted data: %w", err)
+	}
+	evt.SetExtension(ExtSchemaVersion, currentVersion)
+	return nil
+}
+
+// VersionOf returns the schema version of the event, it's LegacyVersion if the event doesn't carry the version.
+func VersionOf(evt *cloudevents.Event) (int, error) {
+	val, found := evt.Extensions()[ExtSchemaVersion]
+	if !found {
+		return LegacyVersion, nil
+	}
+	version, err := types.ToInteger(val)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse the schema version %v: %w", val, err)
+	}
+	return int(version), nil
+}
+
+// DefaultRegistry is the registry shared by the agent to stamp the emitted events and the manager to upcast the
+// received events. Bump the version of the event type and register the upcaster here once its payload is changed
+// in an incompatible way.
+var DefaultRegistry = NewRegistry()
+
+func init() {
+	// the hub inventory fields of the version 2 are optional, the version 1 payload is kept as is
+	DefaultRegistry.Register(enum.HubClusterInfoType, cluster.HubClusterInfoSchemaVersion, map[int]UpcastFunc{
+		LegacyVersion: func(data []byte) ([]byte, error) { return data, nil },
+	})
+}
@@ -0,0 +1,86 @@
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+func newEvent(t *testing.T, eventType enum.EventType, data string) *cloudevents.Event {
+	evt := cloudevents.NewEvent()
+	evt.SetID("1")
+	evt.SetSource("hub1")
+	evt.SetType(string(eventType))
+	require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, []byte(data)))
+	return &evt
+}
+
+func TestRegistry(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(enum.HubClusterInfoType, 3, map[int]UpcastFunc{
+		1: func(data []byte) ([]byte, error) {
+			return []byte(strings.Replace(string(data), "consoleURL", "consoleUrl", 1)), nil
+		},
+		2: func(data []byte) ([]byte, error) {
+			return []byte(strings.Replace(string(data), "consoleUrl", "consoleURL", 1)), nil
+		},
+	})
+
+	// stamp the current version, and keep the version if it's set
+	evt := newEvent(t, enum.HubClusterInfoType, `{}`)
+	registry.Stamp(evt)
+	require.Equal(t, int32(3), evt.Extensions()[ExtSchemaVersion])
+	evt.SetExtension(ExtSchemaVersion, 2)
+	registry.Stamp(evt)
+	version, err := VersionOf(evt)
+	require.NoError(t, err)
+	require.Equal(t, 2, version)
+
+	// the unregistered event type is at the legacy version
+	evt = newEvent(t, enum.ManagedClusterType, `{}`)
+	registry.Stamp(evt)
+	require.Equal(t, int32(LegacyVersion), evt.Extensions()[ExtSchemaVersion])
+	require.NoError(t, registry.Upcast(evt))
+
+	// the legacy event is upcasted through all the versions
+	evt = newEvent(t, enum.HubClusterInfoType, `{"consoleURL":"https://console"}`)
+	require.NoError(t, registry.Upcast(evt))
+	require.Equal(t, `{"consoleURL":"https://console"}`, string(evt.Data()))
+	version, err = VersionOf(evt)
+	require.NoError(t, err)
+	require.Equal(t, 3, version)
+
+	// the event emitted by a newer agent is incompatible
+	evt = newEvent(t, enum.HubClusterInfoType, `{}`)
+	evt.SetExtension(ExtSchemaVersion, 4)
+	err = registry.Upcast(evt)
+	incompatibleErr := &IncompatibleError{}
+	require.True(t, errors.As(err, &incompatibleErr))
+	require.Equal(t, 4, incompatibleErr.Version)
+	require.Equal(t, 3, incompatibleErr.SupportedVersion)
+
+	// the event of the version without upcaster is incompatible
+	registry.Register(enum.HubClusterInfoType, 3, map[int]UpcastFunc{
+		2: func(data []byte) ([]byte, error) { return data, nil },
+	})
+	evt = newEvent(t, enum.HubClusterInfoType, `{}`)
+	err = registry.Upcast(evt)
+	require.True(t, errors.As(err, &incompatibleErr))
+	require.Equal(t, LegacyVersion, incompatibleErr.Version)
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	// the hub cluster info of the legacy agent is accepted by the manager with the inventory
+	evt := newEvent(t, enum.HubClusterInfoType, `{"consoleURL":"https://console","clusterId":"1"}`)
+	require.NoError(t, DefaultRegistry.Upcast(evt))
+	require.Equal(t, `{"consoleURL":"https://console","clusterId":"1"}`, string(evt.Data()))
+	version, err := VersionOf(evt)
+	require.NoError(t, err)
+	require.Equal(t, cluster.HubClusterInfoSchemaVersion, version)
+}
@@ -2,6 +2,7 @@ package compressor
 
 import (
 	"errors"
+	"sync"
 )
 
 // Compressor declares the functionality provided by the different supported compressors.
@@ -24,16 +25,40 @@ const (
 	NoOp CompressionType = "no-op"
 	// GZip is used to create a gzip-based Compressor.
 	GZip CompressionType = "gzip"
+	// Zstd is used to create a zstd-based Compressor.
+	Zstd CompressionType = "zstd"
+	// Snappy is used to create a snappy-based Compressor.
+	Snappy CompressionType = "snappy"
+	// LZ4 is used to create a lz4-based Compressor.
+	LZ4 CompressionType = "lz4"
 )
 
+var (
+	factoriesLock sync.RWMutex
+	factories     = map[CompressionType]func() Compressor{
+		NoOp:   newNoOpCompressor,
+		GZip:   newGZipCompressor,
+		Zstd:   newZstdCompressor,
+		Snappy: newSnappyCompressor,
+		LZ4:    newLZ4Compressor,
+	}
+)
+
+// RegisterCompressor registers the factory of the compressor with the CompressionType, it overrides the existing
+// one with the same type.
+func RegisterCompressor(compressionType CompressionType, factory func() Compressor) {
+	factoriesLock.Lock()
+	defer factoriesLock.Unlock()
+	factories[compressionType] = factory
+}
+
 // NewCompressor returns a compressor instance that corresponds to the given CompressionType.
 func NewCompressor(compressionType CompressionType) (Compressor, error) {
-	switch compressionType {
-	case NoOp:
-		return newNoOpCompressor(), nil
-	case GZip:
-		return newGZipCompressor(), nil
-	default:
+	factoriesLock.RLock()
+	defer factoriesLock.RUnlock()
+	factory, found := factories[compressionType]
+	if !found {
 		return nil, errCompressionTypeNotFound
 	}
+	return factory(), nil
 }
@@ -2,8 +2,10 @@ package compressor_test
 
 import (
 	"encoding/json"
+	"strings"
 	"testing"
 
+	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/event"
@@ -46,3 +48,55 @@ func prettyMessage(i interface{}) string {
 	s, _ := json.MarshalIndent(i, "", "\t")
 	return string(s)
 }
+
+func TestCompressors(t *testing.T) {
+	payload := []byte(strings.Repeat(`{"name":"cluster1","namespace":"cluster1","status":"Ready"}`, 100))
+	for _, compressionType := range []compressor.CompressionType{
+		compressor.NoOp, compressor.GZip, compressor.Zstd, compressor.Snappy, compressor.LZ4,
+	} {
+		t.Run(string(compressionType), func(t *testing.T) {
+			c, err := compressor.NewCompressor(compressionType)
+			assert.NoError(t, err)
+			assert.Equal(t, string(compressionType), c.GetType())
+
+			compressed, err := c.Compress(payload)
+			assert.NoError(t, err)
+			if compressionType != compressor.NoOp {
+				assert.Less(t, len(compressed), len(payload))
+			}
+
+			decompressed, err := c.Decompress(compressed)
+			assert.NoError(t, err)
+			assert.Equal(t, payload, decompressed)
+		})
+	}
+
+	_, err := compressor.NewCompressor("brotli")
+	assert.Error(t, err)
+}
+
+func TestCompressEvent(t *testing.T) {
+	payload := []byte(strings.Repeat(`{"name":"policy1","compliance":"Compliant"}`, 100))
+
+	evt := cloudevents.NewEvent()
+	evt.SetType("test")
+	assert.NoError(t, evt.SetData(cloudevents.ApplicationJSON, payload))
+
+	// no-op keeps the event as it is
+	assert.NoError(t, compressor.CompressEvent(&evt, compressor.NoOp))
+	assert.NotContains(t, evt.Extensions(), compressor.ExtCompression)
+	assert.Equal(t, payload, evt.Data())
+
+	assert.NoError(t, compressor.CompressEvent(&evt, compressor.Zstd))
+	assert.Equal(t, "zstd", evt.Extensions()[compressor.ExtCompression])
+	assert.Less(t, len(evt.Data()), len(payload))
+
+	assert.NoError(t, compressor.DecompressEvent(&evt))
+	assert.NotContains(t, evt.Extensions(), compressor.ExtCompression)
+	assert.Equal(t, cloudevents.ApplicationJSON, evt.DataContentType())
+	assert.Equal(t, payload, evt.Data())
+
+	// the event without the extension is left as it is
+	assert.NoError(t, compressor.DecompressEvent(&evt))
+	assert.Equal(t, payload, evt.Data())
+}
@@ -0,0 +1,63 @@
+package compressor
+
+import (
+	"fmt"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/types"
+)
+
+const (
+	// ExtCompression is the cloudevents extension carrying the compression type of the event data.
+	ExtCompression = "extcompression"
+	// compressedContentType is the content type of the compressed event data.
+	compressedContentType = "application/octet-stream"
+)
+
+// CompressEvent compresses the event data with the given compression type, and stamps the type into the
+// ExtCompression extension, so the receiver can decompress it per message. The event is left as it is for the
+// NoOp type, which keeps it readable by the receivers without the decompression support.
+func CompressEvent(evt *cloudevents.Event, compressionType CompressionType) error {
+	if compressionType == "" || compressionType == NoOp || len(evt.Data()) == 0 {
+		return nil
+	}
+	compressor, err := NewCompressor(compressionType)
+	if err != nil {
+		return fmt.Errorf("failed to get the %s compressor: %w", compressionType, err)
+	}
+	compressed, err := compressor.Compress(evt.Data())
+	if err != nil {
+		return err
+	}
+	if err := evt.SetData(compressedContentType, compressed); err != nil {
+		return fmt.Errorf("failed to set the compressed data: %w", err)
+	}
+	evt.SetExtension(ExtCompression, compressor.GetType())
+	return nil
+}
+
+// DecompressEvent decompresses the event data based on the ExtCompression extension, then removes the extension.
+// The event without the extension is left as it is.
+func DecompressEvent(evt *cloudevents.Event) error {
+	val, found := evt.Extensions()[ExtCompression]
+	if !found {
+		return nil
+	}
+	compressionType, err := types.ToString(val)
+	if err != nil {
+		return fmt.Errorf("failed to parse the compression type: %w", err)
+	}
+	compressor, err := NewCompressor(CompressionType(compressionType))
+	if err != nil {
+		return fmt.Errorf("failed to get the %s compressor: %w", compressionType, err)
+	}
+	data, err := compressor.Decompress(evt.Data())
+	if err != nil {
+		return err
+	}
+	if err := evt.SetData(cloudevents.ApplicationJSON, data); err != nil {
+		return fmt.Errorf("failed to set the decompressed data: %w", err)
+	}
+	evt.SetExtension(ExtCompression, nil)
+	return nil
+}
@@ -0,0 +1,53 @@
+package compressor
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/pierrec/lz4/v4"
+)
+
+const (
+	lz4CompressorErrorString = "lz4 compressor error"
+	lz4CompressorErrorFormat = "%s - %w"
+	lz4Type                  = "lz4"
+)
+
+// newLZ4Compressor returns a new instance of lz4-based compressor.
+func newLZ4Compressor() Compressor {
+	return &CompressorLZ4{}
+}
+
+// CompressorLZ4 implements Compressor with lz4-based logic.
+type CompressorLZ4 struct{}
+
+// GetType returns the string identifier for lz4 compressor.
+func (compressor *CompressorLZ4) GetType() string {
+	return lz4Type
+}
+
+// Compress compresses a slice of bytes using lz4 lib, the output is in lz4 frame format.
+func (compressor *CompressorLZ4) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writer := lz4.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf(lz4CompressorErrorFormat, lz4CompressorErrorString, err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf(lz4CompressorErrorFormat, lz4CompressorErrorString, err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decompress decompresses a slice of lz4-compressed bytes using lz4 lib.
+func (compressor *CompressorLZ4) Decompress(compressedData []byte) ([]byte, error) {
+	data, err := io.ReadAll(lz4.NewReader(bytes.NewReader(compressedData)))
+	if err != nil {
+		return nil, fmt.Errorf(lz4CompressorErrorFormat, lz4CompressorErrorString, err)
+	}
+	return data, nil
+}
@@ -110,6 +110,10 @@ const (
 	GHStorageSecretName       = "multicluster-global-hub-storage"   // #nosec G101
 	KafkaCertSecretName       = "kafka-certs-secret"                // #nosec G101
 	GHDefaultStorageRetention = "18m"                               // 18 months
+	// the database url of the primary global hub, it's replicated by the standby global hub
+	GHPrimaryDatabaseSecret = "multicluster-global-hub-primary-database" // #nosec G101
+	// the kubeconfig of the witness cluster holding the failover lease, it's required by both the global hubs
+	GHFailoverWitnessSecret = "multicluster-global-hub-failover-witness" // #nosec G101
 )
 
 // the global hub transport config secret for manager and agent
@@ -148,6 +152,25 @@ const (
 	UpgradeKafkaFromZookeeperAnnotation = "global-hub.open-cluster-management.io/upgrade-from-zookeeper"
 	// kafka-cluster-id save the current kafka cluster id
 	KafkaClusterIdAnnotation = "global-hub.open-cluster-management.io/kafka-cluster-id" // #nosec G101
+	// put the managed hub cluster into the planned maintenance if it's "true", remove it to exit the maintenance
+	HubMaintenanceAnnotation = "global-hub.open-cluster-management.io/maintenance"
+	// identify the status of the managed cluster is stale since its managed hub is in maintenance
+	StaleStatusAnnotation = "global-hub.open-cluster-management.io/stale"
+	// request the managed hub cluster to resend the full state of the comma separated event types, e.g.
+	// "managedcluster,policy.localspec", it's removed once the request is recorded
+	HubResyncAnnotation = "global-hub.open-cluster-management.io/resync"
+	// the failover role of the global hub, "active" or "standby", it's set on the MulticlusterGlobalHub, and it's
+	// switched to "active" by the standby manager once it takes over the primary one
+	GHFailoverRoleAnnotation = "global-hub.open-cluster-management.io/failover-role"
+	// the key id of the CA issued the transport client certificate the manager or agent is connected with, it's set
+	// on the transport config secret once reconnected, and on the managed hub cluster by the manager for the agent
+	ClientCertIssuerAnnotation = "global-hub.open-cluster-management.io/client-cert-issuer"
+)
+
+// the failover roles of the global hub
+const (
+	FailoverRoleActive  = "active"
+	FailoverRoleStandby = "standby"
 )
 
 // store all the finalizers
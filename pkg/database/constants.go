@@ -50,6 +50,9 @@ const (
 
 	// SecurityAlertCountsTable is the name of the table for security alert counts.
 	SecurityAlertCountsTable = "alert_counts"
+
+	// SecurityViolationsTable is the name of the table for security violations.
+	SecurityViolationsTable = "violations"
 )
 
 // default values.
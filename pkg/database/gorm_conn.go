@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/url"
 	"sync"
+	"time"
 
 	_ "github.com/lib/pq"
 	"gorm.io/driver/postgres"
@@ -39,6 +40,10 @@ type DatabaseConfig struct {
 	Dialect    string
 	CaCertPath string
 	PoolSize   int
+	// ReplicaURLs are the read replicas of the database, the read-only queries are routed to them
+	ReplicaURLs []string
+	// MaxReplicaLag is the max replication lag of the replicas serving the read-only queries
+	MaxReplicaLag time.Duration
 }
 
 func InitGormInstance(config *DatabaseConfig) error {
@@ -55,6 +60,9 @@ func InitGormInstance(config *DatabaseConfig) error {
 			return err
 		}
 		sqlDB.SetMaxOpenConns(config.PoolSize)
+		if len(config.ReplicaURLs) > 0 {
+			return initReplicas(config)
+		}
 	}
 	return nil
 }
@@ -127,6 +135,12 @@ func Lock(lockConn *sql.Conn) error {
 	if !IsBackupEnabled {
 		return nil
 	}
+	return AdvisoryLock(lockConn)
+}
+
+// AdvisoryLock takes the database lock even if the backup is disabled, it's used by the callers which must exclude
+// the others in any case, e.g. the schema migration
+func AdvisoryLock(lockConn *sql.Conn) error {
 	log.Debug("Add db lock")
 	defer log.Debug("db locked")
 	_, err := lockConn.ExecContext(ctx, "select pg_advisory_lock($1)", constants.LockId)
@@ -137,6 +151,11 @@ func Unlock(lockConn *sql.Conn) {
 	if !IsBackupEnabled {
 		return
 	}
+	AdvisoryUnlock(lockConn)
+}
+
+// AdvisoryUnlock releases the lock taken by the AdvisoryLock
+func AdvisoryUnlock(lockConn *sql.Conn) {
 	log.Debug("unlock db")
 	defer log.Debug("db unlocked")
 	err := retry.OnError(retry.DefaultRetry, func(err error) bool {
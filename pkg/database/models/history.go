@@ -29,3 +29,27 @@ type LocalComplianceHistory struct {
 func (LocalComplianceHistory) TableName() string {
 	return "history.local_compliance"
 }
+
+type LocalComplianceSnapshot struct {
+	SnapshotAt  time.Time `gorm:"column:snapshot_at;not null"`
+	PolicyID    string    `gorm:"column:policy_id;not null"`
+	ClusterID   string    `gorm:"column:cluster_id;not null"`
+	ClusterName string    `gorm:"column:cluster_name;not null"`
+	LeafHubName string    `gorm:"column:leaf_hub_name;not null"`
+	Compliance  string    `gorm:"column:compliance;not null"`
+}
+
+func (LocalComplianceSnapshot) TableName() string {
+	return "history.local_compliance_snapshots"
+}
+
+// LocalComplianceAt is the compliance of the policy on the cluster at a past time, which is rebuilt by the
+// history.get_local_compliance_at function.
+type LocalComplianceAt struct {
+	PolicyID    string    `gorm:"column:policy_id" json:"policyId"`
+	ClusterID   string    `gorm:"column:cluster_id" json:"clusterId"`
+	ClusterName string    `gorm:"column:cluster_name" json:"clusterName"`
+	LeafHubName string    `gorm:"column:leaf_hub_name" json:"leafHubName"`
+	Compliance  string    `gorm:"column:compliance" json:"compliance"`
+	ChangedAt   time.Time `gorm:"column:changed_at" json:"changedAt"`
+}
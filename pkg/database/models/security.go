@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
 
 // SecurityAlertCounts contains a summary of the security alerts from a hub.
 type SecurityAlertCounts struct {
@@ -39,3 +43,75 @@ type SecurityAlertCounts struct {
 func (SecurityAlertCounts) TableName() string {
 	return "security.alert_counts"
 }
+
+// SecurityViolation contains the details of a policy violation reported by the Central instance of a hub.
+type SecurityViolation struct {
+	// HubName is the name of the hub.
+	HubName string `gorm:"column:hub_name;primaryKey" json:"hubName"`
+
+	// Source is the Central CR instance from which the data was retrieved.
+	// This should follow the format: "<namespace>/<name>"
+	Source string `gorm:"column:source;primaryKey" json:"source"`
+
+	// ID is the identifier of the alert in the Central instance.
+	ID string `gorm:"column:id;primaryKey" json:"id"`
+
+	// PolicyID is the identifier of the violated policy.
+	PolicyID string `gorm:"column:policy_id;not null" json:"policyId"`
+
+	// PolicyName is the name of the violated policy.
+	PolicyName string `gorm:"column:policy_name;not null" json:"policyName"`
+
+	// Severity is the severity of the violated policy, for example HIGH_SEVERITY.
+	Severity string `gorm:"column:severity;not null" json:"severity"`
+
+	// Categories are the categories of the violated policy.
+	Categories datatypes.JSON `gorm:"column:categories;type:jsonb" json:"categories"`
+
+	// LifecycleStage is the stage where the violation was detected: DEPLOY, BUILD or RUNTIME.
+	LifecycleStage string `gorm:"column:lifecycle_stage" json:"lifecycleStage"`
+
+	// State is the state of the violation: ACTIVE, SNOOZED, RESOLVED or ATTEMPTED.
+	State string `gorm:"column:state;not null" json:"state"`
+
+	// ClusterName is the name of the cluster where the violation was detected.
+	ClusterName string `gorm:"column:cluster_name" json:"clusterName"`
+
+	// ClusterID is the identifier of the cluster in the Central instance.
+	ClusterID string `gorm:"column:cluster_id" json:"clusterId"`
+
+	// Namespace is the namespace of the violating resource.
+	Namespace string `gorm:"column:namespace" json:"namespace"`
+
+	// DeploymentName is the name of the violating deployment.
+	DeploymentName string `gorm:"column:deployment_name" json:"deploymentName"`
+
+	// DeploymentID is the identifier of the violating deployment.
+	DeploymentID string `gorm:"column:deployment_id" json:"deploymentId"`
+
+	// ResourceType is the type of the violating resource, for example DEPLOYMENT.
+	ResourceType string `gorm:"column:resource_type" json:"resourceType"`
+
+	// EnforcementAction is the action taken to enforce the policy.
+	EnforcementAction string `gorm:"column:enforcement_action" json:"enforcementAction"`
+
+	// EnforcementCount is the number of times the policy was enforced.
+	EnforcementCount int `gorm:"column:enforcement_count;not null" json:"enforcementCount"`
+
+	// DetailURL is the URL where the user can see the details of the violation in the Stackrox Central UI.
+	DetailURL string `gorm:"column:detail_url" json:"detailUrl"`
+
+	// CreatedAt is the date and time when the violation was first seen by the global hub, it isn't changed once the
+	// violation is stored since the table is partitioned by it.
+	CreatedAt time.Time `gorm:"column:created_at;primaryKey" json:"createdAt"`
+
+	// LastSeenAt is the date and time of the latest occurrence of the violation reported by the central.
+	LastSeenAt time.Time `gorm:"column:last_seen_at" json:"lastSeenAt"`
+
+	// UpdatedAt is the date and time when the row was last updated.
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime:true" json:"updatedAt"`
+}
+
+func (SecurityViolation) TableName() string {
+	return "security.violations"
+}
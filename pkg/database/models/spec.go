@@ -12,6 +12,8 @@ type ManagedClusterLabel struct {
 	ManagedClusterName string         `gorm:"column:managed_cluster_name;not null"`
 	Labels             datatypes.JSON `gorm:"column:labels;type:jsonb"`
 	DeletedLabelKeys   datatypes.JSON `gorm:"column:deleted_label_keys;type:jsonb"`
+	LastAppliedLabels  datatypes.JSON `gorm:"column:last_applied_labels;type:jsonb;default:'{}'"`
+	LabelPolicies      datatypes.JSON `gorm:"column:label_policies;type:jsonb;default:'{}'"`
 	Version            int            `gorm:"column:version;not null"`
 	UpdatedAt          time.Time      `gorm:"column:updated_at;autoUpdateTime:true"`
 	// CreatedAt time.Time `gorm:"column:created_at;autoCreateTime:true"`
@@ -22,6 +24,32 @@ func (ManagedClusterLabel) TableName() string {
 	return "spec.managed_clusters_labels"
 }
 
+const (
+	// LabelPolicyHubWins keeps the label value changed on the hub, and adopts it as the global desired value
+	LabelPolicyHubWins = "hub-wins"
+	// LabelPolicyGlobalWins reapplies the global desired value of the label, it's the default policy
+	LabelPolicyGlobalWins = "global-wins"
+)
+
+// LabelConflict is the last conflict of the managed cluster label, the label is changed on the hub since it's
+// applied, and the change is different from the global desired value. The nil value means the label is absent.
+type LabelConflict struct {
+	LeafHubName        string    `gorm:"column:leaf_hub_name;primaryKey" json:"leafHubName"`
+	ManagedClusterName string    `gorm:"column:managed_cluster_name;primaryKey" json:"managedClusterName"`
+	LabelKey           string    `gorm:"column:label_key;primaryKey" json:"labelKey"`
+	DesiredValue       *string   `gorm:"column:desired_value" json:"desiredValue"`
+	LastAppliedValue   *string   `gorm:"column:last_applied_value" json:"lastAppliedValue"`
+	LiveValue          *string   `gorm:"column:live_value" json:"liveValue"`
+	Policy             string    `gorm:"column:policy;not null" json:"policy"`
+	Resolution         string    `gorm:"column:resolution;not null" json:"resolution"`
+	CreatedAt          time.Time `gorm:"column:created_at;autoCreateTime:true" json:"createdAt"`
+	UpdatedAt          time.Time `gorm:"column:updated_at;autoUpdateTime:true" json:"updatedAt"`
+}
+
+func (LabelConflict) TableName() string {
+	return "spec.label_conflicts"
+}
+
 // CREATE TABLE IF NOT EXISTS spec.policies (
 // 	id uuid PRIMARY KEY,
 // 	payload jsonb NOT NULL,
@@ -65,3 +93,16 @@ type SpecPlacementBinding struct {
 func (SpecPlacementBinding) TableName() string {
 	return "spec.placementbindings"
 }
+
+// SpecOutbox is the change of the spec tables recorded by the trigger, it's pending until the DeliveredAt is set.
+type SpecOutbox struct {
+	Seq         int64      `gorm:"column:seq;primaryKey;autoIncrement"`
+	Table       string     `gorm:"column:table_name;not null"`
+	ObjectID    string     `gorm:"column:object_id;not null"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime:true"`
+	DeliveredAt *time.Time `gorm:"column:delivered_at"`
+}
+
+func (SpecOutbox) TableName() string {
+	return "spec.outbox"
+}
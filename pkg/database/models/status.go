@@ -72,6 +72,52 @@ func (Transport) TableName() string {
 	return "status.transport"
 }
 
+// DeadLetter is the event failed to be handled by the conflation handlers, it can be replayed once it's requested.
+type DeadLetter struct {
+	ID                int64          `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	LeafHubName       string         `gorm:"column:leaf_hub_name;not null" json:"leafHubName"`
+	EventType         string         `gorm:"column:event_type;not null" json:"eventType"`
+	EventVersion      string         `gorm:"column:event_version" json:"eventVersion"`
+	Error             string         `gorm:"column:error;not null" json:"error"`
+	Event             datatypes.JSON `gorm:"column:event;type:jsonb" json:"event"`
+	Data              []byte         `gorm:"column:data" json:"-"`
+	CreatedAt         time.Time      `gorm:"column:created_at;autoCreateTime:true" json:"createdAt"`
+	ReplayRequestedAt *time.Time     `gorm:"column:replay_requested_at" json:"replayRequestedAt,omitempty"`
+	ReplayedAt        *time.Time     `gorm:"column:replayed_at" json:"replayedAt,omitempty"`
+}
+
+func (DeadLetter) TableName() string {
+	return "status.dead_letters"
+}
+
+// ResyncRequest is the request to resend the full state of the event type from the leaf hub
+type ResyncRequest struct {
+	ID          int64      `gorm:"column:id;primaryKey;autoIncrement" json:"id"`
+	LeafHubName string     `gorm:"column:leaf_hub_name;not null" json:"leafHubName"`
+	EventType   string     `gorm:"column:event_type;not null" json:"eventType"`
+	RequestedBy string     `gorm:"column:requested_by;not null" json:"requestedBy"`
+	RequestedAt time.Time  `gorm:"column:requested_at;autoCreateTime:true" json:"requestedAt"`
+	SentAt      *time.Time `gorm:"column:sent_at" json:"sentAt,omitempty"`
+	CommittedAt *time.Time `gorm:"column:committed_at" json:"committedAt,omitempty"`
+}
+
+func (ResyncRequest) TableName() string {
+	return "status.resync_requests"
+}
+
+// GlobalHubHeartbeat is the heartbeat of the active global hub manager, it's replicated to the standby global hub to
+// detect the health loss of the active one
+type GlobalHubHeartbeat struct {
+	Name        string     `gorm:"column:name;primaryKey"`
+	Role        string     `gorm:"column:role;not null"`
+	HeartbeatAt time.Time  `gorm:"column:heartbeat_at;autoUpdateTime:false"`
+	PromotedAt  *time.Time `gorm:"column:promoted_at"`
+}
+
+func (GlobalHubHeartbeat) TableName() string {
+	return "status.global_hub_heartbeats"
+}
+
 type LeafHubHeartbeat struct {
 	Name         string    `gorm:"column:leaf_hub_name;primaryKey"`
 	Status       string    `gorm:"column:status;default:(-)"`
@@ -82,6 +128,23 @@ func (LeafHubHeartbeat) TableName() string {
 	return "status.leaf_hub_heartbeats"
 }
 
+// LeafHubHealth is a health sample of the leaf hub
+type LeafHubHealth struct {
+	LeafHubName             string    `gorm:"column:leaf_hub_name;not null"`
+	Score                   int       `gorm:"column:score;not null"`
+	Healthy                 bool      `gorm:"column:healthy;not null"`
+	HeartbeatLatencySeconds float64   `gorm:"column:heartbeat_latency_seconds"`
+	EventLagSeconds         float64   `gorm:"column:event_lag_seconds"`
+	QueueBacklog            int       `gorm:"column:queue_backlog"`
+	HandledEvents           int64     `gorm:"column:handled_events"`
+	FailedEvents            int64     `gorm:"column:failed_events"`
+	CreatedAt               time.Time `gorm:"column:created_at;autoCreateTime:true"`
+}
+
+func (LeafHubHealth) TableName() string {
+	return "status.leaf_hub_health"
+}
+
 func (h LeafHubHeartbeat) UpInsertHeartBeat(db *gorm.DB) error {
 	tmp := `INSERT INTO status.leaf_hub_heartbeats (leaf_hub_name, status, last_timestamp)
 		VALUES ($1, $2, $3) ON CONFLICT (leaf_hub_name) DO UPDATE SET last_timestamp = $3;`
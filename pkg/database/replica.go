@@ -0,0 +1,182 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultMaxReplicaLag is the default max replication lag of the replicas serving the read-only queries
+	DefaultMaxReplicaLag = 30 * time.Second
+	// ReplicaProbeInterval is the interval of checking the health and the replication lag of the replicas
+	ReplicaProbeInterval = 10 * time.Second
+
+	// the lag is 0 if the replica has replayed all the received WAL, otherwise it's the time since the last replayed
+	// transaction. It's -1 if the database isn't in recovery, which means it isn't a replica, and -2 if the WAL
+	// receiver isn't streaming from the primary, e.g. it's disconnected, then the received WAL might be stale. The
+	// status of the receiver is only visible to the superuser or the member of the pg_read_all_stats role.
+	replicationLagQuery = `SELECT CASE
+		WHEN NOT pg_is_in_recovery() THEN -1
+		WHEN NOT EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming') THEN -2
+		WHEN pg_last_wal_receive_lsn() = pg_last_wal_replay_lsn() THEN 0
+		ELSE COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+	END`
+)
+
+// the read replicas of the database, the read-only queries are routed to the healthy ones
+var readReplicas = &replicaSet{maxLag: DefaultMaxReplicaLag}
+
+type replica struct {
+	host    string
+	gormDB  *gorm.DB
+	sqlDB   *sql.DB
+	healthy bool
+}
+
+type replicaSet struct {
+	mutex    sync.RWMutex
+	replicas []*replica
+	maxLag   time.Duration
+	next     atomic.Uint64
+}
+
+func initReplicas(config *DatabaseConfig) error {
+	readReplicas.mutex.Lock()
+	defer readReplicas.mutex.Unlock()
+
+	if config.MaxReplicaLag > 0 {
+		readReplicas.maxLag = config.MaxReplicaLag
+	}
+	for _, replicaURL := range config.ReplicaURLs {
+		gormConn, sqlConn, err := NewGormConn(&DatabaseConfig{
+			URL:        replicaURL,
+			Dialect:    config.Dialect,
+			CaCertPath: config.CaCertPath,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to connect the replica %s: %w", ReplicaHost(replicaURL), err)
+		}
+		sqlConn.SetMaxOpenConns(config.PoolSize)
+		// the replica serves the queries once it's probed as healthy
+		readReplicas.replicas = append(readReplicas.replicas, &replica{
+			host:   ReplicaHost(replicaURL),
+			gormDB: gormConn,
+			sqlDB:  sqlConn,
+		})
+	}
+	return nil
+}
+
+// GetReadGorm returns the connection for the read-only queries, e.g. the rest apis. It's one of the healthy replicas
+// within the max replication lag, or the primary database if there isn't any.
+func GetReadGorm() *gorm.DB {
+	if db := readReplicas.pick(); db != nil {
+		return db
+	}
+	return GetGorm()
+}
+
+func (s *replicaSet) pick() *gorm.DB {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	healthy := make([]*replica, 0, len(s.replicas))
+	for _, r := range s.replicas {
+		if r.healthy {
+			healthy = append(healthy, r)
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+	return healthy[s.next.Add(1)%uint64(len(healthy))].gormDB
+}
+
+// ReplicationLag returns the replication lag of the replica, it fails if the database isn't a replica or it isn't
+// streaming from the primary
+func ReplicationLag(ctx context.Context, db *sql.DB) (time.Duration, error) {
+	var seconds float64
+	if err := db.QueryRowContext(ctx, replicationLagQuery).Scan(&seconds); err != nil {
+		return 0, fmt.Errorf("failed to query the replication lag: %w", err)
+	}
+	return replicationLag(seconds)
+}
+
+func replicationLag(seconds float64) (time.Duration, error) {
+	switch {
+	case seconds == -1:
+		return 0, fmt.Errorf("the database isn't in recovery, it isn't a replica")
+	case seconds < 0:
+		return 0, fmt.Errorf("the replica isn't streaming the WAL from the primary")
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
+}
+
+// ReplicaHost returns the host of the database url without the credential
+func ReplicaHost(databaseURL string) string {
+	urlObj, err := url.Parse(databaseURL)
+	if err != nil {
+		return ""
+	}
+	return urlObj.Host
+}
+
+// MonitorReplicas probes the replicas until the ctx is done, a replica is unhealthy if it's unreachable or the
+// replication lag exceeds the max lag, then the read-only queries fall back to the other replicas or the primary.
+func MonitorReplicas(ctx context.Context, interval time.Duration) {
+	readReplicas.probe(ctx)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			readReplicas.probe(ctx)
+		}
+	}
+}
+
+func (s *replicaSet) probe(ctx context.Context) {
+	s.mutex.RLock()
+	replicas := s.replicas
+	s.mutex.RUnlock()
+
+	for _, r := range replicas {
+		probeCtx, cancel := context.WithTimeout(ctx, ReplicaProbeInterval)
+		lag, err := ReplicationLag(probeCtx, r.sqlDB)
+		cancel()
+		if err == nil && lag > s.maxLag {
+			err = fmt.Errorf("the replication lag %s exceeds the max lag %s", lag, s.maxLag)
+		}
+
+		s.mutex.Lock()
+		if r.healthy != (err == nil) {
+			if err != nil {
+				log.Warnw("the replica is unhealthy, fall back to the primary", "host", r.host, "error", err)
+			} else {
+				log.Infow("the replica is healthy", "host", r.host, "lag", lag)
+			}
+		}
+		r.healthy = err == nil
+		s.mutex.Unlock()
+	}
+}
+
+// CloseReplicas closes the connections of the replicas
+func CloseReplicas() {
+	readReplicas.mutex.Lock()
+	defer readReplicas.mutex.Unlock()
+
+	for _, r := range readReplicas.replicas {
+		CloseGorm(r.sqlDB)
+	}
+	readReplicas.replicas = nil
+}
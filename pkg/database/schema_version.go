@@ -0,0 +1,49 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+const (
+	// SchemaVersion is the version of the database schema expected by the binaries of this release, the operator
+	// migrates the database to it. Bump it once a migration is added to the operator.
+	SchemaVersion = 3
+	// SchemaMigrationsTable records the migrations applied to the database
+	SchemaMigrationsTable = "public.schema_migrations"
+)
+
+// GetSchemaVersion returns the latest migration applied to the database, it's 0 if no migration is applied
+func GetSchemaVersion(ctx context.Context, db *sql.DB) (int, error) {
+	var table sql.NullString
+	if err := db.QueryRowContext(ctx, "SELECT to_regclass($1)::text", SchemaMigrationsTable).
+		Scan(&table); err != nil {
+		return 0, fmt.Errorf("failed to check the table %s: %w", SchemaMigrationsTable, err)
+	}
+	if !table.Valid {
+		return 0, nil
+	}
+
+	var version int
+	if err := db.QueryRowContext(ctx, fmt.Sprintf("SELECT COALESCE(MAX(version), 0) FROM %s",
+		SchemaMigrationsTable)).Scan(&version); err != nil {
+		return 0, fmt.Errorf("failed to get the schema version: %w", err)
+	}
+	return version, nil
+}
+
+// CheckSchemaVersion returns an error if the database schema is older than the SchemaVersion, the binary shouldn't
+// start until the operator migrates the database. A newer schema is allowed, since the migrations are applied before
+// the binaries are rolled out.
+func CheckSchemaVersion(ctx context.Context, db *sql.DB) error {
+	version, err := GetSchemaVersion(ctx, db)
+	if err != nil {
+		return err
+	}
+	if version < SchemaVersion {
+		return fmt.Errorf("the database schema version %d is older than the expected version %d, "+
+			"waiting for the operator to migrate it", version, SchemaVersion)
+	}
+	return nil
+}
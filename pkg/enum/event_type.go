@@ -40,6 +40,7 @@ const (
 
 	// Used to send security alerts:
 	SecurityAlertCountsType EventType = EventTypePrefix + "security.alertcounts"
+	SecurityViolationsType  EventType = EventTypePrefix + "security.violations"
 )
 
 func ShortenEventType(eventType string) string {
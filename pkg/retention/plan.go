@@ -0,0 +1,271 @@
+package retention
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Granularity is the time range of the partitions of a table
+type Granularity string
+
+const (
+	Daily   Granularity = "daily"
+	Weekly  Granularity = "weekly"
+	Monthly Granularity = "monthly"
+
+	// TimeFormat formats the cutoff to compare with the timestamp without time zone columns
+	TimeFormat = "2006-01-02 15:04:05"
+)
+
+func (g Granularity) valid() bool {
+	return g == Daily || g == Weekly || g == Monthly
+}
+
+// Truncate returns the start of the partition containing t, the weekly partitions start on Monday
+func (g Granularity) Truncate(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	switch g {
+	case Daily:
+		return day
+	case Weekly:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// Next returns the start of the next partition of the partition starting at start
+func (g Granularity) Next(start time.Time) time.Time {
+	switch g {
+	case Daily:
+		return start.AddDate(0, 0, 1)
+	case Weekly:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+// PartitionName returns the name of the partition starting at start, e.g. event.managed_clusters_2025_01 for the
+// monthly partition, event.managed_clusters_2025_w02 for the weekly one and event.managed_clusters_2025_01_06 for
+// the daily one
+func (g Granularity) PartitionName(table string, start time.Time) string {
+	switch g {
+	case Daily:
+		return fmt.Sprintf("%s_%s", table, start.Format("2006_01_02"))
+	case Weekly:
+		year, week := start.ISOWeek()
+		return fmt.Sprintf("%s_%d_w%02d", table, year, week)
+	default:
+		return fmt.Sprintf("%s_%s", table, start.Format("2006_01"))
+	}
+}
+
+// Partition is a partition of the table with the range [From, To)
+type Partition struct {
+	Name string
+	From time.Time
+	To   time.Time
+}
+
+var partitionBoundRegex = regexp.MustCompile(`^FOR VALUES FROM \('([^']+)'\) TO \('([^']+)'\)$`)
+
+// ParsePartitionBound parses the range of the partition from the bound expression, e.g.
+// FOR VALUES FROM ('2025-01-01 00:00:00') TO ('2025-02-01 00:00:00'). It returns false for the default partition.
+func ParsePartitionBound(name, bound string, loc *time.Location) (Partition, bool) {
+	matches := partitionBoundRegex.FindStringSubmatch(bound)
+	if matches == nil {
+		return Partition{}, false
+	}
+	partition := Partition{Name: name}
+	for i, value := range matches[1:] {
+		var parsed time.Time
+		var err error
+		for _, layout := range []string{TimeFormat, time.DateOnly} {
+			if parsed, err = time.ParseInLocation(layout, value, loc); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			return Partition{}, false
+		}
+		if i == 0 {
+			partition.From = parsed
+		} else {
+			partition.To = parsed
+		}
+	}
+	return partition, true
+}
+
+// Missing returns the partitions to create for covering [from, to) with the granularity. The ranges covered by the
+// existing partitions, e.g. the ones created before the granularity is changed, are skipped, and the partitions of
+// the remaining gaps are named by their ranges.
+func Missing(table string, g Granularity, from, to time.Time, existing []Partition) []Partition {
+	missing := []Partition{}
+	for start := g.Truncate(from); start.Before(to); start = g.Next(start) {
+		end := g.Next(start)
+		for _, gap := range gaps(start, end, existing) {
+			if gap.From.Equal(start) && gap.To.Equal(end) {
+				gap.Name = g.PartitionName(table, start)
+			} else {
+				gap.Name = fmt.Sprintf("%s_%s_%s", table, gap.From.Format("20060102"), gap.To.Format("20060102"))
+			}
+			missing = append(missing, gap)
+		}
+	}
+	return missing
+}
+
+// gaps returns the ranges within [from, to) which aren't covered by the partitions
+func gaps(from, to time.Time, partitions []Partition) []Partition {
+	result := []Partition{{From: from, To: to}}
+	for _, p := range partitions {
+		next := []Partition{}
+		for _, r := range result {
+			if !p.From.Before(r.To) || !r.From.Before(p.To) {
+				next = append(next, r)
+				continue
+			}
+			if r.From.Before(p.From) {
+				next = append(next, Partition{From: r.From, To: p.From})
+			}
+			if p.To.Before(r.To) {
+				next = append(next, Partition{From: p.To, To: r.To})
+			}
+		}
+		result = next
+	}
+	return result
+}
+
+// Rule deletes the rows in the scope older than the cutoff, except the rows of the more specific rules
+type Rule struct {
+	Hub       string
+	EventType string
+	Cutoff    time.Time
+	// Excludes are the scopes of the more specific rules overlapping with the rule
+	Excludes []Scope
+}
+
+// Where returns the condition of the rows deleted by the rule
+func (r Rule) Where(t Table) (string, []interface{}) {
+	conditions := []string{t.TimeColumn + " < ?"}
+	args := []interface{}{r.Cutoff.Format(TimeFormat)}
+	if r.Hub != "" {
+		conditions = append(conditions, t.HubColumn+" = ?")
+		args = append(args, r.Hub)
+	}
+	if r.EventType != "" {
+		conditions = append(conditions, t.ReasonColumn+" = ?")
+		args = append(args, r.EventType)
+	}
+	for _, exclude := range r.Excludes {
+		matches := []string{}
+		if exclude.Hub != "" {
+			matches = append(matches, t.HubColumn+" IS NOT DISTINCT FROM ?")
+			args = append(args, exclude.Hub)
+		}
+		if exclude.EventType != "" {
+			matches = append(matches, t.ReasonColumn+" IS NOT DISTINCT FROM ?")
+			args = append(args, exclude.EventType)
+		}
+		conditions = append(conditions, "NOT ("+strings.Join(matches, " AND ")+")")
+	}
+	return strings.Join(conditions, " AND "), args
+}
+
+// Plan is how the data retention job cleans up a table
+type Plan struct {
+	Table       Table
+	Granularity Granularity
+	// DropBefore is the time the partitions ending before or at it are dropped, it's zero for the tables without
+	// partitions
+	DropBefore time.Time
+	// Rules delete the expired rows which aren't dropped with the partitions
+	Rules []Rule
+}
+
+// Resolve plans the clean up of the tables at now, the data is retained for the default period unless a policy
+// overrides it. The cutoffs are aligned to the start of the partitions, so the partitioned tables keep the whole
+// partitions within the retention.
+func Resolve(now time.Time, defaultPeriod Period, policies []Policy) ([]Plan, error) {
+	plans := []Plan{}
+	for _, t := range Tables {
+		plan := Plan{Table: t, Granularity: Monthly}
+		period := defaultPeriod
+		scoped := map[Scope]Policy{}
+		for _, p := range policies {
+			if !p.appliesTo(t) {
+				continue
+			}
+			if p.scope() == (Scope{}) {
+				var err error
+				if period, err = ParsePeriod(p.Retention); err != nil {
+					return nil, err
+				}
+				if p.PartitionGranularity != "" {
+					plan.Granularity = p.PartitionGranularity
+				}
+				continue
+			}
+			// the policy of the table overrides the one of all the tables
+			if existing, ok := scoped[p.scope()]; ok && existing.Table != "" {
+				continue
+			}
+			scoped[p.scope()] = p
+		}
+
+		start := plan.Granularity.Truncate(now)
+		rules := []Rule{{Cutoff: period.Before(start)}}
+		for s, p := range scoped {
+			scopedPeriod, err := ParsePeriod(p.Retention)
+			if err != nil {
+				return nil, err
+			}
+			rules = append(rules, Rule{Hub: s.Hub, EventType: s.EventType, Cutoff: scopedPeriod.Before(start)})
+		}
+
+		// the partitions are dropped once all the rows are expired
+		if t.Partitioned {
+			plan.DropBefore = rules[0].Cutoff
+			for _, r := range rules {
+				if r.Cutoff.Before(plan.DropBefore) {
+					plan.DropBefore = r.Cutoff
+				}
+			}
+		}
+		for _, r := range rules {
+			if t.Partitioned && !r.Cutoff.After(plan.DropBefore) {
+				continue
+			}
+			rScope := Scope{Hub: r.Hub, EventType: r.EventType}
+			for s := range scoped {
+				if s.specificity() > rScope.specificity() && s.overlaps(rScope) {
+					r.Excludes = append(r.Excludes, s)
+				}
+			}
+			plan.Rules = append(plan.Rules, r)
+		}
+		sortRules(plan.Rules)
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// sortRules orders the rules and their excludes by the scopes, so the plans are stable
+func sortRules(rules []Rule) {
+	less := func(a, b Scope) bool {
+		return a.Hub < b.Hub || (a.Hub == b.Hub && a.EventType < b.EventType)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return less(Scope{Hub: rules[i].Hub, EventType: rules[i].EventType},
+			Scope{Hub: rules[j].Hub, EventType: rules[j].EventType})
+	})
+	for _, r := range rules {
+		sort.Slice(r.Excludes, func(i, j int) bool { return less(r.Excludes[i], r.Excludes[j]) })
+	}
+}
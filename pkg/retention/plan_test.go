@@ -0,0 +1,211 @@
+package retention
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		retention string
+		want      Period
+		wantErr   bool
+	}{
+		{retention: "18m", want: Period{Months: 18}},
+		{retention: "2y", want: Period{Months: 24}},
+		{retention: "1y6m", want: Period{Months: 18}},
+		{retention: "2w", want: Period{Days: 14}},
+		{retention: "1m10d", want: Period{Months: 1, Days: 10}},
+		{retention: "", wantErr: true},
+		{retention: "0m", wantErr: true},
+		{retention: "6m1y", wantErr: true},
+		{retention: "1h", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.retention, func(t *testing.T) {
+			got, err := ParsePeriod(tt.retention)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		policies []Policy
+		wantErr  string
+	}{
+		{
+			name: "valid",
+			policies: []Policy{
+				{Table: "history.local_compliance", Retention: "24m"},
+				{Table: "event.managed_clusters", Retention: "1m", PartitionGranularity: Daily},
+				{EventType: "PolicyStatusSync", Retention: "3m"},
+				{Hub: "hub1", Retention: "6m"},
+			},
+		},
+		{
+			name:     "unknown table",
+			policies: []Policy{{Table: "status.transport", Retention: "1m"}},
+			wantErr:  "isn't retained",
+		},
+		{
+			name:     "event type of the table without events",
+			policies: []Policy{{Table: "history.local_compliance", EventType: "PolicyStatusSync", Retention: "1m"}},
+			wantErr:  "doesn't store the events",
+		},
+		{
+			name:     "granularity of the soft deleted table",
+			policies: []Policy{{Table: "status.leaf_hubs", Retention: "1m", PartitionGranularity: Weekly}},
+			wantErr:  "only applies to a partitioned table",
+		},
+		{
+			name:     "granularity of the hub",
+			policies: []Policy{{Table: "event.managed_clusters", Hub: "hub1", Retention: "1m", PartitionGranularity: Daily}},
+			wantErr:  "only applies to a partitioned table",
+		},
+		{
+			name:     "no selector",
+			policies: []Policy{{Retention: "1m"}},
+			wantErr:  "is required",
+		},
+		{
+			name: "duplicated",
+			policies: []Policy{
+				{Hub: "hub1", Retention: "1m"},
+				{Hub: "hub1", Retention: "2m"},
+			},
+			wantErr: "select the same data",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.policies)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestGranularity(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 1, 8, 13, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC), Daily.Truncate(now))
+	assert.Equal(t, time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC), Weekly.Truncate(now))
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), Monthly.Truncate(now))
+
+	assert.Equal(t, "event.managed_clusters_2025_01_08", Daily.PartitionName("event.managed_clusters",
+		Daily.Truncate(now)))
+	assert.Equal(t, "event.managed_clusters_2025_w02", Weekly.PartitionName("event.managed_clusters",
+		Weekly.Truncate(now)))
+	assert.Equal(t, "event.managed_clusters_2025_01", Monthly.PartitionName("event.managed_clusters",
+		Monthly.Truncate(now)))
+}
+
+func TestParsePartitionBound(t *testing.T) {
+	p, ok := ParsePartitionBound("event.managed_clusters_2025_01",
+		"FOR VALUES FROM ('2025-01-01 00:00:00') TO ('2025-02-01 00:00:00')", time.UTC)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), p.From)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), p.To)
+
+	p, ok = ParsePartitionBound("history.local_compliance_2025_01",
+		"FOR VALUES FROM ('2025-01-01') TO ('2025-02-01')", time.UTC)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), p.To)
+
+	_, ok = ParsePartitionBound("event.managed_clusters_default", "DEFAULT", time.UTC)
+	assert.False(t, ok)
+}
+
+func TestMissing(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 0, 0, 0, 0, time.UTC) }
+
+	// the monthly partitions of the current and the next month
+	missing := Missing("event.managed_clusters", Monthly, day(1, 8), day(3, 1), []Partition{
+		{Name: "event.managed_clusters_2025_01", From: day(1, 1), To: day(2, 1)},
+	})
+	assert.Equal(t, []Partition{
+		{Name: "event.managed_clusters_2025_02", From: day(2, 1), To: day(3, 1)},
+	}, missing)
+
+	// switch the monthly partitions to the weekly ones, the gap between the monthly partition and the next week is
+	// named by its range
+	missing = Missing("event.managed_clusters", Weekly, day(1, 27), day(2, 11), []Partition{
+		{Name: "event.managed_clusters_2025_01", From: day(1, 1), To: day(2, 1)},
+	})
+	assert.Equal(t, []Partition{
+		{Name: "event.managed_clusters_20250201_20250203", From: day(2, 1), To: day(2, 3)},
+		{Name: "event.managed_clusters_2025_w06", From: day(2, 3), To: day(2, 10)},
+		{Name: "event.managed_clusters_2025_w07", From: day(2, 10), To: day(2, 17)},
+	}, missing)
+}
+
+func TestResolve(t *testing.T) {
+	now := time.Date(2025, 6, 18, 10, 0, 0, 0, time.UTC)
+	month := func(year int, m time.Month) time.Time { return time.Date(year, m, 1, 0, 0, 0, 0, time.UTC) }
+
+	plans, err := Resolve(now, Period{Months: 18}, []Policy{
+		{Table: "history.local_compliance", Retention: "24m"},
+		{Table: "event.managed_clusters", Retention: "1m", PartitionGranularity: Weekly},
+		{Table: "event.managed_clusters", Hub: "hub1", Retention: "3m"},
+		{EventType: "PolicyStatusSync", Retention: "6m"},
+		{Hub: "hub2", Retention: "2m"},
+	})
+	require.NoError(t, err)
+	byName := map[string]Plan{}
+	for _, plan := range plans {
+		byName[plan.Table.Name] = plan
+	}
+
+	// the table policy keeps the history longer than the default
+	compliance := byName["history.local_compliance"]
+	assert.Equal(t, Monthly, compliance.Granularity)
+	assert.Equal(t, month(2023, 6), compliance.DropBefore)
+	assert.Equal(t, []Rule{
+		{Hub: "hub2", Cutoff: month(2025, 4)},
+	}, compliance.Rules)
+
+	// the weekly partitions are kept for the longest retention of the hub, the rows of the other hubs expire earlier
+	week := time.Date(2025, 6, 16, 0, 0, 0, 0, time.UTC)
+	clusterEvents := byName["event.managed_clusters"]
+	assert.Equal(t, Weekly, clusterEvents.Granularity)
+	assert.Equal(t, week.AddDate(0, -6, 0), clusterEvents.DropBefore)
+	assert.Equal(t, []Rule{
+		{Cutoff: week.AddDate(0, -1, 0), Excludes: []Scope{{EventType: "PolicyStatusSync"}, {Hub: "hub1"}, {Hub: "hub2"}}},
+		{Hub: "hub1", Cutoff: week.AddDate(0, -3, 0)},
+		{Hub: "hub2", Cutoff: week.AddDate(0, -2, 0)},
+	}, clusterEvents.Rules)
+
+	// the soft deleted records
+	leafHubs := byName["status.leaf_hubs"]
+	assert.True(t, leafHubs.DropBefore.IsZero())
+	assert.Equal(t, []Rule{
+		{Cutoff: month(2023, 12), Excludes: []Scope{{Hub: "hub2"}}},
+		{Hub: "hub2", Cutoff: month(2025, 4)},
+	}, leafHubs.Rules)
+
+	// the dead letters are deleted by the creation time
+	deadLetters := byName["status.dead_letters"]
+	where, args := deadLetters.Rules[0].Where(deadLetters.Table)
+	assert.Equal(t, "created_at < ? AND NOT (leaf_hub_name IS NOT DISTINCT FROM ?)", where)
+	assert.Equal(t, []interface{}{"2023-12-01 00:00:00", "hub2"}, args)
+	assert.NotContains(t, TableNames(false), "status.dead_letters")
+
+	where, args = clusterEvents.Rules[0].Where(clusterEvents.Table)
+	assert.Equal(t, "created_at < ? AND NOT (reason IS NOT DISTINCT FROM ?) AND "+
+		"NOT (leaf_hub_name IS NOT DISTINCT FROM ?) AND NOT (leaf_hub_name IS NOT DISTINCT FROM ?)", where)
+	assert.Equal(t, []interface{}{"2025-05-16 00:00:00", "PolicyStatusSync", "hub1", "hub2"}, args)
+}
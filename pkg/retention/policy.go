@@ -0,0 +1,193 @@
+package retention
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+// Table is the table cleaned up by the data retention job
+type Table struct {
+	Name string
+	// TimeColumn is the partition key of the partitioned table, or the deleted_at of the soft deleted records
+	TimeColumn string
+	HubColumn  string
+	// ReasonColumn is the column of the event type, it's empty if the table doesn't store the events
+	ReasonColumn string
+	Partitioned  bool
+}
+
+// softDeletedColumn is the time column of the tables with the soft deleted records
+const softDeletedColumn = "deleted_at"
+
+// Tables are the tables growing over time, the partitioned tables drop the expired partitions, and the others
+// delete the soft deleted records, or the records created before the retention
+var Tables = []Table{
+	{"event.local_policies", "created_at", "leaf_hub_name", "reason", true},
+	{"event.local_root_policies", "created_at", "leaf_hub_name", "reason", true},
+	{"history.local_compliance", "compliance_date", "leaf_hub_name", "", true},
+	{"event.managed_clusters", "created_at", "leaf_hub_name", "reason", true},
+	{"security.violations", "created_at", "hub_name", "", true},
+	{"history.local_compliance_snapshots", "snapshot_at", "leaf_hub_name", "", true},
+	{"status.leaf_hub_health", "created_at", "leaf_hub_name", "", true},
+	{"status.managed_clusters", "deleted_at", "leaf_hub_name", "", false},
+	{"status.leaf_hubs", "deleted_at", "leaf_hub_name", "", false},
+	{"local_spec.policies", "deleted_at", "leaf_hub_name", "", false},
+	{"status.dead_letters", "created_at", "leaf_hub_name", "", false},
+}
+
+// TableNames returns the names of the partitioned tables, or the tables with the soft deleted records
+func TableNames(partitioned bool) []string {
+	names := []string{}
+	for _, t := range Tables {
+		if t.Partitioned == partitioned && (partitioned || t.TimeColumn == softDeletedColumn) {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
+func getTable(name string) (Table, bool) {
+	for _, t := range Tables {
+		if t.Name == name {
+			return t, true
+		}
+	}
+	return Table{}, false
+}
+
+// Period is how long the data is retained, e.g. 1y6m, 4w or 30d
+type Period struct {
+	Months int
+	Days   int
+}
+
+var periodRegex = regexp.MustCompile(`^(?:(\d+)y)?(?:(\d+)m)?(?:(\d+)w)?(?:(\d+)d)?$`)
+
+func ParsePeriod(s string) (Period, error) {
+	matches := periodRegex.FindStringSubmatch(s)
+	if s == "" || matches == nil {
+		return Period{}, fmt.Errorf("invalid retention %q, the valid units are y, m, w and d", s)
+	}
+	values := make([]int, 4)
+	for i, match := range matches[1:] {
+		if match == "" {
+			continue
+		}
+		value, err := strconv.Atoi(match)
+		if err != nil {
+			return Period{}, fmt.Errorf("invalid retention %q: %w", s, err)
+		}
+		values[i] = value
+	}
+	period := Period{Months: values[0]*12 + values[1], Days: values[2]*7 + values[3]}
+	if period.Months == 0 && period.Days == 0 {
+		return Period{}, fmt.Errorf("the retention %q must be positive", s)
+	}
+	return period, nil
+}
+
+// Before returns the time the period before t
+func (p Period) Before(t time.Time) time.Time {
+	return t.AddDate(0, -p.Months, -p.Days)
+}
+
+// Policy overrides the default retention of a table, the events of a type, or the data of a hub. The table level
+// policy also chooses the partition granularity of the partitioned table.
+type Policy struct {
+	// Table is the name of the table, the policy applies to all the tables of the event type or the hub if it's empty
+	Table string `json:"table,omitempty"`
+	// EventType is the reason of the events, e.g. PolicyStatusSync
+	EventType            string      `json:"eventType,omitempty"`
+	Hub                  string      `json:"hub,omitempty"`
+	Retention            string      `json:"retention"`
+	PartitionGranularity Granularity `json:"partitionGranularity,omitempty"`
+}
+
+// Scope identifies the rows of a table a policy applies to, the empty scope is the whole table
+type Scope struct {
+	Hub       string
+	EventType string
+}
+
+func (p Policy) scope() Scope {
+	return Scope{Hub: p.Hub, EventType: p.EventType}
+}
+
+// specificity decides the policy of the rows in the scopes of multiple policies, the hub overrides the event type
+func (s Scope) specificity() int {
+	specificity := 0
+	if s.Hub != "" {
+		specificity += 2
+	}
+	if s.EventType != "" {
+		specificity++
+	}
+	return specificity
+}
+
+// overlaps returns whether a row can be in both scopes
+func (s Scope) overlaps(o Scope) bool {
+	return (s.Hub == "" || o.Hub == "" || s.Hub == o.Hub) &&
+		(s.EventType == "" || o.EventType == "" || s.EventType == o.EventType)
+}
+
+func (p Policy) appliesTo(t Table) bool {
+	if p.Table != "" && p.Table != t.Name {
+		return false
+	}
+	return p.EventType == "" || t.ReasonColumn != ""
+}
+
+// Validate checks the policies, the policy must select the table, the event type or the hub, and a scope of the table
+// can't be selected by multiple policies
+func Validate(policies []Policy) error {
+	selected := map[string]int{}
+	for i, p := range policies {
+		if _, err := ParsePeriod(p.Retention); err != nil {
+			return fmt.Errorf("retention policy %d: %w", i, err)
+		}
+		if p.Table == "" && p.EventType == "" && p.Hub == "" {
+			return fmt.Errorf("retention policy %d: one of the table, the event type or the hub is required", i)
+		}
+		if p.Table != "" {
+			t, ok := getTable(p.Table)
+			if !ok {
+				return fmt.Errorf("retention policy %d: the table %s isn't retained by the retention job", i, p.Table)
+			}
+			if p.EventType != "" && t.ReasonColumn == "" {
+				return fmt.Errorf("retention policy %d: the table %s doesn't store the events", i, p.Table)
+			}
+		}
+		if p.PartitionGranularity != "" {
+			if !p.PartitionGranularity.valid() {
+				return fmt.Errorf("retention policy %d: invalid partition granularity %s", i,
+					p.PartitionGranularity)
+			}
+			if t, ok := getTable(p.Table); !ok || !t.Partitioned || p.EventType != "" || p.Hub != "" {
+				return fmt.Errorf("retention policy %d: the partition granularity only applies to a partitioned table",
+					i)
+			}
+		}
+		key := fmt.Sprintf("%s/%s/%s", p.Table, p.EventType, p.Hub)
+		if j, ok := selected[key]; ok {
+			return fmt.Errorf("retention policies %d and %d select the same data", j, i)
+		}
+		selected[key] = i
+	}
+	return nil
+}
+
+// ParsePolicies parses and validates the policies in json
+func ParsePolicies(data string) ([]Policy, error) {
+	if data == "" {
+		return nil, nil
+	}
+	policies := []Policy{}
+	if err := json.Unmarshal([]byte(data), &policies); err != nil {
+		return nil, fmt.Errorf("failed to parse the retention policies: %w", err)
+	}
+	return policies, Validate(policies)
+}
@@ -0,0 +1,67 @@
+package statistics
+
+import (
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+)
+
+// HubHealthSample is the health signals of the leaf hub collected by the status path since the last collection.
+type HubHealthSample struct {
+	// QueueBacklog is the latest number of the ready events of the hub waiting in the conflation ready queue
+	QueueBacklog int
+	// MaxEventLag is the max duration from the time of the event to the time the event is handled into database
+	MaxEventLag time.Duration
+	// HandledEvents is the number of the events of the hub handled by the db workers
+	HandledEvents int64
+	// FailedEvents is the number of the events of the hub failed to be handled
+	FailedEvents int64
+}
+
+func (s *Statistics) hubHealthSample(hubName string) *HubHealthSample {
+	if s.hubHealth == nil {
+		s.hubHealth = map[string]*HubHealthSample{}
+	}
+	sample, ok := s.hubHealth[hubName]
+	if !ok {
+		sample = &HubHealthSample{}
+		s.hubHealth[hubName] = sample
+	}
+	return sample
+}
+
+func (s *Statistics) setHubQueueBacklog(hubName string, backlog int) {
+	s.hubHealthMutex.Lock()
+	defer s.hubHealthMutex.Unlock()
+	s.hubHealthSample(hubName).QueueBacklog = backlog
+}
+
+// addHubHandledEvent records the lag and the result of the handled event of the hub.
+func (s *Statistics) addHubHandledEvent(evt *cloudevents.Event, err error) {
+	s.hubHealthMutex.Lock()
+	defer s.hubHealthMutex.Unlock()
+	sample := s.hubHealthSample(evt.Source())
+	sample.HandledEvents++
+	if err != nil {
+		sample.FailedEvents++
+	}
+	if evt.Time().IsZero() {
+		return
+	}
+	if lag := time.Since(evt.Time()); lag > sample.MaxEventLag {
+		sample.MaxEventLag = lag
+	}
+}
+
+// CollectHubHealthSamples returns the health samples of the hubs since the last collection, and resets the counters
+// of the samples. The queue backlog is kept since it's a gauge.
+func (s *Statistics) CollectHubHealthSamples() map[string]HubHealthSample {
+	s.hubHealthMutex.Lock()
+	defer s.hubHealthMutex.Unlock()
+	samples := make(map[string]HubHealthSample, len(s.hubHealth))
+	for hubName, sample := range s.hubHealth {
+		samples[hubName] = *sample
+		s.hubHealth[hubName] = &HubHealthSample{QueueBacklog: sample.QueueBacklog}
+	}
+	return samples
+}
@@ -0,0 +1,71 @@
+package statistics
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	hubQueueDepthGaugeVec = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_conflation_queue_depth",
+			Help: "The number of the ready events of the leaf hub waiting to be dispatched to the db workers.",
+		},
+		[]string{"hub"},
+	)
+	hubQueueWaitHistogramVec = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "multicluster_global_hub_conflation_queue_wait_seconds",
+			Help:    "The time the ready events of the leaf hub wait in the queue before being dispatched.",
+			Buckets: prometheus.ExponentialBuckets(0.001, 4, 10),
+		},
+		[]string{"hub"},
+	)
+	hubInFlightGaugeVec = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "multicluster_global_hub_conflation_inflight_jobs",
+			Help: "The number of the jobs of the leaf hub being handled by the db workers.",
+		},
+		[]string{"hub"},
+	)
+	hubSpilledJobsCounterVec = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "multicluster_global_hub_conflation_spilled_jobs_total",
+			Help: "The number of the delta events of the leaf hub spilled into the dead letters to be replayed later.",
+		},
+		[]string{"hub"},
+	)
+	registerHubMetricsOnce sync.Once
+)
+
+// RegisterHubMetrics registers the per hub metrics of the conflation ready queue with the global prometheus registry
+func RegisterHubMetrics() {
+	registerHubMetricsOnce.Do(func() {
+		metrics.Registry.MustRegister(hubQueueDepthGaugeVec, hubQueueWaitHistogramVec, hubInFlightGaugeVec,
+			hubSpilledJobsCounterVec)
+	})
+}
+
+// SetHubQueueDepth sets the number of the ready events of the hub in the conflation ready queue.
+func (s *Statistics) SetHubQueueDepth(hubName string, depth int) {
+	hubQueueDepthGaugeVec.WithLabelValues(hubName).Set(float64(depth))
+	s.setHubQueueBacklog(hubName, depth)
+}
+
+// ObserveHubQueueWait records the time an event of the hub waited in the conflation ready queue.
+func (s *Statistics) ObserveHubQueueWait(hubName string, wait time.Duration) {
+	hubQueueWaitHistogramVec.WithLabelValues(hubName).Observe(wait.Seconds())
+}
+
+// SetHubInFlight sets the number of the jobs of the hub being handled by the db workers.
+func (s *Statistics) SetHubInFlight(hubName string, inFlight int) {
+	hubInFlightGaugeVec.WithLabelValues(hubName).Set(float64(inFlight))
+}
+
+// IncrementHubSpilledJobs counts the delta event of the hub spilled into the dead letters to be replayed later.
+func (s *Statistics) IncrementHubSpilledJobs(hubName string) {
+	hubSpilledJobsCounterVec.WithLabelValues(hubName).Inc()
+}
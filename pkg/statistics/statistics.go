@@ -36,6 +36,9 @@ type Statistics struct {
 	eventMetrics             map[string]*eventMetrics
 	logInterval              string
 	mutex                    sync.Mutex
+	// hubHealth is the health signals of each hub, it has its own lock since it's updated by the ready queue lock held
+	hubHealth      map[string]*HubHealthSample
+	hubHealthMutex sync.Mutex
 }
 
 func (s *Statistics) Register(eventType string) {
@@ -61,6 +64,8 @@ func (s *Statistics) SetNumberOfAvailableDBWorkers(numOf int) {
 
 // SetConflationReadyQueueSize sets conflation ready queue size.
 func (s *Statistics) SetConflationReadyQueueSize(size int) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	s.conflationReadyQueueSize = size
 }
 
@@ -95,6 +100,8 @@ func (s *Statistics) IncrementNumberOfConflations() {
 
 // AddDatabaseMetrics adds database metrics of the specific event type.
 func (s *Statistics) AddDatabaseMetrics(evt *cloudevents.Event, duration time.Duration, err error) {
+	s.addHubHandledEvent(evt, err)
+
 	s.mutex.Lock()
 	defer s.mutex.Unlock()
 	eventMetrics, ok := s.eventMetrics[evt.Type()]
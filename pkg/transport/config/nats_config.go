@@ -0,0 +1,78 @@
+package config
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/kustomize/kyaml/yaml"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+	"github.com/stolostron/multicluster-global-hub/pkg/utils"
+)
+
+// NatsHeaderReserve is the bytes reserved for the cloudevents headers when chunking the payload into the
+// nats message, the remaining of the server max_payload is used to carry the data.
+const NatsHeaderReserve = 64 * 1024
+
+func GetNatsConnBySecret(transportSecret *corev1.Secret, c client.Client) (*transport.NatsConfig, error) {
+	natsYaml, ok := transportSecret.Data["nats.yaml"]
+	if !ok {
+		return nil, fmt.Errorf("must set the `nats.yaml` in the transport secret(%s)", transportSecret.Name)
+	}
+	conn := &transport.NatsConfig{}
+	if err := yaml.Unmarshal(natsYaml, conn); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal nats config to transport credentail: %w", err)
+	}
+
+	err := utils.DecodeTransportCertificate(transportSecret.Namespace, c, conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the cert credentail: %w", err)
+	}
+	return conn, nil
+}
+
+// GetNatsConnection connects to the nats server with the credential, the connection keeps reconnecting forever
+// like the kafka client does, so the callers don't need to handle the transient server outage.
+func GetNatsConnection(conn *transport.NatsConfig, name string) (*nats.Conn, error) {
+	if conn == nil || conn.ServerURL == "" {
+		return nil, errors.New("the nats server url must not be empty")
+	}
+	opts := []nats.Option{
+		nats.Name(name),
+		nats.MaxReconnects(-1),
+		nats.DisconnectErrHandler(func(_ *nats.Conn, err error) {
+			if err != nil {
+				log.Warnw("disconnected from the nats server", "name", name, "error", err)
+			}
+		}),
+		nats.ReconnectHandler(func(nc *nats.Conn) {
+			log.Infow("reconnected to the nats server", "name", name, "url", nc.ConnectedUrl())
+		}),
+	}
+
+	// if the certs is invalid
+	if conn.CACert == "" || conn.ClientCert == "" || conn.ClientKey == "" {
+		log.Warn("Connect to NATS without TLS")
+		return nats.Connect(conn.ServerURL, opts...)
+	}
+
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM([]byte(conn.CACert)) {
+		return nil, errors.New("failed to append the nats ca certificate")
+	}
+	clientCert, err := tls.X509KeyPair([]byte(conn.ClientCert), []byte(conn.ClientKey))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the nats client certificate: %w", err)
+	}
+	opts = append(opts, nats.Secure(&tls.Config{
+		RootCAs:      caPool,
+		Certificates: []tls.Certificate{clientCert},
+		MinVersion:   tls.VersionTLS12,
+	}))
+	return nats.Connect(conn.ServerURL, opts...)
+}
@@ -18,12 +18,14 @@ import (
 	"github.com/cloudevents/sdk-go/v2/protocol/gochan"
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/compressor"
 	"github.com/stolostron/multicluster-global-hub/pkg/database"
 	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
 	"github.com/stolostron/multicluster-global-hub/pkg/enum"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/jetstream"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/utils"
 )
 
@@ -98,6 +100,12 @@ func (c *GenericConsumer) initClient(tranConfig *transport.TransportInternalConf
 		if err != nil {
 			return err
 		}
+	case string(transport.Nats):
+		log.Info("transport consumer with cloudevents-jetstream receiver")
+		clientProtocol, err = getJetStreamReceiverProtocol(tranConfig, topics)
+		if err != nil {
+			return err
+		}
 	case string(transport.Chan):
 		log.Info("transport consumer with go chan receiver")
 		if tranConfig.Extends == nil {
@@ -145,7 +153,7 @@ func (c *GenericConsumer) Reconnect(ctx context.Context,
 		c.consumerCancel()
 	}
 	c.consumerCtx, c.consumerCancel = context.WithCancel(ctx)
-	consumerGroupId := tranConfig.KafkaCredential.ConsumerGroupID
+	consumerGroupId := tranConfig.GetConsumerGroupID()
 	go func() {
 		log.Infof("reconnect consumer: %s", consumerGroupId)
 		if err := c.Start(c.consumerCtx); err != nil {
@@ -183,14 +191,14 @@ func (c *GenericConsumer) Start(ctx context.Context) error {
 
 		chunk, isChunk := c.assembler.messageChunk(event)
 		if !isChunk {
-			c.eventChan <- &event
+			c.deliver(&event)
 			return ceprotocol.ResultACK
 		}
 		if payload := c.assembler.assemble(chunk); payload != nil {
 			if err := event.SetData(cloudevents.ApplicationJSON, payload); err != nil {
 				log.Errorw("failed the set the assembled data to event", "error", err)
 			} else {
-				c.eventChan <- &event
+				c.deliver(&event)
 			}
 		}
 		return ceprotocol.ResultACK
@@ -202,6 +210,17 @@ func (c *GenericConsumer) Start(ctx context.Context) error {
 	return nil
 }
 
+// deliver decompresses the (assembled) event by the compression type stamped by the sender, then sends it to the
+// event channel
+func (c *GenericConsumer) deliver(event *cloudevents.Event) {
+	if err := compressor.DecompressEvent(event); err != nil {
+		log.Errorw("failed to decompress the event", "type", enum.ShortenEventType(event.Type()),
+			"source", event.Source(), "error", err)
+		return
+	}
+	c.eventChan <- event
+}
+
 func (c *GenericConsumer) EventChan() chan *cloudevents.Event {
 	return c.eventChan
 }
@@ -275,3 +294,18 @@ func getConfluentReceiverProtocol(transportConfig *transport.TransportInternalCo
 	}
 	return consumer, protocol, nil
 }
+
+func getJetStreamReceiverProtocol(transportConfig *transport.TransportInternalConfig, topics []string,
+) (*jetstream.Protocol, error) {
+	durable := transportConfig.GetConsumerGroupID()
+	conn, err := config.GetNatsConnection(transportConfig.NatsCredential, fmt.Sprintf("consumer-%s", durable))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect the nats server: %w", err)
+	}
+	protocol, err := jetstream.New(conn, jetstream.WithReceiverTopics(topics), jetstream.WithDurable(durable))
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return protocol, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -118,18 +119,28 @@ func (c *TransportCtrl) Reconcile(ctx context.Context, request ctrl.Request) (ct
 		return ctrl.Result{}, err
 	}
 
-	// currently, transport type is always kafka
-	c.transportConfig.TransportType = string(transport.Kafka)
 	var updated bool
 	var err error
 
-	_, enableKafka := secret.Data["kafka.yaml"]
-	if enableKafka {
-		updated, err = c.ReconcileKafkaCredential(ctx, secret)
-		if err != nil {
-			return ctrl.Result{}, err
+	// the transport type is kafka unless the nats(jetstream) is selected explicitly
+	var enableMessaging bool
+	if c.transportConfig.TransportType == string(transport.Nats) {
+		_, enableMessaging = secret.Data["nats.yaml"]
+		if enableMessaging {
+			updated, err = c.ReconcileNatsCredential(ctx, secret)
+		}
+	} else {
+		c.transportConfig.TransportType = string(transport.Kafka)
+		_, enableMessaging = secret.Data["kafka.yaml"]
+		if enableMessaging {
+			updated, err = c.ReconcileKafkaCredential(ctx, secret)
 		}
+	}
+	if err != nil {
+		return ctrl.Result{}, err
+	}
 
+	if enableMessaging {
 		if updated || c.transportClient.consumer == nil {
 			// reconcile consumer when credential is updated or consumer needs reinitialization
 			if !c.disableConsumer {
@@ -141,6 +152,9 @@ func (c *TransportCtrl) Reconcile(ctx context.Context, request ctrl.Request) (ct
 			if err := c.ReconcileProducer(); err != nil {
 				return ctrl.Result{}, err
 			}
+			if err := c.recordClientCertIssuer(ctx, secret, c.transportConfig.GetCertificate()); err != nil {
+				log.Warnf("failed to record the issuer of the client certificate: %v", err)
+			}
 		}
 	}
 
@@ -155,6 +169,9 @@ func (c *TransportCtrl) Reconcile(ctx context.Context, request ctrl.Request) (ct
 			if err := c.ReconcileRequester(ctx); err != nil {
 				return ctrl.Result{}, err
 			}
+			if err := c.recordClientCertIssuer(ctx, secret, c.transportConfig.RestfulCredential); err != nil {
+				log.Warnf("failed to record the issuer of the client certificate: %v", err)
+			}
 		}
 	}
 
@@ -182,9 +199,9 @@ func (c *TransportCtrl) Reconcile(ctx context.Context, request ctrl.Request) (ct
 func (c *TransportCtrl) ReconcileProducer() error {
 	// set producerTopic to spec or status topic based on running in manager or not
 	if c.inManager {
-		c.producerTopic = c.transportConfig.KafkaCredential.SpecTopic
+		c.producerTopic = c.transportConfig.GetClusterTopic().SpecTopic
 	} else {
-		c.producerTopic = c.transportConfig.KafkaCredential.StatusTopic
+		c.producerTopic = c.transportConfig.GetClusterTopic().StatusTopic
 	}
 
 	if c.transportClient.producer == nil {
@@ -204,7 +221,7 @@ func (c *TransportCtrl) ReconcileProducer() error {
 // ReconcileConsumer, transport config is changed, then create/update the consumer
 func (c *TransportCtrl) ReconcileConsumer(ctx context.Context) error {
 	// if the consumer groupId is empty, then it's means the agent is in the standalone mode, don't create the consumer
-	if c.transportConfig.KafkaCredential.ConsumerGroupID == "" {
+	if c.transportConfig.GetConsumerGroupID() == "" {
 		log.Infof("skip initializing consumer, consumer group id is not set")
 		return nil
 	}
@@ -212,14 +229,14 @@ func (c *TransportCtrl) ReconcileConsumer(ctx context.Context) error {
 	options := []consumer.GenericConsumeOption{}
 	// set consumerTopics to status or spec topic based on running in manager or not
 	if c.inManager {
-		c.consumerTopics = []string{c.transportConfig.KafkaCredential.StatusTopic}
+		c.consumerTopics = []string{c.transportConfig.GetClusterTopic().StatusTopic}
 		options = append(options, consumer.SetTopicMetadataRefreshInterval(constants.TopicMetadataRefreshInterval))
 	} else {
-		c.consumerTopics = []string{c.transportConfig.KafkaCredential.SpecTopic}
+		c.consumerTopics = []string{c.transportConfig.GetClusterTopic().SpecTopic}
 	}
 
-	consumerGroupID := c.transportConfig.KafkaCredential.ConsumerGroupID
-	// create/update the consumer with the kafka transport
+	consumerGroupID := c.transportConfig.GetConsumerGroupID()
+	// create/update the consumer with the kafka/nats transport
 	if c.transportClient.consumer == nil {
 		receiver, err := consumer.NewGenericConsumer(c.transportConfig, c.consumerTopics, options...)
 		if err != nil {
@@ -279,6 +296,10 @@ func (c *TransportCtrl) ReconcileKafkaCredential(ctx context.Context, secret *co
 	if err != nil {
 		return false, err
 	}
+	err = c.resyncRotatedClientSecret(ctx, kafkaConn, secret.Namespace)
+	if err != nil {
+		return false, err
+	}
 	// update the watching secret lits
 	if kafkaConn.CASecretName != "" || !utils.ContainsString(c.extraSecretNames, kafkaConn.CASecretName) {
 		c.extraSecretNames = append(c.extraSecretNames, kafkaConn.CASecretName)
@@ -297,6 +318,35 @@ func (c *TransportCtrl) ReconcileKafkaCredential(ctx context.Context, secret *co
 	return true, nil
 }
 
+// ReconcileNatsCredential update the nats connection credential based on the secret, return true if the nats
+// credential is updated
+func (c *TransportCtrl) ReconcileNatsCredential(ctx context.Context, secret *corev1.Secret) (bool, error) {
+	natsConn, err := config.GetNatsConnBySecret(secret, c.runtimeClient)
+	if err != nil {
+		return false, err
+	}
+
+	// update the watching secret lits
+	if natsConn.CASecretName != "" && !utils.ContainsString(c.extraSecretNames, natsConn.CASecretName) {
+		c.extraSecretNames = append(c.extraSecretNames, natsConn.CASecretName)
+	}
+	if natsConn.ClientSecretName != "" && !utils.ContainsString(c.extraSecretNames, natsConn.ClientSecretName) {
+		c.extraSecretNames = append(c.extraSecretNames, natsConn.ClientSecretName)
+	}
+
+	if reflect.DeepEqual(c.transportConfig.NatsCredential, natsConn) {
+		return false, nil
+	}
+	c.transportConfig.NatsCredential = natsConn
+	// owner identity is the cluster identity to record the stream sequence of specific server
+	ownerIdentity := natsConn.ClusterID
+	if ownerIdentity == "" {
+		ownerIdentity = natsConn.ServerURL
+	}
+	config.SetKafkaOwnerIdentity(ownerIdentity)
+	return true, nil
+}
+
 // Resync the kafka client secret because we recreate the kafka cluster in globalhub 1.4 and restore case.
 func (c *TransportCtrl) ResyncKafkaClientSecret(ctx context.Context, kafkaConn *transport.KafkaConfig, secret *corev1.Secret) error {
 	if kafkaConn.ClusterID == "" {
@@ -344,6 +394,54 @@ func (c *TransportCtrl) ResyncKafkaClientSecret(ctx context.Context, kafkaConn *
 	return c.runtimeClient.Update(ctx, transportSecret)
 }
 
+// resyncRotatedClientSecret removes the kafka client secret if the certificate isn't issued by the current client CA,
+// so that it's re-issued by the rotated CA before the old one is retired
+func (c *TransportCtrl) resyncRotatedClientSecret(ctx context.Context, kafkaConn *transport.KafkaConfig,
+	namespace string,
+) error {
+	if kafkaConn.ClientCAKeyID == "" || kafkaConn.ClientSecretName == "" || kafkaConn.ClientCert == "" {
+		return nil
+	}
+	issuer, err := utils.CertificateIssuerKeyID([]byte(kafkaConn.ClientCert))
+	if err != nil {
+		return err
+	}
+	if issuer == kafkaConn.ClientCAKeyID {
+		return nil
+	}
+
+	log.Infof("remove kafka client secret %s issued by the rotated CA: %s", kafkaConn.ClientSecretName, issuer)
+	err = c.runtimeClient.Delete(ctx, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      kafkaConn.ClientSecretName,
+			Namespace: namespace,
+		},
+	})
+	return client.IgnoreNotFound(err)
+}
+
+// recordClientCertIssuer annotates the transport config secret with the key id of the CA issued the client
+// certificate once the transport is reconnected with it. The operator retires the old CA in the certificate rotation
+// only if all the clients are reconnected with the certificates issued by the new one.
+func (c *TransportCtrl) recordClientCertIssuer(ctx context.Context, secret *corev1.Secret,
+	conn transport.TransportCertificate,
+) error {
+	if conn == nil || conn.GetClientCert() == "" {
+		return nil
+	}
+	issuer, err := utils.CertificateIssuerKeyID([]byte(conn.GetClientCert()))
+	if err != nil {
+		return err
+	}
+	if secret.Annotations[constants.ClientCertIssuerAnnotation] == issuer {
+		return nil
+	}
+	log.Infof("reconnected with the client certificate issued by: %s", issuer)
+	// patch the annotation since the secret might be updated by resyncing the kafka client secret
+	patch := fmt.Sprintf(`{"metadata":{"annotations":{%q:%q}}}`, constants.ClientCertIssuerAnnotation, issuer)
+	return c.runtimeClient.Patch(ctx, secret, client.RawPatch(types.MergePatchType, []byte(patch)))
+}
+
 func (c *TransportCtrl) ReconcileRestfulCredential(ctx context.Context, secret *corev1.Secret) (
 	updated bool, err error,
 ) {
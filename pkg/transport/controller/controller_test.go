@@ -2,12 +2,22 @@ package controller
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
 	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
 	"testing"
+	"time"
 
 	cloudevents "github.com/cloudevents/sdk-go/v2"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -345,3 +355,96 @@ func TestTransportCtrl_ReconcileConsumer(t *testing.T) {
 		assert.True(t, mock.reconnectCalled)
 	})
 }
+
+// newTestClientCert returns the key id of a self-signed CA and a client certificate issued by it
+func newTestClientCert(t *testing.T) (string, []byte) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "clients-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	assert.NoError(t, err)
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "hub1-kafka-user"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caCert, &caKey.PublicKey, caKey)
+	assert.NoError(t, err)
+	return hex.EncodeToString(caCert.SubjectKeyId),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER})
+}
+
+func TestTransportCtrl_RecordClientCertIssuer(t *testing.T) {
+	caKeyID, clientPEM := newTestClientCert(t)
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "transport-config",
+			Annotations: map[string]string{
+				constants.KafkaClusterIdAnnotation: "0001",
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret.DeepCopy()).Build()
+	c := &TransportCtrl{runtimeClient: fakeClient}
+
+	// skip the credential without the client certificate, e.g. the scram authentication
+	assert.NoError(t, c.recordClientCertIssuer(context.Background(), secret, &transport.KafkaConfig{}))
+	assert.NoError(t, c.recordClientCertIssuer(context.Background(), secret, nil))
+
+	assert.NoError(t, c.recordClientCertIssuer(context.Background(), secret,
+		&transport.KafkaConfig{ClientCert: string(clientPEM)}))
+	updated := &corev1.Secret{}
+	assert.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(secret), updated))
+	assert.Equal(t, caKeyID, updated.Annotations[constants.ClientCertIssuerAnnotation])
+	assert.Equal(t, "0001", updated.Annotations[constants.KafkaClusterIdAnnotation])
+
+	assert.Error(t, c.recordClientCertIssuer(context.Background(), secret,
+		&transport.KafkaConfig{ClientCert: "invalid"}))
+}
+
+func TestTransportCtrl_ResyncRotatedClientSecret(t *testing.T) {
+	caKeyID, clientPEM := newTestClientCert(t)
+
+	clientSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "kafka-client"},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(clientSecret.DeepCopy()).Build()
+	c := &TransportCtrl{runtimeClient: fakeClient}
+
+	// keep the secret if the certificate is issued by the current CA
+	assert.NoError(t, c.resyncRotatedClientSecret(context.Background(), &transport.KafkaConfig{
+		ClientCert: string(clientPEM), ClientSecretName: clientSecret.Name, ClientCAKeyID: caKeyID,
+	}, "default"))
+	assert.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(clientSecret), &corev1.Secret{}))
+
+	// keep the secret if the current CA isn't specified
+	assert.NoError(t, c.resyncRotatedClientSecret(context.Background(), &transport.KafkaConfig{
+		ClientCert: string(clientPEM), ClientSecretName: clientSecret.Name,
+	}, "default"))
+	assert.NoError(t, fakeClient.Get(context.Background(), client.ObjectKeyFromObject(clientSecret), &corev1.Secret{}))
+
+	// remove the secret issued by the rotated CA
+	assert.NoError(t, c.resyncRotatedClientSecret(context.Background(), &transport.KafkaConfig{
+		ClientCert: string(clientPEM), ClientSecretName: clientSecret.Name, ClientCAKeyID: "new-ca",
+	}, "default"))
+	err := fakeClient.Get(context.Background(), client.ObjectKeyFromObject(clientSecret), &corev1.Secret{})
+	assert.True(t, apierrors.IsNotFound(err))
+
+	// the secret is removed already
+	assert.NoError(t, c.resyncRotatedClientSecret(context.Background(), &transport.KafkaConfig{
+		ClientCert: string(clientPEM), ClientSecretName: clientSecret.Name, ClientCAKeyID: "new-ca",
+	}, "default"))
+}
@@ -0,0 +1,335 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package jetstream
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	kafka_confluent "github.com/cloudevents/sdk-go/protocol/kafka_confluent/v2"
+	nats_jetstream "github.com/cloudevents/sdk-go/protocol/nats_jetstream/v2"
+	"github.com/cloudevents/sdk-go/v2/binding"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	"github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/nats-io/nats.go"
+	natsjs "github.com/nats-io/nats.go/jetstream"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/logger"
+)
+
+var (
+	_ protocol.Sender   = (*Protocol)(nil)
+	_ protocol.Opener   = (*Protocol)(nil)
+	_ protocol.Receiver = (*Protocol)(nil)
+	_ protocol.Closer   = (*Protocol)(nil)
+)
+
+const (
+	// DefaultStreamMaxAge is the retention of the streams created by the protocol, it aligns with the
+	// default retention(7 days) of the kafka topics.
+	DefaultStreamMaxAge = 7 * 24 * time.Hour
+	// DefaultAckWait is the time the server waits for the ack before redelivering the message, the handler of
+	// the generic consumer might be blocked by the conflation manager, so it's much longer than the default 30s.
+	DefaultAckWait = 5 * time.Minute
+
+	// the header prefix of the cloudevents attributes/extensions in binary mode
+	headerPrefix = "ce-"
+)
+
+var log = logger.DefaultZapLogger()
+
+// Protocol implements the cloudevents sender and receiver on the NATS JetStream, so that it can be plugged into
+// the generic producer and consumer in the same way as the kafka protocol. Each topic is mapped into a subject,
+// and the subjects sharing the same root token are persisted in one stream, e.g. 'gh-status.hub1' and
+// 'gh-status.hub2' are in the 'gh-status' stream.
+type Protocol struct {
+	conn *nats.Conn
+	js   natsjs.JetStream
+
+	// sender
+	senderSubject string
+	streams       sync.Map
+
+	// receiver
+	receiverSubjects []string
+	durable          string
+	incoming         chan natsjs.Msg
+
+	closerMux sync.Mutex
+	cancel    context.CancelFunc
+}
+
+type Option func(*Protocol) error
+
+// WithSenderTopic sets the default topic(subject) to publish the events
+func WithSenderTopic(topic string) Option {
+	return func(p *Protocol) error {
+		if topic == "" {
+			return errors.New("the sender topic must not be empty")
+		}
+		p.senderSubject = TopicToSubject(topic)
+		return nil
+	}
+}
+
+// WithReceiverTopics sets the topics(subjects) to consume, all of them must belong to the same stream
+func WithReceiverTopics(topics []string) Option {
+	return func(p *Protocol) error {
+		if len(topics) == 0 {
+			return errors.New("the receiver topics must not be empty")
+		}
+		for _, topic := range topics {
+			p.receiverSubjects = append(p.receiverSubjects, TopicToSubject(topic))
+		}
+		return nil
+	}
+}
+
+// WithDurable sets the durable consumer name, the server keeps the delivered position of the durable consumer,
+// so the receiver continues from where it left off after a restart, like the kafka consumer group.
+func WithDurable(name string) Option {
+	return func(p *Protocol) error {
+		p.durable = durableName(name)
+		return nil
+	}
+}
+
+func New(conn *nats.Conn, opts ...Option) (*Protocol, error) {
+	js, err := natsjs.New(conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the jetstream context: %w", err)
+	}
+	p := &Protocol{
+		conn:     conn,
+		js:       js,
+		incoming: make(chan natsjs.Msg),
+	}
+	for _, fn := range opts {
+		if err := fn(p); err != nil {
+			return nil, err
+		}
+	}
+	if len(p.receiverSubjects) > 0 && p.durable == "" {
+		return nil, errors.New("the durable name must be set for the receiver")
+	}
+	return p, nil
+}
+
+// Conn returns the underlying nats connection
+func (p *Protocol) Conn() *nats.Conn {
+	return p.conn
+}
+
+// Send publishes the message into the stream in binary mode, the attributes and extensions are carried by the
+// headers, then the chunked payload (extsize/extoffset) can be sent as it is.
+func (p *Protocol) Send(ctx context.Context, in binding.Message, transformers ...binding.Transformer) (err error) {
+	defer func() { _ = in.Finish(err) }()
+
+	subject := p.senderSubject
+	if topic := cecontext.TopicFrom(ctx); topic != "" {
+		subject = TopicToSubject(topic)
+	}
+	if subject == "" {
+		return errors.New("the sender topic must be set")
+	}
+
+	if err = p.ensureStream(ctx, StreamName(subject)); err != nil {
+		return err
+	}
+
+	data := new(bytes.Buffer)
+	header, err := nats_jetstream.WriteMsg(ctx, in, data, transformers...)
+	if err != nil {
+		return fmt.Errorf("failed to write the nats message: %w", err)
+	}
+	if _, err = p.js.PublishMsg(ctx, &nats.Msg{Subject: subject, Header: header, Data: data.Bytes()}); err != nil {
+		return fmt.Errorf("failed to publish the message to %s: %w", subject, err)
+	}
+	return nil
+}
+
+// OpenInbound creates or binds the durable consumer, and pulls the messages until the context is done. If the
+// durable consumer doesn't exist and the positions are given by kafka_confluent.WithTopicPartitionOffsets, then
+// the consumer starts from the stream sequence(offset) of the position whose topic is the stream name.
+func (p *Protocol) OpenInbound(ctx context.Context) error {
+	if len(p.receiverSubjects) == 0 {
+		return errors.New("the receiver topics must be set")
+	}
+	stream := StreamName(p.receiverSubjects[0])
+	for _, subject := range p.receiverSubjects {
+		if StreamName(subject) != stream {
+			return fmt.Errorf("the receiver topics %v must belong to the same stream", p.receiverSubjects)
+		}
+	}
+
+	p.closerMux.Lock()
+	ctx, p.cancel = context.WithCancel(ctx)
+	p.closerMux.Unlock()
+	defer p.cancel()
+
+	// the connection is owned by the inbound
+	defer func() {
+		close(p.incoming)
+		p.conn.Close()
+	}()
+
+	if err := p.ensureStream(ctx, stream); err != nil {
+		return err
+	}
+	consumer, err := p.ensureConsumer(ctx, stream)
+	if err != nil {
+		return err
+	}
+
+	logger := cecontext.LoggerFrom(ctx)
+	logger.Infof("consuming the stream %s with durable consumer %s: %v", stream, p.durable, p.receiverSubjects)
+	iter, err := consumer.Messages()
+	if err != nil {
+		return fmt.Errorf("failed to pull messages from the stream %s: %w", stream, err)
+	}
+	go func() {
+		<-ctx.Done()
+		iter.Stop()
+	}()
+
+	for {
+		msg, err := iter.Next()
+		if errors.Is(err, natsjs.ErrMsgIteratorClosed) {
+			return nil
+		}
+		if err != nil {
+			logger.Warnf("failed to get the next message from the stream %s: %v", stream, err)
+			continue
+		}
+		select {
+		case p.incoming <- msg:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+// Receive implements Receiver.Receive, the message is acked once it's finished by the client.
+func (p *Protocol) Receive(ctx context.Context) (binding.Message, error) {
+	select {
+	case m, ok := <-p.incoming:
+		if !ok {
+			return nil, io.EOF
+		}
+		return newMessage(m), nil
+	case <-ctx.Done():
+		return nil, io.EOF
+	}
+}
+
+// Close stops the inbound and closes the connection of the sender
+func (p *Protocol) Close(ctx context.Context) error {
+	p.closerMux.Lock()
+	defer p.closerMux.Unlock()
+
+	if p.cancel != nil {
+		p.cancel()
+	}
+	if len(p.receiverSubjects) == 0 && !p.conn.IsClosed() {
+		// flush the pending messages before closing the sender connection
+		if err := p.conn.Drain(); err != nil {
+			return fmt.Errorf("failed to drain the nats connection: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p *Protocol) ensureStream(ctx context.Context, stream string) error {
+	if _, found := p.streams.Load(stream); found {
+		return nil
+	}
+	_, err := p.js.CreateStream(ctx, natsjs.StreamConfig{
+		Name:      stream,
+		Subjects:  []string{stream, stream + ".>"},
+		Retention: natsjs.LimitsPolicy,
+		Storage:   natsjs.FileStorage,
+		MaxAge:    DefaultStreamMaxAge,
+	})
+	// the stream might be created or tuned by others, keep it as it is
+	if err != nil && !errors.Is(err, natsjs.ErrStreamNameAlreadyInUse) {
+		return fmt.Errorf("failed to create the stream %s: %w", stream, err)
+	}
+	p.streams.Store(stream, struct{}{})
+	return nil
+}
+
+func (p *Protocol) ensureConsumer(ctx context.Context, stream string) (natsjs.Consumer, error) {
+	consumer, err := p.js.Consumer(ctx, stream, p.durable)
+	if err == nil {
+		return consumer, nil
+	}
+	if !errors.Is(err, natsjs.ErrConsumerNotFound) {
+		return nil, fmt.Errorf("failed to get the consumer %s: %w", p.durable, err)
+	}
+
+	consumerConfig := natsjs.ConsumerConfig{
+		Durable:        p.durable,
+		FilterSubjects: p.receiverSubjects,
+		AckPolicy:      natsjs.AckExplicitPolicy,
+		AckWait:        DefaultAckWait,
+		DeliverPolicy:  natsjs.DeliverAllPolicy,
+	}
+	for _, position := range kafka_confluent.TopicPartitionOffsetsFrom(ctx) {
+		if position.Topic != nil && *position.Topic == stream && position.Offset > 0 {
+			consumerConfig.DeliverPolicy = natsjs.DeliverByStartSequencePolicy
+			consumerConfig.OptStartSeq = uint64(position.Offset)
+			log.Infow("create the consumer with start sequence", "stream", stream, "sequence", position.Offset)
+		}
+	}
+	return p.js.CreateConsumer(ctx, stream, consumerConfig)
+}
+
+// newMessage converts the jetstream message into the binding message. The stream position is attached as the
+// kafka position extensions(topic: stream, partition: 0, offset: stream sequence), so the conflation committer
+// can persist it as the transport.EventPosition without knowing the transport type.
+func newMessage(m natsjs.Msg) binding.Message {
+	header := nats.Header{}
+	for key, values := range m.Headers() {
+		header[key] = values
+	}
+	if meta, err := m.Metadata(); err == nil {
+		header.Set(headerPrefix+kafka_confluent.KafkaTopicKey, meta.Stream)
+		header.Set(headerPrefix+kafka_confluent.KafkaPartitionKey, "0")
+		header.Set(headerPrefix+kafka_confluent.KafkaOffsetKey, strconv.FormatUint(meta.Sequence.Stream, 10))
+	}
+	msg := nats_jetstream.NewMessage(&nats.Msg{Subject: m.Subject(), Header: header, Data: m.Data()})
+	return binding.WithFinish(msg, func(err error) {
+		if protocol.IsACK(err) {
+			err = m.Ack()
+		} else {
+			err = m.Nak()
+		}
+		if err != nil {
+			log.Warnw("failed to acknowledge the message", "subject", m.Subject(), "error", err)
+		}
+	})
+}
+
+// TopicToSubject converts the kafka topic into the nats subject, the regex topic like '^gh-status.*' is
+// converted into the wildcard subject 'gh-status.*'
+func TopicToSubject(topic string) string {
+	return strings.TrimPrefix(topic, "^")
+}
+
+// StreamName returns the stream which persists the subject, it's the root token of the subject
+func StreamName(subject string) string {
+	return strings.SplitN(subject, ".", 2)[0]
+}
+
+// durableName removes the characters which are not allowed in the consumer name
+func durableName(name string) string {
+	return strings.NewReplacer(".", "_", "*", "_", ">", "_", " ", "_").Replace(name)
+}
@@ -0,0 +1,223 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package jetstream
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	kafka_confluent "github.com/cloudevents/sdk-go/protocol/kafka_confluent/v2"
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	"github.com/cloudevents/sdk-go/v2/client"
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	ceprotocol "github.com/cloudevents/sdk-go/v2/protocol"
+	"github.com/cloudevents/sdk-go/v2/types"
+	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+func TestProtocol(t *testing.T) {
+	certDir, clientTLS := generateCerts(t)
+	server := NewEmbeddedServer("127.0.0.1", -1, t.TempDir(), certDir)
+	require.NoError(t, server.Run())
+	defer server.Shutdown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// send the events to the status topic of hub1
+	senderConn, err := nats.Connect(server.ClientURL(), nats.Secure(clientTLS))
+	require.NoError(t, err)
+	sender, err := New(senderConn, WithSenderTopic("gh-status.hub1"))
+	require.NoError(t, err)
+	senderClient, err := cloudevents.NewClient(sender, cloudevents.WithTimeNow(), cloudevents.WithUUIDs())
+	require.NoError(t, err)
+
+	for i := 1; i <= 3; i++ {
+		evt := cloudevents.NewEvent()
+		evt.SetType("test.event")
+		evt.SetSource("hub1")
+		evt.SetExtension(transport.ChunkSizeKey, 10)
+		evt.SetExtension(transport.ChunkOffsetKey, i)
+		// the chunk is not a valid json, it must be carried as it is
+		require.NoError(t, evt.SetData(cloudevents.ApplicationJSON, []byte(`{"chunk":`)))
+		require.True(t, cloudevents.IsACK(senderClient.Send(ctx, evt)))
+	}
+	require.NoError(t, sender.Close(ctx))
+
+	receive := func(ctx context.Context, startOffset int64, count int) []cloudevents.Event {
+		conn, err := nats.Connect(server.ClientURL(), nats.Secure(clientTLS))
+		require.NoError(t, err)
+		receiver, err := New(conn, WithReceiverTopics([]string{"^gh-status.*"}), WithDurable("global-hub.manager"))
+		require.NoError(t, err)
+		receiverClient, err := cloudevents.NewClient(receiver, client.WithPollGoroutines(1))
+		require.NoError(t, err)
+
+		if startOffset > 0 {
+			stream := "gh-status"
+			ctx = kafka_confluent.WithTopicPartitionOffsets(ctx, []kafka.TopicPartition{
+				{Topic: &stream, Partition: 0, Offset: kafka.Offset(startOffset)},
+			})
+		}
+		receiveCtx, receiveCancel := context.WithTimeout(ctx, 10*time.Second)
+		defer receiveCancel()
+
+		events := []cloudevents.Event{}
+		err = receiverClient.StartReceiver(receiveCtx, func(ctx context.Context, evt cloudevents.Event) ceprotocol.Result {
+			events = append(events, evt)
+			if len(events) == count {
+				receiveCancel()
+			}
+			return ceprotocol.ResultACK
+		})
+		require.NoError(t, err)
+		return events
+	}
+
+	// the durable consumer starts from the stream sequence 2
+	events := receive(cecontext.WithLogger(ctx, log), 2, 2)
+	require.Len(t, events, 2)
+	chunkOffsets := []int32{}
+	for _, evt := range events {
+		require.Equal(t, "test.event", evt.Type())
+		require.Equal(t, `{"chunk":`, string(evt.Data()))
+
+		chunkOffset, err := types.ToInteger(evt.Extensions()[transport.ChunkOffsetKey])
+		require.NoError(t, err)
+		chunkOffsets = append(chunkOffsets, chunkOffset)
+
+		topic, err := types.ToString(evt.Extensions()[kafka_confluent.KafkaTopicKey])
+		require.NoError(t, err)
+		require.Equal(t, "gh-status", topic)
+		require.Equal(t, chunkOffset, mustInteger(t, evt.Extensions()[kafka_confluent.KafkaOffsetKey]))
+	}
+	// the callback might be invoked concurrently, so the events might be out of order
+	require.ElementsMatch(t, []int32{2, 3}, chunkOffsets)
+
+	// send another event, the durable consumer continues from the acked position
+	senderConn, err = nats.Connect(server.ClientURL(), nats.Secure(clientTLS))
+	require.NoError(t, err)
+	sender, err = New(senderConn, WithSenderTopic("gh-spec"))
+	require.NoError(t, err)
+	senderClient, err = cloudevents.NewClient(sender, cloudevents.WithTimeNow(), cloudevents.WithUUIDs())
+	require.NoError(t, err)
+	evt := cloudevents.NewEvent()
+	evt.SetType("test.event")
+	evt.SetSource("hub2")
+	require.True(t, cloudevents.IsACK(senderClient.Send(cecontext.WithTopic(ctx, "gh-status.hub2"), evt)))
+	require.NoError(t, sender.Close(ctx))
+
+	events = receive(ctx, 0, 1)
+	require.Len(t, events, 1)
+	require.Equal(t, "hub2", events[0].Source())
+	require.Equal(t, int32(4), mustInteger(t, events[0].Extensions()[kafka_confluent.KafkaOffsetKey]))
+}
+
+func TestEmbeddedServerTLS(t *testing.T) {
+	// the server refuses to start without the certificates
+	require.Error(t, NewEmbeddedServer("127.0.0.1", -1, t.TempDir(), t.TempDir()).Run())
+
+	certDir, clientTLS := generateCerts(t)
+	server := NewEmbeddedServer("127.0.0.1", -1, t.TempDir(), certDir)
+	require.NoError(t, server.Run())
+	defer server.Shutdown()
+
+	// the plain connection is rejected
+	_, err := nats.Connect(server.ClientURL(), nats.NoReconnect())
+	require.Error(t, err)
+
+	// the connection without the client certificate is rejected
+	_, err = nats.Connect(server.ClientURL(), nats.NoReconnect(), nats.Secure(&tls.Config{
+		RootCAs:    clientTLS.RootCAs,
+		MinVersion: tls.VersionTLS12,
+	}))
+	require.Error(t, err)
+
+	conn, err := nats.Connect(server.ClientURL(), nats.Secure(clientTLS))
+	require.NoError(t, err)
+	conn.Close()
+}
+
+func TestTopicToStream(t *testing.T) {
+	require.Equal(t, "gh-status.*", TopicToSubject("^gh-status.*"))
+	require.Equal(t, "gh-status", StreamName(TopicToSubject("^gh-status.*")))
+	require.Equal(t, "gh-spec", StreamName(TopicToSubject("gh-spec")))
+	require.Equal(t, "global-hub_manager", durableName("global-hub.manager"))
+}
+
+func mustInteger(t *testing.T, val interface{}) int32 {
+	i, err := types.ToInteger(val)
+	require.NoError(t, err)
+	return i
+}
+
+// generateCerts writes the server certificate and the CA into the certificate directory, and returns the client
+// tls config with a certificate issued by the CA
+func generateCerts(t *testing.T) (string, *tls.Config) {
+	caKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	ca, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	issue := func(serial int64, cn string, usage x509.ExtKeyUsage) ([]byte, []byte) {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		template := &x509.Certificate{
+			SerialNumber: big.NewInt(serial),
+			Subject:      pkix.Name{CommonName: cn},
+			NotBefore:    time.Now().Add(-time.Hour),
+			NotAfter:     time.Now().Add(time.Hour),
+			KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+			ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+			IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+		require.NoError(t, err)
+		return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+			pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	}
+
+	certDir := t.TempDir()
+	serverCert, serverKey := issue(2, "nats-server", x509.ExtKeyUsageServerAuth)
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	require.NoError(t, os.WriteFile(filepath.Join(certDir, serverCertFile), serverCert, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(certDir, serverKeyFile), serverKey, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(certDir, clientCAFile), caPEM, 0o600))
+
+	clientCert, clientKey := issue(3, "hub1-kafka-user", x509.ExtKeyUsageClientAuth)
+	keyPair, err := tls.X509KeyPair(clientCert, clientKey)
+	require.NoError(t, err)
+	caPool := x509.NewCertPool()
+	caPool.AddCert(ca)
+	return certDir, &tls.Config{
+		RootCAs:      caPool,
+		Certificates: []tls.Certificate{keyPair},
+		MinVersion:   tls.VersionTLS12,
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright (c) 2025 Red Hat, Inc.
+// Copyright Contributors to the Open Cluster Management project
+
+package jetstream
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+)
+
+const serverReadyTimeout = 30 * time.Second
+
+// the files in the certificate directory of the server, the ca.crt is the CA issuing the client certificates
+const (
+	serverCertFile = "tls.crt"
+	serverKeyFile  = "tls.key"
+	clientCAFile   = "ca.crt"
+)
+
+// EmbeddedServer runs the JetStream enabled NATS server inside the current process. It's used by the edge
+// deployments which cannot afford a dedicated kafka cluster, the global hub manager hosts the server and the
+// agents connect to it with the "nats.yaml" transport credential. Like the kafka users, the clients must present
+// a certificate issued by the clients CA, the server rejects the plain and the anonymous connections.
+type EmbeddedServer struct {
+	host     string
+	port     int
+	storeDir string
+	certDir  string
+	server   *server.Server
+}
+
+func NewEmbeddedServer(host string, port int, storeDir, certDir string) *EmbeddedServer {
+	return &EmbeddedServer{
+		host:     host,
+		port:     port,
+		storeDir: storeDir,
+		certDir:  certDir,
+	}
+}
+
+// Start implements the manager.Runnable, it starts the server and shuts it down once the context is done
+func (s *EmbeddedServer) Start(ctx context.Context) error {
+	if err := s.Run(); err != nil {
+		return err
+	}
+	<-ctx.Done()
+	s.Shutdown()
+	return nil
+}
+
+// NeedLeaderElection makes the server running on the leader only, otherwise each replica serves its own stream
+// and the events are split across them. The store directory should be on a volume shared by the replicas, so the
+// new leader takes over the stream once the leadership is changed.
+func (s *EmbeddedServer) NeedLeaderElection() bool {
+	return true
+}
+
+// Run starts the server in the background, and waits until it's ready for the connections
+func (s *EmbeddedServer) Run() error {
+	tlsConfig, err := s.tlsConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load the tls config of the embedded nats server: %w", err)
+	}
+	ns, err := server.NewServer(&server.Options{
+		Host:      s.host,
+		Port:      s.port,
+		JetStream: true,
+		StoreDir:  s.storeDir,
+		NoSigs:    true,
+		TLS:       true,
+		TLSVerify: true,
+		TLSConfig: tlsConfig,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the embedded nats server: %w", err)
+	}
+	ns.Start()
+	if !ns.ReadyForConnections(serverReadyTimeout) {
+		ns.Shutdown()
+		return fmt.Errorf("the embedded nats server isn't ready in %v", serverReadyTimeout)
+	}
+	s.server = ns
+	log.Infow("the embedded nats server is started", "url", ns.ClientURL(), "storeDir", s.storeDir)
+	return nil
+}
+
+// tlsConfig loads the server certificate and the clients CA from the certificate directory, the clients must be
+// verified by the CA
+func (s *EmbeddedServer) tlsConfig() (*tls.Config, error) {
+	if s.certDir == "" {
+		return nil, errors.New("the certificate directory must not be empty")
+	}
+	cert, err := tls.LoadX509KeyPair(filepath.Join(s.certDir, serverCertFile), filepath.Join(s.certDir, serverKeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load the server certificate: %w", err)
+	}
+	caPEM, err := os.ReadFile(filepath.Join(s.certDir, clientCAFile))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the clients CA: %w", err)
+	}
+	caPool := x509.NewCertPool()
+	if !caPool.AppendCertsFromPEM(caPEM) {
+		return nil, errors.New("failed to append the clients CA")
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    caPool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// ClientURL returns the url for the clients to connect the server
+func (s *EmbeddedServer) ClientURL() string {
+	if s.server == nil {
+		return ""
+	}
+	return s.server.ClientURL()
+}
+
+func (s *EmbeddedServer) Shutdown() {
+	if s.server == nil {
+		return
+	}
+	s.server.Shutdown()
+	s.server.WaitForShutdown()
+	log.Info("the embedded nats server is stopped")
+}
@@ -15,6 +15,8 @@ type KafkaConfig struct {
 	CASecretName     string `yaml:"ca.secret,omitempty"`
 	ClientSecretName string `yaml:"client.secret,omitempty"`
 	ConsumerGroupID  string `yaml:"consumergroup.id,omitempty"`
+	// ClientCAKeyID is the key id of the current client CA, the client certificate issued by the other CA is re-issued
+	ClientCAKeyID string `yaml:"client.ca.keyid,omitempty"`
 }
 
 // YamlMarshal marshal the connection credential object, rawCert specifies whether to keep the cert in the data directly
@@ -45,6 +47,7 @@ func (k *KafkaConfig) DeepCopy() *KafkaConfig {
 		ClientKey:        k.ClientKey,
 		CASecretName:     k.CASecretName,
 		ClientSecretName: k.ClientSecretName,
+		ClientCAKeyID:    k.ClientCAKeyID,
 	}
 }
 
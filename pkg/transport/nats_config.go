@@ -0,0 +1,82 @@
+package transport
+
+import "sigs.k8s.io/kustomize/kyaml/yaml"
+
+// NatsConfig is used to connect the NATS(JetStream) server.
+// This struct can be marshalled into a single Secret entry like "nats.yaml".
+type NatsConfig struct {
+	ServerURL        string `yaml:"server.url"`
+	StatusTopic      string `yaml:"topic.status,omitempty"`
+	SpecTopic        string `yaml:"topic.spec,omitempty"`
+	ClusterID        string `yaml:"cluster.id,omitempty"`
+	CACert           string `yaml:"ca.crt,omitempty"`
+	ClientCert       string `yaml:"client.crt,omitempty"`
+	ClientKey        string `yaml:"client.key,omitempty"`
+	CASecretName     string `yaml:"ca.secret,omitempty"`
+	ClientSecretName string `yaml:"client.secret,omitempty"`
+	// ConsumerGroupID is used as the durable consumer name of the stream
+	ConsumerGroupID string `yaml:"consumergroup.id,omitempty"`
+}
+
+// YamlMarshal marshal the connection credential object, rawCert specifies whether to keep the cert in the data directly
+func (n *NatsConfig) YamlMarshal(rawCert bool) ([]byte, error) {
+	copy := n.DeepCopy()
+	if rawCert {
+		copy.CASecretName = ""
+		copy.ClientSecretName = ""
+	} else {
+		copy.CACert = ""
+		copy.ClientCert = ""
+		copy.ClientKey = ""
+	}
+	bytes, err := yaml.Marshal(copy)
+	return bytes, err
+}
+
+// DeepCopy creates a deep copy of NatsConfig
+func (n *NatsConfig) DeepCopy() *NatsConfig {
+	return &NatsConfig{
+		ServerURL:        n.ServerURL,
+		StatusTopic:      n.StatusTopic,
+		SpecTopic:        n.SpecTopic,
+		ClusterID:        n.ClusterID,
+		ConsumerGroupID:  n.ConsumerGroupID,
+		CACert:           n.CACert,
+		ClientCert:       n.ClientCert,
+		ClientKey:        n.ClientKey,
+		CASecretName:     n.CASecretName,
+		ClientSecretName: n.ClientSecretName,
+	}
+}
+
+func (n *NatsConfig) GetCACert() string {
+	return n.CACert
+}
+
+func (n *NatsConfig) SetCACert(cert string) {
+	n.CACert = cert
+}
+
+func (n *NatsConfig) GetClientCert() string {
+	return n.ClientCert
+}
+
+func (n *NatsConfig) SetClientCert(cert string) {
+	n.ClientCert = cert
+}
+
+func (n *NatsConfig) GetClientKey() string {
+	return n.ClientKey
+}
+
+func (n *NatsConfig) SetClientKey(key string) {
+	n.ClientKey = key
+}
+
+func (n *NatsConfig) GetCASecretName() string {
+	return n.CASecretName
+}
+
+func (n *NatsConfig) GetClientSecretName() string {
+	return n.ClientSecretName
+}
@@ -16,9 +16,11 @@ import (
 	"github.com/confluentinc/confluent-kafka-go/v2/kafka"
 	"go.uber.org/zap"
 
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/schema"
 	"github.com/stolostron/multicluster-global-hub/pkg/logger"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/config"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport/jetstream"
 	"github.com/stolostron/multicluster-global-hub/pkg/transport/utils"
 )
 
@@ -63,6 +65,9 @@ func (p *GenericProducer) SendEvent(ctx context.Context, evt cloudevents.Event)
 		evtCtx = kafka_confluent.WithMessageKey(ctx, evt.Type())
 	}
 
+	// schema version, so that the receiver is able to upcast or reject the payload of a different version
+	schema.DefaultRegistry.Stamp(&evt)
+
 	// data
 	payloadBytes := evt.Data()
 	chunks := p.splitPayloadIntoChunks(payloadBytes)
@@ -116,6 +121,17 @@ func (p *GenericProducer) initClient(transportConfig *transport.TransportInterna
 		handleProducerEvents(p.log, eventChan, transportConfig.FailureThreshold, p.eventErrorHandler)
 		p.ceProtocol = kafkaProtocol
 		p.kafkaProducer = producer
+	case string(transport.Nats):
+		natsProtocol, err := getJetStreamSenderProtocol(transportConfig.NatsCredential, topic)
+		if err != nil {
+			return err
+		}
+		// the nats server rejects the message exceeds the max_payload(1MB by default)
+		if maxDataSize := int(natsProtocol.Conn().MaxPayload()) - config.NatsHeaderReserve; maxDataSize > 0 &&
+			p.messageSizeLimit > maxDataSize {
+			p.messageSizeLimit = maxDataSize
+		}
+		p.ceProtocol = natsProtocol
 	case string(transport.Chan):
 		if transportConfig.Extends == nil {
 			transportConfig.Extends = make(map[string]interface{})
@@ -178,6 +194,20 @@ func getConfluentSenderProtocol(logger *zap.SugaredLogger, kafkaCredentail *tran
 	return producer, protocol, nil
 }
 
+func getJetStreamSenderProtocol(natsCredential *transport.NatsConfig, defaultTopic string,
+) (*jetstream.Protocol, error) {
+	conn, err := config.GetNatsConnection(natsCredential, fmt.Sprintf("producer-%s", defaultTopic))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect the nats server: %w", err)
+	}
+	protocol, err := jetstream.New(conn, jetstream.WithSenderTopic(defaultTopic))
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return protocol, nil
+}
+
 func handleProducerEvents(log *zap.SugaredLogger, eventChan chan kafka.Event, transportFailureThreshold int,
 	eventErrorHandler func(event *kafka.Message),
 ) {
@@ -10,12 +10,13 @@ const (
 	ChunkOffsetKey = "extoffset" // ChunkOffsetKey is the key used for message fragment offset header.
 )
 
-// indicate the transport type, only support kafka or go chan
+// indicate the transport type, support kafka, nats(jetstream) or go chan
 type TransportType string
 
 const (
 	// transportType values
 	Kafka TransportType = "kafka"
+	Nats  TransportType = "nats"
 	Chan  TransportType = "chan"
 	Rest  TransportType = "rest"
 )
@@ -38,11 +39,45 @@ type TransportInternalConfig struct {
 	EnableDatabaseOffset bool
 	// set the kafka credential in the transport controller
 	KafkaCredential   *KafkaConfig
+	NatsCredential    *NatsConfig
 	RestfulCredential *RestfulConfig
 	Extends           map[string]interface{}
 	FailureThreshold  int
 }
 
+// GetClusterTopic returns the spec and status topics of the credential selected by the transport type
+func (c *TransportInternalConfig) GetClusterTopic() *ClusterTopic {
+	if c.TransportType == string(Nats) && c.NatsCredential != nil {
+		return &ClusterTopic{SpecTopic: c.NatsCredential.SpecTopic, StatusTopic: c.NatsCredential.StatusTopic}
+	}
+	if c.KafkaCredential != nil {
+		return &ClusterTopic{SpecTopic: c.KafkaCredential.SpecTopic, StatusTopic: c.KafkaCredential.StatusTopic}
+	}
+	return &ClusterTopic{}
+}
+
+// GetConsumerGroupID returns the consumer group(kafka) or durable consumer name(nats) of the selected credential
+func (c *TransportInternalConfig) GetConsumerGroupID() string {
+	if c.TransportType == string(Nats) && c.NatsCredential != nil {
+		return c.NatsCredential.ConsumerGroupID
+	}
+	if c.KafkaCredential != nil {
+		return c.KafkaCredential.ConsumerGroupID
+	}
+	return ""
+}
+
+// GetCertificate returns the certificates of the credential selected by the transport type, or nil if it isn't loaded
+func (c *TransportInternalConfig) GetCertificate() TransportCertificate {
+	if c.TransportType == string(Nats) && c.NatsCredential != nil {
+		return c.NatsCredential
+	}
+	if c.KafkaCredential != nil {
+		return c.KafkaCredential
+	}
+	return nil
+}
+
 // KafkaInternalConfig specifics the configuration for the global hub manager, agent, or even inventory
 type KafkaInternalConfig struct {
 	ClusterIdentity string
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+)
+
+// ParseCertificate parses the first certificate of the PEM encoded data
+func ParseCertificate(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, fmt.Errorf("failed to decode the PEM certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// CertificateKeyID returns the hex encoded subject key identifier of the first certificate, it identifies the CA
+// which issues the certificates with the same authority key identifier
+func CertificateKeyID(certPEM []byte) (string, error) {
+	cert, err := ParseCertificate(certPEM)
+	if err != nil {
+		return "", err
+	}
+	if len(cert.SubjectKeyId) == 0 {
+		return "", fmt.Errorf("the certificate %s has no subject key identifier", cert.Subject.CommonName)
+	}
+	return hex.EncodeToString(cert.SubjectKeyId), nil
+}
+
+// CertificateIssuerKeyID returns the hex encoded authority key identifier of the first certificate, it's the key id
+// of the CA issued the certificate
+func CertificateIssuerKeyID(certPEM []byte) (string, error) {
+	cert, err := ParseCertificate(certPEM)
+	if err != nil {
+		return "", err
+	}
+	if len(cert.AuthorityKeyId) == 0 {
+		return "", fmt.Errorf("the certificate %s has no authority key identifier", cert.Subject.CommonName)
+	}
+	return hex.EncodeToString(cert.AuthorityKeyId), nil
+}
+
+// KeyMatchesCertificate returns whether the PEM encoded private key, in PKCS#8, PKCS#1 or SEC 1 form, is the key of
+// the first certificate, e.g. the key of the CA is checked before it signs the certificates
+func KeyMatchesCertificate(keyPEM, certPEM []byte) (bool, error) {
+	cert, err := ParseCertificate(certPEM)
+	if err != nil {
+		return false, err
+	}
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return false, fmt.Errorf("failed to decode the PEM private key")
+	}
+	var key crypto.PrivateKey
+	if key, err = x509.ParsePKCS8PrivateKey(block.Bytes); err != nil {
+		if key, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			if key, err = x509.ParseECPrivateKey(block.Bytes); err != nil {
+				return false, fmt.Errorf("failed to parse the private key")
+			}
+		}
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return false, fmt.Errorf("the private key isn't a signer")
+	}
+	public, ok := signer.Public().(interface{ Equal(crypto.PublicKey) bool })
+	return ok && public.Equal(cert.PublicKey), nil
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCertificateKeyID(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	require.NoError(t, err)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	clientTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "test-client"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientDER, err := x509.CreateCertificate(rand.Reader, clientTemplate, caCert, &clientKey.PublicKey, caKey)
+	require.NoError(t, err)
+
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+	clientPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientDER})
+
+	caKeyID, err := CertificateKeyID(caPEM)
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(caCert.SubjectKeyId), caKeyID)
+
+	issuerKeyID, err := CertificateIssuerKeyID(clientPEM)
+	require.NoError(t, err)
+	require.Equal(t, caKeyID, issuerKeyID)
+
+	// the client certificate isn't generated with the subject key id
+	_, err = CertificateKeyID(clientPEM)
+	require.Error(t, err)
+
+	_, err = CertificateIssuerKeyID([]byte("invalid"))
+	require.Error(t, err)
+}
+
+func TestKeyMatchesCertificate(t *testing.T) {
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	require.NoError(t, err)
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER})
+
+	pkcs8Bytes, err := x509.MarshalPKCS8PrivateKey(caKey)
+	require.NoError(t, err)
+	ecBytes, err := x509.MarshalECPrivateKey(caKey)
+	require.NoError(t, err)
+	for _, keyPEM := range [][]byte{
+		pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8Bytes}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: ecBytes}),
+	} {
+		matched, err := KeyMatchesCertificate(keyPEM, caPEM)
+		require.NoError(t, err)
+		require.True(t, matched)
+	}
+
+	// the key of another CA doesn't match
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	otherBytes, err := x509.MarshalPKCS8PrivateKey(otherKey)
+	require.NoError(t, err)
+	matched, err := KeyMatchesCertificate(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: otherBytes}),
+		caPEM)
+	require.NoError(t, err)
+	require.False(t, matched)
+
+	_, err = KeyMatchesCertificate([]byte("invalid"), caPEM)
+	require.Error(t, err)
+}
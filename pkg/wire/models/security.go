@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // SecurityAlertCounts contains a summary of the security alerts from a hub.
 type SecurityAlertCounts struct {
 	// Low is the total number of low severity alerts.
@@ -24,3 +26,63 @@ type SecurityAlertCounts struct {
 	// This should follow the format: "<namespace>/<name>"
 	Source string `json:"source,omitempty"`
 }
+
+// SecurityViolation contains the details of a policy violation of a workload in a cluster of a hub.
+type SecurityViolation struct {
+	// ID is the identifier of the alert in the Central instance.
+	ID string `json:"id"`
+
+	// PolicyID is the identifier of the violated policy.
+	PolicyID string `json:"policy_id,omitempty"`
+
+	// PolicyName is the name of the violated policy.
+	PolicyName string `json:"policy_name,omitempty"`
+
+	// Severity is the severity of the violated policy, for example HIGH_SEVERITY.
+	Severity string `json:"severity,omitempty"`
+
+	// Categories are the categories of the violated policy.
+	Categories []string `json:"categories,omitempty"`
+
+	// LifecycleStage is the stage where the violation was detected: DEPLOY, BUILD or RUNTIME.
+	LifecycleStage string `json:"lifecycle_stage,omitempty"`
+
+	// State is the state of the violation: ACTIVE, SNOOZED, RESOLVED or ATTEMPTED.
+	State string `json:"state,omitempty"`
+
+	// ClusterName is the name of the cluster where the violation was detected.
+	ClusterName string `json:"cluster_name,omitempty"`
+
+	// ClusterID is the identifier of the cluster in the Central instance.
+	ClusterID string `json:"cluster_id,omitempty"`
+
+	// Namespace is the namespace of the violating resource.
+	Namespace string `json:"namespace,omitempty"`
+
+	// DeploymentName is the name of the violating deployment, empty if the resource isn't a deployment.
+	DeploymentName string `json:"deployment_name,omitempty"`
+
+	// DeploymentID is the identifier of the violating deployment.
+	DeploymentID string `json:"deployment_id,omitempty"`
+
+	// ResourceType is the type of the violating resource, for example DEPLOYMENT.
+	ResourceType string `json:"resource_type,omitempty"`
+
+	// EnforcementAction is the action taken to enforce the policy.
+	EnforcementAction string `json:"enforcement_action,omitempty"`
+
+	// EnforcementCount is the number of times the policy was enforced.
+	EnforcementCount int `json:"enforcement_count,omitempty"`
+
+	// Time is the time when the violation was detected.
+	Time time.Time `json:"time"`
+
+	// DetailURL is the URL where the user can see the details of the violation in the Stackrox Central UI:
+	//
+	//	https://central-rhacs-operator.apps.../main/violations/<id>
+	DetailURL string `json:"detail_url,omitempty"`
+
+	// Source is the Central CR instance from which the data was retrieved.
+	// This should follow the format: "<namespace>/<name>"
+	Source string `json:"source,omitempty"`
+}
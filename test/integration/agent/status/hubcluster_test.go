@@ -10,6 +10,7 @@ import (
 	configv1 "github.com/openshift/api/config/v1"
 	routev1 "github.com/openshift/api/route/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/stolostron/multicluster-global-hub/pkg/bundle/cluster"
@@ -127,4 +128,31 @@ var _ = Describe("HubClusterInfo", Ordered, func() {
 			return nil
 		}, 50*time.Second, 1*time.Second).Should(Succeed())
 	})
+
+	It("should get the inventory in the cluster info", func() {
+		By("Create the node in the managed hub cluster")
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "hub-inventory-node"}}
+		Expect(runtimeClient.Create(ctx, node)).Should(Succeed())
+		node.Status.Allocatable = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("16Gi"),
+		}
+		Expect(runtimeClient.Status().Update(ctx, node)).Should(Succeed())
+
+		By("Check the capacity and the kubernetes version are carried by the hub cluster info")
+		Eventually(func() error {
+			evt := <-hubInfoConsumer.EventChan()
+			clusterInfo := &cluster.HubClusterInfo{}
+			if err := evt.DataAs(clusterInfo); err != nil {
+				return err
+			}
+			if clusterInfo.KubernetesVersion == "" {
+				return fmt.Errorf("the kubernetes version isn't reported")
+			}
+			if clusterInfo.Capacity == nil || clusterInfo.Capacity.AllocatableCPUMillicores != 4000 {
+				return fmt.Errorf("want 4000 allocatable cpu millicores, got %v", clusterInfo.Capacity)
+			}
+			return nil
+		}, 50*time.Second, 1*time.Second).Should(Succeed())
+	})
 })
@@ -0,0 +1,101 @@
+package nonk8sapi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// go test ./test/integration/manager/api -v -ginkgo.focus "leaf hub inventory"
+var _ = Describe("leaf hub inventory", Ordered, func() {
+	var db *gorm.DB
+	var router *gin.Engine
+
+	BeforeAll(func() {
+		err := database.InitGormInstance(&database.DatabaseConfig{
+			URL:        testPostgres.URI,
+			Dialect:    database.PostgresDialect,
+			CaCertPath: "ca-cert-path",
+			PoolSize:   2,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		db = database.GetGorm()
+
+		router, err = restapis.SetupRouter(&restapis.RestApiServerConfig{
+			ServerBasePath: "/global-hub-api/v1",
+			ClusterAPIURL:  testAuthServer.URL,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(db.Exec(`INSERT INTO status.leaf_hubs (leaf_hub_name, cluster_id, payload) VALUES
+			('inventory-hub1', '00000000-0000-0000-0000-000000000011', '{"clusterId": "00000000-0000-0000-0000-000000000011",
+				"mchVersion": "2.13.0", "openshiftVersion": "4.18.5", "kubernetesVersion": "v1.31.6",
+				"mceVersion": "2.8.0", "mchComponents": ["console", "grc"],
+				"capacity": {"nodeCount": 3, "allocatableCPUMillicores": 12000, "allocatableMemoryBytes": 51539607552},
+				"managedClusters": {"total": 10, "available": 9, "unavailable": 1, "unknown": 0},
+				"addons": {"work-manager": {"total": 10, "available": 9, "degraded": 1, "unavailable": 0}}}'),
+			('inventory-hub2', '00000000-0000-0000-0000-000000000012', '{"clusterId": "00000000-0000-0000-0000-000000000012",
+				"mchVersion": "2.12.2"}')`).Error).To(Succeed())
+		Expect(db.Exec(`INSERT INTO status.leaf_hub_heartbeats (leaf_hub_name, last_timestamp, status)
+			VALUES ('inventory-hub1', now(), 'active')`).Error).To(Succeed())
+	})
+
+	AfterAll(func() {
+		Expect(db.Exec(`DELETE FROM status.leaf_hubs WHERE leaf_hub_name LIKE 'inventory-hub%'`).Error).To(Succeed())
+		Expect(db.Exec(`DELETE FROM status.leaf_hub_heartbeats WHERE leaf_hub_name LIKE 'inventory-hub%'`).
+			Error).To(Succeed())
+	})
+
+	list := func(query string) []map[string]interface{} {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("GET", "/global-hub-api/v1/leafhubs"+query, nil)
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(http.StatusOK))
+		result := []map[string]interface{}{}
+		Expect(json.Unmarshal(w.Body.Bytes(), &result)).To(Succeed())
+		return result
+	}
+
+	It("store the inventory in the generated columns", func() {
+		var nodeCount, clusterCount int
+		var cpu, memory int64
+		Expect(db.Raw(`SELECT node_count, allocatable_cpu_millicores, allocatable_memory_bytes, managed_cluster_count
+			FROM status.leaf_hubs WHERE leaf_hub_name = 'inventory-hub1'`).Row().
+			Scan(&nodeCount, &cpu, &memory, &clusterCount)).To(Succeed())
+		Expect(nodeCount).To(Equal(3))
+		Expect(cpu).To(Equal(int64(12000)))
+		Expect(memory).To(Equal(int64(48 << 30)))
+		Expect(clusterCount).To(Equal(10))
+	})
+
+	It("list the inventory of the leaf hubs", func() {
+		hubs := list("?leafHubName=inventory-hub1")
+		Expect(hubs).To(HaveLen(1))
+		Expect(hubs[0]["status"]).To(Equal("active"))
+		info := hubs[0]["info"].(map[string]interface{})
+		Expect(info["openshiftVersion"]).To(Equal("4.18.5"))
+		Expect(info["mceVersion"]).To(Equal("2.8.0"))
+		Expect(info["mchComponents"]).To(ConsistOf("console", "grc"))
+		Expect(info["capacity"].(map[string]interface{})["nodeCount"]).To(BeEquivalentTo(3))
+		Expect(info["managedClusters"].(map[string]interface{})["unavailable"]).To(BeEquivalentTo(1))
+
+		// the leaf hub without the heartbeat and the inventory reported by the legacy agent
+		hubs = list("?mchVersion=2.12.2")
+		Expect(hubs).To(HaveLen(1))
+		Expect(hubs[0]["leafHubName"]).To(Equal("inventory-hub2"))
+		Expect(hubs[0]["status"]).To(BeEmpty())
+		Expect(hubs[0]["info"].(map[string]interface{})).NotTo(HaveKey("capacity"))
+
+		Expect(list("?openshiftVersion=4.18.5")).To(HaveLen(1))
+		Expect(list("?openshiftVersion=4.12.0")).To(BeEmpty())
+	})
+})
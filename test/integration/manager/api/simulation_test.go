@@ -0,0 +1,126 @@
+package nonk8sapi_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gorm.io/gorm"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/restapis"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+)
+
+// go test ./test/integration/manager/api -v -ginkgo.focus "placement simulation"
+var _ = Describe("placement simulation", Ordered, func() {
+	var db *gorm.DB
+	var router *gin.Engine
+	hubName := "simulation-hub1"
+	prod1ID, prod2ID, dev1ID := uuid.New().String(), uuid.New().String(), uuid.New().String()
+	policyID := uuid.New().String()
+
+	BeforeAll(func() {
+		err := database.InitGormInstance(&database.DatabaseConfig{
+			URL:        testPostgres.URI,
+			Dialect:    database.PostgresDialect,
+			CaCertPath: "ca-cert-path",
+			PoolSize:   2,
+		})
+		Expect(err).NotTo(HaveOccurred())
+		db = database.GetGorm()
+
+		router, err = restapis.SetupRouter(&restapis.RestApiServerConfig{
+			ServerBasePath:       "/global-hub-api/v1",
+			ClusterAPIURL:        testAuthServer.URL,
+			EnableGlobalResource: true,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for id, payload := range map[string]string{
+			prod1ID: `{"metadata": {"name": "sim-prod1", "labels": {"env": "prod"}}}`,
+			prod2ID: `{"metadata": {"name": "sim-prod2", "labels": {"env": "prod"}},
+				"spec": {"taints": [{"key": "cluster.open-cluster-management.io/unreachable", "effect": "NoSelect"}]}}`,
+			dev1ID: `{"metadata": {"name": "sim-dev1", "labels": {"env": "dev"}}}`,
+		} {
+			Expect(db.Exec(`INSERT INTO status.managed_clusters (cluster_id,leaf_hub_name,payload,error)
+				VALUES (?, ?, ?, 'none')`, id, hubName, payload).Error).To(Succeed())
+		}
+		Expect(db.Exec(`INSERT INTO spec.policies (id,payload) VALUES (?, ?)`, policyID,
+			`{"metadata": {"name": "sim-policy", "namespace": "default"}}`).Error).To(Succeed())
+		Expect(db.Exec(`INSERT INTO status.compliance (policy_id,cluster_name,leaf_hub_name,error,compliance)
+			VALUES (?, 'sim-prod1', ?, 'none', 'non_compliant')`, policyID, hubName).Error).To(Succeed())
+	})
+
+	AfterAll(func() {
+		Expect(db.Exec(`DELETE FROM status.managed_clusters WHERE leaf_hub_name = ?`, hubName).Error).To(Succeed())
+		Expect(db.Exec(`DELETE FROM status.compliance WHERE policy_id = ?`, policyID).Error).To(Succeed())
+		Expect(db.Exec(`DELETE FROM spec.policies WHERE id = ?`, policyID).Error).To(Succeed())
+	})
+
+	simulate := func(body string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req, err := http.NewRequest("POST", "/global-hub-api/v1/simulation", bytes.NewBufferString(body))
+		Expect(err).ToNot(HaveOccurred())
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			return w.Code, nil
+		}
+		result := map[string]interface{}{}
+		Expect(json.Unmarshal(w.Body.Bytes(), &result)).To(Succeed())
+		for _, hub := range result["hubs"].([]interface{}) {
+			if hub.(map[string]interface{})["leafHubName"] == hubName {
+				return w.Code, hub.(map[string]interface{})
+			}
+		}
+		return w.Code, nil
+	}
+
+	It("select the clusters and predict the compliance by the existing policy", func() {
+		code, hub := simulate(`{
+			"placement": {"spec": {"predicates": [{"requiredClusterSelector": {
+				"labelSelector": {"matchLabels": {"env": "prod"}}}}]}},
+			"policy": {"metadata": {"name": "sim-policy", "namespace": "default"}}
+		}`)
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(hub).NotTo(BeNil())
+		Expect(hub["matchedClusters"]).To(BeEquivalentTo(1))
+		Expect(hub["clusters"]).To(ConsistOf(map[string]interface{}{
+			"name":             "sim-prod1",
+			"clusterId":        prod1ID,
+			"compliance":       "non_compliant",
+			"complianceSource": "global",
+		}))
+
+		By("tolerate the taint and predict the unknown compliance of the cluster without the history")
+		code, hub = simulate(`{
+			"placement": {"spec": {"tolerations": [{"operator": "Exists"}], "predicates": [{"requiredClusterSelector": {
+				"labelSelector": {"matchLabels": {"env": "prod"}}}}]}},
+			"policy": {"metadata": {"name": "sim-policy", "namespace": "default"}}
+		}`)
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(hub["matchedClusters"]).To(BeEquivalentTo(2))
+		Expect(hub["compliance"]).To(Equal(map[string]interface{}{
+			"non_compliant": float64(1),
+			"unknown":       float64(1),
+		}))
+
+		By("the spec tables aren't changed by the simulation")
+		var count int64
+		Expect(db.Raw(`SELECT COUNT(*) FROM spec.policies WHERE payload -> 'metadata' ->> 'name' = 'sim-policy'`).
+			Scan(&count).Error).To(Succeed())
+		Expect(count).To(Equal(int64(1)))
+	})
+
+	It("reject the invalid placement", func() {
+		code, _ := simulate(`{"policy": {"metadata": {"name": "sim-policy"}}}`)
+		Expect(code).To(Equal(http.StatusBadRequest))
+		code, _ = simulate(`{"placement": {"spec": {"predicates": [{"requiredClusterSelector": {
+			"celSelector": {"celExpressions": ["true"]}}}]}}}`)
+		Expect(code).To(Equal(http.StatusBadRequest))
+	})
+})
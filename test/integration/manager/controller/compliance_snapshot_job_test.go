@@ -0,0 +1,86 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-co-op/gocron"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/cronjob/task"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// go test ./test/integration/manager/controller -v -ginkgo.focus "LocalComplianceSnapshot"
+var _ = Describe("LocalComplianceSnapshot", Ordered, func() {
+	const (
+		policyID  = "00000000-0000-0000-0000-000000000004"
+		clusterID = "00000004-0000-0000-0000-000000000001"
+	)
+
+	complianceAt := func(at time.Time) (string, error) {
+		compliance := []models.LocalComplianceAt{}
+		err := db.Raw(`SELECT * FROM history.get_local_compliance_at(?::timestamp) WHERE policy_id = ?`,
+			at.Format("2006-01-02 15:04:05.999999"), policyID).Scan(&compliance).Error
+		if err != nil {
+			return "", err
+		}
+		if len(compliance) != 1 {
+			return "", fmt.Errorf("expected 1 compliance, but got %d", len(compliance))
+		}
+		return compliance[0].Compliance, nil
+	}
+
+	It("rebuild the compliance by the snapshot and the events after it", func() {
+		By("Create the data to the local_status.compliance table")
+		err := db.Exec(`INSERT INTO local_status.compliance (policy_id, cluster_name, leaf_hub_name, error,
+			compliance, cluster_id) VALUES (?, 'managedcluster-1', 'hub4', 'none', 'compliant', ?)`,
+			policyID, clusterID).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Create the non compliant event by the clock of the hub, it's overridden by the current compliance")
+		hubEventAt := time.Now().Add(-time.Hour).Truncate(time.Second)
+		err = db.Exec(`INSERT INTO event.local_policies (event_name, policy_id, cluster_id, cluster_name,
+			leaf_hub_name, message, reason, count, compliance, created_at) VALUES ('policy-event-0', ?, ?,
+			'managedcluster-1', 'hub4', 'violation', 'PolicyStatusSync', 1, 'non_compliant', ?)`,
+			policyID, clusterID, hubEventAt).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Snapshot the local_status.compliance to history.local_compliance_snapshots")
+		s := gocron.NewScheduler(time.UTC)
+		_, err = s.Every(1).Hour().DoWithJobDetails(task.LocalComplianceSnapshot, ctx)
+		Expect(err).ToNot(HaveOccurred())
+		s.StartAsync()
+		defer s.Clear()
+
+		var snapshotAt time.Time
+		Eventually(func() error {
+			snapshot := &models.LocalComplianceSnapshot{}
+			if err := db.Where("policy_id = ?", policyID).First(snapshot).Error; err != nil {
+				return err
+			}
+			snapshotAt = snapshot.SnapshotAt
+			return nil
+		}, 10*time.Second, 1*time.Second).ShouldNot(HaveOccurred())
+		// the snapshot is taken at the time of the latest event of the hub
+		Expect(snapshotAt.Format(time.DateTime)).To(Equal(hubEventAt.Format(time.DateTime)))
+
+		By("Create the non compliant event after the snapshot")
+		eventAt := snapshotAt.Add(time.Minute)
+		err = db.Exec(`INSERT INTO event.local_policies (event_name, policy_id, cluster_id, cluster_name,
+			leaf_hub_name, message, reason, count, compliance, created_at) VALUES ('policy-event-1', ?, ?,
+			'managedcluster-1', 'hub4', 'violation', 'PolicyStatusSync', 1, 'non_compliant', ?)`,
+			policyID, clusterID, eventAt).Error
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Rebuild the compliance at the snapshot and after the event")
+		compliance, err := complianceAt(snapshotAt.Add(time.Second))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(compliance).To(Equal("compliant"))
+
+		compliance, err = complianceAt(eventAt.Add(time.Second))
+		Expect(err).ToNot(HaveOccurred())
+		Expect(compliance).To(Equal("non_compliant"))
+	})
+})
@@ -0,0 +1,137 @@
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/backup"
+)
+
+// go test ./test/integration/manager/controller -v -ginkgo.focus "DatabaseBackup"
+var _ = Describe("DatabaseBackup", Ordered, func() {
+	const (
+		clusterID = "00000005-0000-0000-0000-000000000001"
+		topic     = "gh-status@0"
+	)
+
+	var store backup.ObjectStore
+
+	BeforeAll(func() {
+		var err error
+		store, err = backup.NewObjectStore("file://" + GinkgoT().TempDir())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	hubNames := func() []string {
+		names := []string{}
+		Expect(db.Raw(`SELECT leaf_hub_name FROM status.leaf_hubs WHERE cluster_id = ? ORDER BY leaf_hub_name`,
+			clusterID).Scan(&names).Error).ToNot(HaveOccurred())
+		return names
+	}
+
+	offset := func() string {
+		var payload string
+		Expect(db.Raw(`SELECT payload->>'offset' FROM status.transport WHERE name = ?`, topic).
+			Scan(&payload).Error).ToNot(HaveOccurred())
+		return payload
+	}
+
+	It("restore the incremental backup with the transport offsets", func() {
+		By("Create the leaf hubs and the transport offset")
+		Expect(db.Exec(`INSERT INTO status.leaf_hubs (leaf_hub_name, cluster_id, payload) VALUES
+			('backup-hub1', ?, '{}'), ('backup-hub2', ?, '{}')`, clusterID, clusterID).Error).ToNot(HaveOccurred())
+		Expect(db.Exec(`INSERT INTO status.transport (name, payload) VALUES (?, '{"offset": 10}')`,
+			topic).Error).ToNot(HaveOccurred())
+
+		By("Run the full backup")
+		backupper := backup.NewBackupper(store, time.Hour)
+		full, err := backupper.Backup(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(full.Base).To(Equal(full.ID))
+		Expect(full.Tables).To(HaveKey("status.leaf_hubs"))
+		Expect(full.Tables["status.leaf_hubs"].Mode).To(Equal(backup.ModeIncremental))
+
+		By("Update the leaf hubs and the offset, then run the incremental backup")
+		// the backup ids are in seconds
+		time.Sleep(time.Second)
+		Expect(db.Exec(`DELETE FROM status.leaf_hubs WHERE leaf_hub_name = 'backup-hub1'`).Error).
+			ToNot(HaveOccurred())
+		Expect(db.Exec(`INSERT INTO status.leaf_hubs (leaf_hub_name, cluster_id, payload) VALUES
+			('backup-hub3', ?, '{}')`, clusterID).Error).ToNot(HaveOccurred())
+		Expect(db.Exec(`UPDATE status.transport SET payload = '{"offset": 20}' WHERE name = ?`,
+			topic).Error).ToNot(HaveOccurred())
+		Expect(db.Exec(`INSERT INTO event.managed_clusters (event_namespace, event_name, cluster_name, cluster_id,
+			leaf_hub_name, event_type) VALUES ('default', 'backup-event', 'cluster1', ?, 'backup-hub2', 'Normal')`,
+			clusterID).Error).ToNot(HaveOccurred())
+
+		incremental, err := backupper.Backup(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(incremental.Base).To(Equal(full.ID))
+		Expect(incremental.Parent).To(Equal(full.ID))
+		Expect(incremental.Tables["status.leaf_hubs"].Keys).NotTo(BeNil())
+
+		By("Export the partition of the new event again, and reuse the unchanged partitions")
+		exported := []string{}
+		for name, entry := range incremental.Tables {
+			previous, found := full.Tables[name]
+			if entry.Parent != "event.managed_clusters" || !found {
+				continue
+			}
+			if entry.Fingerprint == previous.Fingerprint {
+				Expect(entry.Objects).To(Equal(previous.Objects))
+			} else {
+				Expect(entry.Objects).NotTo(Equal(previous.Objects))
+				exported = append(exported, name)
+			}
+		}
+		Expect(exported).To(HaveLen(1))
+
+		By("Change the database after the backup")
+		Expect(db.Exec(`DELETE FROM status.leaf_hubs WHERE cluster_id = ?`, clusterID).Error).ToNot(HaveOccurred())
+		Expect(db.Exec(`DELETE FROM event.managed_clusters WHERE cluster_id = ?`, clusterID).Error).
+			ToNot(HaveOccurred())
+		Expect(db.Exec(`UPDATE status.transport SET payload = '{"offset": 30}' WHERE name = ?`,
+			topic).Error).ToNot(HaveOccurred())
+
+		By("Restore the latest backup")
+		Expect(backup.Restore(ctx, store, backup.LatestBackup)).To(Succeed())
+		Expect(hubNames()).To(Equal([]string{"backup-hub2", "backup-hub3"}))
+		Expect(offset()).To(Equal("20"))
+
+		By("Restore the full backup")
+		Expect(backup.Restore(ctx, store, full.ID)).To(Succeed())
+		Expect(hubNames()).To(Equal([]string{"backup-hub1", "backup-hub2"}))
+		Expect(offset()).To(Equal("10"))
+	})
+
+	It("restart the interrupted backup in a single snapshot", func() {
+		By("Record the backup interrupted after exporting the transport offsets")
+		interrupted := &backup.Manifest{
+			ID:        time.Now().Add(time.Hour).UTC().Format("20060102T150405Z"),
+			StartedAt: time.Now(),
+			Tables: map[string]*backup.TableBackup{
+				"status.transport": {
+					Schema: "status", Name: "transport", Mode: backup.ModeFull,
+					Objects: []backup.ObjectRef{{Key: "stale", SHA256: "stale"}},
+				},
+			},
+		}
+		interrupted.Base = interrupted.ID
+		data, err := json.Marshal(interrupted)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(store.Put(ctx, path.Join(interrupted.ID, "manifest.json"), bytes.NewReader(data))).To(Succeed())
+
+		By("Restart the backup, the transport offsets are exported again with the other tables")
+		manifest, err := backup.NewBackupper(store, time.Hour).Backup(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(manifest.ID).To(Equal(interrupted.ID))
+		Expect(manifest.CompletedAt).NotTo(BeNil())
+		Expect(manifest.Tables).To(HaveKey("status.leaf_hubs"))
+		Expect(manifest.Tables["status.transport"].Objects[0].SHA256).NotTo(Equal("stale"))
+	})
+})
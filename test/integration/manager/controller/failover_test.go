@@ -0,0 +1,181 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/configs"
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/failover"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+)
+
+// go test ./test/integration/manager/controller -v -ginkgo.focus "Failover"
+var _ = Describe("Failover", Ordered, func() {
+	const (
+		namespace   = "failover-test"
+		standbyName = "standby-hub"
+		activeName  = "active-hub"
+		// the lease is in the same cluster as the global hubs in the test, it's in the witness cluster in practice
+		leaseNamespace = "default"
+	)
+
+	var leaseClient client.Client
+	newLease := func(holder string) *failover.Lease {
+		return failover.NewLease(leaseClient, leaseNamespace, holder, time.Minute)
+	}
+	failoverConfig := func(name string) *configs.FailoverConfig {
+		return &configs.FailoverConfig{
+			Role:               constants.FailoverRoleStandby,
+			Name:               name,
+			PrimaryDatabaseURL: "postgres://postgres@127.0.0.1:1/hoh?sslmode=disable",
+			HeartbeatInterval:  time.Second,
+			Timeout:            time.Minute,
+		}
+	}
+	leaseHolder := func() string {
+		lease := &coordinationv1.Lease{}
+		if err := leaseClient.Get(ctx, client.ObjectKey{Namespace: leaseNamespace,
+			Name: "multicluster-global-hub-failover"}, lease); err != nil {
+			return err.Error()
+		}
+		return *lease.Spec.HolderIdentity
+	}
+
+	BeforeAll(func() {
+		var err error
+		leaseClient, err = client.New(cfg, client.Options{})
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	It("refresh the heartbeat and publish the tables by the active global hub", func() {
+		heartbeatCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		heartbeater := failover.NewHeartbeater(failoverConfig(activeName), newLease(activeName))
+		Expect(heartbeater.Acquire(heartbeatCtx)).To(Succeed())
+		go func() {
+			_ = heartbeater.Start(heartbeatCtx)
+		}()
+
+		Eventually(func() error {
+			heartbeat := &models.GlobalHubHeartbeat{}
+			return db.Where("name = ? AND role = ?", activeName, constants.FailoverRoleActive).
+				First(heartbeat).Error
+		}, 10*time.Second, time.Second).ShouldNot(HaveOccurred())
+
+		var publications int64
+		Expect(db.Raw("SELECT COUNT(*) FROM pg_publication WHERE pubname = 'global_hub_failover'").
+			Scan(&publications).Error).ToNot(HaveOccurred())
+		Expect(publications).To(Equal(int64(1)))
+
+		// the table without the primary key is published with the full replica identity
+		var replicaIdentity string
+		Expect(db.Raw(`SELECT relreplident::text FROM pg_class WHERE oid = 'status.leaf_hub_heartbeats'::regclass`).
+			Scan(&replicaIdentity).Error).ToNot(HaveOccurred())
+		Expect(replicaIdentity).To(Equal("f"))
+
+		// the active global hub holds the failover lease
+		Expect(leaseHolder()).To(Equal(activeName))
+	})
+
+	It("promote the standby global hub once the heartbeat of the active one is expired", func() {
+		By("Create the standby multiclusterglobalhub")
+		Expect(mgr.GetClient().Create(ctx, &corev1.Namespace{
+			ObjectMeta: metav1.ObjectMeta{Name: namespace},
+		})).To(Succeed())
+		mgh := &unstructured.Unstructured{}
+		mgh.SetGroupVersionKind(schema.GroupVersionKind{
+			Group: "operator.open-cluster-management.io", Version: "v1alpha4", Kind: "MulticlusterGlobalHub",
+		})
+		mgh.SetName("multiclusterglobalhub")
+		mgh.SetNamespace(namespace)
+		mgh.SetAnnotations(map[string]string{constants.GHFailoverRoleAnnotation: constants.FailoverRoleStandby})
+		Expect(mgr.GetClient().Create(ctx, mgh)).To(Succeed())
+
+		By("Expire the heartbeat of the active global hub, and subscribe to the unreachable database")
+		Expect(db.Exec(`UPDATE status.global_hub_heartbeats SET heartbeat_at = LOCALTIMESTAMP - interval '1 hour'
+			WHERE name = ?`, activeName).Error).ToNot(HaveOccurred())
+		primaryURL := failoverConfig(standbyName).PrimaryDatabaseURL
+		Expect(db.Exec(`CREATE SUBSCRIPTION global_hub_failover CONNECTION '` + primaryURL +
+			`' PUBLICATION global_hub_failover WITH (connect = false)`).Error).ToNot(HaveOccurred())
+
+		By("The standby global hub isn't promoted while the active one holds the failover lease, e.g. a partition")
+		standby, err := failover.NewStandby(cfg, namespace, failoverConfig(standbyName), newLease(standbyName))
+		Expect(err).ToNot(HaveOccurred())
+		partitionCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		defer cancel()
+		promoted, err := standby.Run(partitionCtx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promoted).To(BeFalse())
+		Expect(leaseHolder()).To(Equal(activeName))
+
+		By("Expire the failover lease of the active global hub")
+		lease := &coordinationv1.Lease{}
+		Expect(leaseClient.Get(ctx, client.ObjectKey{Namespace: leaseNamespace,
+			Name: "multicluster-global-hub-failover"}, lease)).To(Succeed())
+		lease.Spec.RenewTime = &metav1.MicroTime{Time: time.Now().Add(-time.Hour)}
+		Expect(leaseClient.Update(ctx, lease)).To(Succeed())
+
+		By("Run the standby global hub until it's promoted")
+		standby, err = failover.NewStandby(cfg, namespace, failoverConfig(standbyName), newLease(standbyName))
+		Expect(err).ToNot(HaveOccurred())
+		promoted, err = standby.Run(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promoted).To(BeTrue())
+		Expect(leaseHolder()).To(Equal(standbyName))
+
+		By("Check the subscription is dropped and the roles are switched")
+		var subscriptions int64
+		Expect(db.Raw("SELECT COUNT(*) FROM pg_subscription WHERE subname = 'global_hub_failover'").
+			Scan(&subscriptions).Error).ToNot(HaveOccurred())
+		Expect(subscriptions).To(BeZero())
+
+		heartbeats := []models.GlobalHubHeartbeat{}
+		Expect(db.Order("name").Find(&heartbeats).Error).ToNot(HaveOccurred())
+		Expect(heartbeats).To(HaveLen(2))
+		Expect(heartbeats[0].Name).To(Equal(activeName))
+		Expect(heartbeats[0].Role).To(Equal(constants.FailoverRoleStandby))
+		Expect(heartbeats[1].Name).To(Equal(standbyName))
+		Expect(heartbeats[1].Role).To(Equal(constants.FailoverRoleActive))
+		Expect(heartbeats[1].PromotedAt).NotTo(BeNil())
+
+		Eventually(func() string {
+			current := mgh.DeepCopy()
+			if err := mgr.GetAPIReader().Get(ctx, client.ObjectKeyFromObject(mgh), current); err != nil {
+				return err.Error()
+			}
+			return current.GetAnnotations()[constants.GHFailoverRoleAnnotation]
+		}, 10*time.Second, time.Second).Should(Equal(constants.FailoverRoleActive))
+
+		By("Restart the promoted global hub, it doesn't subscribe to the previous active one")
+		standby, err = failover.NewStandby(cfg, namespace, failoverConfig(standbyName), newLease(standbyName))
+		Expect(err).ToNot(HaveOccurred())
+		promoted, err = standby.Run(ctx)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(promoted).To(BeTrue())
+	})
+
+	It("step down the active global hub once another global hub is promoted", func() {
+		By("The previous active global hub is fenced since it's marked as the standby one by the promotion")
+		err := failover.NewHeartbeater(failoverConfig(activeName), newLease(activeName)).Acquire(ctx)
+		Expect(err).To(MatchError(failover.ErrFenced))
+
+		By("The global hub sees the newer promotion steps down, and isn't started as the active one again")
+		const fencedName = "fenced-hub"
+		err = failover.NewHeartbeater(failoverConfig(fencedName), newLease(fencedName)).Acquire(ctx)
+		Expect(err).To(MatchError(failover.ErrFenced))
+		heartbeat := &models.GlobalHubHeartbeat{}
+		Expect(db.Where("name = ?", fencedName).First(heartbeat).Error).ToNot(HaveOccurred())
+		Expect(heartbeat.Role).To(Equal(constants.FailoverRoleStandby))
+		Expect(leaseHolder()).To(Equal(standbyName))
+	})
+})
@@ -0,0 +1,103 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/pkg/constants"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/transport"
+)
+
+// go test ./test/integration/manager/controller -v -ginkgo.focus "hub maintenance"
+var _ = Describe("hub maintenance", Ordered, func() {
+	const hubName = "maintenance-hub01"
+
+	It("pause the inactivity handling in maintenance and resync the hub on exit", func() {
+		db := database.GetGorm()
+		By("Create the hub which missed the heartbeat for 10 minutes")
+		err := db.Create(&models.LeafHubHeartbeat{
+			Name:         hubName,
+			Status:       hubmanagement.HubActive,
+			LastUpdateAt: time.Now().Add(-10 * time.Minute),
+		}).Error
+		Expect(err).To(Succeed())
+		defer func() {
+			Expect(db.Where("leaf_hub_name = ?", hubName).Delete(&models.LeafHubHeartbeat{}).Error).To(Succeed())
+			Expect(db.Where("leaf_hub_name = ?", hubName).Delete(&models.ResyncRequest{}).Error).To(Succeed())
+		}()
+
+		hubStatus := func() string {
+			hub := models.LeafHubHeartbeat{}
+			Expect(db.Where("leaf_hub_name = ?", hubName).Take(&hub).Error).To(Succeed())
+			return hub.Status
+		}
+
+		By("Enter the maintenance")
+		Expect(hubmanagement.EnterMaintenance(ctx, hubName)).To(Succeed())
+		Expect(hubmanagement.EnterMaintenance(ctx, hubName)).To(Succeed())
+		Expect(hubStatus()).To(Equal(hubmanagement.HubMaintenance))
+		paused, err := hubmanagement.IsStatusPaused(ctx, hubName)
+		Expect(err).To(Succeed())
+		Expect(paused).To(BeTrue())
+		Expect(hubmanagement.EnterMaintenance(ctx, "maintenance-hub-nonexistent")).
+			To(MatchError(hubmanagement.ErrHubNotFound))
+
+		producer := &resyncProducer{}
+		hubManagement := hubmanagement.NewHubManagement(producer, 1*time.Second, 90*time.Second)
+		Expect(hubManagement.Start(ctx)).To(Succeed())
+
+		By("The hub isn't inactivated in the maintenance")
+		Consistently(hubStatus, 3*time.Second, 1*time.Second).Should(Equal(hubmanagement.HubMaintenance))
+
+		By("Exit the maintenance")
+		Expect(hubmanagement.ExitMaintenance(ctx, hubName)).To(Succeed())
+		Eventually(hubStatus, 10*time.Second, 1*time.Second).Should(Equal(hubmanagement.HubActive))
+		paused, err = hubmanagement.IsStatusPaused(ctx, hubName)
+		Expect(err).To(Succeed())
+		Expect(paused).To(BeFalse())
+
+		By("The status paused in the maintenance is requested to resync asynchronously")
+		var requests []models.ResyncRequest
+		Expect(db.Where("leaf_hub_name = ? AND requested_by = ?", hubName, "maintenance").
+			Find(&requests).Error).To(Succeed())
+		Expect(requests).NotTo(BeEmpty())
+		Eventually(producer.resyncedHubs, 30*time.Second, 1*time.Second).Should(ContainElement(hubName))
+
+		hub := models.LeafHubHeartbeat{}
+		Expect(db.Where("leaf_hub_name = ?", hubName).Take(&hub).Error).To(Succeed())
+		Expect(hub.LastUpdateAt).To(BeTemporally(">", time.Now().Add(-time.Minute)))
+	})
+})
+
+// resyncProducer records the destinations of the resync events
+type resyncProducer struct {
+	mutex sync.Mutex
+	hubs  []string
+}
+
+func (p *resyncProducer) SendEvent(ctx context.Context, evt cloudevents.Event) error {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if evt.Type() == constants.ResyncMsgKey {
+		p.hubs = append(p.hubs, evt.Extensions()[constants.CloudEventExtensionKeyClusterName].(string))
+	}
+	return nil
+}
+
+func (p *resyncProducer) Reconnect(config *transport.TransportInternalConfig, topic string) error {
+	return nil
+}
+
+func (p *resyncProducer) resyncedHubs() []string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return append([]string{}, p.hubs...)
+}
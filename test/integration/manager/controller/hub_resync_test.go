@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"time"
+
+	cloudevents "github.com/cloudevents/sdk-go/v2"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/manager/pkg/processes/hubmanagement"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+)
+
+// go test ./test/integration/manager/controller -v -ginkgo.focus "hub resync"
+var _ = Describe("hub resync", Ordered, func() {
+	const hubName = "resync-hub01"
+
+	It("send the resync request to the hub and commit it by the resent bundle", func() {
+		db := database.GetGorm()
+		err := db.Create(&models.LeafHubHeartbeat{
+			Name:         hubName,
+			Status:       hubmanagement.HubActive,
+			LastUpdateAt: time.Now(),
+		}).Error
+		Expect(err).To(Succeed())
+		defer func() {
+			Expect(db.Where("leaf_hub_name = ?", hubName).Delete(&models.LeafHubHeartbeat{}).Error).To(Succeed())
+		}()
+
+		By("Request to resync the managed clusters from the hub")
+		eventTypes, err := hubmanagement.ParseResyncEventTypes([]string{"managedcluster"})
+		Expect(err).To(Succeed())
+		requests, err := hubmanagement.RequestResync(ctx, []string{hubName}, eventTypes, "tester")
+		Expect(err).To(Succeed())
+		Expect(requests).To(HaveLen(1))
+		_, err = hubmanagement.RequestResync(ctx, []string{"resync-hub-nonexistent"}, eventTypes, "tester")
+		Expect(err).To(MatchError(hubmanagement.ErrHubNotFound))
+		duplicated, err := hubmanagement.RequestResync(ctx, []string{hubName, hubName}, eventTypes, "tester")
+		Expect(err).To(Succeed())
+		Expect(duplicated).To(HaveLen(1))
+
+		producer := &resyncProducer{}
+		hubManagement := hubmanagement.NewHubManagement(producer, time.Minute, time.Hour)
+		Expect(hubManagement.Start(ctx)).To(Succeed())
+
+		request := func() models.ResyncRequest {
+			request := models.ResyncRequest{}
+			Expect(db.Where("id = ?", requests[0].ID).Take(&request).Error).To(Succeed())
+			return request
+		}
+		Eventually(func() *time.Time {
+			return request().SentAt
+		}, 30*time.Second, 1*time.Second).ShouldNot(BeNil())
+		Expect(producer.resyncedHubs()).To(ContainElement(hubName))
+
+		By("Skip the managed cluster bundle created before the request is sent")
+		staleEvt := cloudevents.NewEvent()
+		staleEvt.SetSource(hubName)
+		staleEvt.SetType(string(enum.ManagedClusterType))
+		staleEvt.SetTime(request().SentAt.Add(-time.Minute))
+		hubmanagement.CommitResync(ctx, &staleEvt)
+		Expect(request().CommittedAt).To(BeNil())
+
+		By("Commit the resync by the managed cluster bundle from the hub")
+		Eventually(func() *time.Time {
+			evt := cloudevents.NewEvent()
+			evt.SetSource(hubName)
+			evt.SetType(string(enum.ManagedClusterType))
+			evt.SetTime(time.Now())
+			hubmanagement.CommitResync(ctx, &evt)
+			return request().CommittedAt
+		}, 30*time.Second, 1*time.Second).ShouldNot(BeNil())
+	})
+})
@@ -29,11 +29,13 @@ var _ = Describe("scheduler", func() {
 		Expect(err).To(Succeed())
 
 		_, err = scheduler.Every(1).Month(1, 15).At("00:00").Tag(task.RetentionTaskName).
-			DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention)
+			DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention,
+				&task.RetentionOptions{})
 		Expect(err).To(Succeed())
 
 		_, err = scheduler.Every(1).MonthLastDay().At("00:00").Tag(task.RetentionTaskName).
-			DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention)
+			DoWithJobDetails(task.DataRetention, ctx, managerConfig.DatabaseConfig.DataRetention,
+				&task.RetentionOptions{})
 		Expect(err).To(Succeed())
 
 		globalScheduler := cronjob.NewGlobalHubScheduler(scheduler,
@@ -24,8 +24,10 @@ var _ = Describe("HubClusterInfoHandler", Ordered, func() {
 	It("should handle the hub cluster info event", func() {
 		By("Create hubClusterInfo event")
 		data := cluster.HubClusterInfo{
-			ConsoleURL: routeHost,
-			ClusterId:  "00000000-0000-0000-0000-000000000001",
+			ConsoleURL:       routeHost,
+			ClusterId:        "00000000-0000-0000-0000-000000000001",
+			OpenShiftVersion: "4.18.5",
+			Capacity:         &cluster.HubCapacity{NodeCount: 3},
 		}
 
 		version := eventversion.NewVersion()
@@ -54,5 +56,13 @@ var _ = Describe("HubClusterInfoHandler", Ordered, func() {
 			}
 			return fmt.Errorf("not found expected resource on the table")
 		}, 30*time.Second, 100*time.Microsecond).ShouldNot(HaveOccurred())
+
+		By("Check the inventory columns of the leaf hub")
+		var openshiftVersion string
+		var nodeCount int
+		Expect(database.GetGorm().Raw(`SELECT openshift_version, node_count FROM status.leaf_hubs
+			WHERE leaf_hub_name = ?`, leafHubName).Row().Scan(&openshiftVersion, &nodeCount)).To(Succeed())
+		Expect(openshiftVersion).To(Equal("4.18.5"))
+		Expect(nodeCount).To(Equal(3))
 	})
 })
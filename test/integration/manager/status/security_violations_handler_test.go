@@ -0,0 +1,133 @@
+package status
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cecontext "github.com/cloudevents/sdk-go/v2/context"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/stolostron/multicluster-global-hub/pkg/bundle/generic"
+	eventversion "github.com/stolostron/multicluster-global-hub/pkg/bundle/version"
+	"github.com/stolostron/multicluster-global-hub/pkg/database"
+	"github.com/stolostron/multicluster-global-hub/pkg/database/models"
+	"github.com/stolostron/multicluster-global-hub/pkg/enum"
+	wiremodels "github.com/stolostron/multicluster-global-hub/pkg/wire/models"
+)
+
+// go test ./test/integration/manager/status -v -ginkgo.focus "SecurityViolationsHandler"
+var _ = Describe("SecurityViolationsHandler", Ordered, func() {
+	const (
+		leafHubName = "hub1"
+		source      = "rhacs-operator/stackrox-central-services"
+	)
+
+	var (
+		version        = eventversion.NewVersion()
+		statusTopicCtx context.Context
+	)
+
+	BeforeEach(func() {
+		db := database.GetSqlDb()
+		sql := fmt.Sprintf(`TRUNCATE TABLE %s.%s`, database.SecuritySchema, database.SecurityViolationsTable)
+		_, err := db.Query(sql)
+		Expect(err).To(Succeed())
+
+		statusTopicCtx = cecontext.WithTopic(ctx, "event")
+	})
+
+	newViolation := func(id string, detectedAt time.Time) wiremodels.SecurityViolation {
+		return wiremodels.SecurityViolation{
+			ID:             id,
+			PolicyID:       "policy1",
+			PolicyName:     "Latest tag",
+			Severity:       "LOW_SEVERITY",
+			Categories:     []string{"DevOps Best Practices"},
+			LifecycleStage: "DEPLOY",
+			State:          "ACTIVE",
+			ClusterName:    "cluster1",
+			Namespace:      "default",
+			DeploymentName: "nginx",
+			ResourceType:   "DEPLOYMENT",
+			Time:           detectedAt,
+			DetailURL:      "https://hub1/main/violations/" + id,
+			Source:         source,
+		}
+	}
+
+	sendBundle := func(bundle *generic.GenericBundle[wiremodels.SecurityViolation]) {
+		version.Incr()
+		event := ToCloudEvent(leafHubName, string(enum.SecurityViolationsType), version, bundle)
+		Expect(producer.SendEvent(statusTopicCtx, *event)).To(Succeed())
+		version.Next()
+	}
+
+	It("Should sync the violations and resolve the ones no longer active", func() {
+		By("Sync the active violations, one of them is detected in the previous months")
+		bundle := generic.NewGenericBundle[wiremodels.SecurityViolation]()
+		bundle.Resync = []wiremodels.SecurityViolation{
+			newViolation("alert1", time.Now()),
+			newViolation("alert2", time.Now().AddDate(0, -3, 0)),
+		}
+		sendBundle(bundle)
+
+		Eventually(func() error {
+			var violations []models.SecurityViolation
+			if err := database.GetGorm().Where("hub_name = ?", leafHubName).Find(&violations).Error; err != nil {
+				return err
+			}
+			if len(violations) != 2 {
+				return fmt.Errorf("want 2 violations, but got %d", len(violations))
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+
+		By("Update the time of the alert2 by the new occurrence")
+		lastSeenAt := time.Now().UTC().Truncate(time.Second)
+		bundle = generic.NewGenericBundle[wiremodels.SecurityViolation]()
+		bundle.Update = []wiremodels.SecurityViolation{newViolation("alert2", lastSeenAt)}
+		sendBundle(bundle)
+
+		Eventually(func() error {
+			var violations []models.SecurityViolation
+			err := database.GetGorm().Where("hub_name = ? AND id = ?", leafHubName, "alert2").Find(&violations).Error
+			if err != nil {
+				return err
+			}
+			if len(violations) != 1 {
+				return fmt.Errorf("want the alert2 stored once, but got %d", len(violations))
+			}
+			if !violations[0].LastSeenAt.Equal(lastSeenAt) {
+				return fmt.Errorf("want the alert2 last seen at %s, but got %s", lastSeenAt, violations[0].LastSeenAt)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+
+		By("Sync the metadata without the alert2")
+		bundle = generic.NewGenericBundle[wiremodels.SecurityViolation]()
+		bundle.ResyncMetadata = []generic.ObjectMetadata{
+			{Namespace: source},
+			{ID: "alert1", Namespace: source},
+		}
+		sendBundle(bundle)
+
+		Eventually(func() error {
+			violation := &models.SecurityViolation{}
+			err := database.GetGorm().Where("hub_name = ? AND id = ?", leafHubName, "alert2").First(violation).Error
+			if err != nil {
+				return err
+			}
+			if violation.State != "RESOLVED" {
+				return fmt.Errorf("want the alert2 resolved, but got %s", violation.State)
+			}
+			return nil
+		}, 30*time.Second, 100*time.Millisecond).Should(Succeed())
+
+		violation := &models.SecurityViolation{}
+		Expect(database.GetGorm().Where("hub_name = ? AND id = ?", leafHubName, "alert1").
+			First(violation).Error).To(Succeed())
+		Expect(violation.State).To(Equal("ACTIVE"))
+	})
+})
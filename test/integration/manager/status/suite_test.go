@@ -126,7 +126,7 @@ var _ = BeforeSuite(func() {
 		},
 	}
 	configs.SetEnableInventoryAPI(true)
-	err = status.AddStatusSyncers(mgr, consumer, requester, managerConfig)
+	err = status.AddStatusSyncers(mgr, consumer, producer, requester, managerConfig)
 	Expect(err).ToNot(HaveOccurred())
 
 	By("Start the manager")
@@ -51,12 +51,16 @@ func InitDatabase(uri string) error {
 		fmt.Printf("script %s executed successfully.\n", file.Name())
 	}
 
-	sqlDir = filepath.Join(dirname, "operator", "pkg", "controllers", "storage", "upgrade")
-	upgradeFiles, err := os.ReadDir(sqlDir)
+	// the up migrations are ordered by the version prefix of the file names
+	sqlDir = filepath.Join(dirname, "operator", "pkg", "controllers", "storage", "migration", "migrations")
+	migrationFiles, err := os.ReadDir(sqlDir)
 	if err != nil {
 		return err
 	}
-	for _, file := range upgradeFiles {
+	for _, file := range migrationFiles {
+		if !strings.HasSuffix(file.Name(), ".up.sql") {
+			continue
+		}
 		filePath := filepath.Join(sqlDir, file.Name())
 		fileContent, err := os.ReadFile(filePath)
 		if err != nil {